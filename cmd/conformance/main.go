@@ -0,0 +1,91 @@
+// Command conformance checks plugin SDKs against the host protocol. Each argument names an SDK and
+// the command that launches its conformance fixture:
+//
+//	conformance go="go run ./packages/go/conformance" node="node packages/node/conformance.js"
+//
+// Commands are split on spaces. The report lists every scenario per SDK; the exit status is 1 if
+// any scenario failed. make conformance builds the fixture of every SDK under packages/ and checks
+// them all.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/secmc/plugin/plugin/conformance"
+)
+
+func main() {
+	address := flag.String("address", "", "address to listen on for the plugin (default a free local TCP port)")
+	timeout := flag.Duration("timeout", 5*time.Second, "how long to wait for each expected message")
+	connectTimeout := flag.Duration("connect-timeout", 60*time.Second, "how long a fixture may take to first connect")
+	jsonOut := flag.Bool("json", false, "write the reports as JSON")
+	verbose := flag.Bool("v", false, "show fixture output and progress")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] name=command...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var targets []conformance.Target
+	for _, arg := range flag.Args() {
+		name, command, ok := strings.Cut(arg, "=")
+		fields := strings.Fields(command)
+		if !ok || name == "" || len(fields) == 0 {
+			fmt.Fprintf(os.Stderr, "invalid target %q, want name=command\n", arg)
+			os.Exit(2)
+		}
+		targets = append(targets, conformance.Target{Name: name, Command: fields[0], Args: fields[1:]})
+	}
+	if len(targets) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	r := &conformance.Runner{Address: *address, Timeout: *timeout, ConnectTimeout: *connectTimeout}
+	if *verbose {
+		r.Output = os.Stderr
+		r.Log = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	reports := make([]conformance.Report, 0, len(targets))
+	passed := true
+	for _, t := range targets {
+		report := r.Run(ctx, t)
+		passed = passed && report.Passed()
+		reports = append(reports, report)
+	}
+
+	if err := write(os.Stdout, reports, *jsonOut); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if !passed {
+		os.Exit(1)
+	}
+}
+
+func write(w io.Writer, reports []conformance.Report, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	}
+	for i, report := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		report.WriteText(w)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Command inspect connects to the plugin host as a read-only observer and prints the events it
+// emits, optionally filtered by type, player and world:
+//
+//	inspect -events chat,player_join -player Steve
+//
+// Lines typed on stdin while it runs are sent as actions, such as
+// "teleport 5d6a5ba0-3f2f-4fa4-9b3e-4a6bc7c7f0c1 0 80 0", and their results printed; "help" lists
+// the actions.
+//
+// The ID must be listed in plugins.yaml without a command so the host accepts the connection.
+// Observers are never waited on, so the inspector cannot slow down or change the events it sees.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/secmc/plugin/packages/go/plugin"
+	"github.com/secmc/plugin/plugin/inspect"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func main() {
+	id := flag.String("id", "inspector", "plugin ID to connect as; it must be configured in plugins.yaml")
+	address := flag.String("address", "", "plugin host address (default DF_PLUGIN_SERVER_ADDRESS or the default Unix socket)")
+	events := flag.String("events", "all", "comma-separated event types to show, e.g. chat,player_join")
+	playerFilter := flag.String("player", "", "only show events of the player with this name or UUID")
+	worldFilter := flag.String("world", "", "only show events in the world with this name or dimension")
+	jsonOut := flag.Bool("json", false, "print events and action results as JSON, one per line")
+	resultTimeout := flag.Duration("result-timeout", 2*time.Second, "how long to wait for the result of an action")
+	verbose := flag.Bool("v", false, "log connection problems")
+	flag.Parse()
+
+	types, err := inspect.ParseEventTypes(*events)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	log := slog.New(slog.DiscardHandler)
+	if *verbose {
+		log = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+	opts := []plugin.Option{plugin.WithID(*id), plugin.WithName("Event inspector"), plugin.WithObserver(), plugin.WithLogger(log)}
+	if *address != "" {
+		opts = append(opts, plugin.WithAddress(*address))
+	}
+	p, err := plugin.New(opts...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	out := &syncWriter{w: os.Stdout}
+	printer := inspect.Printer{W: out, JSON: *jsonOut}
+	filter := inspect.Filter{Player: *playerFilter, World: *worldFilter}
+	for _, t := range types {
+		p.Handle(t, func(e *plugin.Event) {
+			if filter.Match(e.Envelope) {
+				_ = printer.Event(e.Envelope)
+			}
+		})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go repl(ctx, p, os.Stdin, out, printer, *resultTimeout)
+	if err := p.Run(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// repl reads actions from in, one per line, until it is closed. Events keep being printed after
+// that until the inspector is interrupted.
+func repl(ctx context.Context, p *plugin.Plugin, in io.Reader, out io.Writer, printer inspect.Printer, timeout time.Duration) {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		name, _, _ := strings.Cut(line, " ")
+		switch name {
+		case "":
+		case "help", "?":
+			help(out, strings.TrimSpace(strings.TrimPrefix(line, name)))
+		default:
+			action, err := inspect.ParseAction(line)
+			if err != nil {
+				fmt.Fprintln(out, "error:", err)
+				continue
+			}
+			do(ctx, p, action, out, printer, timeout)
+		}
+	}
+}
+
+// do sends action and prints its result.
+func do(ctx context.Context, p *plugin.Plugin, action *pb.Action, out io.Writer, printer inspect.Printer, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	res, err := p.Do(ctx, action)
+	switch {
+	case res != nil:
+		_ = printer.ActionResult(res)
+	case errors.Is(err, context.DeadlineExceeded):
+		fmt.Fprintf(out, "sent, no result within %s (the host only answers world actions and queries)\n", timeout)
+	default:
+		fmt.Fprintln(out, "error:", err)
+	}
+}
+
+func help(out io.Writer, action string) {
+	if action != "" {
+		usage, err := inspect.Usage(action)
+		if err != nil {
+			fmt.Fprintln(out, "error:", err)
+			return
+		}
+		fmt.Fprintln(out, usage)
+		return
+	}
+	fmt.Fprintln(out, "Type an action followed by its fields, positionally or in protobuf text format:")
+	fmt.Fprintln(out, "  teleport <player_uuid> <x> <y> <z>")
+	fmt.Fprintln(out, `  send_chat target_uuid: "" message: "hello"`)
+	fmt.Fprintln(out, "help <action> shows an action's fields. Actions:")
+	fmt.Fprintln(out, " ", strings.Join(inspect.Actions(), " "))
+}
+
+// syncWriter serialises writes from event handlers and the REPL.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(b []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(b)
+}
@@ -0,0 +1,111 @@
+// Command replay feeds a recording made by the host (see recording in plugins.yaml) to a plugin
+// and compares what the plugin sends with what it sent when the recording was made:
+//
+//	replay plugins/recordings/example-go-20250101T120000-000000.dfrec go run ./examples/plugins/go
+//
+// The plugin is launched with the recorded plugin ID and connects to the replayer as it would to
+// the host. The exit status is 1 if its messages differ from the recording. With -print the
+// recording is listed instead and no plugin is needed.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"github.com/secmc/plugin/plugin/recording"
+)
+
+func main() {
+	address := flag.String("address", "", "address to listen on for the plugin (default a free local TCP port)")
+	realtime := flag.Bool("realtime", false, "keep the recorded timing instead of replaying as fast as the plugin answers")
+	timeout := flag.Duration("timeout", 5*time.Second, "how long to wait for the plugin to catch up before each host message")
+	connectTimeout := flag.Duration("connect-timeout", 60*time.Second, "how long the plugin may take to connect")
+	ignore := flag.String("ignore", strings.Join(recording.DefaultIgnore, ","), "comma-separated fields and message kinds left out of the comparison")
+	dir := flag.String("dir", "", "working directory for the plugin command")
+	jsonOut := flag.Bool("json", false, "write the result as JSON")
+	printOnly := flag.Bool("print", false, "list the recording's messages instead of replaying it")
+	verbose := flag.Bool("v", false, "show plugin output and progress")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] recording%s command [args...]\n       %s -print recording%s\n",
+			os.Args[0], recording.Ext, os.Args[0], recording.Ext)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 || (!*printOnly && flag.NArg() < 2) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	rec, err := recording.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open recording: %v\n", err)
+		os.Exit(2)
+	}
+	if *printOnly {
+		if err := list(rec); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	r := &recording.Replayer{
+		Address:        *address,
+		Realtime:       *realtime,
+		Timeout:        *timeout,
+		ConnectTimeout: *connectTimeout,
+		Ignore:         []string{},
+	}
+	for _, field := range strings.Split(*ignore, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			r.Ignore = append(r.Ignore, field)
+		}
+	}
+	if *verbose {
+		r.Output = os.Stderr
+		r.Log = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	res, err := r.Run(ctx, rec, recording.Target{Command: flag.Arg(1), Args: flag.Args()[2:], Dir: *dir})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(res); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	} else {
+		res.WriteText(os.Stdout)
+	}
+	if !res.Matched() {
+		os.Exit(1)
+	}
+}
+
+// list prints every frame of rec with its offset and direction.
+func list(rec *recording.Recording) error {
+	fmt.Printf("plugin %s, recorded %s, %d messages\n", rec.PluginID, rec.Start.Format(time.RFC3339), len(rec.Frames))
+	for i, f := range rec.Frames {
+		msg, err := f.Decode()
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i, err)
+		}
+		fmt.Printf("%10s  %s  %s\n", f.Offset.Round(time.Microsecond), f.Direction, prototext.MarshalOptions{}.Format(msg))
+	}
+	return nil
+}
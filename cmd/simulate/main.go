@@ -0,0 +1,93 @@
+// Command simulate plays a scripted timeline of player activity against plugins, without a game
+// client or a full server:
+//
+//	simulate plugins/timeline.yaml
+//
+// The timeline's config (or -config) is the plugins.yaml whose plugins are launched. Every step is
+// printed with its outcome and the actions plugins sent; the exit status is 1 if any step did not
+// meet its expectations.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/simulate"
+)
+
+func main() {
+	configPath := flag.String("config", "", "plugins.yaml to use instead of the timeline's config")
+	jsonOut := flag.Bool("json", false, "write the report as JSON instead of step by step")
+	verbose := flag.Bool("v", false, "show manager logs and plugin output")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] timeline.yaml\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	tl, err := simulate.Load(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	path := tl.Config
+	if *configPath != "" {
+		path = *configPath
+	}
+	if path == "" {
+		path = config.ConfigFile
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load config: %v\n", err)
+		os.Exit(2)
+	}
+
+	s := &simulate.Simulator{}
+	if !*jsonOut {
+		s.Output = os.Stdout
+	}
+	if *verbose {
+		s.Log = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := s.Run(ctx, cfg, tl)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if report == nil {
+			os.Exit(2)
+		}
+	}
+
+	if err := write(os.Stdout, report, *jsonOut); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err != nil || !report.Passed() {
+		os.Exit(1)
+	}
+}
+
+func write(w io.Writer, report *simulate.Report, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	fmt.Fprintln(w)
+	report.WriteSummary(w)
+	return nil
+}
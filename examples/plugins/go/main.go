@@ -0,0 +1,51 @@
+// Command go is an example plugin built with the Go SDK. It greets joining players, rewrites chat
+// and adds a /heal command.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/secmc/plugin/packages/go/plugin"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+type healArgs struct {
+	Amount float64 `cmd:"amount,optional"`
+}
+
+func main() {
+	p, err := plugin.New(plugin.WithName("Go Example"), plugin.WithVersion("0.1.0"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	plugin.On(p, func(e *plugin.Event, join *pb.PlayerJoinEvent) {
+		_ = p.Actions().
+			SendTitle(join.PlayerUuid, "Welcome", join.Name).
+			SendChat("", join.Name+" joined the server").
+			Send()
+	})
+	plugin.On(p, func(e *plugin.Event, chat *pb.ChatEvent) {
+		e.Mutate(plugin.ChatMessage(fmt.Sprintf("[Go] %s", chat.Message)))
+	})
+	plugin.Command(p, "heal", "Restore your health", func(c *plugin.CommandContext, args healArgs) error {
+		health := 20.0
+		if args.Amount > 0 {
+			health = args.Amount
+		}
+		if err := p.Actions().SetHealth(c.Command.PlayerUuid, health).Send(); err != nil {
+			return err
+		}
+		return c.Reply(fmt.Sprintf("Healed to %.0f", health))
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := p.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,78 @@
+//go:build wasip1
+
+// Command wasm is an example plugin compiled to WebAssembly and run inside the host's sandbox. It
+// rewrites chat messages. Build it as a reactor:
+//
+//	GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared -o chat.wasm ./examples/plugins/wasm
+package main
+
+import (
+	"unsafe"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func main() {}
+
+// inbox holds the buffer handed out by alloc until receive reads it.
+var inbox []byte
+
+//go:wasmimport df send
+func hostSend(ptr, size uint32) uint32
+
+func send(msg *pb.PluginToHost) {
+	data, err := proto.Marshal(msg)
+	if err != nil || len(data) == 0 {
+		return
+	}
+	hostSend(uint32(uintptr(unsafe.Pointer(&data[0]))), uint32(len(data)))
+}
+
+//go:wasmexport df_start
+func start() {
+	send(&pb.PluginToHost{Payload: &pb.PluginToHost_Hello{Hello: &pb.PluginHello{
+		Name:       "WASM Example",
+		Version:    "0.1.0",
+		ApiVersion: "v1",
+	}}})
+	send(&pb.PluginToHost{Payload: &pb.PluginToHost_Subscribe{Subscribe: &pb.EventSubscribe{
+		Events: []pb.EventType{pb.EventType_CHAT},
+	}}})
+}
+
+//go:wasmexport df_alloc
+func alloc(size uint32) uint32 {
+	inbox = make([]byte, size+1)
+	return uint32(uintptr(unsafe.Pointer(&inbox[0])))
+}
+
+//go:wasmexport df_receive
+func receive(ptr, size uint32) {
+	msg := &pb.HostToPlugin{}
+	if err := proto.Unmarshal(inbox[:size], msg); err != nil {
+		return
+	}
+	switch payload := msg.Payload.(type) {
+	case *pb.HostToPlugin_Ping:
+		send(&pb.PluginToHost{Payload: &pb.PluginToHost_Pong{Pong: &pb.PluginPong{Nonce: payload.Ping.Nonce}}})
+	case *pb.HostToPlugin_Event:
+		handle(payload.Event)
+	case *pb.HostToPlugin_Events:
+		for _, ev := range payload.Events.Events {
+			handle(ev)
+		}
+	}
+}
+
+func handle(ev *pb.EventEnvelope) {
+	chat := ev.GetChat()
+	if chat == nil || !ev.ExpectsResponse {
+		return
+	}
+	send(&pb.PluginToHost{Payload: &pb.PluginToHost_EventResult{EventResult: &pb.EventResult{
+		EventId: ev.EventId,
+		Update:  &pb.EventResult_Chat{Chat: &pb.ChatMutation{Message: proto.String("[wasm] " + chat.Message)}},
+	}}})
+}
@@ -0,0 +1,265 @@
+// Fixture for the protocol conformance runner in plugin/conformance. There is no C++ SDK yet, so the
+// fixture speaks the protocol with the generated stubs directly. Regenerate them with `make proto`,
+// then:
+//
+//   cmake -S packages/cpp -B packages/cpp/build && cmake --build packages/cpp/build
+//   go run ./cmd/conformance cpp=packages/cpp/build/conformance
+
+#include <cctype>
+#include <chrono>
+#include <cstdint>
+#include <cstdlib>
+#include <functional>
+#include <iostream>
+#include <map>
+#include <memory>
+#include <string>
+#include <thread>
+#include <utility>
+
+#include <grpcpp/grpcpp.h>
+
+#ifdef HAVE_SNAPPY
+#include <snappy.h>
+#endif
+
+#include "plugin.grpc.pb.h"
+#include "plugin.pb.h"
+
+namespace pb = df::plugin;
+
+namespace {
+
+using Stream = grpc::ClientReaderWriterInterface<pb::PluginToHost, pb::HostToPlugin>;
+
+std::string env(const char* name, const std::string& fallback = "") {
+  const char* value = std::getenv(name);
+  return value != nullptr ? value : fallback;
+}
+
+bool contains(const google::protobuf::RepeatedPtrField<std::string>& list, const std::string& value) {
+  for (const auto& v : list) {
+    if (v == value) {
+      return true;
+    }
+  }
+  return false;
+}
+
+// Session is one connection to the host.
+class Session {
+ public:
+  Session(Stream* stream, std::string plugin_id, std::string boot_id, uint64_t last_sequence)
+      : stream_(stream), plugin_id_(std::move(plugin_id)), boot_id_(std::move(boot_id)), last_sequence_(last_sequence) {}
+
+  void Hello() {
+    pb::PluginToHost msg;
+    auto* hello = msg.mutable_hello();
+    hello->set_name("C++ Conformance");
+    hello->set_version("0.1.0");
+    hello->set_api_version("v1");
+    hello->add_api_versions("v1");
+    hello->add_capabilities("heartbeat");
+    hello->add_capabilities("session.resume");
+#ifdef HAVE_SNAPPY
+    hello->add_capabilities("compression.snappy");
+#endif
+    hello->set_launch_token(env("DF_PLUGIN_TOKEN"));
+    auto* echo = hello->add_commands();
+    echo->set_name("echo");
+    echo->set_description("Repeat the arguments");
+    auto* settime = hello->add_commands();
+    settime->set_name("settime");
+    settime->set_description("Set the time of the world");
+    if (!boot_id_.empty()) {
+      hello->mutable_resume()->set_boot_id(boot_id_);
+      hello->mutable_resume()->set_last_sequence(last_sequence_);
+    }
+    Send(msg);
+
+    pb::PluginToHost sub;
+    sub.mutable_subscribe()->add_events(pb::CHAT);
+    sub.mutable_subscribe()->add_events(pb::COMMAND);
+    sub.mutable_subscribe()->add_events(pb::PLAYER_JOIN);
+    Send(sub);
+  }
+
+  // Run handles messages until the stream ends and reports whether the host shut the plugin down.
+  bool Run() {
+    pb::HostToPlugin msg;
+    while (stream_->Read(&msg)) {
+      switch (msg.payload_case()) {
+        case pb::HostToPlugin::kHello:
+          resume_ = contains(msg.hello().enabled_capabilities(), "session.resume");
+          if (resume_) {
+            if (msg.hello().boot_id() != boot_id_) {
+              last_sequence_ = 0;
+            }
+            boot_id_ = msg.hello().boot_id();
+          }
+          break;
+        case pb::HostToPlugin::kShutdown:
+          stream_->WritesDone();
+          return true;
+        case pb::HostToPlugin::kPing: {
+          pb::PluginToHost pong;
+          pong.mutable_pong()->set_nonce(msg.ping().nonce());
+          Send(pong);
+          break;
+        }
+        case pb::HostToPlugin::kEvent:
+          Event(msg.event());
+          break;
+        case pb::HostToPlugin::kEvents:
+          for (const auto& ev : msg.events().events()) {
+            Event(ev);
+          }
+          break;
+#ifdef HAVE_SNAPPY
+        case pb::HostToPlugin::kCompressedEvents: {
+          std::string data;
+          pb::EventBatch batch;
+          if (snappy::Uncompress(msg.compressed_events().data().data(), msg.compressed_events().data().size(), &data) &&
+              batch.ParseFromString(data)) {
+            for (const auto& ev : batch.events()) {
+              Event(ev);
+            }
+          }
+          break;
+        }
+#endif
+        case pb::HostToPlugin::kActionResult: {
+          auto it = pending_.find(msg.action_result().correlation_id());
+          if (it != pending_.end()) {
+            auto done = std::move(it->second);
+            pending_.erase(it);
+            done(msg.action_result());
+          }
+          break;
+        }
+        default:
+          break;
+      }
+    }
+    return false;
+  }
+
+  bool resume() const { return resume_; }
+  const std::string& boot_id() const { return boot_id_; }
+  uint64_t last_sequence() const { return last_sequence_; }
+
+ private:
+  void Send(pb::PluginToHost& msg) {
+    msg.set_plugin_id(plugin_id_);
+    stream_->Write(msg);
+  }
+
+  void Event(const pb::EventEnvelope& ev) {
+    pb::PluginToHost msg;
+    auto* result = msg.mutable_event_result();
+    result->set_event_id(ev.event_id());
+    if (ev.has_chat()) {
+      if (ev.chat().message() == "cancel") {
+        result->set_cancel(true);
+      } else {
+        std::string upper = ev.chat().message();
+        for (auto& c : upper) {
+          c = static_cast<char>(std::toupper(static_cast<unsigned char>(c)));
+        }
+        result->mutable_chat()->set_message(upper);
+      }
+    } else if (ev.has_command()) {
+      result->set_cancel(Command(ev.command()));
+    }
+    if (ev.sequence() > last_sequence_) {
+      last_sequence_ = ev.sequence();
+    }
+    if (ev.expects_response()) {
+      Send(msg);
+    }
+  }
+
+  bool Command(const pb::CommandEvent& cmd) {
+    const std::string& player = cmd.player_uuid();
+    if (cmd.command() == "echo") {
+      std::string text;
+      for (int i = 0; i < cmd.args_size(); i++) {
+        if (i > 0) {
+          text += " ";
+        }
+        text += cmd.args(i);
+      }
+      Chat(player, text);
+      return true;
+    }
+    if (cmd.command() == "settime") {
+      SetTime(player, cmd.args_size() > 0 ? std::atoi(cmd.args(0).c_str()) : 0);
+      return true;
+    }
+    return false;
+  }
+
+  void SetTime(const std::string& player, int32_t ticks) {
+    std::string correlation_id = "settime-" + std::to_string(next_correlation_++);
+    pending_[correlation_id] = [this, player](const pb::ActionResult& result) {
+      if (result.status().ok()) {
+        Chat(player, "ok");
+      } else {
+        Chat(player, "error: " + result.status().error());
+      }
+    };
+    pb::PluginToHost msg;
+    auto* action = msg.mutable_actions()->add_actions();
+    action->set_correlation_id(correlation_id);
+    action->mutable_world_set_time()->mutable_world()->set_name("world");
+    action->mutable_world_set_time()->set_time(ticks);
+    Send(msg);
+  }
+
+  void Chat(const std::string& player, const std::string& message) {
+    pb::PluginToHost msg;
+    auto* chat = msg.mutable_actions()->add_actions()->mutable_send_chat();
+    chat->set_target_uuid(player);
+    chat->set_message(message);
+    Send(msg);
+  }
+
+  Stream* stream_;
+  std::string plugin_id_;
+  std::string boot_id_;
+  uint64_t last_sequence_;
+  bool resume_ = false;
+  uint64_t next_correlation_ = 0;
+  std::map<std::string, std::function<void(const pb::ActionResult&)>> pending_;
+};
+
+}  // namespace
+
+int main() {
+  std::string address = env("DF_PLUGIN_SERVER_ADDRESS", "unix:///tmp/dragonfly_plugin.sock");
+  if (address.rfind("/", 0) == 0) {
+    address = "unix://" + address;
+  }
+  std::string plugin_id = env("DF_PLUGIN_ID", "cpp-conformance");
+  auto stub = pb::Plugin::NewStub(grpc::CreateChannel(address, grpc::InsecureChannelCredentials()));
+
+  std::string boot_id;
+  uint64_t last_sequence = 0;
+  while (true) {
+    grpc::ClientContext ctx;
+    auto stream = stub->EventStream(&ctx);
+    Session session(stream.get(), plugin_id, boot_id, last_sequence);
+    session.Hello();
+    bool shutdown = session.Run();
+    grpc::Status status = stream->Finish();
+    if (shutdown) {
+      return 0;
+    }
+    std::cerr << "[cpp] stream lost: " << status.error_message() << std::endl;
+    if (session.resume()) {
+      boot_id = session.boot_id();
+      last_sequence = session.last_sequence();
+    }
+    std::this_thread::sleep_for(std::chrono::milliseconds(500));
+  }
+}
@@ -0,0 +1,65 @@
+// Command conformance is the Go SDK's fixture for the protocol conformance runner. It behaves as
+// the runner in plugin/conformance expects:
+//
+//	go run ./cmd/conformance go="go run ./packages/go/conformance"
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/secmc/plugin/packages/go/plugin"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+type echoArgs struct {
+	Words []string
+}
+
+type setTimeArgs struct {
+	Time int `cmd:"time"`
+}
+
+func main() {
+	p, err := plugin.New(plugin.WithName("Go Conformance"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	setup(p)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := p.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// setup registers the handlers the conformance scenarios drive.
+func setup(p *plugin.Plugin) {
+	plugin.On(p, func(e *plugin.Event, chat *pb.ChatEvent) {
+		if chat.Message == "cancel" {
+			e.Cancel()
+			return
+		}
+		e.Mutate(plugin.ChatMessage(strings.ToUpper(chat.Message)))
+	})
+	// Joins only need to be handled so that their sequences are resumed from.
+	plugin.On(p, func(*plugin.Event, *pb.PlayerJoinEvent) {})
+
+	plugin.Command(p, "echo", "Repeat the arguments", func(c *plugin.CommandContext, args echoArgs) error {
+		return c.Reply(strings.Join(args.Words, " "))
+	})
+	plugin.Command(p, "settime", "Set the time of the world", func(c *plugin.CommandContext, args setTimeArgs) error {
+		res, err := p.Actions().SetTime(&pb.WorldRef{Name: "world"}, int32(args.Time)).Await(c.Context())
+		if err != nil {
+			return err
+		}
+		if status := res[0].GetStatus(); !status.GetOk() {
+			return c.Reply("error: " + status.GetError())
+		}
+		return c.Reply("ok")
+	})
+}
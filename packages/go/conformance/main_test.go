@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/secmc/plugin/packages/go/plugin"
+	"github.com/secmc/plugin/plugin/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	r := &conformance.Runner{}
+	report := r.Run(context.Background(), conformance.Target{
+		Name: "go",
+		Launch: func(ctx context.Context, address, pluginID string) error {
+			p, err := plugin.New(plugin.WithID(pluginID), plugin.WithAddress(address), plugin.WithLogger(slog.New(slog.DiscardHandler)))
+			if err != nil {
+				return err
+			}
+			setup(p)
+			return p.Run(ctx)
+		},
+	})
+	var b strings.Builder
+	report.WriteText(&b)
+	for _, res := range report.Results {
+		if res.Status != conformance.Pass {
+			t.Errorf("Expected every scenario to pass:\n%s", b.String())
+			break
+		}
+	}
+}
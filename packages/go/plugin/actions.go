@@ -0,0 +1,209 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// Batch collects actions to send to the host in one ActionBatch. Its methods return the batch so
+// calls can be chained:
+//
+//	err := p.Actions().SendChat(uuid, "Welcome!").SendTitle(uuid, "Hello", "").Send()
+//
+// The host answers world actions with an ActionResult, which Await waits for. Player actions are
+// not acknowledged.
+type Batch struct {
+	p       *Plugin
+	actions []*pb.Action
+}
+
+// Actions starts a new batch of actions.
+func (p *Plugin) Actions() *Batch {
+	return &Batch{p: p}
+}
+
+// Add appends an action. Set its CorrelationId to have Await wait for its result.
+func (b *Batch) Add(action *pb.Action) *Batch {
+	b.actions = append(b.actions, action)
+	return b
+}
+
+// addAwaited appends an action the host answers, giving it a correlation ID.
+func (b *Batch) addAwaited(action *pb.Action) *Batch {
+	action.CorrelationId = proto.String(b.p.correlationID())
+	return b.Add(action)
+}
+
+// SendChat sends a chat message to a player, or to everyone if target is empty.
+func (b *Batch) SendChat(target, message string) *Batch {
+	return b.Add(&pb.Action{Kind: &pb.Action_SendChat{SendChat: &pb.SendChatAction{TargetUuid: target, Message: message}}})
+}
+
+// Teleport moves a player. rotation may be nil to keep the player's rotation.
+func (b *Batch) Teleport(player string, position, rotation *pb.Vec3) *Batch {
+	return b.Add(&pb.Action{Kind: &pb.Action_Teleport{Teleport: &pb.TeleportAction{PlayerUuid: player, Position: position, Rotation: rotation}}})
+}
+
+// Kick disconnects a player.
+func (b *Batch) Kick(player, reason string) *Batch {
+	return b.Add(&pb.Action{Kind: &pb.Action_Kick{Kick: &pb.KickAction{PlayerUuid: player, Reason: reason}}})
+}
+
+// SetGameMode changes a player's game mode.
+func (b *Batch) SetGameMode(player string, mode pb.GameMode) *Batch {
+	return b.Add(&pb.Action{Kind: &pb.Action_SetGameMode{SetGameMode: &pb.SetGameModeAction{PlayerUuid: player, GameMode: mode}}})
+}
+
+// GiveItem adds an item to a player's inventory.
+func (b *Batch) GiveItem(player string, item *pb.ItemStack) *Batch {
+	return b.Add(&pb.Action{Kind: &pb.Action_GiveItem{GiveItem: &pb.GiveItemAction{PlayerUuid: player, Item: item}}})
+}
+
+// SetHealth sets a player's health.
+func (b *Batch) SetHealth(player string, health float64) *Batch {
+	return b.Add(&pb.Action{Kind: &pb.Action_SetHealth{SetHealth: &pb.SetHealthAction{PlayerUuid: player, Health: health}}})
+}
+
+// SendTitle shows a title to a player. subtitle may be empty.
+func (b *Batch) SendTitle(player, title, subtitle string) *Batch {
+	act := &pb.SendTitleAction{PlayerUuid: player, Title: title}
+	if subtitle != "" {
+		act.Subtitle = proto.String(subtitle)
+	}
+	return b.Add(&pb.Action{Kind: &pb.Action_SendTitle{SendTitle: act}})
+}
+
+// SendPopup shows a popup to a player.
+func (b *Batch) SendPopup(player, message string) *Batch {
+	return b.Add(&pb.Action{Kind: &pb.Action_SendPopup{SendPopup: &pb.SendPopupAction{PlayerUuid: player, Message: message}}})
+}
+
+// SendTip shows a tip to a player.
+func (b *Batch) SendTip(player, message string) *Batch {
+	return b.Add(&pb.Action{Kind: &pb.Action_SendTip{SendTip: &pb.SendTipAction{PlayerUuid: player, Message: message}}})
+}
+
+// ExecuteCommand runs a command as a player. command has no leading slash.
+func (b *Batch) ExecuteCommand(player, command string) *Batch {
+	return b.Add(&pb.Action{Kind: &pb.Action_ExecuteCommand{ExecuteCommand: &pb.ExecuteCommandAction{PlayerUuid: player, Command: command}}})
+}
+
+// SetBlock places a block. A nil block clears the position to air.
+func (b *Batch) SetBlock(world *pb.WorldRef, position *pb.BlockPos, block *pb.BlockState) *Batch {
+	return b.addAwaited(&pb.Action{Kind: &pb.Action_WorldSetBlock{WorldSetBlock: &pb.WorldSetBlockAction{World: world, Position: position, Block: block}}})
+}
+
+// SetTime sets the time of day of a world.
+func (b *Batch) SetTime(world *pb.WorldRef, time int32) *Batch {
+	return b.addAwaited(&pb.Action{Kind: &pb.Action_WorldSetTime{WorldSetTime: &pb.WorldSetTimeAction{World: world, Time: time}}})
+}
+
+// PlaySound plays a sound at a position in a world.
+func (b *Batch) PlaySound(world *pb.WorldRef, sound pb.Sound, position *pb.Vec3) *Batch {
+	return b.addAwaited(&pb.Action{Kind: &pb.Action_WorldPlaySound{WorldPlaySound: &pb.WorldPlaySoundAction{World: world, Sound: sound, Position: position}}})
+}
+
+// AddParticle shows a particle at a position in a world.
+func (b *Batch) AddParticle(world *pb.WorldRef, particle pb.ParticleType, position *pb.Vec3) *Batch {
+	return b.addAwaited(&pb.Action{Kind: &pb.Action_WorldAddParticle{WorldAddParticle: &pb.WorldAddParticleAction{World: world, Particle: particle, Position: position}}})
+}
+
+// QueryBlock asks for the block at a position. Its result is in ActionResult.GetWorldBlock.
+func (b *Batch) QueryBlock(world *pb.WorldRef, position *pb.BlockPos) *Batch {
+	return b.addAwaited(&pb.Action{Kind: &pb.Action_WorldQueryBlock{WorldQueryBlock: &pb.WorldQueryBlockAction{World: world, Position: position}}})
+}
+
+// QueryPlayers asks for the players in a world. Its result is in ActionResult.GetWorldPlayers.
+func (b *Batch) QueryPlayers(world *pb.WorldRef) *Batch {
+	return b.addAwaited(&pb.Action{Kind: &pb.Action_WorldQueryPlayers{WorldQueryPlayers: &pb.WorldQueryPlayersAction{World: world}}})
+}
+
+// Send sends the batch without waiting for results.
+func (b *Batch) Send() error {
+	if len(b.actions) == 0 {
+		return nil
+	}
+	return b.p.send(&pb.PluginToHost{Payload: &pb.PluginToHost_Actions{Actions: &pb.ActionBatch{Actions: b.actions}}})
+}
+
+// Await sends the batch and waits for the result of every action with a correlation ID. Results
+// are returned in the order of those actions. A failed action does not make Await fail; check
+// each result's Status.
+func (b *Batch) Await(ctx context.Context) ([]*pb.ActionResult, error) {
+	var ids []string
+	var waits []chan *pb.ActionResult
+	for _, action := range b.actions {
+		if id := action.GetCorrelationId(); id != "" {
+			ids = append(ids, id)
+			waits = append(waits, b.p.await(id))
+		}
+	}
+	defer b.p.forget(ids...)
+	if err := b.Send(); err != nil {
+		return nil, err
+	}
+	results := make([]*pb.ActionResult, len(waits))
+	for i, ch := range waits {
+		select {
+		case results[i] = <-ch:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("await action %s: %w", ids[i], ctx.Err())
+		}
+	}
+	return results, nil
+}
+
+// Do sends a single action and waits for its result. The action is given a correlation ID if it
+// has none. It fails if the action fails.
+func (p *Plugin) Do(ctx context.Context, action *pb.Action) (*pb.ActionResult, error) {
+	if action.GetCorrelationId() == "" {
+		action.CorrelationId = proto.String(p.correlationID())
+	}
+	results, err := p.Actions().Add(action).Await(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := results[0]
+	if !res.GetStatus().GetOk() {
+		return res, errors.New(res.GetStatus().GetError())
+	}
+	return res, nil
+}
+
+func (p *Plugin) correlationID() string {
+	return p.id + "-" + strconv.FormatUint(p.nextID.Add(1), 10)
+}
+
+// await registers interest in the result of the action with the given correlation ID.
+func (p *Plugin) await(id string) chan *pb.ActionResult {
+	ch := make(chan *pb.ActionResult, 1)
+	p.pendingMu.Lock()
+	p.pending[id] = ch
+	p.pendingMu.Unlock()
+	return ch
+}
+
+func (p *Plugin) forget(ids ...string) {
+	p.pendingMu.Lock()
+	for _, id := range ids {
+		delete(p.pending, id)
+	}
+	p.pendingMu.Unlock()
+}
+
+// resolve delivers an ActionResult to the Await waiting for it, if any.
+func (p *Plugin) resolve(res *pb.ActionResult) {
+	p.pendingMu.Lock()
+	ch, ok := p.pending[res.GetCorrelationId()]
+	delete(p.pending, res.GetCorrelationId())
+	p.pendingMu.Unlock()
+	if ok {
+		ch <- res
+	}
+}
@@ -0,0 +1,193 @@
+package plugin
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// CommandContext is passed to command handlers.
+type CommandContext struct {
+	*Event
+	// Command is the command event: the player who ran it and the raw arguments.
+	Command *pb.CommandEvent
+}
+
+// Reply sends a chat message to the player who ran the command.
+func (c *CommandContext) Reply(message string) error {
+	return c.Plugin.Actions().SendChat(c.Command.GetPlayerUuid(), message).Send()
+}
+
+// command is a registered command and how to bind its arguments.
+type command struct {
+	spec   *pb.CommandSpec
+	params []param
+	run    func(*CommandContext, reflect.Value) error
+	args   reflect.Type
+}
+
+// param binds one command parameter to a field of the arguments struct.
+type param struct {
+	spec  *pb.ParamSpec
+	field int
+}
+
+// Command registers a command whose arguments are bound to the exported fields of A, in order.
+// Fields may be strings, integers, floats or bools; a final []string field takes the remaining
+// arguments. Field tags adjust the parameter:
+//
+//	Target string  `cmd:"target"`            // parameter name, defaults to the lowercased field name
+//	Amount int     `cmd:"amount,optional"`   // may be left out; the field keeps its zero value
+//	Mode   string  `cmd:"mode" enum:"a|b"`   // must be one of the listed values
+//	Radius float64 `cmd:"radius" suffix:"m"` // suffix shown in the client
+//
+// Errors returned by handler, and arguments that do not bind, are sent to the player. Command
+// panics if A is not a struct or has a field of another type.
+func Command[A any](p *Plugin, name, description string, handler func(*CommandContext, A) error, aliases ...string) {
+	t := reflect.TypeFor[A]()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("plugin: command %s arguments must be a struct, got %s", name, t))
+	}
+	c := &command{
+		spec: &pb.CommandSpec{Name: name, Description: description, Aliases: aliases},
+		args: t,
+		run: func(ctx *CommandContext, v reflect.Value) error {
+			return handler(ctx, v.Interface().(A))
+		},
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		ps := &pb.ParamSpec{Name: strings.ToLower(f.Name), Suffix: f.Tag.Get("suffix")}
+		if tag, ok := f.Tag.Lookup("cmd"); ok {
+			n, opts, _ := strings.Cut(tag, ",")
+			if n != "" {
+				ps.Name = n
+			}
+			ps.Optional = opts == "optional"
+		}
+		switch f.Type.Kind() {
+		case reflect.String:
+			ps.Type = pb.ParamType_PARAM_STRING
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			ps.Type = pb.ParamType_PARAM_INT
+		case reflect.Float32, reflect.Float64:
+			ps.Type = pb.ParamType_PARAM_FLOAT
+		case reflect.Bool:
+			ps.Type = pb.ParamType_PARAM_BOOL
+		case reflect.Slice:
+			if f.Type.Elem().Kind() != reflect.String || i != t.NumField()-1 {
+				panic(fmt.Sprintf("plugin: command %s: only a final []string field may take varargs", name))
+			}
+			ps.Type = pb.ParamType_PARAM_VARARGS
+			ps.Optional = true
+		default:
+			panic(fmt.Sprintf("plugin: command %s: unsupported argument type %s for %s", name, f.Type, f.Name))
+		}
+		if values, ok := f.Tag.Lookup("enum"); ok {
+			ps.Type = pb.ParamType_PARAM_ENUM
+			ps.EnumValues = strings.Split(values, "|")
+		}
+		c.spec.Params = append(c.spec.Params, ps)
+		c.params = append(c.params, param{spec: ps, field: i})
+	}
+
+	p.specs = append(p.specs, c.spec)
+	for _, n := range append([]string{name}, aliases...) {
+		p.commands[strings.ToLower(strings.TrimPrefix(n, "/"))] = c
+	}
+	if _, ok := p.handlers[pb.EventType_COMMAND]; !ok {
+		p.Handle(pb.EventType_COMMAND, p.handleCommand)
+	}
+}
+
+func (p *Plugin) handleCommand(e *Event) {
+	ev := e.Envelope.GetCommand()
+	c, ok := p.commands[strings.ToLower(ev.GetCommand())]
+	if !ok {
+		return
+	}
+	ctx := &CommandContext{Event: e, Command: ev}
+	args, err := c.bind(ev.GetArgs())
+	if err == nil {
+		err = c.run(ctx, args)
+	}
+	if err != nil {
+		if err := ctx.Reply("§c" + err.Error()); err != nil {
+			p.log.Warn("reply to command", "command", c.spec.Name, "error", err)
+		}
+	}
+}
+
+// bind parses args into a new arguments struct.
+func (c *command) bind(args []string) (reflect.Value, error) {
+	v := reflect.New(c.args).Elem()
+	for i, prm := range c.params {
+		field := v.Field(prm.field)
+		if prm.spec.Type == pb.ParamType_PARAM_VARARGS {
+			field.Set(reflect.ValueOf(slices.Clone(args[min(i, len(args)):])))
+			return v, nil
+		}
+		if i >= len(args) {
+			if prm.spec.Optional {
+				continue
+			}
+			return v, fmt.Errorf("missing argument <%s>; usage: %s", prm.spec.Name, c.usage())
+		}
+		if err := setArg(field, prm.spec, args[i]); err != nil {
+			return v, fmt.Errorf("invalid argument <%s>: %w; usage: %s", prm.spec.Name, err, c.usage())
+		}
+	}
+	if len(args) > len(c.params) {
+		return v, fmt.Errorf("too many arguments; usage: %s", c.usage())
+	}
+	return v, nil
+}
+
+func setArg(field reflect.Value, spec *pb.ParamSpec, arg string) error {
+	if len(spec.EnumValues) > 0 && !slices.Contains(spec.EnumValues, arg) {
+		return fmt.Errorf("must be one of %s", strings.Join(spec.EnumValues, ", "))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(arg)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(arg, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", arg)
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(arg, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a number", arg)
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(arg)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", arg)
+		}
+		field.SetBool(b)
+	}
+	return nil
+}
+
+func (c *command) usage() string {
+	var b strings.Builder
+	b.WriteString("/" + strings.TrimPrefix(c.spec.Name, "/"))
+	for _, prm := range c.spec.Params {
+		if prm.Optional {
+			fmt.Fprintf(&b, " [%s]", prm.Name)
+		} else {
+			fmt.Fprintf(&b, " <%s>", prm.Name)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// Event is an event being handled. Handlers registered for the same event type share it, so a
+// later handler sees the cancellation and mutations made by earlier ones.
+type Event struct {
+	// Envelope is the event as received from the host.
+	Envelope *pb.EventEnvelope
+	// Plugin is the plugin handling the event, for sending actions in response.
+	Plugin *Plugin
+
+	ctx    context.Context
+	result *pb.EventResult
+}
+
+// Context returns a context that is cancelled when the plugin stops.
+func (e *Event) Context() context.Context {
+	return e.ctx
+}
+
+// Type returns the event's type.
+func (e *Event) Type() pb.EventType {
+	return e.Envelope.GetType()
+}
+
+// Cancellable reports whether the host waits for the outcome of the event, i.e. whether Cancel and
+// Mutate have any effect.
+func (e *Event) Cancellable() bool {
+	return e.Envelope.GetExpectsResponse()
+}
+
+// Cancel cancels the event.
+func (e *Event) Cancel() {
+	e.result.Cancel = proto.Bool(true)
+}
+
+// Uncancel reverts a cancellation made by an earlier handler.
+func (e *Event) Uncancel() {
+	e.result.Cancel = proto.Bool(false)
+}
+
+// Cancelled reports whether a handler cancelled the event.
+func (e *Event) Cancelled() bool {
+	return e.result.GetCancel()
+}
+
+// Mutate changes the outcome of the event. Mutations must match the event type, e.g. ChatMessage
+// for a CHAT event; the host ignores mismatched ones.
+func (e *Event) Mutate(mutations ...Mutation) {
+	for _, m := range mutations {
+		m(e.result)
+	}
+}
+
+// Result returns the EventResult that will be sent for the event, for changes not covered by the
+// mutation helpers.
+func (e *Event) Result() *pb.EventResult {
+	return e.result
+}
+
+// Handle registers handler for every event of type t. EVENT_TYPE_ALL receives all subscribed events.
+func (p *Plugin) Handle(t pb.EventType, handler func(*Event)) {
+	p.handlers[t] = append(p.handlers[t], handler)
+}
+
+// On registers a typed handler for the event whose payload is T, e.g. *pb.ChatEvent for CHAT. It
+// panics if T is not an event payload.
+func On[T proto.Message](p *Plugin, handler func(*Event, T)) {
+	var zero T
+	t, ok := payloadTypes()[zero.ProtoReflect().Descriptor().FullName()]
+	if !ok {
+		panic(fmt.Sprintf("plugin: %T is not an event payload", zero))
+	}
+	p.Handle(t, func(e *Event) {
+		if payload, ok := payload(e.Envelope).(T); ok {
+			handler(e, payload)
+		}
+	})
+}
+
+// payloadTypes maps each EventEnvelope payload message to its event type. Payload field numbers
+// equal the EventType values.
+var payloadTypes = sync.OnceValue(func() map[protoreflect.FullName]pb.EventType {
+	oneof := (&pb.EventEnvelope{}).ProtoReflect().Descriptor().Oneofs().ByName("payload")
+	types := make(map[protoreflect.FullName]pb.EventType, oneof.Fields().Len())
+	for i := 0; i < oneof.Fields().Len(); i++ {
+		field := oneof.Fields().Get(i)
+		types[field.Message().FullName()] = pb.EventType(field.Number())
+	}
+	return types
+})
+
+// payload returns the message set in the envelope's payload, or nil.
+func payload(env *pb.EventEnvelope) proto.Message {
+	msg := env.ProtoReflect()
+	field := msg.WhichOneof(msg.Descriptor().Oneofs().ByName("payload"))
+	if field == nil {
+		return nil
+	}
+	return msg.Get(field).Message().Interface()
+}
+
+// subscriptions lists the event types with handlers.
+func (p *Plugin) subscriptions() []pb.EventType {
+	events := make([]pb.EventType, 0, len(p.handlers))
+	for t := range p.handlers {
+		events = append(events, t)
+	}
+	slices.Sort(events)
+	return events
+}
+
+// dispatchEvents runs handlers for received events one at a time, in the order they arrived.
+// Handlers run outside the receive loop, so they may wait for action results.
+func (p *Plugin) dispatchEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case env := <-p.events:
+			p.handleEvent(ctx, env)
+		}
+	}
+}
+
+func (p *Plugin) handleEvent(ctx context.Context, env *pb.EventEnvelope) {
+	seq := env.GetSequence()
+	if seq != 0 && seq <= p.lastSequence.Load() {
+		// Already handled before a reconnect.
+		return
+	}
+	e := &Event{
+		Envelope: env,
+		Plugin:   p,
+		ctx:      ctx,
+		result:   &pb.EventResult{EventId: env.GetEventId()},
+	}
+	for _, handler := range p.handlers[env.GetType()] {
+		p.runHandler(handler, e)
+	}
+	for _, handler := range p.handlers[pb.EventType_EVENT_TYPE_ALL] {
+		p.runHandler(handler, e)
+	}
+	if seq != 0 {
+		p.lastSequence.Store(seq)
+	}
+	if env.GetExpectsResponse() {
+		if err := p.send(&pb.PluginToHost{Payload: &pb.PluginToHost_EventResult{EventResult: e.result}}); err != nil {
+			p.log.Warn("send event result", "event", env.GetType(), "error", err)
+		}
+	}
+}
+
+// runHandler runs handler, logging instead of crashing the plugin if it panics.
+func (p *Plugin) runHandler(handler func(*Event), e *Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.log.Error("event handler panicked", "event", e.Type(), "panic", r)
+		}
+	}()
+	handler(e)
+}
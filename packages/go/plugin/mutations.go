@@ -0,0 +1,166 @@
+package plugin
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// Mutation changes the outcome of an event. Mutations for the same event combine; a mutation for
+// a different event type replaces them.
+type Mutation func(*pb.EventResult)
+
+// update returns the result's mutation message in the given update field, setting it first if
+// another update or none is set.
+func update[M proto.Message](r *pb.EventResult, field protoreflect.Name) M {
+	msg := r.ProtoReflect()
+	fd := msg.Descriptor().Fields().ByName(field)
+	return msg.Mutable(fd).Message().Interface().(M)
+}
+
+// ChatMessage replaces the message of a CHAT event.
+func ChatMessage(message string) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.ChatMutation](r, "chat").Message = proto.String(message)
+	}
+}
+
+// BlockBreakDrops replaces the drops of a PLAYER_BLOCK_BREAK event. No items means no drops.
+func BlockBreakDrops(items ...*pb.ItemStack) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.BlockBreakMutation](r, "block_break").Drops = &pb.ItemStackList{Items: items}
+	}
+}
+
+// BlockBreakXP sets the experience dropped by a PLAYER_BLOCK_BREAK event.
+func BlockBreakXP(xp int32) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.BlockBreakMutation](r, "block_break").Xp = proto.Int32(xp)
+	}
+}
+
+// FoodLossTo sets the food level a PLAYER_FOOD_LOSS event ends at.
+func FoodLossTo(to int32) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerFoodLossMutation](r, "player_food_loss").To = proto.Int32(to)
+	}
+}
+
+// HealAmount sets the health restored by a PLAYER_HEAL event.
+func HealAmount(amount float64) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerHealMutation](r, "player_heal").Amount = proto.Float64(amount)
+	}
+}
+
+// HurtDamage sets the damage dealt by a PLAYER_HURT event.
+func HurtDamage(damage float64) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerHurtMutation](r, "player_hurt").Damage = proto.Float64(damage)
+	}
+}
+
+// HurtAttackImmunity sets how long the player is immune to further damage after a PLAYER_HURT
+// event.
+func HurtAttackImmunity(d time.Duration) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerHurtMutation](r, "player_hurt").AttackImmunityMs = proto.Int64(d.Milliseconds())
+	}
+}
+
+// DeathKeepInventory sets whether the player keeps their inventory in a PLAYER_DEATH event.
+func DeathKeepInventory(keep bool) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerDeathMutation](r, "player_death").KeepInventory = proto.Bool(keep)
+	}
+}
+
+// RespawnAt sets where the player respawns in a PLAYER_RESPAWN event. world may be nil to keep
+// the respawn world.
+func RespawnAt(position *pb.Vec3, world *pb.WorldRef) Mutation {
+	return func(r *pb.EventResult) {
+		m := update[*pb.PlayerRespawnMutation](r, "player_respawn")
+		m.Position, m.World = position, world
+	}
+}
+
+// AttackForce sets the knockback force of a PLAYER_ATTACK_ENTITY event.
+func AttackForce(force float64) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerAttackEntityMutation](r, "player_attack_entity").Force = proto.Float64(force)
+	}
+}
+
+// AttackHeight sets the knockback height of a PLAYER_ATTACK_ENTITY event.
+func AttackHeight(height float64) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerAttackEntityMutation](r, "player_attack_entity").Height = proto.Float64(height)
+	}
+}
+
+// AttackCritical sets whether a PLAYER_ATTACK_ENTITY event is a critical hit.
+func AttackCritical(critical bool) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerAttackEntityMutation](r, "player_attack_entity").Critical = proto.Bool(critical)
+	}
+}
+
+// ExperienceGain sets the experience gained in a PLAYER_EXPERIENCE_GAIN event.
+func ExperienceGain(amount int32) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerExperienceGainMutation](r, "player_experience_gain").Amount = proto.Int32(amount)
+	}
+}
+
+// LecternPage sets the page a PLAYER_LECTERN_PAGE_TURN event turns to.
+func LecternPage(page int32) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerLecternPageTurnMutation](r, "player_lectern_page_turn").NewPage = proto.Int32(page)
+	}
+}
+
+// PickupItem replaces the item picked up in a PLAYER_ITEM_PICKUP event.
+func PickupItem(item *pb.ItemStack) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerItemPickupMutation](r, "player_item_pickup").Item = item
+	}
+}
+
+// TransferAddress replaces the server a PLAYER_TRANSFER event sends the player to.
+func TransferAddress(address *pb.Address) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.PlayerTransferMutation](r, "player_transfer").Address = address
+	}
+}
+
+// ExplosionEntities replaces the entities affected by a WORLD_EXPLOSION event.
+func ExplosionEntities(uuids ...string) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.WorldExplosionMutation](r, "world_explosion").EntityUuids = &pb.StringList{Values: uuids}
+	}
+}
+
+// ExplosionBlocks replaces the blocks destroyed by a WORLD_EXPLOSION event.
+func ExplosionBlocks(positions ...*pb.BlockPos) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.WorldExplosionMutation](r, "world_explosion").Blocks = &pb.BlockPosList{Positions: positions}
+	}
+}
+
+// ExplosionItemDropChance sets the chance that destroyed blocks drop items in a WORLD_EXPLOSION
+// event.
+func ExplosionItemDropChance(chance float64) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.WorldExplosionMutation](r, "world_explosion").ItemDropChance = proto.Float64(chance)
+	}
+}
+
+// ExplosionSpawnFire sets whether a WORLD_EXPLOSION event sets fire.
+func ExplosionSpawnFire(fire bool) Mutation {
+	return func(r *pb.EventResult) {
+		update[*pb.WorldExplosionMutation](r, "world_explosion").SpawnFire = proto.Bool(fire)
+	}
+}
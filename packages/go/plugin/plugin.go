@@ -0,0 +1,386 @@
+// Package plugin is the Go SDK for Dragonfly plugins. It connects to the plugin host over gRPC,
+// dispatches events to typed handlers, answers cancellable events with the mutations handlers
+// made, and sends actions back to the host.
+//
+//	p, err := plugin.New(plugin.WithVersion("1.0.0"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	plugin.On(p, func(e *plugin.Event, chat *pb.ChatEvent) {
+//		e.Mutate(plugin.ChatMessage("<" + chat.Name + "> " + chat.Message))
+//	})
+//	log.Fatal(p.Run(context.Background()))
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	// apiVersion is the plugin API version the SDK speaks.
+	apiVersion = "v1"
+	// defaultAddress is used when DF_PLUGIN_SERVER_ADDRESS is not set.
+	defaultAddress = "unix:///tmp/dragonfly_plugin.sock"
+
+	// eventBuffer is how many received events may wait for handlers before the receive loop blocks.
+	eventBuffer = 256
+	// minBackoff and maxBackoff bound the delay between reconnect attempts.
+	minBackoff = 500 * time.Millisecond
+	maxBackoff = 10 * time.Second
+)
+
+// capabilities are the optional protocol features the SDK implements.
+var capabilities = []string{"compression.snappy", "heartbeat", "session.resume"}
+
+// ErrNotConnected is returned when sending while the plugin has no stream to the host.
+var ErrNotConnected = errors.New("plugin is not connected")
+
+// errShutdown ends the reconnect loop after the host sent HostShutdown.
+var errShutdown = errors.New("host shut the plugin down")
+
+// Stream is a connection to the host. The gRPC event stream implements it, as do the in-process
+// connections of plugins compiled into the server.
+type Stream interface {
+	Send(*pb.PluginToHost) error
+	Recv() (*pb.HostToPlugin, error)
+}
+
+// Plugin is a plugin's connection to the host. Register event handlers and commands before
+// calling Run.
+type Plugin struct {
+	id      string
+	address string
+	// token is the launch token the host passes in DF_PLUGIN_TOKEN.
+	token   string
+	name    string
+	version string
+	log     *slog.Logger
+
+	customItems  []*pb.CustomItemDefinition
+	customBlocks []*pb.CustomBlockDefinition
+	observer     bool
+
+	handlers map[pb.EventType][]func(*Event)
+	commands map[string]*command
+	specs    []*pb.CommandSpec
+
+	conn *grpc.ClientConn
+	dial func(ctx context.Context) (Stream, error)
+	// dialed is set when the dialer was given with WithDialer.
+	dialed bool
+
+	sendMu sync.Mutex
+	stream Stream
+
+	events chan *pb.EventEnvelope
+
+	pendingMu sync.Mutex
+	pending   map[string]chan *pb.ActionResult
+	nextID    atomic.Uint64
+
+	hello        atomic.Pointer[pb.HostHello]
+	lastSequence atomic.Uint64
+	refused      atomic.Pointer[string]
+}
+
+// Option configures a Plugin.
+type Option func(*Plugin)
+
+// WithID sets the plugin ID instead of reading DF_PLUGIN_ID.
+func WithID(id string) Option {
+	return func(p *Plugin) { p.id = id }
+}
+
+// WithAddress sets the host address instead of reading DF_PLUGIN_SERVER_ADDRESS. Unix sockets are
+// given as "unix:/path" or a bare absolute path.
+func WithAddress(address string) Option {
+	return func(p *Plugin) { p.address = address }
+}
+
+// WithName sets the name announced to the host. It defaults to the plugin ID.
+func WithName(name string) Option {
+	return func(p *Plugin) { p.name = name }
+}
+
+// WithVersion sets the version announced to the host.
+func WithVersion(version string) Option {
+	return func(p *Plugin) { p.version = version }
+}
+
+// WithLogger sets the logger the SDK reports connection problems and handler panics to.
+func WithLogger(log *slog.Logger) Option {
+	return func(p *Plugin) { p.log = log }
+}
+
+// WithCustomItems registers custom items in the plugin's hello.
+func WithCustomItems(items ...*pb.CustomItemDefinition) Option {
+	return func(p *Plugin) { p.customItems = append(p.customItems, items...) }
+}
+
+// WithCustomBlocks registers custom blocks in the plugin's hello.
+func WithCustomBlocks(blocks ...*pb.CustomBlockDefinition) Option {
+	return func(p *Plugin) { p.customBlocks = append(p.customBlocks, blocks...) }
+}
+
+// WithDialer makes the plugin open its connections to the host with dial instead of gRPC. It is
+// used to run plugins compiled into the server in-process; the host's address is then only used
+// by Query. Such streams need not marshal messages, so the plugin does not ask for compression.
+// The stream must end once the context passed to dial is cancelled.
+func WithDialer(dial func(ctx context.Context) (Stream, error)) Option {
+	return func(p *Plugin) { p.dial, p.dialed = dial, true }
+}
+
+// WithObserver makes the plugin a read-only observer. The host sends it events without waiting
+// for a result, so handlers see every event but cannot cancel or mutate them. Hosts without the
+// observer capability refuse the plugin.
+func WithObserver() Option {
+	return func(p *Plugin) { p.observer = true }
+}
+
+// New creates a plugin. The ID and host address are read from DF_PLUGIN_ID and
+// DF_PLUGIN_SERVER_ADDRESS, which the host sets for plugins it launches, unless given as options.
+// The launch token in DF_PLUGIN_TOKEN is sent in the hello.
+func New(opts ...Option) (*Plugin, error) {
+	p := &Plugin{
+		id:       os.Getenv("DF_PLUGIN_ID"),
+		address:  os.Getenv("DF_PLUGIN_SERVER_ADDRESS"),
+		token:    os.Getenv("DF_PLUGIN_TOKEN"),
+		version:  "0.0.0",
+		log:      slog.Default(),
+		handlers: make(map[pb.EventType][]func(*Event)),
+		commands: make(map[string]*command),
+		events:   make(chan *pb.EventEnvelope, eventBuffer),
+		pending:  make(map[string]chan *pb.ActionResult),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.id == "" {
+		return nil, errors.New("plugin ID not set: DF_PLUGIN_ID is empty")
+	}
+	if p.address == "" {
+		p.address = defaultAddress
+	}
+	if strings.HasPrefix(p.address, "/") {
+		p.address = "unix:" + p.address
+	}
+	if p.name == "" {
+		p.name = p.id
+	}
+	p.log = p.log.With("plugin", p.id)
+
+	conn, err := grpc.NewClient(p.address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(p.identify),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", p.address, err)
+	}
+	p.conn = conn
+	if p.dial == nil {
+		p.dial = func(ctx context.Context) (Stream, error) {
+			return pb.NewPluginClient(p.conn).EventStream(ctx)
+		}
+	}
+	return p, nil
+}
+
+// ID returns the plugin ID.
+func (p *Plugin) ID() string {
+	return p.id
+}
+
+// HostHello returns the host's answer to the last hello, or nil before the first connection.
+func (p *Plugin) HostHello() *pb.HostHello {
+	return p.hello.Load()
+}
+
+// Run connects to the host and handles events until ctx is cancelled or the host shuts the
+// plugin down. Lost connections are re-established, resuming the session so that durable events
+// missed in between are replayed.
+func (p *Plugin) Run(ctx context.Context) error {
+	defer p.conn.Close()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go p.dispatchEvents(ctx)
+
+	backoff := minBackoff
+	for {
+		connected, err := p.session(ctx)
+		if errors.Is(err, errShutdown) {
+			if reason := p.refused.Load(); reason != nil {
+				return fmt.Errorf("host refused plugin: %s", *reason)
+			}
+			return nil
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			backoff = minBackoff
+		}
+		p.log.Warn("connection to host lost, reconnecting", "error", err, "delay", backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// session opens one event stream and receives from it until it fails. connected reports whether
+// the host answered the hello.
+func (p *Plugin) session(ctx context.Context) (connected bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := p.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	p.sendMu.Lock()
+	p.stream = stream
+	p.sendMu.Unlock()
+	defer func() {
+		p.sendMu.Lock()
+		p.stream = nil
+		p.sendMu.Unlock()
+	}()
+
+	if err := p.send(&pb.PluginToHost{Payload: &pb.PluginToHost_Hello{Hello: p.pluginHello()}}); err != nil {
+		return false, err
+	}
+	if events := p.subscriptions(); len(events) > 0 {
+		if err := p.send(&pb.PluginToHost{Payload: &pb.PluginToHost_Subscribe{Subscribe: &pb.EventSubscribe{Events: events}}}); err != nil {
+			return false, err
+		}
+	}
+
+	refused := false
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return connected, err
+		}
+		switch payload := msg.Payload.(type) {
+		case *pb.HostToPlugin_Hello:
+			connected = true
+			// A HostHello without api_version means negotiation failed; the reason follows in
+			// HostShutdown.
+			refused = payload.Hello.GetApiVersion() == ""
+			if !refused {
+				p.hello.Store(payload.Hello)
+			}
+		case *pb.HostToPlugin_Shutdown:
+			if refused {
+				reason := payload.Shutdown.GetReason()
+				p.refused.Store(&reason)
+			}
+			p.log.Info("host shut the plugin down", "reason", payload.Shutdown.GetReason())
+			return connected, errShutdown
+		case *pb.HostToPlugin_Ping:
+			_ = p.send(&pb.PluginToHost{Payload: &pb.PluginToHost_Pong{Pong: &pb.PluginPong{Nonce: payload.Ping.GetNonce()}}})
+		case *pb.HostToPlugin_Event:
+			p.queueEvent(ctx, payload.Event)
+		case *pb.HostToPlugin_Events:
+			for _, ev := range payload.Events.GetEvents() {
+				p.queueEvent(ctx, ev)
+			}
+		case *pb.HostToPlugin_CompressedEvents:
+			batch, err := decompress(payload.CompressedEvents)
+			if err != nil {
+				p.log.Error("decode compressed events", "error", err)
+				continue
+			}
+			for _, ev := range batch.GetEvents() {
+				p.queueEvent(ctx, ev)
+			}
+		case *pb.HostToPlugin_EventGap:
+			p.log.Warn("durable events were lost while disconnected",
+				"from", payload.EventGap.GetFromSequence(), "to", payload.EventGap.GetToSequence())
+		case *pb.HostToPlugin_ActionResult:
+			p.resolve(payload.ActionResult)
+		}
+	}
+}
+
+func (p *Plugin) pluginHello() *pb.PluginHello {
+	hello := &pb.PluginHello{
+		Name:         p.name,
+		Version:      p.version,
+		ApiVersion:   apiVersion,
+		ApiVersions:  []string{apiVersion},
+		Capabilities: p.capabilities(),
+		Commands:     p.specs,
+		CustomItems:  p.customItems,
+		CustomBlocks: p.customBlocks,
+		LaunchToken:  p.token,
+	}
+	if p.observer {
+		hello.RequiredCapabilities = []string{"observer"}
+	}
+	if last := p.hello.Load(); last != nil && last.GetBootId() != "" {
+		hello.Resume = &pb.SessionResume{BootId: last.GetBootId(), LastSequence: p.lastSequence.Load()}
+	}
+	return hello
+}
+
+// capabilities returns the capabilities the plugin asks for.
+func (p *Plugin) capabilities() []string {
+	if p.dialed {
+		return slices.DeleteFunc(slices.Clone(capabilities), func(c string) bool { return c == "compression.snappy" })
+	}
+	return capabilities
+}
+
+func (p *Plugin) queueEvent(ctx context.Context, ev *pb.EventEnvelope) {
+	select {
+	case p.events <- ev:
+	case <-ctx.Done():
+	}
+}
+
+// send writes msg to the current stream.
+func (p *Plugin) send(msg *pb.PluginToHost) error {
+	msg.PluginId = p.id
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	if p.stream == nil {
+		return ErrNotConnected
+	}
+	return p.stream.Send(msg)
+}
+
+// Log sends a message to the host's log for this plugin. level is one of "debug", "info", "warn"
+// or "error".
+func (p *Plugin) Log(level, message string) error {
+	return p.send(&pb.PluginToHost{Payload: &pb.PluginToHost_Log{Log: &pb.LogMessage{Level: level, Message: message}}})
+}
+
+func decompress(c *pb.CompressedEventBatch) (*pb.EventBatch, error) {
+	data, err := snappy.Decode(nil, c.GetData())
+	if err != nil {
+		return nil, err
+	}
+	batch := &pb.EventBatch{}
+	if err := proto.Unmarshal(data, batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
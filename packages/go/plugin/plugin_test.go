@@ -0,0 +1,153 @@
+package plugin_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/packages/go/plugin"
+	"github.com/secmc/plugin/plugin/adapters/grpc"
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// host is the host side of a plugin's event stream.
+type host struct {
+	t      *testing.T
+	stream ports.Stream
+}
+
+func (h host) send(msg *pb.HostToPlugin) {
+	h.t.Helper()
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	if err := h.stream.Send(data); err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+func (h host) recv() *pb.PluginToHost {
+	h.t.Helper()
+	data, err := h.stream.Recv()
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	msg := &pb.PluginToHost{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		h.t.Fatal(err)
+	}
+	return msg
+}
+
+func startHost(t *testing.T) (string, <-chan host) {
+	t.Helper()
+	streams := make(chan host, 1)
+	done := make(chan struct{})
+	server, err := grpc.NewServer("127.0.0.1:0", unixsocket.Permissions{}, func(stream ports.Stream) error {
+		streams <- host{t: t, stream: stream}
+		<-done
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve()
+	t.Cleanup(func() {
+		close(done)
+		server.Stop()
+	})
+	return server.Address(), streams
+}
+
+type giveArgs struct {
+	Amount int `cmd:"amount"`
+	Reason []string
+}
+
+func TestPlugin(t *testing.T) {
+	address, streams := startHost(t)
+	p, err := plugin.New(plugin.WithID("example"), plugin.WithAddress(address))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin.On(p, func(e *plugin.Event, chat *pb.ChatEvent) {
+		e.Mutate(plugin.ChatMessage(strings.ToUpper(chat.Message)))
+	})
+	given := make(chan giveArgs, 1)
+	plugin.Command(p, "give", "Give items", func(_ *plugin.CommandContext, args giveArgs) error {
+		given <- args
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- p.Run(ctx) }()
+
+	h := <-streams
+	hello := h.recv().GetHello()
+	if hello == nil || len(hello.Commands) != 1 || len(hello.Commands[0].Params) != 2 {
+		t.Fatalf("unexpected hello %v", hello)
+	}
+	if params := hello.Commands[0].Params; params[0].Type != pb.ParamType_PARAM_INT || params[1].Type != pb.ParamType_PARAM_VARARGS {
+		t.Fatalf("unexpected params %v", params)
+	}
+	h.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Hello{Hello: &pb.HostHello{ApiVersion: "v1", BootId: "boot"}}})
+	if sub := h.recv().GetSubscribe(); len(sub.GetEvents()) != 2 {
+		t.Fatalf("unexpected subscription %v", sub)
+	}
+
+	h.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Event{Event: &pb.EventEnvelope{
+		EventId: "1", Type: pb.EventType_CHAT, ExpectsResponse: true,
+		Payload: &pb.EventEnvelope_Chat{Chat: &pb.ChatEvent{Message: "hi"}},
+	}}})
+	if res := h.recv().GetEventResult(); res.GetEventId() != "1" || res.GetChat().GetMessage() != "HI" {
+		t.Fatalf("unexpected event result %v", res)
+	}
+
+	h.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Event{Event: &pb.EventEnvelope{
+		EventId: "2", Type: pb.EventType_COMMAND,
+		Payload: &pb.EventEnvelope_Command{Command: &pb.CommandEvent{Command: "give", Args: []string{"3", "for", "fun"}}},
+	}}})
+	if args := <-given; args.Amount != 3 || len(args.Reason) != 2 {
+		t.Fatalf("unexpected command args %+v", args)
+	}
+	h.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Event{Event: &pb.EventEnvelope{
+		EventId: "3", Type: pb.EventType_COMMAND,
+		Payload: &pb.EventEnvelope_Command{Command: &pb.CommandEvent{PlayerUuid: "player", Command: "give", Args: []string{"many"}}},
+	}}})
+	reply := h.recv().GetActions().GetActions()[0].GetSendChat()
+	if reply.GetTargetUuid() != "player" || !strings.Contains(reply.GetMessage(), "invalid argument <amount>") {
+		t.Fatalf("unexpected reply %v", reply)
+	}
+
+	results := make(chan []*pb.ActionResult, 1)
+	go func() {
+		res, err := p.Actions().SendChat("", "tick").SetTime(&pb.WorldRef{Name: "world"}, 100).Await(ctx)
+		if err != nil {
+			t.Error(err)
+		}
+		results <- res
+	}()
+	batch := h.recv().GetActions().GetActions()
+	if len(batch) != 2 || batch[0].CorrelationId != nil || batch[1].GetCorrelationId() == "" {
+		t.Fatalf("unexpected batch %v", batch)
+	}
+	h.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_ActionResult{ActionResult: &pb.ActionResult{
+		CorrelationId: batch[1].GetCorrelationId(), Status: &pb.ActionStatus{Ok: true},
+	}}})
+	if res := <-results; len(res) != 1 || !res[0].GetStatus().GetOk() {
+		t.Fatalf("unexpected results %v", res)
+	}
+
+	h.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Shutdown{Shutdown: &pb.HostShutdown{Reason: "done"}}})
+	if err := <-stopped; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
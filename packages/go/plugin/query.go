@@ -0,0 +1,35 @@
+package plugin
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	// pluginIDMetadata is the metadata key the host's Query service identifies callers by.
+	pluginIDMetadata = "plugin-id"
+	// pluginTokenMetadata is the metadata key the launch token is sent in.
+	pluginTokenMetadata = "plugin-token"
+)
+
+// Query returns a client for the host's Query service, which answers world, player and server
+// queries with unary calls. Calls are made as this plugin and need an open event stream, so use
+// it from event handlers or after Run has connected.
+//
+//	res, err := p.Query().WorldBlock(ctx, &pb.WorldQueryBlockAction{World: world, Position: pos})
+func (p *Plugin) Query() pb.QueryClient {
+	return pb.NewQueryClient(p.conn)
+}
+
+// identify adds the plugin ID and launch token to outgoing unary calls.
+func (p *Plugin) identify(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, pluginIDMetadata, p.id)
+	if p.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, pluginTokenMetadata, p.token)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,68 @@
+// The Node SDK's fixture for the protocol conformance runner in plugin/conformance. Build the SDK
+// with `npm install && npm run build` first, then:
+//
+//   go run ./cmd/conformance node="node packages/node/conformance.js"
+import { EventType, PluginBase, Player, RegisterCommand } from './dist/index.js';
+
+class ConformancePlugin extends PluginBase {
+    pending = new Map();
+    nextCorrelation = 0;
+
+    onLoad() {
+        this.eventManager.registerHandler(EventType.CHAT, (chat, ctx) => {
+            if (chat.message === 'cancel') {
+                ctx.cancel();
+                return;
+            }
+            this.send({
+                pluginId: this.pluginId,
+                eventResult: { eventId: ctx.event.eventId, chat: { message: chat.message.toUpperCase() } },
+            });
+        });
+        // Joins only need to be handled so that their sequences are resumed from.
+        this.eventManager.registerHandler(EventType.PLAYER_JOIN, (_join, ctx) => ctx.ack());
+    }
+
+    onEnable() {
+        this.getStream()?.on('data', (message) => {
+            const result = message.actionResult;
+            const done = result && this.pending.get(result.correlationId);
+            if (done) {
+                this.pending.delete(result.correlationId);
+                done(result);
+            }
+        });
+    }
+
+    onDisable() {}
+
+    echo(playerUuid, args, ctx) {
+        new Player(this, playerUuid).sendMessage(args.join(' '));
+        ctx.cancel();
+    }
+
+    setTime(playerUuid, args, ctx) {
+        const player = new Player(this, playerUuid);
+        const correlationId = `settime-${this.nextCorrelation++}`;
+        this.pending.set(correlationId, (result) => {
+            player.sendMessage(result.status?.ok === false ? 'error: ' + (result.status.error ?? '') : 'ok');
+        });
+        this.send({
+            pluginId: this.pluginId,
+            actions: {
+                actions: [{ correlationId, worldSetTime: { world: { name: 'world' }, time: Number(args[0] ?? 0) } }],
+            },
+        });
+        ctx.cancel();
+    }
+}
+
+// Decorators are TypeScript syntax, so the commands are registered by applying them directly.
+for (const [name, method, description] of [
+    ['echo', 'echo', 'Repeat the arguments'],
+    ['settime', 'setTime', 'Set the time of the world'],
+]) {
+    RegisterCommand({ name, description })(ConformancePlugin.prototype, method);
+}
+
+new ConformancePlugin().run();
@@ -0,0 +1,84 @@
+<?php
+
+// The PHP SDK's fixture for the protocol conformance runner in plugin/conformance. Install the
+// SDK's dependencies with composer first, then:
+//
+//   go run ./cmd/conformance php="php packages/php/conformance/conformance.php"
+
+namespace Dragonfly\PluginLib\Conformance;
+
+require __DIR__ . '/../vendor/autoload.php';
+
+use Df\Plugin\Action;
+use Df\Plugin\ActionResult;
+use Df\Plugin\ChatEvent;
+use Df\Plugin\CommandEvent;
+use Df\Plugin\PlayerJoinEvent;
+use Df\Plugin\WorldRef;
+use Df\Plugin\WorldSetTimeAction;
+use Dragonfly\PluginLib\Events\EventContext;
+use Dragonfly\PluginLib\Events\Listener;
+use Dragonfly\PluginLib\PluginBase;
+
+final class ConformanceListener implements Listener {
+    public function onChat(ChatEvent $e, EventContext $ctx): void {
+        if ($e->getMessage() === 'cancel') {
+            $ctx->cancel();
+            return;
+        }
+        $ctx->chat(strtoupper($e->getMessage()));
+    }
+
+    // Joins only need to be handled so that their sequences are resumed from.
+    public function onPlayerJoin(PlayerJoinEvent $e, EventContext $ctx): void {}
+
+    public function onCommand(CommandEvent $e, EventContext $ctx): void {
+        $player = $e->getPlayerUuid();
+        $args = iterator_to_array($e->getArgs());
+        switch ($e->getCommand()) {
+            case 'echo':
+                $ctx->chatToUuid($player, implode(' ', $args));
+                break;
+            case 'settime':
+                $this->setTime($ctx, $player, (int) ($args[0] ?? 0));
+                break;
+            default:
+                return;
+        }
+        $ctx->cancel();
+    }
+
+    private function setTime(EventContext $ctx, string $player, int $time): void {
+        $correlationId = uniqid('settime_', true);
+        $ctx->onActionResult($correlationId, function (ActionResult $result) use ($ctx, $player): void {
+            $status = $result->getStatus();
+            if ($status !== null && !$status->getOk()) {
+                $ctx->chatToUuid($player, 'error: ' . $status->getError());
+                return;
+            }
+            $ctx->chatToUuid($player, 'ok');
+        });
+
+        $world = new WorldRef();
+        $world->setName('world');
+        $setTime = new WorldSetTimeAction();
+        $setTime->setWorld($world);
+        $setTime->setTime($time);
+        $action = new Action();
+        $action->setCorrelationId($correlationId);
+        $action->setWorldSetTime($setTime);
+        $ctx->sendActions([$action]);
+    }
+}
+
+final class ConformancePlugin extends PluginBase {
+    protected string $name = 'PHP Conformance';
+
+    public function onLoad(): void {
+        $this->registerCommand('echo', 'Repeat the arguments');
+        $this->registerCommand('settime', 'Set the time of the world');
+        $this->registerListener(new ConformanceListener());
+    }
+}
+
+(new ConformancePlugin())->run();
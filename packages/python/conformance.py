@@ -0,0 +1,179 @@
+"""Fixture for the protocol conformance runner in plugin/conformance.
+
+There is no Python SDK yet, so the fixture speaks the protocol with the generated stubs directly.
+Regenerate them with `make proto` and install grpcio (and python-snappy for compressed batches), then:
+
+    go run ./cmd/conformance python="python3 packages/python/conformance.py"
+"""
+
+import os
+import queue
+import sys
+import time
+
+import grpc
+
+sys.path.insert(0, os.path.join(os.path.dirname(os.path.abspath(__file__)), "src", "generated"))
+
+import actions_pb2  # noqa: E402
+import command_pb2  # noqa: E402
+import common_pb2  # noqa: E402
+import mutations_pb2  # noqa: E402
+import plugin_pb2  # noqa: E402
+import plugin_pb2_grpc  # noqa: E402
+
+try:
+    import snappy
+except ImportError:
+    snappy = None
+
+PLUGIN_ID = os.environ.get("DF_PLUGIN_ID", "python-conformance")
+CAPABILITIES = ["heartbeat", "session.resume"] + (["compression.snappy"] if snappy else [])
+
+
+def address():
+    addr = os.environ.get("DF_PLUGIN_SERVER_ADDRESS", "unix:///tmp/dragonfly_plugin.sock")
+    if addr.startswith("/"):
+        return "unix://" + addr
+    return addr
+
+
+class Session:
+    """One connection to the host. Messages to send are queued and streamed by gRPC."""
+
+    def __init__(self, boot_id, last_sequence):
+        self.outgoing = queue.Queue()
+        self.boot_id = boot_id
+        self.last_sequence = last_sequence
+        self.enabled = []
+        self.pending = {}
+        self.next_correlation = 0
+        self.shutdown = False
+
+    def requests(self):
+        while True:
+            msg = self.outgoing.get()
+            if msg is None:
+                return
+            yield msg
+
+    def send(self, **payload):
+        self.outgoing.put(plugin_pb2.PluginToHost(plugin_id=PLUGIN_ID, **payload))
+
+    def hello(self):
+        hello = plugin_pb2.PluginHello(
+            name="Python Conformance",
+            version="0.1.0",
+            api_version="v1",
+            api_versions=["v1"],
+            capabilities=CAPABILITIES,
+            launch_token=os.environ.get("DF_PLUGIN_TOKEN", ""),
+            commands=[
+                command_pb2.CommandSpec(name="echo", description="Repeat the arguments"),
+                command_pb2.CommandSpec(name="settime", description="Set the time of the world"),
+            ],
+        )
+        if self.boot_id:
+            hello.resume.boot_id = self.boot_id
+            hello.resume.last_sequence = self.last_sequence
+        self.send(hello=hello)
+        self.send(subscribe=plugin_pb2.EventSubscribe(
+            events=[plugin_pb2.CHAT, plugin_pb2.COMMAND, plugin_pb2.PLAYER_JOIN],
+        ))
+
+    def handle(self, msg):
+        kind = msg.WhichOneof("payload")
+        if kind == "hello":
+            self.enabled = list(msg.hello.enabled_capabilities)
+            if "session.resume" in self.enabled:
+                if msg.hello.boot_id != self.boot_id:
+                    self.last_sequence = 0
+                self.boot_id = msg.hello.boot_id
+        elif kind == "shutdown":
+            self.shutdown = True
+            self.outgoing.put(None)
+        elif kind == "ping":
+            self.send(pong=plugin_pb2.PluginPong(nonce=msg.ping.nonce))
+        elif kind == "event":
+            self.event(msg.event)
+        elif kind == "events":
+            for ev in msg.events.events:
+                self.event(ev)
+        elif kind == "compressed_events":
+            batch = plugin_pb2.EventBatch.FromString(snappy.uncompress(msg.compressed_events.data))
+            for ev in batch.events:
+                self.event(ev)
+        elif kind == "action_result":
+            done = self.pending.pop(msg.action_result.correlation_id, None)
+            if done:
+                done(msg.action_result)
+
+    def event(self, ev):
+        result = mutations_pb2.EventResult(event_id=ev.event_id)
+        kind = ev.WhichOneof("payload")
+        if kind == "chat":
+            if ev.chat.message == "cancel":
+                result.cancel = True
+            else:
+                result.chat.message = ev.chat.message.upper()
+        elif kind == "command":
+            result.cancel = self.command(ev.command)
+        if ev.sequence > self.last_sequence:
+            self.last_sequence = ev.sequence
+        if ev.expects_response:
+            self.send(event_result=result)
+
+    def command(self, cmd):
+        player = cmd.player_uuid
+        if cmd.command == "echo":
+            self.chat(player, " ".join(cmd.args))
+        elif cmd.command == "settime":
+            self.set_time(player, int(cmd.args[0]) if cmd.args else 0)
+        else:
+            return False
+        return True
+
+    def set_time(self, player, ticks):
+        correlation_id = "settime-%d" % self.next_correlation
+        self.next_correlation += 1
+
+        def done(result):
+            if result.status.ok:
+                self.chat(player, "ok")
+            else:
+                self.chat(player, "error: " + result.status.error)
+
+        self.pending[correlation_id] = done
+        action = actions_pb2.Action(
+            correlation_id=correlation_id,
+            world_set_time=actions_pb2.WorldSetTimeAction(world=common_pb2.WorldRef(name="world"), time=ticks),
+        )
+        self.send(actions=actions_pb2.ActionBatch(actions=[action]))
+
+    def chat(self, player, message):
+        action = actions_pb2.Action(send_chat=actions_pb2.SendChatAction(target_uuid=player, message=message))
+        self.send(actions=actions_pb2.ActionBatch(actions=[action]))
+
+
+def main():
+    boot_id, last_sequence = "", 0
+    with grpc.insecure_channel(address()) as channel:
+        stub = plugin_pb2_grpc.PluginStub(channel)
+        while True:
+            session = Session(boot_id, last_sequence)
+            session.hello()
+            try:
+                for msg in stub.EventStream(session.requests()):
+                    session.handle(msg)
+            except grpc.RpcError as err:
+                print("[python] stream lost: %s" % err.code(), file=sys.stderr)
+            session.outgoing.put(None)
+            if session.shutdown:
+                return
+            if "session.resume" in session.enabled:
+                boot_id, last_sequence = session.boot_id, session.last_sequence
+            time.sleep(0.5)
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,90 @@
+//! The Rust SDK's fixture for the protocol conformance runner in `plugin/conformance`:
+//!
+//! ```sh
+//! cargo build -p dragonfly-plugin-conformance --manifest-path packages/rust/Cargo.toml
+//! go run ./cmd/conformance rust=packages/rust/target/debug/dragonfly-plugin-conformance
+//! ```
+//!
+//! The SDK does not reconnect or surface `ActionResult`s yet, so the reconnect and action
+//! correlation scenarios are expected to fail.
+
+use std::sync::atomic::{AtomicU64, Ordering};
+
+use dragonfly_plugin::{
+    event::{EventContext, EventHandler},
+    event_handler, types, Plugin, PluginRunner, Server,
+};
+
+static NEXT_CORRELATION: AtomicU64 = AtomicU64::new(0);
+
+#[derive(Plugin, Default)]
+#[plugin(
+    id = "conformance",
+    name = "Rust Conformance",
+    version = "0.1.0",
+    api = "v1"
+)]
+struct Conformance;
+
+#[event_handler]
+impl EventHandler for Conformance {
+    async fn on_chat(&self, _server: &Server, event: &mut EventContext<'_, types::ChatEvent>) {
+        if event.data.message == "cancel" {
+            event.cancel().await;
+            return;
+        }
+        let message = event.data.message.to_uppercase();
+        event.set_message(message);
+    }
+
+    // Joins only need to be handled so that their sequences are resumed from.
+    async fn on_player_join(
+        &self,
+        _server: &Server,
+        _event: &mut EventContext<'_, types::PlayerJoinEvent>,
+    ) {
+    }
+
+    // Commands are read off the raw event because echo takes any number of arguments.
+    async fn on_command(&self, server: &Server, event: &mut EventContext<'_, types::CommandEvent>) {
+        let player = event.data.player_uuid.clone();
+        let result = match event.data.command.as_str() {
+            "echo" => server.send_chat(player, event.data.args.join(" ")).await,
+            "settime" => {
+                let time = event
+                    .data
+                    .args
+                    .first()
+                    .and_then(|t| t.parse().ok())
+                    .unwrap_or(0);
+                let action = types::Action {
+                    correlation_id: Some(format!(
+                        "settime-{}",
+                        NEXT_CORRELATION.fetch_add(1, Ordering::Relaxed)
+                    )),
+                    kind: Some(types::ActionKind::WorldSetTime(types::WorldSetTimeAction {
+                        world: Some(types::WorldRef {
+                            name: "world".to_owned(),
+                            ..Default::default()
+                        }),
+                        time,
+                    })),
+                };
+                server.send_actions(vec![action]).await
+            }
+            _ => return,
+        };
+        if let Err(e) = result {
+            eprintln!("Failed to answer /{}: {}", event.data.command, e);
+        }
+    }
+}
+
+#[tokio::main]
+async fn main() -> Result<(), Box<dyn std::error::Error>> {
+    let mut addr = std::env::var("DF_PLUGIN_SERVER_ADDRESS")?;
+    if !addr.contains("://") && !addr.starts_with("unix:") && !addr.starts_with('/') {
+        addr = format!("http://{addr}");
+    }
+    PluginRunner::run(Conformance, &addr).await
+}
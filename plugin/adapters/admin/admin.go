@@ -0,0 +1,229 @@
+// Package admin serves an HTTP JSON API that lets operators inspect and manage plugins at runtime.
+//
+// Every request must carry the configured token as "Authorization: Bearer <token>".
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const shutdownGrace = 5 * time.Second
+
+// Server serves the admin API
+type Server struct {
+	http     *http.Server
+	listener net.Listener
+	token    []byte
+	ctrl     ports.PluginController
+
+	// cancel ends long-running requests such as event tails when the server stops.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewServer creates an admin API server for ctrl. token must not be empty.
+func NewServer(address, token string, ctrl ports.PluginController) (*Server, error) {
+	if token == "" {
+		return nil, errors.New("admin token is required")
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("listen failed: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		listener: listener,
+		token:    []byte(token),
+		ctrl:     ctrl,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/plugins", s.listPlugins)
+	mux.HandleFunc("GET /v1/plugins/{id}", s.getPlugin)
+	mux.HandleFunc("POST /v1/plugins/{id}/start", s.lifecycle(ctrl.StartPlugin))
+	mux.HandleFunc("POST /v1/plugins/{id}/stop", s.lifecycle(ctrl.StopPlugin))
+	mux.HandleFunc("POST /v1/plugins/{id}/restart", s.lifecycle(ctrl.RestartPlugin))
+	mux.HandleFunc("POST /v1/plugins/{id}/reload", s.lifecycle(ctrl.ReloadPlugin))
+	mux.HandleFunc("PUT /v1/plugins/{id}/log-level", s.setLogLevel)
+	mux.HandleFunc("GET /v1/plugins/{id}/events", s.tailEvents)
+	mux.HandleFunc("POST /v1/reload", s.reloadConfig)
+	s.http = &http.Server{
+		Handler:           s.authenticate(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	return s, nil
+}
+
+// Serve starts accepting admin requests
+func (s *Server) Serve() error {
+	if err := s.http.Serve(s.listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop ends open event tails and gracefully stops the server
+func (s *Server) Stop() {
+	s.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+	_ = s.http.Shutdown(ctx)
+}
+
+// Address returns the address the server is listening on
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), s.token) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="dragonfly-plugins"`)
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) listPlugins(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"plugins": s.ctrl.PluginStatuses()})
+}
+
+func (s *Server) getPlugin(w http.ResponseWriter, r *http.Request) {
+	status, err := s.ctrl.PluginStatus(r.PathValue("id"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// lifecycle runs op for the plugin in the path and responds with its new status.
+func (s *Server) lifecycle(op func(id string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if err := op(id); err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		s.getPlugin(w, r)
+	}
+}
+
+func (s *Server) reloadConfig(w http.ResponseWriter, r *http.Request) {
+	if err := s.ctrl.ReloadConfig(); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	s.listPlugins(w, r)
+}
+
+// setLogLevel accepts {"level": "debug"}. An empty level or "default" restores the host's level.
+func (s *Server) setLogLevel(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Level string `json:"level"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decode body: %w", err))
+		return
+	}
+	var level *slog.Level
+	if body.Level != "" && !strings.EqualFold(body.Level, "default") {
+		level = new(slog.Level)
+		if err := level.UnmarshalText([]byte(body.Level)); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if err := s.ctrl.SetPluginLogLevel(r.PathValue("id"), level); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	s.getPlugin(w, r)
+}
+
+// tailEvents streams events sent to the plugin as newline-delimited protojson. Repeated "type"
+// query parameters (e.g. ?type=CHAT&type=PLAYER_JOIN) limit the stream to those event types.
+func (s *Server) tailEvents(w http.ResponseWriter, r *http.Request) {
+	types := make(map[pb.EventType]bool)
+	for _, name := range r.URL.Query()["type"] {
+		t, ok := pb.EventType_value[strings.ToUpper(name)]
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown event type %q", name))
+			return
+		}
+		types[pb.EventType(t)] = true
+	}
+	events, cancel, err := s.ctrl.TailEvents(r.PathValue("id"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if len(types) > 0 && !types[event.Type] {
+				continue
+			}
+			data, err := protojson.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := w.Write(append(data, '\n')); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func errorStatus(err error) int {
+	if errors.Is(err, ports.ErrUnknownPlugin) {
+		return http.StatusNotFound
+	}
+	return http.StatusConflict
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,127 @@
+package admin_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/secmc/plugin/plugin/adapters/admin"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+type fakeController struct {
+	ports.PluginController
+	stopped []string
+	level   *slog.Level
+	events  chan *pb.EventEnvelope
+}
+
+func (c *fakeController) PluginStatuses() []ports.PluginStatus {
+	return []ports.PluginStatus{{ID: "example", State: "ready"}}
+}
+
+func (c *fakeController) PluginStatus(id string) (ports.PluginStatus, error) {
+	if id != "example" {
+		return ports.PluginStatus{}, fmt.Errorf("%w: %s", ports.ErrUnknownPlugin, id)
+	}
+	return ports.PluginStatus{ID: id, State: "stopped"}, nil
+}
+
+func (c *fakeController) StopPlugin(id string) error {
+	c.stopped = append(c.stopped, id)
+	return nil
+}
+
+func (c *fakeController) SetPluginLogLevel(_ string, level *slog.Level) error {
+	c.level = level
+	return nil
+}
+
+func (c *fakeController) TailEvents(string) (<-chan *pb.EventEnvelope, func(), error) {
+	return c.events, func() {}, nil
+}
+
+func startServer(t *testing.T, ctrl ports.PluginController) string {
+	t.Helper()
+	server, err := admin.NewServer("127.0.0.1:0", "secret", ctrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve()
+	t.Cleanup(server.Stop)
+	return "http://" + server.Address()
+}
+
+func do(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestServer(t *testing.T) {
+	ctrl := &fakeController{events: make(chan *pb.EventEnvelope, 1)}
+	base := startServer(t, ctrl)
+
+	for _, token := range []string{"", "wrong"} {
+		if resp := do(t, http.MethodGet, base+"/v1/plugins", token, ""); resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("token %q: got status %d, want 401", token, resp.StatusCode)
+		}
+	}
+
+	resp := do(t, http.MethodGet, base+"/v1/plugins", "secret", "")
+	var list struct {
+		Plugins []ports.PluginStatus `json:"plugins"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Plugins) != 1 || list.Plugins[0].ID != "example" {
+		t.Fatalf("unexpected plugin list %+v", list.Plugins)
+	}
+
+	if resp := do(t, http.MethodGet, base+"/v1/plugins/missing", "secret", ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("got status %d for unknown plugin, want 404", resp.StatusCode)
+	}
+	if resp := do(t, http.MethodPost, base+"/v1/plugins/example/stop", "secret", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("stop: got status %d", resp.StatusCode)
+	}
+	if len(ctrl.stopped) != 1 || ctrl.stopped[0] != "example" {
+		t.Fatalf("stopped %v", ctrl.stopped)
+	}
+
+	if resp := do(t, http.MethodPut, base+"/v1/plugins/example/log-level", "secret", `{"level":"debug"}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("log level: got status %d", resp.StatusCode)
+	}
+	if ctrl.level == nil || *ctrl.level != slog.LevelDebug {
+		t.Fatalf("log level not set to debug: %v", ctrl.level)
+	}
+	if resp := do(t, http.MethodPut, base+"/v1/plugins/example/log-level", "secret", `{"level":"default"}`); resp.StatusCode != http.StatusOK || ctrl.level != nil {
+		t.Fatalf("log level not reset: status %d, level %v", resp.StatusCode, ctrl.level)
+	}
+
+	ctrl.events <- &pb.EventEnvelope{EventId: "1", Type: pb.EventType_CHAT}
+	resp = do(t, http.MethodGet, base+"/v1/plugins/example/events?type=chat", "secret", "")
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(line, `"eventId":"1"`) {
+		t.Fatalf("unexpected tailed event %q", line)
+	}
+}
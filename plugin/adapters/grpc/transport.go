@@ -1,6 +1,7 @@
 package grpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -9,15 +10,44 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	// keepaliveTime is how long a connection may be idle before the server pings the client.
+	keepaliveTime = 15 * time.Second
+	// keepaliveTimeout is how long the server waits for a keepalive ack before closing the connection.
+	keepaliveTimeout = 5 * time.Second
+	// keepaliveMinClientInterval is the most frequent client keepalive ping the server tolerates.
+	keepaliveMinClientInterval = 5 * time.Second
+
+	// PluginIDMetadata is the metadata key Query calls carry the caller's plugin ID in.
+	PluginIDMetadata = "plugin-id"
+	// PluginTokenMetadata is the metadata key Query calls carry the plugin's launch token in.
+	PluginTokenMetadata = "plugin-token"
 )
 
 // GrpcStream wraps a bidirectional stream for a connected plugin
 type GrpcStream struct {
 	stream grpc.ServerStream
 	mu     sync.Mutex
+
+	cred    ports.PeerCredentials
+	hasCred bool
 }
 
 // GrpcServer manages the gRPC server that plugins connect to
@@ -29,8 +59,20 @@ type GrpcServer struct {
 }
 
 // StreamHandler is called when a new plugin connects
-type StreamHandler func(stream *GrpcStream) error
+type StreamHandler func(stream ports.Stream) error
+
+// QueryService serves the typed Query service next to the event stream.
+type QueryService interface {
+	pb.QueryServer
+	// AuthorizeQuery returns an error if a call must not be served for the plugin. cred is nil
+	// unless the call arrived over a Unix socket; token is the launch token the call carried.
+	AuthorizeQuery(pluginID string, cred *ports.PeerCredentials, token string) error
+}
 
+var _ ports.PeerStream = (*GrpcStream)(nil)
+
+// rawProtoCodec passes event stream messages through as bytes, so the plugin manager decodes them
+// itself, and marshals typed messages such as Query requests as usual.
 type rawProtoCodec struct{}
 
 func (rawProtoCodec) Name() string { return "proto" }
@@ -41,6 +83,8 @@ func (rawProtoCodec) Marshal(v any) ([]byte, error) {
 		return t, nil
 	case *[]byte:
 		return *t, nil
+	case proto.Message:
+		return proto.Marshal(t)
 	default:
 		return nil, fmt.Errorf("rawProtoCodec: unsupported marshal type %T", v)
 	}
@@ -51,11 +95,52 @@ func (rawProtoCodec) Unmarshal(data []byte, v any) error {
 	case *[]byte:
 		*t = append((*t)[:0], data...)
 		return nil
+	case proto.Message:
+		return proto.Unmarshal(data, t)
 	default:
-		return fmt.Errorf("rawProtoCodec: unsupported unmarshal target %T (need *[]byte)", v)
+		return fmt.Errorf("rawProtoCodec: unsupported unmarshal target %T (need *[]byte or proto.Message)", v)
 	}
 }
 
+// peerCredentials wraps insecure transport credentials and records SO_PEERCRED for connections
+// accepted on a Unix socket, so streams can report which process they belong to.
+type peerCredentials struct {
+	credentials.TransportCredentials
+}
+
+// peerAuthInfo carries the credentials of the process on the other end of a Unix socket.
+type peerAuthInfo struct {
+	credentials.AuthInfo
+	cred ports.PeerCredentials
+}
+
+func (c peerCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, info, err := c.TransportCredentials.ServerHandshake(conn)
+	if err != nil {
+		return conn, info, err
+	}
+	if cred, ok := unixsocket.Credentials(conn); ok {
+		info = peerAuthInfo{AuthInfo: info, cred: cred}
+	}
+	return conn, info, nil
+}
+
+func (c peerCredentials) Clone() credentials.TransportCredentials {
+	return peerCredentials{TransportCredentials: c.TransportCredentials.Clone()}
+}
+
+func peerFromContext(ctx context.Context) (ports.PeerCredentials, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ports.PeerCredentials{}, false
+	}
+	info, ok := p.AuthInfo.(peerAuthInfo)
+	if !ok {
+		return ports.PeerCredentials{}, false
+	}
+	return info.cred, true
+}
+
 // pluginService implements the gRPC service
 type pluginService struct {
 	handler StreamHandler
@@ -66,11 +151,58 @@ func (s *pluginService) EventStream(stream grpc.ServerStream) error {
 	if s.handler == nil {
 		return errors.New("no handler registered")
 	}
-	return s.handler(&GrpcStream{stream: stream})
+	cred, ok := peerFromContext(stream.Context())
+	return s.handler(&GrpcStream{stream: stream, cred: cred, hasCred: ok})
 }
 
-// NewServer creates a new gRPC server that plugins will connect to
-func NewServer(address string, handler StreamHandler) (*GrpcServer, error) {
+// authorizeQuery returns an interceptor that lets Query calls through only for plugins query
+// accepts, and turns query errors into gRPC status errors.
+func authorizeQuery(query QueryService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ids := metadata.ValueFromIncomingContext(ctx, PluginIDMetadata)
+		if len(ids) == 0 || ids[0] == "" {
+			return nil, status.Errorf(codes.Unauthenticated, "missing %s metadata", PluginIDMetadata)
+		}
+		var cred *ports.PeerCredentials
+		if c, ok := peerFromContext(ctx); ok {
+			cred = &c
+		}
+		var token string
+		if tokens := metadata.ValueFromIncomingContext(ctx, PluginTokenMetadata); len(tokens) > 0 {
+			token = tokens[0]
+		}
+		if err := query.AuthorizeQuery(ids[0], cred, token); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		resp, err := handler(ctx, req)
+		return resp, queryStatus(err)
+	}
+}
+
+// queryStatus maps a query error to a status: unknown worlds and players are NotFound, state the
+// host does not have yet is Unavailable and anything else is a problem with the request.
+func queryStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	if errors.Is(err, ports.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, ports.ErrUnavailable) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// NewServer creates a new gRPC server that plugins will connect to. perms is applied to the socket
+// file when address is a Unix socket. If query is non-nil, the Query service is served as well.
+func NewServer(address string, perms unixsocket.Permissions, handler StreamHandler, query QueryService) (*GrpcServer, error) {
 	// Auto-detect Unix socket vs TCP based on address format
 	network := "tcp"
 	if strings.HasPrefix(address, "/") || strings.HasPrefix(address, "unix://") {
@@ -84,15 +216,31 @@ func NewServer(address string, handler StreamHandler) (*GrpcServer, error) {
 		return nil, fmt.Errorf("listen failed: %w", err)
 	}
 
-	// Set permissions on unix for sockets
+	// Set ownership and permissions on unix for sockets
 	if network == "unix" && runtime.GOOS != "windows" {
-		os.Chmod(address, 0666)
+		if err := perms.Apply(address); err != nil {
+			_ = listener.Close()
+			return nil, err
+		}
 	}
 
-	server := grpc.NewServer(
+	opts := []grpc.ServerOption{
 		grpc.ForceServerCodec(rawProtoCodec{}),
-		grpc.Creds(insecure.NewCredentials()),
-	)
+		grpc.Creds(peerCredentials{TransportCredentials: insecure.NewCredentials()}),
+		// Detect half-open connections so dead plugins release their stream.
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    keepaliveTime,
+			Timeout: keepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             keepaliveMinClientInterval,
+			PermitWithoutStream: true,
+		}),
+	}
+	if query != nil {
+		opts = append(opts, grpc.UnaryInterceptor(authorizeQuery(query)))
+	}
+	server := grpc.NewServer(opts...)
 
 	service := &pluginService{handler: handler}
 
@@ -111,6 +259,9 @@ func NewServer(address string, handler StreamHandler) (*GrpcServer, error) {
 		HandlerType: (*any)(nil),
 		Streams:     []grpc.StreamDesc{streamDesc},
 	}, service)
+	if query != nil {
+		pb.RegisterQueryServer(server, query)
+	}
 
 	return &GrpcServer{
 		server:   server,
@@ -163,6 +314,16 @@ func (s *GrpcStream) Recv() ([]byte, error) {
 	return data, nil
 }
 
+// PeerCredentials returns the credentials of the plugin process when it connected over a Unix socket.
+func (s *GrpcStream) PeerCredentials() (ports.PeerCredentials, bool) {
+	return s.cred, s.hasCred
+}
+
+// CloseSend is a no-op: the server side of the stream is closed when the handler returns.
+func (s *GrpcStream) CloseSend() error {
+	return nil
+}
+
 func (s *GrpcStream) Close() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -0,0 +1,85 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/secmc/plugin/plugin/adapters/grpc"
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+type fakeQuery struct {
+	pb.UnimplementedQueryServer
+}
+
+func (fakeQuery) AuthorizeQuery(pluginID string, _ *ports.PeerCredentials, token string) error {
+	if pluginID != "example" {
+		return errors.New("plugin is not connected")
+	}
+	if token != "secret" {
+		return errors.New("wrong launch token")
+	}
+	return nil
+}
+
+func (fakeQuery) WorldBlock(_ context.Context, act *pb.WorldQueryBlockAction) (*pb.WorldBlockResult, error) {
+	switch act.GetWorld().GetName() {
+	case "overworld":
+	case "starting":
+		return nil, fmt.Errorf("server %w", ports.ErrUnavailable)
+	default:
+		return nil, fmt.Errorf("world %w", ports.ErrNotFound)
+	}
+	return &pb.WorldBlockResult{Position: act.Position, Block: &pb.BlockState{Name: "minecraft:stone"}}, nil
+}
+
+func TestQueryService(t *testing.T) {
+	server, err := grpc.NewServer("127.0.0.1:0", unixsocket.Permissions{}, nil, fakeQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpclib.NewClient(server.Address(), grpclib.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := pb.NewQueryClient(conn)
+
+	req := &pb.WorldQueryBlockAction{World: &pb.WorldRef{Name: "overworld"}, Position: &pb.BlockPos{Y: 64}}
+	asPlugin := func(id string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), grpc.PluginIDMetadata, id, grpc.PluginTokenMetadata, "secret")
+	}
+	for _, tc := range []struct {
+		ctx  context.Context
+		req  *pb.WorldQueryBlockAction
+		want codes.Code
+	}{
+		{context.Background(), req, codes.Unauthenticated},
+		{asPlugin("intruder"), req, codes.PermissionDenied},
+		{metadata.AppendToOutgoingContext(context.Background(), grpc.PluginIDMetadata, "example"), req, codes.PermissionDenied},
+		{asPlugin("example"), &pb.WorldQueryBlockAction{World: &pb.WorldRef{Name: "nether"}}, codes.NotFound},
+		{asPlugin("example"), &pb.WorldQueryBlockAction{World: &pb.WorldRef{Name: "starting"}}, codes.Unavailable},
+		{asPlugin("example"), req, codes.OK},
+	} {
+		res, err := client.WorldBlock(tc.ctx, tc.req)
+		if got := status.Code(err); got != tc.want {
+			t.Fatalf("got code %v (%v), want %v", got, err, tc.want)
+		}
+		if err == nil && res.GetBlock().GetName() != "minecraft:stone" {
+			t.Fatalf("unexpected result %v", res)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package inprocess connects plugins compiled into the server binary to the host over Go channels.
+// Messages are handed over as values, so neither side marshals them; the protocol is otherwise the
+// same as over gRPC.
+package inprocess
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// pipeBuffer is how many messages may be in flight in each direction.
+const pipeBuffer = 256
+
+// Dialer opens a new connection to the host for an in-process plugin. The connection is closed
+// when ctx is cancelled.
+type Dialer func(ctx context.Context) (*PluginStream, error)
+
+// Pipe returns the two ends of an in-process connection. After CloseSend the other end receives
+// the remaining messages and then io.EOF; Close ends both directions.
+//
+// Messages are shared, not copied: neither side may modify a message after sending it or one it
+// received.
+func Pipe() (*HostStream, *PluginStream) {
+	toPlugin, toHost := newHalf[*pb.HostToPlugin](), newHalf[*pb.PluginToHost]()
+	return &HostStream{in: toHost, out: toPlugin}, &PluginStream{in: toPlugin, out: toHost}
+}
+
+// half is one direction of a pipe.
+type half[T any] struct {
+	ch   chan T
+	once sync.Once
+	done chan struct{}
+}
+
+func newHalf[T any]() *half[T] {
+	return &half[T]{ch: make(chan T, pipeBuffer), done: make(chan struct{})}
+}
+
+func (h *half[T]) close() {
+	h.once.Do(func() { close(h.done) })
+}
+
+func (h *half[T]) send(v T) error {
+	select {
+	case <-h.done:
+		return io.ErrClosedPipe
+	default:
+	}
+	select {
+	case h.ch <- v:
+		return nil
+	case <-h.done:
+		return io.ErrClosedPipe
+	}
+}
+
+func (h *half[T]) recv() (T, error) {
+	select {
+	case v := <-h.ch:
+		return v, nil
+	case <-h.done:
+		select {
+		case v := <-h.ch:
+			return v, nil
+		default:
+			var zero T
+			return zero, io.EOF
+		}
+	}
+}
+
+// HostStream is the host's end of a connection. It implements ports.MessageStream; Send and Recv
+// marshal for callers that only deal in bytes.
+type HostStream struct {
+	in  *half[*pb.PluginToHost]
+	out *half[*pb.HostToPlugin]
+}
+
+var _ ports.MessageStream = (*HostStream)(nil)
+
+func (s *HostStream) SendMessage(msg *pb.HostToPlugin) error {
+	return s.out.send(msg)
+}
+
+func (s *HostStream) RecvMessage() (*pb.PluginToHost, error) {
+	return s.in.recv()
+}
+
+func (s *HostStream) Send(data []byte) error {
+	msg := &pb.HostToPlugin{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	return s.SendMessage(msg)
+}
+
+func (s *HostStream) Recv() ([]byte, error) {
+	msg, err := s.RecvMessage()
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(msg)
+}
+
+func (s *HostStream) CloseSend() error {
+	s.out.close()
+	return nil
+}
+
+func (s *HostStream) Close() error {
+	s.in.close()
+	s.out.close()
+	return nil
+}
+
+// PluginStream is the plugin's end of a connection.
+type PluginStream struct {
+	in  *half[*pb.HostToPlugin]
+	out *half[*pb.PluginToHost]
+}
+
+func (s *PluginStream) Send(msg *pb.PluginToHost) error {
+	return s.out.send(msg)
+}
+
+func (s *PluginStream) Recv() (*pb.HostToPlugin, error) {
+	return s.in.recv()
+}
+
+func (s *PluginStream) CloseSend() error {
+	s.out.close()
+	return nil
+}
+
+func (s *PluginStream) Close() error {
+	s.in.close()
+	s.out.close()
+	return nil
+}
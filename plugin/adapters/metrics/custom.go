@@ -0,0 +1,270 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	customNamespace = "dragonfly_custom"
+	maxCustomLabels = 8
+	// maxLabelValueLength bounds the length of a label value in bytes.
+	maxLabelValueLength = 128
+	// maxReported bounds the number of distinct problems remembered per plugin. Once reached, further
+	// problems are no longer returned by Apply.
+	maxReported = 256
+
+	// DefaultMaxMetrics and DefaultMaxSeries are the limits used when NewCustom is given none.
+	DefaultMaxMetrics = 64
+	DefaultMaxSeries  = 1000
+)
+
+var (
+	namePattern    = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	idReplacer     = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	errSeriesLimit = errors.New("series limit reached")
+)
+
+// Custom holds the metrics a single plugin registered through PluginMetrics messages. It enforces
+// a limit on the number of metrics and on the number of label combinations across all of them.
+type Custom struct {
+	pluginID   string
+	prefix     string
+	reg        prometheus.Registerer
+	maxMetrics int
+	maxSeries  int
+
+	mu      sync.Mutex
+	metrics map[string]*customMetric
+	series  int
+	// reported holds the problems already returned by Apply, so a plugin that keeps sending a bad
+	// update is only reported once.
+	reported map[problem]struct{}
+}
+
+// problem identifies a rejected definition or update by what was wrong and the metric it named.
+type problem struct {
+	op     string // "define" or "update"
+	metric string
+}
+
+type customMetric struct {
+	def       *pb.MetricDefinition
+	collector prometheus.Collector
+	counter   *prometheus.CounterVec
+	gauge     *prometheus.GaugeVec
+	histogram *prometheus.HistogramVec
+	series    map[string]struct{}
+}
+
+// NewCustom creates the custom metric set of a plugin, registered with the default registry.
+// Limits that are not positive select DefaultMaxMetrics and DefaultMaxSeries.
+func NewCustom(pluginID string, maxMetrics, maxSeries int) *Custom {
+	if maxMetrics <= 0 {
+		maxMetrics = DefaultMaxMetrics
+	}
+	if maxSeries <= 0 {
+		maxSeries = DefaultMaxSeries
+	}
+	return &Custom{
+		pluginID:   pluginID,
+		prefix:     customNamespace + "_" + idReplacer.ReplaceAllString(pluginID, "_") + "_",
+		reg:        prometheus.DefaultRegisterer,
+		maxMetrics: maxMetrics,
+		maxSeries:  maxSeries,
+		metrics:    make(map[string]*customMetric),
+		reported:   make(map[problem]struct{}),
+	}
+}
+
+// Apply processes the definitions and then the updates of msg. It returns an error for the first
+// rejected definition and the first rejected update of each metric.
+func (c *Custom) Apply(msg *pb.PluginMetrics) []error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for _, def := range msg.GetDefinitions() {
+		if err := c.define(def); err != nil {
+			errs = c.report(errs, problem{op: "define", metric: def.GetName()}, err)
+		}
+	}
+	for _, update := range msg.GetUpdates() {
+		if err := c.update(update); err != nil {
+			errs = c.report(errs, problem{op: "update", metric: update.GetName()}, err)
+		}
+	}
+	return errs
+}
+
+func (c *Custom) report(errs []error, p problem, err error) []error {
+	if _, ok := c.reported[p]; ok || len(c.reported) >= maxReported {
+		return errs
+	}
+	c.reported[p] = struct{}{}
+	return append(errs, fmt.Errorf("%s metric %q: %w", p.op, p.metric, err))
+}
+
+func (c *Custom) define(def *pb.MetricDefinition) error {
+	if existing, ok := c.metrics[def.GetName()]; ok {
+		// Plugins re-send their definitions after reconnecting; identical definitions are fine.
+		if sameDefinition(existing.def, def) {
+			return nil
+		}
+		return errors.New("already defined differently")
+	}
+	if len(c.metrics) >= c.maxMetrics {
+		return fmt.Errorf("plugin already has %d metrics", c.maxMetrics)
+	}
+	if err := validateDefinition(def); err != nil {
+		return err
+	}
+
+	help := def.GetHelp()
+	if help == "" {
+		help = fmt.Sprintf("Custom metric of plugin %s.", c.pluginID)
+	}
+	name := c.prefix + def.GetName()
+	m := &customMetric{def: def, series: make(map[string]struct{})}
+	switch def.GetKind() {
+	case pb.MetricKind_METRIC_KIND_COUNTER:
+		m.counter = prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, def.GetLabelNames())
+		m.collector = m.counter
+	case pb.MetricKind_METRIC_KIND_GAUGE:
+		m.gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, def.GetLabelNames())
+		m.collector = m.gauge
+	case pb.MetricKind_METRIC_KIND_HISTOGRAM:
+		buckets := def.GetBuckets()
+		if len(buckets) == 0 {
+			buckets = prometheus.DefBuckets
+		}
+		m.histogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, def.GetLabelNames())
+		m.collector = m.histogram
+	}
+	if err := c.reg.Register(m.collector); err != nil {
+		return err
+	}
+	c.metrics[def.GetName()] = m
+	return nil
+}
+
+func validateDefinition(def *pb.MetricDefinition) error {
+	if !namePattern.MatchString(def.GetName()) {
+		return errors.New("invalid name")
+	}
+	switch def.GetKind() {
+	case pb.MetricKind_METRIC_KIND_COUNTER, pb.MetricKind_METRIC_KIND_GAUGE, pb.MetricKind_METRIC_KIND_HISTOGRAM:
+	default:
+		return errors.New("unknown kind")
+	}
+	labels := def.GetLabelNames()
+	if len(labels) > maxCustomLabels {
+		return fmt.Errorf("more than %d labels", maxCustomLabels)
+	}
+	for i, label := range labels {
+		if !namePattern.MatchString(label) || strings.HasPrefix(label, "__") {
+			return fmt.Errorf("invalid label name %q", label)
+		}
+		if slices.Contains(labels[:i], label) {
+			return fmt.Errorf("duplicate label name %q", label)
+		}
+		if label == "le" && def.GetKind() == pb.MetricKind_METRIC_KIND_HISTOGRAM {
+			return errors.New(`histograms cannot use the "le" label`)
+		}
+	}
+	buckets := def.GetBuckets()
+	if def.GetKind() != pb.MetricKind_METRIC_KIND_HISTOGRAM && len(buckets) > 0 {
+		return errors.New("buckets are only valid for histograms")
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return errors.New("buckets must be strictly increasing")
+		}
+	}
+	return nil
+}
+
+func sameDefinition(a, b *pb.MetricDefinition) bool {
+	return a.GetKind() == b.GetKind() && a.GetHelp() == b.GetHelp() &&
+		slices.Equal(a.GetLabelNames(), b.GetLabelNames()) && slices.Equal(a.GetBuckets(), b.GetBuckets())
+}
+
+func (c *Custom) update(u *pb.MetricUpdate) error {
+	m, ok := c.metrics[u.GetName()]
+	if !ok {
+		return errors.New("not defined")
+	}
+	values := u.GetLabelValues()
+	if len(values) != len(m.def.GetLabelNames()) {
+		return fmt.Errorf("got %d label values, want %d", len(values), len(m.def.GetLabelNames()))
+	}
+	for _, v := range values {
+		if len(v) > maxLabelValueLength {
+			return fmt.Errorf("label value longer than %d bytes", maxLabelValueLength)
+		}
+	}
+	key := strings.Join(values, "\xff")
+	_, known := m.series[key]
+	if !known && c.series >= c.maxSeries {
+		return errSeriesLimit
+	}
+	if err := m.apply(values, u); err != nil {
+		return err
+	}
+	if !known {
+		m.series[key] = struct{}{}
+		c.series++
+	}
+	return nil
+}
+
+func (m *customMetric) apply(values []string, u *pb.MetricUpdate) error {
+	switch op := u.GetOp().(type) {
+	case *pb.MetricUpdate_Add:
+		switch {
+		case m.counter != nil:
+			if op.Add < 0 {
+				return errors.New("counters cannot decrease")
+			}
+			m.counter.WithLabelValues(values...).Add(op.Add)
+		case m.gauge != nil:
+			m.gauge.WithLabelValues(values...).Add(op.Add)
+		default:
+			return errors.New("add is only valid for counters and gauges")
+		}
+	case *pb.MetricUpdate_Set:
+		if m.gauge == nil {
+			return errors.New("set is only valid for gauges")
+		}
+		m.gauge.WithLabelValues(values...).Set(op.Set)
+	case *pb.MetricUpdate_Observe:
+		if m.histogram == nil {
+			return errors.New("observe is only valid for histograms")
+		}
+		m.histogram.WithLabelValues(values...).Observe(op.Observe)
+	default:
+		return errors.New("missing operation")
+	}
+	return nil
+}
+
+// Unregister removes all of the plugin's metrics from the registry.
+func (c *Custom) Unregister() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, m := range c.metrics {
+		c.reg.Unregister(m.collector)
+		delete(c.metrics, name)
+	}
+	c.series = 0
+}
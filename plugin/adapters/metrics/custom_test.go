@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func TestCustomMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := NewCustom("my-plugin", 2, 2)
+	c.reg = reg
+
+	errs := c.Apply(&pb.PluginMetrics{
+		Definitions: []*pb.MetricDefinition{
+			{Name: "coins_earned_total", Kind: pb.MetricKind_METRIC_KIND_COUNTER, Help: "Coins earned.", LabelNames: []string{"mode"}},
+			{Name: "queue_size", Kind: pb.MetricKind_METRIC_KIND_GAUGE},
+			{Name: "one_too_many", Kind: pb.MetricKind_METRIC_KIND_GAUGE},
+		},
+		Updates: []*pb.MetricUpdate{
+			{Name: "coins_earned_total", LabelValues: []string{"solo"}, Op: &pb.MetricUpdate_Add{Add: 5}},
+			{Name: "coins_earned_total", LabelValues: []string{"solo"}, Op: &pb.MetricUpdate_Add{Add: 2}},
+			{Name: "queue_size", Op: &pb.MetricUpdate_Set{Set: 3}},
+			// Both series are used up, so a third label combination is rejected.
+			{Name: "coins_earned_total", LabelValues: []string{"duos"}, Op: &pb.MetricUpdate_Add{Add: 1}},
+			{Name: "queue_size", Op: &pb.MetricUpdate_Observe{Observe: 1}},
+		},
+	})
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+
+	expected := `
+# HELP dragonfly_custom_my_plugin_coins_earned_total Coins earned.
+# TYPE dragonfly_custom_my_plugin_coins_earned_total counter
+dragonfly_custom_my_plugin_coins_earned_total{mode="solo"} 7
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "dragonfly_custom_my_plugin_coins_earned_total"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Repeating the same bad update is not reported again.
+	errs = c.Apply(&pb.PluginMetrics{Updates: []*pb.MetricUpdate{
+		{Name: "coins_earned_total", LabelValues: []string{"duos"}, Op: &pb.MetricUpdate_Add{Add: 1}},
+	}})
+	if len(errs) != 0 {
+		t.Fatalf("expected repeated error to be suppressed, got %v", errs)
+	}
+
+	c.Unregister()
+	if n, err := testutil.GatherAndCount(reg); err != nil || n != 0 {
+		t.Fatalf("expected no metrics after unregister, got %d (%v)", n, err)
+	}
+}
+
+func TestCustomMetricLimits(t *testing.T) {
+	c := NewCustom("limits", 0, 0)
+	c.reg = prometheus.NewRegistry()
+	if c.maxMetrics != DefaultMaxMetrics || c.maxSeries != DefaultMaxSeries {
+		t.Errorf("Expected default limits, got %d metrics and %d series", c.maxMetrics, c.maxSeries)
+	}
+
+	errs := c.Apply(&pb.PluginMetrics{
+		Definitions: []*pb.MetricDefinition{{Name: "players", Kind: pb.MetricKind_METRIC_KIND_GAUGE, LabelNames: []string{"name"}}},
+		Updates: []*pb.MetricUpdate{
+			{Name: "players", LabelValues: []string{strings.Repeat("x", maxLabelValueLength+1)}, Op: &pb.MetricUpdate_Set{Set: 1}},
+		},
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "label value longer") {
+		t.Fatalf("Expected a long label value to be rejected, got %v", errs)
+	}
+	if c.series != 0 {
+		t.Errorf("Expected no series for the rejected update, got %d", c.series)
+	}
+
+	// Each rejected update names a different metric, which the host remembers only up to a limit.
+	var updates []*pb.MetricUpdate
+	for i := range 2 * maxReported {
+		updates = append(updates, &pb.MetricUpdate{Name: fmt.Sprintf("undefined_%d", i)})
+	}
+	errs = c.Apply(&pb.PluginMetrics{Updates: updates})
+	if len(errs) != maxReported-1 || len(c.reported) != maxReported {
+		t.Errorf("Expected %d errors and %d remembered problems, got %d and %d", maxReported-1, maxReported, len(errs), len(c.reported))
+	}
+}
@@ -0,0 +1,252 @@
+// Package metrics exposes Prometheus collectors for the plugin host. Collectors are registered with
+// the default registry, which cmd/main.go serves through promhttp.Handler.
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	namespace = "dragonfly"
+	subsystem = "plugin"
+)
+
+var (
+	eventsDispatched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name: "events_dispatched_total",
+		Help: "Events queued for delivery to a plugin.",
+	}, []string{"plugin", "event_type"})
+
+	eventsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name: "events_dropped_total",
+		Help: "Events not delivered because the plugin was disconnected or its send queue was full.",
+	}, []string{"plugin", "event_type"})
+
+	sendQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name: "send_queue_depth",
+		Help: "Messages waiting in the plugin's send queue.",
+	}, []string{"plugin"})
+
+	eventRoundTrip = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name:    "event_round_trip_seconds",
+		Help:    "Time from sending a cancellable event to receiving the plugin's result.",
+		Buckets: []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25},
+	}, []string{"plugin", "event_type"})
+
+	eventTimeouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name: "event_timeouts_total",
+		Help: "Cancellable events the plugin did not answer in time.",
+	}, []string{"plugin", "event_type"})
+
+	actions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name: "actions_total",
+		Help: "Actions executed for a plugin, by action kind and result (ok or failed).",
+	}, []string{"plugin", "kind", "result"})
+
+	batchSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name:    "batch_size",
+		Help:    "Number of events or actions per batch.",
+		Buckets: []float64{1, 2, 5, 10, 20, 50, 100, 200, 500},
+	}, []string{"plugin", "direction"})
+
+	compressionRatio = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name:    "compression_ratio",
+		Help:    "Compressed size divided by original size for compressed event batches.",
+		Buckets: prometheus.LinearBuckets(0.1, 0.1, 10),
+	}, []string{"plugin"})
+
+	reconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name: "reconnects_total",
+		Help: "Streams attached by a plugin that had connected before.",
+	}, []string{"plugin"})
+
+	restarts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name: "process_restarts_total",
+		Help: "Plugin processes launched again after their first launch.",
+	}, []string{"plugin"})
+
+	// launched records plugin IDs whose process has been launched at least once.
+	launched sync.Map
+)
+
+// Plugin records metrics for a single plugin. A nil *Plugin discards everything.
+type Plugin struct {
+	id string
+
+	queueDepth  prometheus.Gauge
+	eventBatch  prometheus.Observer
+	actionBatch prometheus.Observer
+	compression prometheus.Observer
+	reconnects  prometheus.Counter
+	restarts    prometheus.Counter
+
+	// Totals kept alongside the collectors so the host can report them without a scrape.
+	dispatched atomic.Uint64
+	dropped    atomic.Uint64
+	timeouts   atomic.Uint64
+	answered   atomic.Uint64
+	roundTrip  atomic.Int64
+}
+
+// Stats are the event totals recorded for a plugin since its process was created.
+type Stats struct {
+	Dispatched uint64
+	Dropped    uint64
+	Timeouts   uint64
+	// Answered counts cancellable events the plugin responded to in time.
+	Answered uint64
+	// AvgRoundTrip is the mean response time of answered events.
+	AvgRoundTrip time.Duration
+}
+
+// Stats returns the plugin's event totals.
+func (p *Plugin) Stats() Stats {
+	if p == nil {
+		return Stats{}
+	}
+	s := Stats{
+		Dispatched: p.dispatched.Load(),
+		Dropped:    p.dropped.Load(),
+		Timeouts:   p.timeouts.Load(),
+		Answered:   p.answered.Load(),
+	}
+	if s.Answered > 0 {
+		s.AvgRoundTrip = time.Duration(p.roundTrip.Load() / int64(s.Answered))
+	}
+	return s
+}
+
+// ForPlugin returns the collectors for the plugin with the given ID.
+func ForPlugin(id string) *Plugin {
+	return &Plugin{
+		id:          id,
+		queueDepth:  sendQueueDepth.WithLabelValues(id),
+		eventBatch:  batchSize.WithLabelValues(id, "events"),
+		actionBatch: batchSize.WithLabelValues(id, "actions"),
+		compression: compressionRatio.WithLabelValues(id),
+		reconnects:  reconnects.WithLabelValues(id),
+		restarts:    restarts.WithLabelValues(id),
+	}
+}
+
+// EventsQueued records events handed to the send queue, or dropped if sent is false.
+func (p *Plugin) EventsQueued(events []*pb.EventEnvelope, sent bool) {
+	if p == nil {
+		return
+	}
+	vec, total := eventsDispatched, &p.dispatched
+	if !sent {
+		vec, total = eventsDropped, &p.dropped
+	}
+	total.Add(uint64(len(events)))
+	for _, event := range events {
+		vec.WithLabelValues(p.id, event.Type.String()).Inc()
+	}
+}
+
+// EventQueued records a single event handed to the send queue, or dropped if sent is false.
+func (p *Plugin) EventQueued(event *pb.EventEnvelope, sent bool) {
+	if p == nil || event == nil {
+		return
+	}
+	p.EventsQueued([]*pb.EventEnvelope{event}, sent)
+}
+
+// QueueDepth records the current length of the send queue.
+func (p *Plugin) QueueDepth(n int) {
+	if p == nil {
+		return
+	}
+	p.queueDepth.Set(float64(n))
+}
+
+// EventRoundTrip records how long the plugin took to answer a cancellable event.
+func (p *Plugin) EventRoundTrip(t pb.EventType, d time.Duration) {
+	if p == nil {
+		return
+	}
+	eventRoundTrip.WithLabelValues(p.id, t.String()).Observe(d.Seconds())
+	p.answered.Add(1)
+	p.roundTrip.Add(int64(d))
+}
+
+// EventTimeout records a cancellable event the plugin did not answer in time.
+func (p *Plugin) EventTimeout(t pb.EventType) {
+	if p == nil {
+		return
+	}
+	eventTimeouts.WithLabelValues(p.id, t.String()).Inc()
+	p.timeouts.Add(1)
+}
+
+// Action records the outcome of executing an action of the given kind.
+func (p *Plugin) Action(kind string, ok bool) {
+	if p == nil {
+		return
+	}
+	result := "ok"
+	if !ok {
+		result = "failed"
+	}
+	actions.WithLabelValues(p.id, kind, result).Inc()
+}
+
+// EventBatch records the number of events sent in one batch.
+func (p *Plugin) EventBatch(n int) {
+	if p == nil {
+		return
+	}
+	p.eventBatch.Observe(float64(n))
+}
+
+// ActionBatch records the number of actions received in one batch.
+func (p *Plugin) ActionBatch(n int) {
+	if p == nil {
+		return
+	}
+	p.actionBatch.Observe(float64(n))
+}
+
+// Compression records the sizes of an event batch before and after compression.
+func (p *Plugin) Compression(original, compressed int) {
+	if p == nil || original == 0 {
+		return
+	}
+	p.compression.Observe(float64(compressed) / float64(original))
+}
+
+// Reconnected records a stream attached by a plugin that had connected before.
+func (p *Plugin) Reconnected() {
+	if p == nil {
+		return
+	}
+	p.reconnects.Inc()
+}
+
+// ProcessStarted records a launch of the plugin's process. Every launch after the first counts as
+// a restart.
+func (p *Plugin) ProcessStarted() {
+	if p == nil {
+		return
+	}
+	if _, loaded := launched.LoadOrStore(p.id, struct{}{}); loaded {
+		p.restarts.Inc()
+	}
+}
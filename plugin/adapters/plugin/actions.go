@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -22,13 +23,29 @@ import (
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
+	"github.com/secmc/plugin/plugin/adapters/tracing"
 	pb "github.com/secmc/plugin/proto/generated/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ObserveActions makes the manager call f with every action a plugin sends, before the action is
+// applied. f runs on the plugin's action goroutine and must not block. A nil f stops observing.
+func (m *Manager) ObserveActions(f func(pluginID string, action *pb.Action)) {
+	if f == nil {
+		m.actionObserver.Store(nil)
+		return
+	}
+	m.actionObserver.Store(&f)
+}
+
 func (m *Manager) applyActions(p *pluginProcess, batch *pb.ActionBatch) {
 	if batch == nil {
 		return
 	}
+	p.metrics.ActionBatch(len(batch.Actions))
+	observe := m.actionObserver.Load()
 
 	// Group world set block actions by world.
 	worldSetBlockActions := make(map[*world.World][]*pb.Action)
@@ -38,6 +55,9 @@ func (m *Manager) applyActions(p *pluginProcess, batch *pb.ActionBatch) {
 		if action == nil {
 			continue
 		}
+		if observe != nil {
+			(*observe)(p.id, action)
+		}
 
 		switch action.Kind.(type) {
 		case *pb.Action_WorldSetBlock:
@@ -45,6 +65,7 @@ func (m *Manager) applyActions(p *pluginProcess, batch *pb.ActionBatch) {
 			w := m.worldFromRef(kind.GetWorld())
 			if w == nil {
 				m.sendActionError(p, action.GetCorrelationId(), "world not found")
+				p.metrics.Action(actionKind(action), false)
 				continue
 			}
 			worldSetBlockActions[w] = append(worldSetBlockActions[w], action)
@@ -55,13 +76,50 @@ func (m *Manager) applyActions(p *pluginProcess, batch *pb.ActionBatch) {
 
 	// Process batched world set block actions.
 	for w, actions := range worldSetBlockActions {
-		m.handleWorldSetBlockBatch(p, w, actions)
+		span := startActionSpan(p, actions[0])
+		applied := m.handleWorldSetBlockBatch(p, w, actions)
+		for _, ok := range applied {
+			if !ok {
+				span.SetStatus(codes.Error, "action failed")
+			}
+			p.metrics.Action("world_set_block", ok)
+		}
+		span.End()
 	}
 
 	// Process other actions individually.
 	for _, action := range otherActions {
+		span := startActionSpan(p, action)
+		before := p.actionErrors.Load()
 		m.handleSingleAction(p, action)
+		ok := p.actionErrors.Load() == before
+		if !ok {
+			span.SetStatus(codes.Error, "action failed")
+		}
+		span.End()
+		p.metrics.Action(actionKind(action), ok)
+	}
+}
+
+// startActionSpan starts a span for an action that carries trace context, linking it to the event
+// that caused it. Actions without trace context get a no-op span.
+func startActionSpan(p *pluginProcess, action *pb.Action) trace.Span {
+	if action.GetTrace() == nil {
+		return trace.SpanFromContext(context.Background())
+	}
+	_, span := tracing.Tracer().Start(tracing.Extract(context.Background(), action.GetTrace()), "action "+actionKind(action),
+		trace.WithAttributes(attribute.String("plugin.id", p.id)))
+	return span
+}
+
+// actionKind returns the name of the action's kind field, e.g. "send_chat", for use as a metric label.
+func actionKind(action *pb.Action) string {
+	msg := action.ProtoReflect()
+	field := msg.WhichOneof(msg.Descriptor().Oneofs().ByName("kind"))
+	if field == nil {
+		return "unknown"
 	}
+	return string(field.Name())
 }
 
 func (m *Manager) handleSingleAction(p *pluginProcess, action *pb.Action) {
@@ -124,35 +182,50 @@ func (m *Manager) handleSingleAction(p *pluginProcess, action *pb.Action) {
 	case *pb.Action_WorldSetSpawn:
 		m.handleWorldSetSpawn(p, correlationID, kind.WorldSetSpawn)
 	case *pb.Action_WorldQueryEntities:
-		m.handleWorldQueryEntities(p, correlationID, kind.WorldQueryEntities)
+		res, err := m.query.WorldEntities(context.Background(), kind.WorldQueryEntities)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldEntities{WorldEntities: res}}, err)
 	case *pb.Action_WorldQueryPlayers:
-		m.handleWorldQueryPlayers(p, correlationID, kind.WorldQueryPlayers)
+		res, err := m.query.WorldPlayers(context.Background(), kind.WorldQueryPlayers)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldPlayers{WorldPlayers: res}}, err)
 	case *pb.Action_WorldQueryEntitiesWithin:
-		m.handleWorldQueryEntitiesWithin(p, correlationID, kind.WorldQueryEntitiesWithin)
+		res, err := m.query.WorldEntitiesWithin(context.Background(), kind.WorldQueryEntitiesWithin)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldEntitiesWithin{WorldEntitiesWithin: res}}, err)
 	case *pb.Action_WorldQueryDefaultGameMode:
-		m.handleWorldQueryDefaultGameMode(p, correlationID, kind.WorldQueryDefaultGameMode)
+		res, err := m.query.WorldDefaultGameMode(context.Background(), kind.WorldQueryDefaultGameMode)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldDefaultGameMode{WorldDefaultGameMode: res}}, err)
 	case *pb.Action_WorldQueryPlayerSpawn:
-		m.handleWorldQueryPlayerSpawn(p, correlationID, kind.WorldQueryPlayerSpawn)
+		res, err := m.query.WorldPlayerSpawn(context.Background(), kind.WorldQueryPlayerSpawn)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldPlayerSpawn{WorldPlayerSpawn: res}}, err)
 	case *pb.Action_WorldQueryBlock:
-		m.handleWorldQueryBlock(p, correlationID, kind.WorldQueryBlock)
+		res, err := m.query.WorldBlock(context.Background(), kind.WorldQueryBlock)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldBlock{WorldBlock: res}}, err)
 	case *pb.Action_WorldQueryBiome:
-		m.handleWorldQueryBiome(p, correlationID, kind.WorldQueryBiome)
+		res, err := m.query.WorldBiome(context.Background(), kind.WorldQueryBiome)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldBiome{WorldBiome: res}}, err)
 	case *pb.Action_WorldQueryLight:
-		m.handleWorldQueryLight(p, correlationID, kind.WorldQueryLight)
+		res, err := m.query.WorldLight(context.Background(), kind.WorldQueryLight)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldLight{WorldLight: res}}, err)
 	case *pb.Action_WorldQuerySkyLight:
-		m.handleWorldQuerySkyLight(p, correlationID, kind.WorldQuerySkyLight)
+		res, err := m.query.WorldSkyLight(context.Background(), kind.WorldQuerySkyLight)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldSkyLight{WorldSkyLight: res}}, err)
 	case *pb.Action_WorldQueryTemperature:
-		m.handleWorldQueryTemperature(p, correlationID, kind.WorldQueryTemperature)
+		res, err := m.query.WorldTemperature(context.Background(), kind.WorldQueryTemperature)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldTemperature{WorldTemperature: res}}, err)
 	case *pb.Action_WorldQueryHighestBlock:
-		m.handleWorldQueryHighestBlock(p, correlationID, kind.WorldQueryHighestBlock)
+		res, err := m.query.WorldHighestBlock(context.Background(), kind.WorldQueryHighestBlock)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldHighestBlock{WorldHighestBlock: res}}, err)
 	case *pb.Action_WorldQueryRainingAt:
-		m.handleWorldQueryRainingAt(p, correlationID, kind.WorldQueryRainingAt)
+		res, err := m.query.WorldRainingAt(context.Background(), kind.WorldQueryRainingAt)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldRainingAt{WorldRainingAt: res}}, err)
 	case *pb.Action_WorldQuerySnowingAt:
-		m.handleWorldQuerySnowingAt(p, correlationID, kind.WorldQuerySnowingAt)
+		res, err := m.query.WorldSnowingAt(context.Background(), kind.WorldQuerySnowingAt)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldSnowingAt{WorldSnowingAt: res}}, err)
 	case *pb.Action_WorldQueryThunderingAt:
-		m.handleWorldQueryThunderingAt(p, correlationID, kind.WorldQueryThunderingAt)
+		res, err := m.query.WorldThunderingAt(context.Background(), kind.WorldQueryThunderingAt)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldThunderingAt{WorldThunderingAt: res}}, err)
 	case *pb.Action_WorldQueryLiquid:
-		m.handleWorldQueryLiquid(p, correlationID, kind.WorldQueryLiquid)
+		res, err := m.query.WorldLiquid(context.Background(), kind.WorldQueryLiquid)
+		m.sendQueryResult(p, correlationID, &pb.ActionResult{Result: &pb.ActionResult_WorldLiquid{WorldLiquid: res}}, err)
 	case *pb.Action_WorldSetBiome:
 		m.handleWorldSetBiome(p, correlationID, kind.WorldSetBiome)
 	case *pb.Action_WorldSetLiquid:
@@ -280,46 +353,48 @@ func (m *Manager) handleSingleAction(p *pluginProcess, action *pb.Action) {
 	}
 }
 
-func (m *Manager) handleWorldSetBlockBatch(p *pluginProcess, w *world.World, actions []*pb.Action) {
-	correlationIDs := make([]string, 0, len(actions))
+// handleWorldSetBlockBatch sets the blocks of actions in one transaction on w and reports for each
+// action whether it was applied.
+func (m *Manager) handleWorldSetBlockBatch(p *pluginProcess, w *world.World, actions []*pb.Action) []bool {
+	failures := make([]string, len(actions))
 	<-w.Exec(func(tx *world.Tx) {
-		for _, action := range actions {
+		for i, action := range actions {
 			act := action.GetWorldSetBlock()
-			correlationID := action.GetCorrelationId()
 			if act.Position == nil {
-				if correlationID != "" {
-					m.sendActionError(p, correlationID, "missing position")
-				}
+				failures[i] = "missing position"
 				continue
 			}
 			pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
 			var blk world.Block
-			var ok bool
 			if act.Block != nil {
-				blk, ok = blockFromProto(act.Block)
-				if !ok {
-					if correlationID != "" {
-						m.sendActionError(p, correlationID, "unknown block")
-					}
+				var ok bool
+				if blk, ok = blockFromProto(act.Block); !ok {
+					failures[i] = "unknown block"
 					continue
 				}
 			}
 			tx.SetBlock(pos, blk, nil)
-			if correlationID != "" {
-				correlationIDs = append(correlationIDs, correlationID)
-			}
 		}
 	})
 
-	for _, correlationID := range correlationIDs {
-		m.sendActionOK(p, correlationID)
+	applied := make([]bool, len(actions))
+	for i, action := range actions {
+		if failures[i] != "" {
+			m.sendActionError(p, action.GetCorrelationId(), failures[i])
+			continue
+		}
+		applied[i] = true
+		m.sendActionOK(p, action.GetCorrelationId())
 	}
+	return applied
 }
 
 func (m *Manager) handleSendChat(act *pb.SendChatAction) {
 	if act.TargetUuid == "" {
-		for p := range m.srv.Players(nil) {
-			p.Message(act.Message)
+		if m.srv != nil {
+			for p := range m.srv.Players(nil) {
+				p.Message(act.Message)
+			}
 		}
 		chat.Global.WriteString(act.Message)
 		return
@@ -1483,122 +1558,6 @@ func (m *Manager) handleWorldSetSpawn(p *pluginProcess, correlationID string, ac
 	m.sendActionOK(p, correlationID)
 }
 
-func (m *Manager) handleWorldQueryEntities(p *pluginProcess, correlationID string, act *pb.WorldQueryEntitiesAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	entities := make([]world.Entity, 0)
-	<-w.Exec(func(tx *world.Tx) {
-		for e := range tx.Entities() {
-			entities = append(entities, e)
-		}
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldEntities{WorldEntities: &pb.WorldEntitiesResult{
-			World:    protoWorldRef(w),
-			Entities: protoEntityRefs(entities),
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryPlayers(p *pluginProcess, correlationID string, act *pb.WorldQueryPlayersAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	players := make([]world.Entity, 0)
-	<-w.Exec(func(tx *world.Tx) {
-		for pl := range tx.Players() {
-			players = append(players, pl)
-		}
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldPlayers{WorldPlayers: &pb.WorldPlayersResult{
-			World:   protoWorldRef(w),
-			Players: protoEntityRefs(players),
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryEntitiesWithin(p *pluginProcess, correlationID string, act *pb.WorldQueryEntitiesWithinAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	box, ok := bboxFromProto(act.Box)
-	if !ok {
-		m.sendActionError(p, correlationID, "invalid bounding box")
-		return
-	}
-	entities := make([]world.Entity, 0)
-	<-w.Exec(func(tx *world.Tx) {
-		for e := range tx.EntitiesWithin(box) {
-			entities = append(entities, e)
-		}
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldEntitiesWithin{WorldEntitiesWithin: &pb.WorldEntitiesWithinResult{
-			World:    protoWorldRef(w),
-			Box:      protoBBox(box),
-			Entities: protoEntityRefs(entities),
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryDefaultGameMode(p *pluginProcess, correlationID string, act *pb.WorldQueryDefaultGameModeAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	mode := w.DefaultGameMode()
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldDefaultGameMode{WorldDefaultGameMode: &pb.WorldDefaultGameModeResult{
-			World:    protoWorldRef(w),
-			GameMode: func() pb.GameMode { id, _ := world.GameModeID(mode); return pb.GameMode(id) }(),
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryPlayerSpawn(p *pluginProcess, correlationID string, act *pb.WorldQueryPlayerSpawnAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.PlayerUuid == "" {
-		m.sendActionError(p, correlationID, "missing player_uuid")
-		return
-	}
-	id, err := uuid.Parse(act.PlayerUuid)
-	if err != nil {
-		m.sendActionError(p, correlationID, "invalid player_uuid")
-		return
-	}
-	spawn := w.PlayerSpawn(id)
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldPlayerSpawn{WorldPlayerSpawn: &pb.WorldPlayerSpawnResult{
-			World:      protoWorldRef(w),
-			PlayerUuid: act.PlayerUuid,
-			Spawn:      protoBlockPos(spawn),
-		}},
-	})
-}
-
 func (m *Manager) execMethod(id uuid.UUID, method func(pl *player.Player)) {
 	if m.srv == nil {
 		return
@@ -1630,6 +1589,10 @@ func (m *Manager) sendActionOK(p *pluginProcess, correlationID string) {
 }
 
 func (m *Manager) sendActionError(p *pluginProcess, correlationID, msg string) {
+	if p != nil {
+		p.actionErrors.Add(1)
+		p.recordError(msg)
+	}
 	if correlationID == "" {
 		return
 	}
@@ -1759,267 +1722,6 @@ func playerTitleFromAction(act *pb.SendTitleAction) title.Title {
 	return t
 }
 
-// World query handlers
-
-func (m *Manager) handleWorldQueryBlock(p *pluginProcess, correlationID string, act *pb.WorldQueryBlockAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.Position == nil {
-		m.sendActionError(p, correlationID, "missing position")
-		return
-	}
-	pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
-	var block world.Block
-	<-w.Exec(func(tx *world.Tx) {
-		block = tx.Block(pos)
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldBlock{WorldBlock: &pb.WorldBlockResult{
-			World:    protoWorldRef(w),
-			Position: act.Position,
-			Block:    protoBlockState(block),
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryBiome(p *pluginProcess, correlationID string, act *pb.WorldQueryBiomeAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.Position == nil {
-		m.sendActionError(p, correlationID, "missing position")
-		return
-	}
-	pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
-	var biome world.Biome
-	<-w.Exec(func(tx *world.Tx) {
-		biome = tx.Biome(pos)
-	})
-	biomeID := fmt.Sprintf("%d", biome.EncodeBiome())
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldBiome{WorldBiome: &pb.WorldBiomeResult{
-			World:    protoWorldRef(w),
-			Position: act.Position,
-			BiomeId:  biomeID,
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryLight(p *pluginProcess, correlationID string, act *pb.WorldQueryLightAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.Position == nil {
-		m.sendActionError(p, correlationID, "missing position")
-		return
-	}
-	pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
-	var lightLevel uint8
-	<-w.Exec(func(tx *world.Tx) {
-		lightLevel = tx.Light(pos)
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldLight{WorldLight: &pb.WorldLightResult{
-			World:      protoWorldRef(w),
-			Position:   act.Position,
-			LightLevel: int32(lightLevel),
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQuerySkyLight(p *pluginProcess, correlationID string, act *pb.WorldQuerySkyLightAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.Position == nil {
-		m.sendActionError(p, correlationID, "missing position")
-		return
-	}
-	pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
-	var skyLightLevel uint8
-	<-w.Exec(func(tx *world.Tx) {
-		skyLightLevel = tx.SkyLight(pos)
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldSkyLight{WorldSkyLight: &pb.WorldSkyLightResult{
-			World:         protoWorldRef(w),
-			Position:      act.Position,
-			SkyLightLevel: int32(skyLightLevel),
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryTemperature(p *pluginProcess, correlationID string, act *pb.WorldQueryTemperatureAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.Position == nil {
-		m.sendActionError(p, correlationID, "missing position")
-		return
-	}
-	pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
-	var temperature float64
-	<-w.Exec(func(tx *world.Tx) {
-		temperature = tx.Temperature(pos)
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldTemperature{WorldTemperature: &pb.WorldTemperatureResult{
-			World:       protoWorldRef(w),
-			Position:    act.Position,
-			Temperature: temperature,
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryHighestBlock(p *pluginProcess, correlationID string, act *pb.WorldQueryHighestBlockAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	var y int
-	<-w.Exec(func(tx *world.Tx) {
-		y = tx.HighestBlock(int(act.X), int(act.Z))
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldHighestBlock{WorldHighestBlock: &pb.WorldHighestBlockResult{
-			World: protoWorldRef(w),
-			X:     act.X,
-			Z:     act.Z,
-			Y:     int32(y),
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryRainingAt(p *pluginProcess, correlationID string, act *pb.WorldQueryRainingAtAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.Position == nil {
-		m.sendActionError(p, correlationID, "missing position")
-		return
-	}
-	pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
-	var raining bool
-	<-w.Exec(func(tx *world.Tx) {
-		raining = tx.RainingAt(pos)
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldRainingAt{WorldRainingAt: &pb.WorldRainingAtResult{
-			World:    protoWorldRef(w),
-			Position: act.Position,
-			Raining:  raining,
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQuerySnowingAt(p *pluginProcess, correlationID string, act *pb.WorldQuerySnowingAtAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.Position == nil {
-		m.sendActionError(p, correlationID, "missing position")
-		return
-	}
-	pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
-	var snowing bool
-	<-w.Exec(func(tx *world.Tx) {
-		snowing = tx.SnowingAt(pos)
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldSnowingAt{WorldSnowingAt: &pb.WorldSnowingAtResult{
-			World:    protoWorldRef(w),
-			Position: act.Position,
-			Snowing:  snowing,
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryThunderingAt(p *pluginProcess, correlationID string, act *pb.WorldQueryThunderingAtAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.Position == nil {
-		m.sendActionError(p, correlationID, "missing position")
-		return
-	}
-	pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
-	var thundering bool
-	<-w.Exec(func(tx *world.Tx) {
-		thundering = tx.ThunderingAt(pos)
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldThunderingAt{WorldThunderingAt: &pb.WorldThunderingAtResult{
-			World:      protoWorldRef(w),
-			Position:   act.Position,
-			Thundering: thundering,
-		}},
-	})
-}
-
-func (m *Manager) handleWorldQueryLiquid(p *pluginProcess, correlationID string, act *pb.WorldQueryLiquidAction) {
-	w := m.worldFromRef(act.GetWorld())
-	if w == nil {
-		m.sendActionError(p, correlationID, "world not found")
-		return
-	}
-	if act.Position == nil {
-		m.sendActionError(p, correlationID, "missing position")
-		return
-	}
-	pos := cube.Pos{int(act.Position.X), int(act.Position.Y), int(act.Position.Z)}
-	var liquidState *pb.LiquidState
-	<-w.Exec(func(tx *world.Tx) {
-		if liq, ok := tx.Liquid(pos); ok {
-			liquidState = protoLiquidState(liq)
-		}
-	})
-	m.sendActionResult(p, &pb.ActionResult{
-		CorrelationId: correlationID,
-		Status:        &pb.ActionStatus{Ok: true},
-		Result: &pb.ActionResult_WorldLiquid{WorldLiquid: &pb.WorldLiquidResult{
-			World:    protoWorldRef(w),
-			Position: act.Position,
-			Liquid:   liquidState,
-		}},
-	})
-}
-
 // World mutation handlers
 
 func (m *Manager) handleWorldSetBiome(p *pluginProcess, correlationID string, act *pb.WorldSetBiomeAction) {
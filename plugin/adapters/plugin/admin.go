@@ -0,0 +1,374 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+var _ ports.PluginController = (*Manager)(nil)
+
+// pluginError is the last error reported for a plugin, shown by the admin API.
+type pluginError struct {
+	msg string
+	at  time.Time
+}
+
+func (p *pluginProcess) recordError(msg string) {
+	p.lastError.Store(&pluginError{msg: msg, at: time.Now()})
+}
+
+// PluginStatuses returns the status of every configured plugin, sorted by ID.
+func (m *Manager) PluginStatuses() []ports.PluginStatus {
+	m.mu.RLock()
+	statuses := make([]ports.PluginStatus, 0, len(m.plugins))
+	for _, proc := range m.plugins {
+		statuses = append(statuses, proc.status())
+	}
+	m.mu.RUnlock()
+	slices.SortFunc(statuses, func(a, b ports.PluginStatus) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return statuses
+}
+
+// PluginStatus returns the status of the plugin with the given ID.
+func (m *Manager) PluginStatus(id string) (ports.PluginStatus, error) {
+	proc, err := m.pluginByID(id)
+	if err != nil {
+		return ports.PluginStatus{}, err
+	}
+	return proc.status(), nil
+}
+
+// StartPlugin starts a stopped plugin again.
+func (m *Manager) StartPlugin(id string) error {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	proc, err := m.pluginByID(id)
+	if err != nil {
+		return err
+	}
+	if !proc.closed.Load() {
+		return fmt.Errorf("plugin %s is already running", id)
+	}
+	m.replacePlugin(proc, proc.cfg)
+	return nil
+}
+
+// StopPlugin asks the plugin to shut down and stops it. The plugin stays listed as stopped.
+func (m *Manager) StopPlugin(id string) error {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	proc, err := m.pluginByID(id)
+	if err != nil {
+		return err
+	}
+	if proc.closed.Load() {
+		return fmt.Errorf("plugin %s is not running", id)
+	}
+	m.stopPlugin(proc, "stopped by operator")
+	return nil
+}
+
+// RestartPlugin stops the plugin if it is running and starts it again with the same config.
+func (m *Manager) RestartPlugin(id string) error {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	proc, err := m.pluginByID(id)
+	if err != nil {
+		return err
+	}
+	m.replacePlugin(proc, proc.cfg)
+	return nil
+}
+
+// ReloadPlugin re-reads the plugin's definition from the config file and restarts it. A git
+// plugin keeps its checkout unless the repository or ref it is cloned from changed.
+func (m *Manager) ReloadPlugin(id string) error {
+	cfg, err := m.reloadableConfig()
+	if err != nil {
+		return err
+	}
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	proc, err := m.pluginByID(id)
+	if err != nil {
+		return err
+	}
+	for _, pc := range cfg.Plugins {
+		if pc.ID == id {
+			if err := m.checkout(proc, &pc); err != nil {
+				proc.recordError(err.Error())
+				return err
+			}
+			m.replacePlugin(proc, pc)
+			return nil
+		}
+	}
+	return fmt.Errorf("plugin %s is no longer in %s", id, cfg.Path)
+}
+
+// ReloadConfig re-reads plugin definitions from the config file. Added plugins are started,
+// removed plugins are stopped and plugins whose definition changed are restarted. Only added
+// git plugins and those whose repository or ref changed are cloned; the others keep the commit
+// they run, which watchUpdates moves. Host settings such as listeners only take effect after a
+// server restart.
+func (m *Manager) ReloadConfig() error {
+	cfg, err := m.reloadableConfig()
+	if err != nil {
+		return err
+	}
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+
+	var errs []error
+	seen := make(map[string]bool, len(cfg.Plugins))
+	for _, pc := range cfg.Plugins {
+		seen[pc.ID] = true
+		m.mu.RLock()
+		proc, ok := m.plugins[pc.ID]
+		m.mu.RUnlock()
+		if !ok {
+			if err := m.checkout(nil, &pc); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			m.log.Info("starting added plugin", "plugin", pc.ID)
+			m.startPlugin(newPluginProcess(m, pc))
+			continue
+		}
+		if err := m.checkout(proc, &pc); err != nil {
+			proc.recordError(err.Error())
+			errs = append(errs, err)
+			continue
+		}
+		if !reflect.DeepEqual(proc.cfg, pc) {
+			m.log.Info("restarting changed plugin", "plugin", pc.ID)
+			m.replacePlugin(proc, pc)
+		}
+	}
+
+	m.mu.RLock()
+	var removed []*pluginProcess
+	for id, proc := range m.plugins {
+		if !seen[id] {
+			removed = append(removed, proc)
+		}
+	}
+	m.mu.RUnlock()
+	for _, proc := range removed {
+		m.log.Info("stopping removed plugin", "plugin", proc.id)
+		if !proc.closed.Load() {
+			m.stopPlugin(proc, "removed from configuration")
+		}
+		m.mu.Lock()
+		if m.plugins[proc.id] == proc {
+			delete(m.plugins, proc.id)
+		}
+		m.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// checkout clones the repository of the git plugin pc, which replaces proc if not nil. If proc
+// runs a checkout of the same repository and ref, pc keeps it and the commit proc runs instead.
+// Otherwise proc is stopped first, as the clone replaces its checkout. A ref that resolves to a
+// commit an update rejected is not checked out again.
+func (m *Manager) checkout(proc *pluginProcess, pc *config.PluginConfig) error {
+	if pc.WorkDir.Git.Remote == "" {
+		return nil
+	}
+	if proc != nil {
+		if sameSource(proc.cfg, *pc) {
+			pc.WorkDir.Git.Commit = proc.cfg.WorkDir.Git.Commit
+			return nil
+		}
+		if !proc.closed.Load() {
+			m.stopPlugin(proc, "repository changed")
+		}
+	}
+	rejected, _ := m.rejectedCommits.Load(pc.ID)
+	commit, _ := rejected.(string)
+	return config.ClonePlugin(pc, commit)
+}
+
+// sameSource reports whether a and b check out the same repository and ref into the same work
+// directory and build it the same way.
+func sameSource(a, b config.PluginConfig) bool {
+	ga, gb := a.WorkDir.Git, b.WorkDir.Git
+	ga.PollIntervalMs, gb.PollIntervalMs = 0, 0
+	ga.Commit, gb.Commit = "", ""
+	return a.WorkDir.Path == b.WorkDir.Path && reflect.DeepEqual(ga, gb) && reflect.DeepEqual(a.Env, b.Env)
+}
+
+// SetPluginLogLevel overrides the level of the plugin's log output, including messages it sends
+// through LogMessage. A nil level restores the host's level.
+func (m *Manager) SetPluginLogLevel(id string, level *slog.Level) error {
+	proc, err := m.pluginByID(id)
+	if err != nil {
+		return err
+	}
+	proc.logLevel.Store(level)
+	return nil
+}
+
+// TailEvents returns a channel receiving the events sent to the plugin.
+func (m *Manager) TailEvents(id string) (<-chan *pb.EventEnvelope, func(), error) {
+	proc, err := m.pluginByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, cancel := proc.tap.subscribe()
+	return events, cancel, nil
+}
+
+func (m *Manager) pluginByID(id string) (*pluginProcess, error) {
+	m.mu.RLock()
+	proc, ok := m.plugins[id]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrUnknownPlugin, id)
+	}
+	return proc, nil
+}
+
+func (m *Manager) reloadableConfig() (config.Config, error) {
+	if m.configPath == "" {
+		return config.Config{}, errors.New("plugin config was not loaded from a file")
+	}
+	cfg, err := config.ParseConfig(m.configPath)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("reload plugin config: %w", err)
+	}
+	cfg.Plugins = m.withInProcess(cfg.Plugins)
+	return cfg, nil
+}
+
+// stopPlugin asks the plugin to shut down and stops it.
+func (m *Manager) stopPlugin(proc *pluginProcess, reason string) {
+	proc.queue(&pb.HostToPlugin{
+		PluginId: proc.id,
+		Payload: &pb.HostToPlugin_Shutdown{
+			Shutdown: &pb.HostShutdown{Reason: reason},
+		},
+	})
+	proc.Stop()
+	proc.log.Info("plugin stopped", "reason", reason)
+}
+
+// replacePlugin stops old if it is still running and starts a fresh process for cfg in its place,
+// which it returns. The plugin's log level override is kept.
+func (m *Manager) replacePlugin(old *pluginProcess, cfg config.PluginConfig) *pluginProcess {
+	if !old.closed.Load() {
+		m.stopPlugin(old, "restarting")
+	}
+	next := newPluginProcess(m, cfg)
+	next.logLevel.Store(old.logLevel.Load())
+	m.startPlugin(next)
+	return next
+}
+
+func (m *Manager) startPlugin(proc *pluginProcess) {
+	m.mu.Lock()
+	m.plugins[proc.id] = proc
+	m.mu.Unlock()
+	go proc.start(m.ctx, m.serverAddress)
+}
+
+// status returns a snapshot of the plugin for the admin API.
+func (p *pluginProcess) status() ports.PluginStatus {
+	st := ports.PluginStatus{
+		ID:            p.id,
+		Name:          p.cfg.Name,
+		PID:           p.pid.Load(),
+		Healthy:       !p.unhealthy.Load(),
+		Subscriptions: []string{},
+		Commands:      []string{},
+		CustomItems:   []string{},
+		CustomBlocks:  []string{},
+		SendQueue:     len(p.sendCh),
+		ActionQueue:   len(p.actionCh),
+		RTTMillis:     float64(p.rtt.Load()) / float64(time.Millisecond),
+		LogLevel:      "default",
+		Capabilities:  slices.Clone(p.session.Load().capabilities),
+		Commit:        p.cfg.WorkDir.Git.Commit,
+	}
+	stats := p.metrics.Stats()
+	st.EventsDispatched, st.EventsDropped, st.EventTimeouts = stats.Dispatched, stats.Dropped, stats.Timeouts
+	st.AvgResponseMillis = float64(stats.AvgRoundTrip) / float64(time.Millisecond)
+	switch {
+	case p.closed.Load():
+		st.State = "stopped"
+		st.PID = 0
+	case p.connected.Load() && p.ready.Load():
+		st.State = "ready"
+	case p.connected.Load():
+		st.State = "connected"
+	case st.PID != 0:
+		st.State = "launched"
+	default:
+		st.State = "waiting"
+	}
+	if hello := p.helloInfo(); hello != nil {
+		if st.Name == "" {
+			st.Name = hello.Name
+		}
+		st.Version = hello.Version
+		st.APIVersion = p.session.Load().apiVersion
+		for _, cmd := range hello.Commands {
+			st.Commands = append(st.Commands, cmd.GetName())
+		}
+		for _, it := range hello.CustomItems {
+			st.CustomItems = append(st.CustomItems, it.GetId())
+		}
+		for _, b := range hello.CustomBlocks {
+			st.CustomBlocks = append(st.CustomBlocks, b.GetId())
+		}
+	}
+	p.subscriptions.Range(func(key, _ any) bool {
+		st.Subscriptions = append(st.Subscriptions, key.(pb.EventType).String())
+		return true
+	})
+	slices.Sort(st.Subscriptions)
+	if level := p.logLevel.Load(); level != nil {
+		st.LogLevel = level.String()
+	}
+	if last := p.lastError.Load(); last != nil {
+		at := last.at
+		st.LastError, st.LastErrorAt = last.msg, &at
+	}
+	return st
+}
+
+// levelHandler lets a plugin's log level be changed at runtime. Until a level is set, the wrapped
+// handler decides what is logged.
+type levelHandler struct {
+	slog.Handler
+	level *atomic.Pointer[slog.Level]
+}
+
+func (h levelHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	if level := h.level.Load(); level != nil {
+		return l >= *level
+	}
+	return h.Handler.Enabled(ctx, l)
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
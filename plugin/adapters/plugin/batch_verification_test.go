@@ -1,9 +1,15 @@
 package plugin
 
 import (
+	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/secmc/plugin/plugin/adapters/metrics"
 	pb "github.com/secmc/plugin/proto/generated/go"
 )
 
@@ -52,3 +58,79 @@ func TestEventBatching(t *testing.T) {
 	close(p.done)
 	p.wg.Wait()
 }
+
+func TestBatchedDispatchMetrics(t *testing.T) {
+	const id = "batch-metrics"
+	p := &pluginProcess{
+		id:      id,
+		log:     slog.New(slog.DiscardHandler),
+		metrics: metrics.ForPlugin(id),
+		sendCh:  make(chan *pb.HostToPlugin, 10),
+		done:    make(chan struct{}),
+	}
+	p.connected.Store(true)
+	p.wg.Add(1)
+	go p.batchSendLoop()
+	defer func() {
+		close(p.done)
+		p.wg.Wait()
+	}()
+
+	batches := eventBatches(t, id)
+	for i := 0; i < 3; i++ {
+		p.queueEvent(&pb.EventEnvelope{EventId: fmt.Sprint(i), Type: pb.EventType_CHAT})
+	}
+	waitStats(t, p, func(s metrics.Stats) bool { return s.Dispatched == 3 })
+	if n := eventBatches(t, id) - batches; n == 0 || n > 3 {
+		t.Errorf("Expected 1 to 3 event batches to be observed, got %d", n)
+	}
+
+	// With the send queue full, the next batch is dropped.
+	for len(p.sendCh) < cap(p.sendCh) {
+		p.sendCh <- &pb.HostToPlugin{}
+	}
+	p.queueEvent(&pb.EventEnvelope{EventId: "dropped", Type: pb.EventType_CHAT})
+	s := waitStats(t, p, func(s metrics.Stats) bool { return s.Dropped == 1 })
+	if s.Dispatched != 3 {
+		t.Errorf("Expected 3 dispatched events, got %d", s.Dispatched)
+	}
+}
+
+func waitStats(t *testing.T, p *pluginProcess, cond func(metrics.Stats) bool) metrics.Stats {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		s := p.metrics.Stats()
+		if cond(s) {
+			return s
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for event totals, got %+v", s)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// eventBatches returns the number of event batches observed for the plugin.
+func eventBatches(t *testing.T, plugin string) uint64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if !strings.HasSuffix(f.GetName(), "_batch_size") {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["plugin"] == plugin && labels["direction"] == "events" {
+				return m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
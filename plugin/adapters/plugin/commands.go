@@ -6,6 +6,7 @@ import (
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/world"
+	"github.com/secmc/plugin/plugin/ports"
 	pb "github.com/secmc/plugin/proto/generated/go"
 )
 
@@ -119,3 +120,162 @@ func buildParamInfo(spec *pb.CommandSpec) []cmd.ParamInfo {
 	}
 	return params
 }
+
+// registerPluginsCommand registers the built-in /plugins command that lets operators inspect and
+// manage plugins in game.
+func (m *Manager) registerPluginsCommand() {
+	base := pluginsCommandBase{mgr: m}
+	cmd.Register(cmd.New("plugins", "Inspect and manage plugins", nil,
+		pluginsList{pluginsCommandBase: base},
+		pluginsInfo{pluginsCommandBase: base},
+		pluginsStats{pluginsCommandBase: base},
+		pluginsRestart{pluginsCommandBase: base},
+		pluginsDisable{pluginsCommandBase: base},
+		pluginsEnable{pluginsCommandBase: base},
+	))
+}
+
+// isOperator reports whether the player is listed in the operators config by name or XUID.
+func (m *Manager) isOperator(p *player.Player) bool {
+	for _, op := range m.operators {
+		if strings.EqualFold(op, p.Name()) || (p.XUID() != "" && op == p.XUID()) {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginsCommandBase gates every /plugins subcommand to operators. Sources other than players,
+// such as the console, are always allowed.
+type pluginsCommandBase struct {
+	mgr *Manager
+}
+
+func (b pluginsCommandBase) Allow(src cmd.Source) bool {
+	p, ok := src.(*player.Player)
+	return !ok || b.mgr.isOperator(p)
+}
+
+type pluginsList struct {
+	pluginsCommandBase
+}
+
+func (c pluginsList) Run(_ cmd.Source, output *cmd.Output, _ *world.Tx) {
+	statuses := c.mgr.PluginStatuses()
+	if len(statuses) == 0 {
+		output.Printf("No plugins configured.")
+		return
+	}
+	output.Printf("Plugins (%d):", len(statuses))
+	for _, st := range statuses {
+		output.Printf("- %s %s [api %s] %s, %d subscriptions", st.ID, orDash(st.Version), orDash(st.APIVersion), st.State, len(st.Subscriptions))
+	}
+}
+
+type pluginsInfo struct {
+	pluginsCommandBase
+	Info   cmd.SubCommand `cmd:"info"`
+	Plugin string         `cmd:"plugin"`
+}
+
+func (c pluginsInfo) Run(_ cmd.Source, output *cmd.Output, _ *world.Tx) {
+	st, err := c.mgr.PluginStatus(c.Plugin)
+	if err != nil {
+		output.Errorf("%v", err)
+		return
+	}
+	output.Printf("%s (%s) %s [api %s]", st.ID, orDash(st.Name), orDash(st.Version), orDash(st.APIVersion))
+	output.Printf("State: %s, healthy: %t, pid: %d", st.State, st.Healthy, st.PID)
+	if st.Commit != "" {
+		output.Printf("Commit: %s", st.Commit)
+	}
+	output.Printf("Subscriptions: %s", joinOrDash(st.Subscriptions))
+	output.Printf("Commands: %s", joinOrDash(st.Commands))
+	output.Printf("Custom items: %s", joinOrDash(st.CustomItems))
+	output.Printf("Custom blocks: %s", joinOrDash(st.CustomBlocks))
+	output.Printf("Queues: %d sends, %d action batches", st.SendQueue, st.ActionQueue)
+	if st.LastError != "" {
+		output.Printf("Last error (%s): %s", st.LastErrorAt.Format("15:04:05"), st.LastError)
+	}
+}
+
+type pluginsStats struct {
+	pluginsCommandBase
+	Stats  cmd.SubCommand       `cmd:"stats"`
+	Plugin cmd.Optional[string] `cmd:"plugin"`
+}
+
+func (c pluginsStats) Run(_ cmd.Source, output *cmd.Output, _ *world.Tx) {
+	statuses := c.mgr.PluginStatuses()
+	if id, ok := c.Plugin.Load(); ok {
+		st, err := c.mgr.PluginStatus(id)
+		if err != nil {
+			output.Errorf("%v", err)
+			return
+		}
+		statuses = []ports.PluginStatus{st}
+	}
+	for _, st := range statuses {
+		output.Printf("%s: %d events sent, %d dropped, %d timeouts, avg response %.2fms, rtt %.2fms, queue %d",
+			st.ID, st.EventsDispatched, st.EventsDropped, st.EventTimeouts, st.AvgResponseMillis, st.RTTMillis, st.SendQueue)
+	}
+}
+
+type pluginsRestart struct {
+	pluginsCommandBase
+	Restart cmd.SubCommand `cmd:"restart"`
+	Plugin  string         `cmd:"plugin"`
+}
+
+func (c pluginsRestart) Run(_ cmd.Source, output *cmd.Output, _ *world.Tx) {
+	c.runAsync(output, c.Plugin, "Restarting", c.mgr.RestartPlugin)
+}
+
+type pluginsDisable struct {
+	pluginsCommandBase
+	Disable cmd.SubCommand `cmd:"disable"`
+	Plugin  string         `cmd:"plugin"`
+}
+
+func (c pluginsDisable) Run(_ cmd.Source, output *cmd.Output, _ *world.Tx) {
+	c.runAsync(output, c.Plugin, "Disabling", c.mgr.StopPlugin)
+}
+
+type pluginsEnable struct {
+	pluginsCommandBase
+	Enable cmd.SubCommand `cmd:"enable"`
+	Plugin string         `cmd:"plugin"`
+}
+
+func (c pluginsEnable) Run(_ cmd.Source, output *cmd.Output, _ *world.Tx) {
+	c.runAsync(output, c.Plugin, "Enabling", c.mgr.StartPlugin)
+}
+
+// runAsync runs op for the plugin outside the world transaction, since stopping a plugin waits for
+// its goroutines. The outcome is logged.
+func (b pluginsCommandBase) runAsync(output *cmd.Output, id, verb string, op func(string) error) {
+	if _, err := b.mgr.PluginStatus(id); err != nil {
+		output.Errorf("%v", err)
+		return
+	}
+	output.Printf("%s %s...", verb, id)
+	go func() {
+		if err := op(id); err != nil {
+			b.mgr.log.Warn(strings.ToLower(verb)+" plugin failed", "plugin", id, "error", err)
+		}
+	}()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func joinOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,109 @@
+package plugin_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+
+	"github.com/secmc/plugin/plugin/adapters/plugin/plugintest"
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// console is a command source that is not a player and keeps the output of the last command.
+type console struct {
+	output *cmd.Output
+}
+
+func (*console) Position() mgl64.Vec3 { return mgl64.Vec3{} }
+
+func (c *console) SendCommandOutput(o *cmd.Output) { c.output = o }
+
+// runPlugins runs /plugins with args from the console and returns its messages and errors.
+func runPlugins(t *testing.T, h *plugintest.Host, args string) (string, string) {
+	t.Helper()
+	command, ok := cmd.ByAlias("plugins")
+	if !ok {
+		t.Fatal("Expected /plugins to be registered")
+	}
+	src := &console{}
+	h.Exec(func(tx *world.Tx) { command.Execute(args, src, tx) })
+	var messages, errs []string
+	for _, m := range src.output.Messages() {
+		messages = append(messages, m.String())
+	}
+	for _, err := range src.output.Errors() {
+		errs = append(errs, err.Error())
+	}
+	return strings.Join(messages, "\n"), strings.Join(errs, "\n")
+}
+
+func TestPluginsCommandOperators(t *testing.T) {
+	h := plugintest.NewHost(t, config.Config{Operators: []string{"Steve"}})
+	command, ok := cmd.ByAlias("plugins")
+	if !ok {
+		t.Fatal("Expected /plugins to be registered")
+	}
+	for name, allowed := range map[string]bool{"steve": true, "Alex": false} {
+		h.WithPlayer(h.AddPlayer(name), func(_ *world.Tx, p *player.Player) {
+			if got := len(command.Runnables(p)) > 0; got != allowed {
+				t.Errorf("Expected %s allowed to run /plugins: %v, got %v", name, allowed, got)
+			}
+		})
+	}
+	if got := len(command.Runnables(&console{})); got != 6 {
+		t.Errorf("Expected the console to run all 6 subcommands, got %d", got)
+	}
+}
+
+func TestPluginsCommand(t *testing.T) {
+	h := newHost(t, "alpha")
+	fp := h.Connect("alpha")
+	fp.Hello(&pb.PluginHello{Name: "Alpha", Version: "1.2.3"})
+	fp.Subscribe(pb.EventType_CHAT)
+	waitStatus(t, h, "alpha", "alpha to be ready", func(st ports.PluginStatus) bool { return st.State == "ready" })
+
+	out, errs := runPlugins(t, h, "")
+	if errs != "" || !strings.Contains(out, "Plugins (1):") || !strings.Contains(out, "- alpha 1.2.3") || !strings.Contains(out, "ready, 1 subscriptions") {
+		t.Errorf("Unexpected list output %q (%s)", out, errs)
+	}
+	out, errs = runPlugins(t, h, "info alpha")
+	if errs != "" || !strings.Contains(out, "alpha (Alpha) 1.2.3") || !strings.Contains(out, "Subscriptions: CHAT") {
+		t.Errorf("Unexpected info output %q (%s)", out, errs)
+	}
+	if _, errs = runPlugins(t, h, "info missing"); !strings.Contains(errs, "missing") {
+		t.Errorf("Expected info of an unknown plugin to fail, got %q", errs)
+	}
+	out, errs = runPlugins(t, h, "stats")
+	if errs != "" || !strings.Contains(out, "alpha: 0 events sent") {
+		t.Errorf("Unexpected stats output %q (%s)", out, errs)
+	}
+	if out, errs = runPlugins(t, h, "stats alpha"); errs != "" || !strings.HasPrefix(out, "alpha:") {
+		t.Errorf("Unexpected stats output for alpha %q (%s)", out, errs)
+	}
+
+	if out, _ = runPlugins(t, h, "restart alpha"); out != "Restarting alpha..." {
+		t.Errorf("Unexpected restart output %q", out)
+	}
+	fp.WaitClosed()
+	waitStatus(t, h, "alpha", "alpha to wait for a new connection", func(st ports.PluginStatus) bool { return st.State == "waiting" })
+
+	if out, _ = runPlugins(t, h, "disable alpha"); out != "Disabling alpha..." {
+		t.Errorf("Unexpected disable output %q", out)
+	}
+	waitStatus(t, h, "alpha", "alpha to stop", func(st ports.PluginStatus) bool { return st.State == "stopped" })
+
+	if out, _ = runPlugins(t, h, "enable alpha"); out != "Enabling alpha..." {
+		t.Errorf("Unexpected enable output %q", out)
+	}
+	waitStatus(t, h, "alpha", "alpha to be enabled", func(st ports.PluginStatus) bool { return st.State == "waiting" })
+
+	if _, errs = runPlugins(t, h, "restart missing"); !strings.Contains(errs, "missing") {
+		t.Errorf("Expected restarting an unknown plugin to fail, got %q", errs)
+	}
+}
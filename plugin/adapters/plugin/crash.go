@@ -0,0 +1,263 @@
+package plugin
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+
+	"github.com/secmc/plugin/plugin/config"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	defaultCrashOutputLines = 100
+	defaultCrashHistory     = 50
+	// crashEntryLimit truncates events and actions in crash reports.
+	crashEntryLimit = 500
+)
+
+// ring keeps the last items added to it.
+type ring[T any] struct {
+	items []T
+	next  int
+	full  bool
+}
+
+func newRing[T any](size int) ring[T] {
+	return ring[T]{items: make([]T, size)}
+}
+
+func (r *ring[T]) add(v T) {
+	if len(r.items) == 0 {
+		return
+	}
+	r.items[r.next] = v
+	r.next = (r.next + 1) % len(r.items)
+	r.full = r.full || r.next == 0
+}
+
+// list returns the items, oldest first.
+func (r *ring[T]) list() []T {
+	if !r.full {
+		return slices.Clone(r.items[:r.next])
+	}
+	return append(slices.Clone(r.items[r.next:]), r.items[:r.next]...)
+}
+
+// historyEntry is an event delivered to or an action received from a plugin.
+type historyEntry struct {
+	at  time.Time
+	msg proto.Message
+}
+
+// crashHistory keeps a plugin's recent output and traffic for its crash report. A nil
+// crashHistory records nothing.
+type crashHistory struct {
+	mu      sync.Mutex
+	stdout  ring[string]
+	stderr  ring[string]
+	events  ring[historyEntry]
+	actions ring[historyEntry]
+}
+
+func newCrashHistory(outputLines, history int) *crashHistory {
+	if outputLines <= 0 {
+		outputLines = defaultCrashOutputLines
+	}
+	if history <= 0 {
+		history = defaultCrashHistory
+	}
+	return &crashHistory{
+		stdout:  newRing[string](outputLines),
+		stderr:  newRing[string](outputLines),
+		events:  newRing[historyEntry](history),
+		actions: newRing[historyEntry](history),
+	}
+}
+
+func (h *crashHistory) output(stream, line string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if stream == "stdout" {
+		h.stdout.add(line)
+	} else {
+		h.stderr.add(line)
+	}
+}
+
+func (h *crashHistory) event(ev *pb.EventEnvelope) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events.add(historyEntry{at: time.Now(), msg: ev})
+}
+
+func (h *crashHistory) actionBatch(batch *pb.ActionBatch) {
+	if h == nil {
+		return
+	}
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, action := range batch.GetActions() {
+		h.actions.add(historyEntry{at: now, msg: action})
+	}
+}
+
+// crash describes a plugin failure.
+type crash struct {
+	reason string
+	// state is the exited process, nil for in-process plugins.
+	state *os.ProcessState
+}
+
+// exitStatus returns the process's exit code, or the name of the signal that killed it.
+func (c crash) exitStatus() (code *int32, signal string) {
+	if c.state == nil {
+		return nil, ""
+	}
+	if ws, ok := c.state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return nil, ws.Signal().String()
+	}
+	return proto.Int32(int32(c.state.ExitCode())), ""
+}
+
+// reportCrash writes a crash report for the failure and tells the other plugins about it.
+func (p *pluginProcess) reportCrash(c crash) {
+	code, signal := c.exitStatus()
+	ev := &pb.PluginCrashEvent{
+		PluginId:        p.id,
+		Name:            p.cfg.Name,
+		Reason:          c.reason,
+		ExitCode:        code,
+		Signal:          signal,
+		PendingEventIds: p.pendingEventIDs(),
+	}
+	if started := p.startedAt.Load(); started != nil {
+		ev.UptimeMs = time.Since(*started).Milliseconds()
+	}
+	if dir := p.manager.crashReports.Dir; dir != "" {
+		path, err := p.writeCrashReport(dir, ev)
+		if err != nil {
+			p.log.Error("write crash report", "error", err)
+		} else {
+			p.log.Warn("crash report written", "path", path)
+			ev.Report = path
+		}
+	}
+	p.manager.broadcastEvent(p.manager.ctx, &pb.EventEnvelope{
+		Type:    pb.EventType_PLUGIN_CRASH,
+		Payload: &pb.EventEnvelope_PluginCrash{PluginCrash: ev},
+	})
+}
+
+func (p *pluginProcess) pendingEventIDs() []string {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	return slices.Sorted(maps.Keys(p.pending))
+}
+
+// writeCrashReport writes the report for ev to dir and returns its path.
+func (p *pluginProcess) writeCrashReport(dir string, ev *pb.PluginCrashEvent) (string, error) {
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plugin crash report\n\n")
+	fmt.Fprintf(&b, "Plugin:  %s\n", p.id)
+	if ev.Name != "" {
+		fmt.Fprintf(&b, "Name:    %s\n", ev.Name)
+	}
+	if hello := p.helloInfo(); hello != nil && hello.Version != "" {
+		fmt.Fprintf(&b, "Version: %s\n", hello.Version)
+	}
+	fmt.Fprintf(&b, "Time:    %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Reason:  %s\n", ev.Reason)
+	if ev.ExitCode != nil {
+		fmt.Fprintf(&b, "Exit:    code %d\n", *ev.ExitCode)
+	}
+	if ev.Signal != "" {
+		fmt.Fprintf(&b, "Exit:    signal %s\n", ev.Signal)
+	}
+	fmt.Fprintf(&b, "Uptime:  %s\n", (time.Duration(ev.UptimeMs) * time.Millisecond).String())
+	fmt.Fprintf(&b, "Pending: %s\n", strings.Join(ev.PendingEventIds, ", "))
+
+	cfg, err := yaml.Marshal(redactedConfig(p.cfg))
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "\n-- Config --\n%s", cfg)
+
+	var events, actions []historyEntry
+	var stdout, stderr []string
+	if h := p.history; h != nil {
+		h.mu.Lock()
+		events, actions, stdout, stderr = h.events.list(), h.actions.list(), h.stdout.list(), h.stderr.list()
+		h.mu.Unlock()
+	}
+	fmt.Fprintf(&b, "\n-- Last %d events delivered --\n", len(events))
+	for _, e := range events {
+		fmt.Fprintf(&b, "%s %s\n", e.at.Format("15:04:05.000"), crashEntry(e.msg))
+	}
+	fmt.Fprintf(&b, "\n-- Last %d actions received --\n", len(actions))
+	for _, a := range actions {
+		fmt.Fprintf(&b, "%s %s\n", a.at.Format("15:04:05.000"), crashEntry(a.msg))
+	}
+	for _, out := range []struct {
+		name  string
+		lines []string
+	}{{"stdout", stdout}, {"stderr", stderr}} {
+		fmt.Fprintf(&b, "\n-- Last %d lines of %s --\n", len(out.lines), out.name)
+		for _, line := range out.lines {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	// Reports hold the plugin's output and recent traffic, so only the host's user may read them.
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.txt", p.id, now.Format("2006-01-02_15.04.05.000")))
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// crashEntry formats msg on one line, truncated.
+func crashEntry(msg proto.Message) string {
+	text := prototext.MarshalOptions{}.Format(msg)
+	if len(text) > crashEntryLimit {
+		text = text[:crashEntryLimit] + "..."
+	}
+	return text
+}
+
+// redactedConfig returns cfg without the values of its environment and its webhook secret.
+func redactedConfig(cfg config.PluginConfig) config.PluginConfig {
+	if len(cfg.Env) > 0 {
+		env := make(map[string]string, len(cfg.Env))
+		for k := range cfg.Env {
+			env[k] = "<redacted>"
+		}
+		cfg.Env = env
+	}
+	if cfg.Webhook.Secret != "" {
+		cfg.Webhook.Secret = "<redacted>"
+	}
+	return cfg
+}
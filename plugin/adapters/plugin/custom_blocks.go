@@ -107,6 +107,7 @@ func (m *Manager) registerCustomBlocks(p *pluginProcess, defs []*pb.CustomBlockD
 			p.log.Error("failed to register custom block", "id", def.Id, "error", err)
 			continue
 		}
+		m.invalidateRegistry()
 		m.log.Info("registered custom block", "plugin", pluginName, "id", def.Id, "name", def.DisplayName)
 	}
 }
@@ -53,6 +53,7 @@ func (m *Manager) registerCustomItems(p *pluginProcess, defs []*pb.CustomItemDef
 			p.log.Error("failed to register custom item", "id", def.Id, "error", err)
 			continue
 		}
+		m.invalidateRegistry()
 		m.log.Info("registered custom item", "plugin", pluginName, "id", def.Id, "name", def.DisplayName)
 	}
 }
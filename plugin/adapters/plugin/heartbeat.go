@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"time"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// heartbeatLoop pings the plugin every heartbeat interval and marks it unhealthy once too many pings
+// go unanswered. Unhealthy plugins receive no events until they answer a ping again. It only runs
+// for plugins that negotiated CapabilityHeartbeat, and a plugin that has not answered any ping on
+// its stream is never marked unhealthy, since SDKs written before HostPing ignore it.
+func (p *pluginProcess) heartbeatLoop(interval time.Duration, missedLimit int) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			if !p.connected.Load() {
+				continue
+			}
+			if p.pingOutstanding.Load() && p.answeredPing.Load() {
+				missed := p.missedPings.Add(1)
+				if int(missed) >= missedLimit && p.unhealthy.CompareAndSwap(false, true) {
+					p.log.Warn("plugin unhealthy, pausing event dispatch", "missed_heartbeats", missed)
+				}
+			}
+			p.sendPing()
+		}
+	}
+}
+
+func (p *pluginProcess) sendPing() {
+	now := time.Now()
+	nonce := p.pingNonce.Add(1)
+	p.lastPingAt.Store(now.UnixNano())
+	p.pingOutstanding.Store(true)
+	p.queue(&pb.HostToPlugin{
+		PluginId: p.id,
+		Payload: &pb.HostToPlugin_Ping{
+			Ping: &pb.HostPing{Nonce: nonce, SentUnixMs: now.UnixMilli()},
+		},
+	})
+}
+
+// handlePong records the round-trip time of the latest ping and restores dispatch to a plugin that
+// was previously marked unhealthy. Late pongs for older pings still count as a sign of life.
+func (p *pluginProcess) handlePong(pong *pb.PluginPong) {
+	if pong == nil {
+		return
+	}
+	if pong.Nonce == p.pingNonce.Load() {
+		rtt := time.Since(time.Unix(0, p.lastPingAt.Load()))
+		p.rtt.Store(int64(rtt))
+		p.pingOutstanding.Store(false)
+		p.log.Debug("heartbeat", "rtt_us", rtt.Microseconds())
+	}
+	p.answeredPing.Store(true)
+	p.missedPings.Store(0)
+	if p.unhealthy.CompareAndSwap(true, false) {
+		p.log.Info("plugin recovered, resuming event dispatch")
+	}
+}
+
+// resetHeartbeat clears heartbeat state when a fresh stream is attached.
+func (p *pluginProcess) resetHeartbeat() {
+	p.pingOutstanding.Store(false)
+	p.answeredPing.Store(false)
+	p.missedPings.Store(0)
+	p.unhealthy.Store(false)
+}
+
+// roundTrip returns the round-trip time measured by the most recently answered heartbeat.
+func (p *pluginProcess) roundTrip() time.Duration {
+	return time.Duration(p.rtt.Load())
+}
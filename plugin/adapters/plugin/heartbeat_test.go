@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func TestHeartbeat(t *testing.T) {
+	p := &pluginProcess{
+		id:     "heartbeat",
+		log:    slog.New(slog.DiscardHandler),
+		done:   make(chan struct{}),
+		sendCh: make(chan *pb.HostToPlugin, 16),
+	}
+	p.connected.Store(true)
+	p.wg.Add(1)
+	go p.heartbeatLoop(5*time.Millisecond, 2)
+	defer func() {
+		close(p.done)
+		p.wg.Wait()
+	}()
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for %s", what)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	answer := func() {
+		p.handlePong(&pb.PluginPong{Nonce: p.pingNonce.Load()})
+	}
+
+	// A plugin that never answered is not expected to.
+	waitFor("pings", func() bool { return p.pingNonce.Load() >= 5 })
+	if p.unhealthy.Load() {
+		t.Fatal("Expected a plugin that never answered a ping to stay healthy")
+	}
+
+	answer()
+	if p.roundTrip() <= 0 {
+		t.Error("Expected the round trip to be recorded")
+	}
+	waitFor("missed pings to mark the plugin unhealthy", p.unhealthy.Load)
+	if msg := <-p.sendCh; msg.GetPing() == nil {
+		t.Errorf("Expected pings to be sent, got %v", msg)
+	}
+
+	answer()
+	if p.unhealthy.Load() {
+		t.Error("Expected a pong to restore the plugin")
+	}
+}
+
+func TestUnhealthyPluginRetainsDurableEvents(t *testing.T) {
+	p := &pluginProcess{
+		id:     "unhealthy",
+		log:    slog.New(slog.DiscardHandler),
+		replay: newReplayBuffer(8),
+	}
+	p.ready.Store(true)
+	p.connected.Store(true)
+	p.unhealthy.Store(true)
+	p.updateSubscriptions([]pb.EventType{pb.EventType_EVENT_TYPE_ALL})
+	if !p.HasSubscription(pb.EventType_PLAYER_JOIN) {
+		t.Fatal("Expected an unhealthy plugin to keep its subscriptions")
+	}
+
+	p.queueEvent(&pb.EventEnvelope{EventId: "join", Type: pb.EventType_PLAYER_JOIN})
+	p.queueEvent(&pb.EventEnvelope{EventId: "chat", Type: pb.EventType_CHAT})
+	if len(p.eventBuffer) != 0 {
+		t.Errorf("Expected no events to be sent to an unhealthy plugin, got %v", p.eventBuffer)
+	}
+	events, _ := p.replay.since(0)
+	if len(events) != 1 || events[0].EventId != "join" {
+		t.Errorf("Expected the durable event to be retained for replay, got %v", events)
+	}
+}
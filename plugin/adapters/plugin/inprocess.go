@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/secmc/plugin/plugin/adapters/inprocess"
+	"github.com/secmc/plugin/plugin/adapters/wasm"
+	"github.com/secmc/plugin/plugin/adapters/webhook"
+	"github.com/secmc/plugin/plugin/config"
+)
+
+// InProcessFunc runs a plugin compiled into the server. It is called each time the plugin is
+// started with a dialer for connections to the host, and must return once ctx is cancelled.
+type InProcessFunc func(ctx context.Context, dial inprocess.Dialer) error
+
+// RegisterInProcess registers a plugin compiled into the server binary under id. It runs in the
+// host process and exchanges messages over channels instead of a transport, but is otherwise
+// managed like any other plugin: it is started with the manager, or at once if the manager is
+// already running, and can be restarted through the admin API. An entry for id in plugins.yaml
+// may set its name; it must not have a command.
+func (m *Manager) RegisterInProcess(id string, run InProcessFunc) {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	m.mu.Lock()
+	if m.inProcess == nil {
+		m.inProcess = make(map[string]InProcessFunc)
+	}
+	m.inProcess[id] = run
+	proc, configured := m.plugins[id]
+	started := m.serverAddress != ""
+	m.mu.Unlock()
+
+	switch {
+	case !started:
+	case configured:
+		m.replacePlugin(proc, proc.cfg)
+	default:
+		m.startPlugin(newPluginProcess(m, inProcessConfig(id)))
+	}
+}
+
+func inProcessConfig(id string) config.PluginConfig {
+	return config.PluginConfig{ID: id, Transport: "inprocess"}
+}
+
+// inProcessPlugin returns the function registered for id, or nil.
+func (m *Manager) inProcessPlugin(id string) InProcessFunc {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.inProcess[id]
+}
+
+// withInProcess adds a definition for every registered in-process plugin missing from plugins.
+func (m *Manager) withInProcess(plugins []config.PluginConfig) []config.PluginConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for id := range m.inProcess {
+		found := false
+		for _, pc := range plugins {
+			found = found || pc.ID == id
+		}
+		if !found {
+			plugins = append(plugins, inProcessConfig(id))
+		}
+	}
+	return plugins
+}
+
+// runsInProcess reports whether the plugin runs inside the host: compiled into the server, as a
+// WebAssembly module or as the bridge to a webhook.
+func (p *pluginProcess) runsInProcess() bool {
+	return p.inProcessRunner() != nil
+}
+
+// inProcessRunner returns the function that runs the plugin inside the host, or nil if it is a
+// separate process.
+func (p *pluginProcess) inProcessRunner() InProcessFunc {
+	if p.cfg.Webhook.URL != "" {
+		return func(ctx context.Context, dial inprocess.Dialer) error {
+			return webhook.Run(ctx, p.cfg, p.log, dial)
+		}
+	}
+	if p.cfg.IsWasm() {
+		return func(ctx context.Context, dial inprocess.Dialer) error {
+			return wasm.Run(ctx, p.cfg, p.log, dial)
+		}
+	}
+	if p.cfg.Command != "" {
+		return nil
+	}
+	return p.manager.inProcessPlugin(p.id)
+}
+
+// runInProcess starts run on its own goroutine until the plugin is stopped.
+func (p *pluginProcess) runInProcess(ctx context.Context, run InProcessFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-p.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer cancel()
+		if err := run(ctx, p.dialInProcess); err != nil && !p.closed.Load() {
+			p.log.Error("in-process plugin exited", "error", err)
+			p.recordError(fmt.Sprintf("in-process plugin exited: %v", err))
+			p.reportCrash(crash{reason: fmt.Sprintf("in-process plugin exited: %v", err)})
+		}
+	}()
+}
+
+// dialInProcess connects the plugin to the manager over a new pipe, closed when ctx is cancelled.
+func (p *pluginProcess) dialInProcess(ctx context.Context) (*inprocess.PluginStream, error) {
+	if p.closed.Load() {
+		return nil, errors.New("plugin is stopped")
+	}
+	hostEnd, pluginEnd := inprocess.Pipe()
+	context.AfterFunc(ctx, func() { _ = pluginEnd.Close() })
+	go func() {
+		if err := p.manager.acceptStream(hostEnd, p.id); err != nil && !p.closed.Load() && !errors.Is(err, context.Canceled) {
+			p.log.Error("in-process stream", "error", err)
+		}
+		_ = hostEnd.Close()
+	}()
+	return pluginEnd, nil
+}
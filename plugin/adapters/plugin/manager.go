@@ -12,6 +12,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/df-mc/dragonfly/server"
@@ -19,7 +22,12 @@ import (
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/google/uuid"
+	"github.com/secmc/plugin/plugin/adapters/admin"
 	"github.com/secmc/plugin/plugin/adapters/grpc"
+	"github.com/secmc/plugin/plugin/adapters/shm"
+	"github.com/secmc/plugin/plugin/adapters/tracing"
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/adapters/websocket"
 	"github.com/secmc/plugin/plugin/config"
 	"github.com/secmc/plugin/plugin/ports"
 	pb "github.com/secmc/plugin/proto/generated/go"
@@ -33,6 +41,8 @@ type Manager struct {
 	cancel context.CancelFunc
 
 	grpcServer *grpc.GrpcServer
+	wsServer   *websocket.Server
+	shmServer  *shm.Server
 
 	mu       sync.RWMutex
 	plugins  map[string]*pluginProcess
@@ -52,6 +62,47 @@ type Manager struct {
 	worldHandlerFactory  ports.WorldHandlerFactory
 
 	bootID string
+
+	replayBufferSize int
+
+	heartbeatInterval    time.Duration
+	heartbeatMissedLimit int
+
+	peerCheck string
+
+	customMetricsLimit int
+	customSeriesLimit  int
+
+	traceShutdown func(context.Context) error
+
+	adminServer *admin.Server
+	// configPath and serverAddress let the admin API reload the config and relaunch plugins.
+	configPath    string
+	serverAddress string
+	// registryCache is the game registry sent to plugins, rebuilt after custom content changes.
+	registryMu    sync.Mutex
+	registryCache *pb.Registry
+	// query answers query actions and serves the Query gRPC service.
+	query *queryService
+
+	// operators may use the in-game /plugins command.
+	operators []string
+	// lifecycleMu serializes starting, stopping and reloading plugins through the admin API, and
+	// updates of plugins fetched from git.
+	lifecycleMu sync.Mutex
+	// recording selects the plugin connections whose traffic is recorded.
+	recording config.RecordingConfig
+	// crashReports sets where crash reports go and how much history they include.
+	crashReports config.CrashReportConfig
+	// helloTimeout is how long an updated plugin has to send its hello before it is rolled back.
+	helloTimeout time.Duration
+	// rejectedCommits holds, by plugin ID, the last commit an update failed to build or start, so
+	// it is not tried again.
+	rejectedCommits sync.Map
+	// actionObserver is called with every action a plugin sends, see ObserveActions.
+	actionObserver atomic.Pointer[func(pluginID string, action *pb.Action)]
+	// inProcess holds the plugins compiled into the server, see RegisterInProcess.
+	inProcess map[string]InProcessFunc
 }
 
 func (m *Manager) logEventLatency(eventType pb.EventType, eventID string, pluginID string, duration time.Duration, metricType string) {
@@ -80,14 +131,14 @@ type commandBinding struct {
 	descriptor *pb.CommandSpec
 }
 
-const eventResponseTimeout = 250 * time.Millisecond
+const eventResponseTimeout = ports.EventResponseTimeout
 
 func NewManager(srv *server.Server, log *slog.Logger, playerHandlerFactory ports.PlayerHandlerFactory, worldHandlerFactory ports.WorldHandlerFactory) *Manager {
 	if log == nil {
 		log = slog.Default()
 	}
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Manager{
+	m := &Manager{
 		srv:                  srv,
 		log:                  log.With("component", "plugin-manager"),
 		ctx:                  ctx,
@@ -102,6 +153,8 @@ func NewManager(srv *server.Server, log *slog.Logger, playerHandlerFactory ports
 		worldHandlerFactory:  worldHandlerFactory,
 		bootID:               uuid.NewString(),
 	}
+	m.query = &queryService{m: m}
+	return m
 }
 
 func (m *Manager) Start(configPath string) error {
@@ -118,13 +171,44 @@ func (m *Manager) Start(configPath string) error {
 
 // StartWithConfig starts the plugin adapter using a pre-loaded plugin config.
 func (m *Manager) StartWithConfig(cfg config.Config) error {
+	m.replayBufferSize = cfg.ReplayBufferSize
+	m.heartbeatInterval = time.Duration(cfg.HeartbeatIntervalMs) * time.Millisecond
+	m.heartbeatMissedLimit = cfg.HeartbeatMissedLimit
+	m.peerCheck = cfg.PeerCheck
+	m.customMetricsLimit = cfg.CustomMetricsLimit
+	m.customSeriesLimit = cfg.CustomSeriesLimit
+	m.configPath = cfg.Path
+	m.operators = cfg.Operators
+	m.recording = cfg.Recording
+	m.crashReports = cfg.CrashReports
+	m.helloTimeout = time.Duration(cfg.HelloTimeoutMs) * time.Millisecond
+	if m.helloTimeout <= 0 {
+		m.helloTimeout = defaultHelloTimeout
+	}
+
+	mode, err := unixsocket.ParseMode(cfg.SocketMode)
+	if err != nil {
+		return err
+	}
+	perms := unixsocket.Permissions{Mode: mode, Owner: cfg.SocketOwner, Group: cfg.SocketGroup}
+
+	traceShutdown, err := tracing.Setup(m.ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("set up tracing: %w", err)
+	}
+	m.traceShutdown = traceShutdown
+	if cfg.Tracing.Exporter != "" {
+		m.log.Info("tracing enabled", "exporter", cfg.Tracing.Exporter, "sample_ratio", cfg.Tracing.SampleRatio)
+	}
+
 	// Start gRPC server to accept plugin connections
 	address := cfg.ServerPort
-	grpcServer, err := grpc.NewServer(address, m.handlePluginConnection)
+	grpcServer, err := grpc.NewServer(address, perms, m.handlePluginConnection, m.query)
 	if err != nil {
 		return fmt.Errorf("start plugin server: %w", err)
 	}
 	m.grpcServer = grpcServer
+	m.serverAddress = grpcServer.Address()
 	m.log.Info("plugin server listening", "address", grpcServer.Address())
 
 	// Start accepting connections in background
@@ -134,8 +218,52 @@ func (m *Manager) StartWithConfig(cfg config.Config) error {
 		}
 	}()
 
+	if cfg.WebSocketAddress != "" {
+		wsServer, err := websocket.NewServer(cfg.WebSocketAddress, cfg.WebSocketOrigins, m.handlePluginConnection)
+		if err != nil {
+			return fmt.Errorf("start websocket plugin server: %w", err)
+		}
+		m.wsServer = wsServer
+		m.log.Info("websocket plugin server listening", "address", wsServer.Address())
+		go func() {
+			if err := wsServer.Serve(); err != nil {
+				m.log.Error("websocket plugin server error", "error", err)
+			}
+		}()
+	}
+
+	if cfg.ShmSocket != "" {
+		shmServer, err := shm.NewServer(cfg.ShmSocket, perms, m.handlePluginConnection)
+		if err != nil {
+			return fmt.Errorf("start shared-memory plugin server: %w", err)
+		}
+		m.shmServer = shmServer
+		m.log.Info("shared-memory plugin server listening", "address", shmServer.Address())
+		go func() {
+			if err := shmServer.Serve(); err != nil {
+				m.log.Error("shared-memory plugin server error", "error", err)
+			}
+		}()
+	}
+
+	if cfg.AdminAddress != "" {
+		adminServer, err := admin.NewServer(cfg.AdminAddress, cfg.AdminToken, m)
+		if err != nil {
+			return fmt.Errorf("start admin server: %w", err)
+		}
+		m.adminServer = adminServer
+		m.log.Info("admin API listening", "address", adminServer.Address())
+		go func() {
+			if err := adminServer.Serve(); err != nil {
+				m.log.Error("admin API error", "error", err)
+			}
+		}()
+	}
+
+	m.registerPluginsCommand()
+
 	// Launch plugin processes
-	for _, pc := range cfg.Plugins {
+	for _, pc := range m.withInProcess(cfg.Plugins) {
 		if pc.ID == "" {
 			pc.ID = pc.Name
 		}
@@ -151,24 +279,33 @@ func (m *Manager) StartWithConfig(cfg config.Config) error {
 	return nil
 }
 
-// handlePluginConnection is called when a plugin connects to the gRPC server
-func (m *Manager) handlePluginConnection(stream *grpc.GrpcStream) error {
+// handlePluginConnection is called when a plugin connects over any transport
+func (m *Manager) handlePluginConnection(stream ports.Stream) error {
+	return m.acceptStream(stream, "")
+}
 
+// Accept attaches a plugin connected over a transport the manager did not start, such as an
+// in-memory stream in tests. It blocks until the plugin stops or the manager is closed.
+func (m *Manager) Accept(stream ports.Stream) error {
+	return m.handlePluginConnection(stream)
+}
+
+// acceptStream identifies the plugin behind stream from its first message and attaches it. If
+// expectedID is set, the stream belongs to a known process and may only identify as that plugin.
+func (m *Manager) acceptStream(stream ports.Stream, expectedID string) error {
 	// Read the first message to identify the plugin
-	data, err := stream.Recv()
+	msg, err := recvFirst(stream)
 	if err != nil {
-		return fmt.Errorf("receive first message: %w", err)
-	}
-
-	msg := &pb.PluginToHost{}
-	if err := proto.Unmarshal(data, msg); err != nil {
-		return fmt.Errorf("decode first message: %w", err)
+		return err
 	}
 
 	pluginID := msg.PluginId
 	if pluginID == "" {
 		return errors.New("first message missing plugin_id")
 	}
+	if expectedID != "" && pluginID != expectedID {
+		return fmt.Errorf("plugin %s identified as %s", expectedID, pluginID)
+	}
 
 	// Find the plugin process
 	m.mu.RLock()
@@ -178,6 +315,24 @@ func (m *Manager) handlePluginConnection(stream *grpc.GrpcStream) error {
 	if !ok {
 		return fmt.Errorf("unknown plugin ID: %s", pluginID)
 	}
+	if err := proc.verifyPeer(stream, msg.GetHello()); err != nil {
+		m.log.Warn("rejected plugin connection", "plugin", pluginID, "error", err)
+		return err
+	}
+	if rec := m.startRecording(stream, pluginID, msg); rec != nil {
+		stream = rec
+		defer rec.closeRecording()
+	}
+	if hello := msg.GetHello(); hello != nil {
+		s, err := negotiate(hello)
+		if err != nil {
+			m.log.Warn("refused plugin", "plugin", pluginID, "error", err)
+			proc.recordError(err.Error())
+			m.refuse(stream, pluginID, err)
+			return err
+		}
+		proc.session.Store(s)
+	}
 
 	// Handle the first message (likely PluginHello)
 	m.handlePluginMessage(proc, msg)
@@ -186,6 +341,7 @@ func (m *Manager) handlePluginConnection(stream *grpc.GrpcStream) error {
 	if err := proc.attachStream(stream); err != nil {
 		return fmt.Errorf("attach stream: %w", err)
 	}
+	proc.resumeSession(msg.GetHello().GetResume())
 
 	// Block until the stream is closed (the process will handle recv loop)
 	select {
@@ -196,13 +352,43 @@ func (m *Manager) handlePluginConnection(stream *grpc.GrpcStream) error {
 	}
 }
 
+// recvFirst reads the message a plugin identifies itself with.
+func recvFirst(stream ports.Stream) (*pb.PluginToHost, error) {
+	if ms, ok := stream.(ports.MessageStream); ok {
+		msg, err := ms.RecvMessage()
+		if err != nil {
+			return nil, fmt.Errorf("receive first message: %w", err)
+		}
+		return msg, nil
+	}
+	data, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("receive first message: %w", err)
+	}
+	msg := &pb.PluginToHost{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("decode first message: %w", err)
+	}
+	return msg, nil
+}
+
 func (m *Manager) Close() {
 	m.cancel()
 
+	if m.adminServer != nil {
+		m.adminServer.Stop()
+	}
+
 	// Stop gRPC server
 	if m.grpcServer != nil {
 		m.grpcServer.Stop()
 	}
+	if m.wsServer != nil {
+		m.wsServer.Stop()
+	}
+	if m.shmServer != nil {
+		m.shmServer.Stop()
+	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -217,6 +403,14 @@ func (m *Manager) Close() {
 		proc.Stop()
 	}
 	m.plugins = make(map[string]*pluginProcess)
+
+	if m.traceShutdown != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := m.traceShutdown(ctx); err != nil {
+			m.log.Warn("failed to flush traces", "error", err)
+		}
+	}
 }
 
 func (m *Manager) AttachWorld(w *world.World) {
@@ -273,6 +467,9 @@ func (m *Manager) broadcastEvent(ctx context.Context, envelope *pb.EventEnvelope
 		}
 	}
 
+	_, span := m.startEventSpan(ctx, envelope, startTime)
+	defer span.End()
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -304,6 +501,8 @@ func (m *Manager) dispatchEvent(ctx context.Context, envelope *pb.EventEnvelope,
 			}
 		}
 	}
+	ctx, span := m.startEventSpan(ctx, envelope, overallStartTime)
+	defer span.End()
 
 	m.mu.RLock()
 	procs := make([]*pluginProcess, 0, len(m.plugins))
@@ -322,32 +521,51 @@ func (m *Manager) dispatchEvent(ctx context.Context, envelope *pb.EventEnvelope,
 
 	results := make([]*pb.EventResult, 0, len(procs))
 	for _, proc := range procs {
+		event := proc.retainEvent(envelope)
+		if !proc.receiving() {
+			// Nobody can answer; durable events were retained above for replay on resume.
+			continue
+		}
+		expect := expectResult
+		if proc.observing() {
+			event, expect = observed(event), false
+		}
 		var waitCh chan *pb.EventResult
-		if expectResult {
+		if expect {
 			waitCh = proc.expectEventResult(envelope.EventId)
 		}
 
 		msg := &pb.HostToPlugin{
 			PluginId: proc.id,
 			Payload: &pb.HostToPlugin_Event{
-				Event: envelope,
+				Event: event,
 			},
 		}
 		dispatchStart := time.Now()
 		proc.log.Debug("sending event", "event_id", envelope.EventId, "type", envelope.Type.String())
-		proc.queue(msg)
+		_, queueSpan := tracing.Tracer().Start(ctx, "queue", trace.WithAttributes(attribute.String("plugin.id", proc.id)))
+		sent := proc.queue(msg)
+		queueSpan.End()
+		proc.metrics.EventQueued(event, sent)
 		m.logEventLatency(eventType, envelope.EventId, proc.id, time.Since(dispatchStart), "dispatch_queue")
 
-		if !expectResult {
+		if !expect {
+			continue
+		}
+		if !sent {
+			proc.discardEventResult(envelope.EventId)
 			continue
 		}
 
 		waitStart := time.Now()
+		waitCtx, waitSpan := tracing.Tracer().Start(ctx, "wait", trace.WithAttributes(attribute.String("plugin.id", proc.id)))
 		res, err := proc.waitEventResult(waitCh, eventResponseTimeout)
+		endWaitSpan(waitCtx, waitSpan, res, err)
 		pluginResponseTime := time.Since(waitStart)
 
 		if err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
+				proc.metrics.EventTimeout(eventType)
 				proc.log.Warn("plugin did not respond to event",
 					"event_id", envelope.EventId,
 					"type", envelope.Type.String(),
@@ -359,6 +577,7 @@ func (m *Manager) dispatchEvent(ctx context.Context, envelope *pb.EventEnvelope,
 		}
 		if res != nil {
 			results = append(results, res)
+			proc.metrics.EventRoundTrip(eventType, pluginResponseTime)
 			m.logEventLatency(eventType, envelope.EventId, proc.id, pluginResponseTime, "plugin_response")
 		}
 	}
@@ -384,6 +603,8 @@ func (m *Manager) dispatchEventParallel(ctx context.Context, envelope *pb.EventE
 			}
 		}
 	}
+	ctx, span := m.startEventSpan(ctx, envelope, overallStartTime)
+	defer span.End()
 
 	m.mu.RLock()
 	procs := make([]*pluginProcess, 0, len(m.plugins))
@@ -406,37 +627,59 @@ func (m *Manager) dispatchEventParallel(ctx context.Context, envelope *pb.EventE
 		wg.Add(1)
 		go func(idx int, proc *pluginProcess) {
 			defer wg.Done()
+			event := proc.retainEvent(envelope)
+			if !proc.receiving() {
+				// Nobody can answer; durable events were retained above for replay on resume.
+				return
+			}
+			expect := expectResult
+			if proc.observing() {
+				event, expect = observed(event), false
+			}
 			var waitCh chan *pb.EventResult
-			if expectResult {
+			if expect {
 				waitCh = proc.expectEventResult(envelope.EventId)
 			}
 
 			msg := &pb.HostToPlugin{
 				PluginId: proc.id,
 				Payload: &pb.HostToPlugin_Event{
-					Event: envelope,
+					Event: event,
 				},
 			}
 			dispatchStart := time.Now()
 			proc.log.Debug("sending event", "event_id", envelope.EventId, "type", envelope.Type.String())
-			proc.queue(msg)
+			_, queueSpan := tracing.Tracer().Start(ctx, "queue", trace.WithAttributes(attribute.String("plugin.id", proc.id)))
+			sent := proc.queue(msg)
+			queueSpan.End()
+			proc.metrics.EventQueued(event, sent)
 			m.logEventLatency(eventType, envelope.EventId, proc.id, time.Since(dispatchStart), "dispatch_queue") // Log dispatch queue time
 
-			if !expectResult {
+			if !expect {
+				return
+			}
+			if !sent {
+				proc.discardEventResult(envelope.EventId)
 				return
 			}
 			waitStart := time.Now()
+			waitCtx, waitSpan := tracing.Tracer().Start(ctx, "wait", trace.WithAttributes(attribute.String("plugin.id", proc.id)))
 			res, err := proc.waitEventResult(waitCh, eventResponseTimeout)
+			endWaitSpan(waitCtx, waitSpan, res, err)
 			pluginResponseTime := time.Since(waitStart)
 
 			if err != nil {
 				if errors.Is(err, context.DeadlineExceeded) {
+					proc.metrics.EventTimeout(eventType)
 					proc.log.Warn("plugin did not respond to event", "event_id", envelope.EventId, "type", envelope.Type.String())
 				}
 				proc.discardEventResult(envelope.EventId)
 				m.logEventLatency(eventType, envelope.EventId, proc.id, pluginResponseTime, "plugin_response_error") // Log error response
 				return
 			}
+			if res != nil {
+				proc.metrics.EventRoundTrip(eventType, pluginResponseTime)
+			}
 			m.logEventLatency(eventType, envelope.EventId, proc.id, pluginResponseTime, "plugin_response") // Log actual plugin response time
 			results[idx] = res
 		}(idx, proc)
@@ -497,10 +740,38 @@ func applyMutations[T any, P PtrTo[T]](
 		if mut == nil {
 			continue
 		}
+		_, span := tracing.Tracer().Start(tracing.Extract(context.Background(), res.GetTrace()), "apply mutation",
+			trace.WithAttributes(attribute.String("event.id", res.GetEventId())))
 		applier(mut)
+		span.End()
 	}
 }
 
+// startEventSpan starts the root span of an event dispatch and stores its trace context in the
+// envelope so plugins can continue the trace.
+func (m *Manager) startEventSpan(ctx context.Context, envelope *pb.EventEnvelope, start time.Time) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "event "+envelope.Type.String(),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attribute.String("event.id", envelope.EventId)),
+	)
+	envelope.Trace = tracing.Inject(ctx)
+	return ctx, span
+}
+
+// endWaitSpan ends the span covering a wait for one plugin's result. If the plugin did not continue
+// the trace itself, its mutations are parented to the wait span.
+func endWaitSpan(ctx context.Context, span trace.Span, res *pb.EventResult, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	} else if res != nil && res.Trace == nil {
+		res.Trace = tracing.Inject(ctx)
+	}
+	span.End()
+}
+
 // mutateField applies a single field mutation if both pointers are non-nil.
 func mutateField[T any](dest *T, src *T) {
 	if dest != nil && src != nil {
@@ -654,6 +925,8 @@ func (m *Manager) handlePluginMessage(p *pluginProcess, msg *pb.PluginToHost) {
 		}
 		m.log.Info(fmt.Sprintf("  %s subscribed to %d events", pluginName, len(eventNames)), "events", eventNames)
 		p.updateSubscriptions(subscribe.Events)
+	case *pb.PluginToHost_Pong:
+		p.handlePong(payload.Pong)
 	case *pb.PluginToHost_Actions:
 		p.queueActions(payload.Actions)
 	case *pb.PluginToHost_Log:
@@ -667,12 +940,24 @@ func (m *Manager) handlePluginMessage(p *pluginProcess, msg *pb.PluginToHost) {
 		default:
 			p.log.Info(logMsg.Message)
 		}
+	case *pb.PluginToHost_Metrics:
+		if !p.session.Load().has(CapabilityCustomMetrics) {
+			p.log.Debug("ignoring metrics", "reason", "capability not negotiated")
+			return
+		}
+		for _, err := range p.customMetrics.Apply(payload.Metrics) {
+			p.log.Warn("rejected plugin metric", "error", err)
+		}
+	case *pb.PluginToHost_Registry:
+		m.sendRegistry(p, payload.Registry)
 	case *pb.PluginToHost_ServerInfo:
 		var pluginNames []string
 
+		m.mu.RLock()
 		for _, pl := range m.plugins {
 			pluginNames = append(pluginNames, pl.cfg.Name)
 		}
+		m.mu.RUnlock()
 		p.sendServerInfo(pluginNames)
 	default:
 		p.log.Info(fmt.Sprintf("unhandled event: %#v", payload))
@@ -0,0 +1,603 @@
+package plugin_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/protobuf/proto"
+
+	sdk "github.com/secmc/plugin/packages/go/plugin"
+	"github.com/secmc/plugin/plugin/adapters/inprocess"
+	"github.com/secmc/plugin/plugin/adapters/plugin/plugintest"
+	"github.com/secmc/plugin/plugin/adapters/tracing"
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func newHost(t *testing.T, ids ...string) *plugintest.Host {
+	cfg := config.Config{}
+	for _, id := range ids {
+		cfg.Plugins = append(cfg.Plugins, config.PluginConfig{ID: id})
+	}
+	return plugintest.NewHost(t, cfg)
+}
+
+func chatMutation(message string) *pb.EventResult {
+	return &pb.EventResult{Update: &pb.EventResult_Chat{Chat: &pb.ChatMutation{Message: proto.String(message)}}}
+}
+
+// emitChat sends msg as a chat message from the player and returns the message after plugins
+// have handled it, and whether they cancelled it.
+func emitChat(h *plugintest.Host, handle *world.EntityHandle, msg string) (string, bool) {
+	var cancelled bool
+	h.WithPlayer(handle, func(_ *world.Tx, p *player.Player) {
+		ctx := event.C(p)
+		h.Manager.EmitChat(ctx, p, &msg)
+		cancelled = ctx.Cancelled()
+	})
+	return msg, cancelled
+}
+
+// waitStatus waits until the status of plugin id satisfies cond and returns it.
+func waitStatus(t *testing.T, h *plugintest.Host, id, what string, cond func(st ports.PluginStatus) bool) ports.PluginStatus {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		st, err := h.Manager.PluginStatus(id)
+		if err == nil && cond(st) {
+			return st
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s, got %+v (%v)", what, st, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestChatMutation(t *testing.T) {
+	h := newHost(t, "chat")
+	fp := h.Connect("chat")
+	if hello := fp.Hello(nil); hello.GetApiVersion() == "" {
+		t.Fatalf("Expected negotiated API version, got %v", hello)
+	}
+	fp.Subscribe(pb.EventType_CHAT)
+	fp.Answer(pb.EventType_CHAT, plugintest.Respond(chatMutation("changed")))
+
+	steve := h.AddPlayer("Steve")
+	msg, cancelled := emitChat(h, steve, "hello")
+	if cancelled || msg != "changed" {
+		t.Errorf("Expected mutated message, got %q (cancelled %v)", msg, cancelled)
+	}
+	if ev := fp.Event(pb.EventType_CHAT); ev.GetChat().GetMessage() != "hello" || ev.GetChat().GetName() != "Steve" {
+		t.Errorf("Unexpected chat event %v", ev)
+	}
+}
+
+func TestCancelDiscardsMutations(t *testing.T) {
+	h := newHost(t, "mutator", "canceller")
+	mutator, canceller := h.Connect("mutator"), h.Connect("canceller")
+	for _, fp := range []*plugintest.FakePlugin{mutator, canceller} {
+		fp.Hello(nil)
+		fp.Subscribe(pb.EventType_CHAT)
+	}
+	mutator.Answer(pb.EventType_CHAT, plugintest.Respond(chatMutation("changed")))
+	canceller.Answer(pb.EventType_CHAT, plugintest.Cancel())
+
+	steve := h.AddPlayer("Steve")
+	if msg, cancelled := emitChat(h, steve, "hello"); !cancelled || msg != "hello" {
+		t.Errorf("Expected cancelled and unchanged message, got %q (cancelled %v)", msg, cancelled)
+	}
+
+	// Without the cancel the mutation applies again.
+	canceller.Answer(pb.EventType_CHAT, nil)
+	if msg, cancelled := emitChat(h, steve, "hello"); cancelled || msg != "changed" {
+		t.Errorf("Expected mutated message, got %q (cancelled %v)", msg, cancelled)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	h := newHost(t, "traced")
+	fp := h.Connect("traced")
+	fp.Hello(nil)
+	fp.Subscribe(pb.EventType_CHAT)
+	// The plugin continues the trace of the event and returns its own span with the result.
+	fp.Answer(pb.EventType_CHAT, func(ev *pb.EventEnvelope) *pb.EventResult {
+		ctx, span := provider.Tracer("plugin").Start(tracing.Extract(context.Background(), ev.GetTrace()), "handle chat")
+		defer span.End()
+		res := chatMutation("traced")
+		res.Trace = tracing.Inject(ctx)
+		return res
+	})
+
+	steve := h.AddPlayer("Steve")
+	if msg, _ := emitChat(h, steve, "hello"); msg != "traced" {
+		t.Fatalf("Expected mutated message, got %q", msg)
+	}
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	dispatch, handler, mutation := spans["event CHAT"], spans["handle chat"], spans["apply mutation"]
+	if dispatch == nil || handler == nil || mutation == nil {
+		t.Fatalf("Expected dispatch, plugin and mutation spans, got %v", slices.Collect(maps.Keys(spans)))
+	}
+	if handler.Parent().SpanID() != dispatch.SpanContext().SpanID() {
+		t.Error("Expected the plugin's span to be a child of the dispatch span")
+	}
+	if mutation.Parent().SpanID() != handler.SpanContext().SpanID() {
+		t.Error("Expected the mutation span to be a child of the plugin's span")
+	}
+	if mutation.SpanContext().TraceID() != dispatch.SpanContext().TraceID() {
+		t.Error("Expected all spans to share the dispatch span's trace")
+	}
+}
+
+func TestObserverIsNotWaitedOn(t *testing.T) {
+	h := newHost(t, "chat", "observer")
+	fp, observer := h.Connect("chat"), h.Connect("observer")
+	fp.Hello(nil)
+	fp.Subscribe(pb.EventType_CHAT)
+	fp.Answer(pb.EventType_CHAT, plugintest.Respond(chatMutation("changed")))
+	observer.Hello(&pb.PluginHello{ApiVersions: []string{"v1"}, RequiredCapabilities: []string{"observer"}})
+	observer.Subscribe(pb.EventType_EVENT_TYPE_ALL)
+	// Observers are never asked, so this cancel must not take effect.
+	observer.Answer(pb.EventType_CHAT, plugintest.Cancel())
+
+	steve := h.AddPlayer("Steve")
+	if msg, cancelled := emitChat(h, steve, "hello"); cancelled || msg != "changed" {
+		t.Errorf("Expected the observer to be ignored, got %q (cancelled %v)", msg, cancelled)
+	}
+	if ev := observer.Event(pb.EventType_CHAT); ev.ExpectsResponse || ev.GetChat().GetMessage() != "hello" {
+		t.Errorf("Expected the chat event without a response requested, got %v", ev)
+	}
+}
+
+func TestWorldActions(t *testing.T) {
+	h := newHost(t, "world")
+	fp := h.Connect("world")
+	fp.Hello(nil)
+	fp.Subscribe()
+
+	overworld := &pb.WorldRef{Dimension: "overworld"}
+	fp.Act(
+		&pb.Action{CorrelationId: proto.String("time"), Kind: &pb.Action_WorldSetTime{WorldSetTime: &pb.WorldSetTimeAction{World: overworld, Time: 1000}}},
+		&pb.Action{CorrelationId: proto.String("missing"), Kind: &pb.Action_WorldSetTime{WorldSetTime: &pb.WorldSetTimeAction{World: &pb.WorldRef{Name: "missing"}}}},
+		&pb.Action{CorrelationId: proto.String("players"), Kind: &pb.Action_WorldQueryPlayers{WorldQueryPlayers: &pb.WorldQueryPlayersAction{World: overworld}}},
+	)
+
+	if res := fp.ActionResult("time"); !res.GetStatus().GetOk() {
+		t.Errorf("Expected set time to succeed, got %v", res)
+	}
+	if got := h.World.Time(); got != 1000 {
+		t.Errorf("Expected time 1000, got %d", got)
+	}
+	if res := fp.ActionResult("missing"); res.GetStatus().GetOk() || res.GetStatus().GetError() == "" {
+		t.Errorf("Expected unknown world to fail, got %v", res)
+	}
+	if res := fp.ActionResult("players"); !res.GetStatus().GetOk() || len(res.GetWorldPlayers().GetPlayers()) != 0 {
+		t.Errorf("Expected no players, got %v", res)
+	}
+}
+
+// blockActions returns the world_set_block actions counted for plugin with the given result.
+func blockActions(t *testing.T, plugin, result string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if !strings.HasSuffix(f.GetName(), "_actions_total") {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["plugin"] == plugin && labels["kind"] == "world_set_block" && labels["result"] == result {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestSetBlockBatch(t *testing.T) {
+	h := newHost(t, "blocks")
+	fp := h.Connect("blocks")
+	fp.Hello(nil)
+	fp.Subscribe()
+
+	overworld := &pb.WorldRef{Dimension: "overworld"}
+	setBlock := func(id string, pos *pb.BlockPos, block *pb.BlockState) *pb.Action {
+		return &pb.Action{CorrelationId: proto.String(id), Kind: &pb.Action_WorldSetBlock{WorldSetBlock: &pb.WorldSetBlockAction{
+			World: overworld, Position: pos, Block: block,
+		}}}
+	}
+	applied, failed := blockActions(t, "blocks", "ok"), blockActions(t, "blocks", "failed")
+	// The failing actions come first, so that outcomes cannot be matched to actions by count.
+	fp.Act(
+		setBlock("unknown", &pb.BlockPos{X: 1, Y: 1, Z: 1}, &pb.BlockState{}),
+		setBlock("air", &pb.BlockPos{X: 2, Y: 1, Z: 2}, nil),
+		setBlock("nowhere", nil, nil),
+	)
+
+	if res := fp.ActionResult("unknown"); res.GetStatus().GetOk() || res.GetStatus().GetError() != "unknown block" {
+		t.Errorf("Expected the unknown block to fail, got %v", res)
+	}
+	if res := fp.ActionResult("air"); !res.GetStatus().GetOk() {
+		t.Errorf("Expected the block to be cleared, got %v", res)
+	}
+	if res := fp.ActionResult("nowhere"); res.GetStatus().GetOk() || res.GetStatus().GetError() != "missing position" {
+		t.Errorf("Expected the block without a position to fail, got %v", res)
+	}
+	// Results are sent before the outcomes are counted.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		gotApplied, gotFailed := blockActions(t, "blocks", "ok")-applied, blockActions(t, "blocks", "failed")-failed
+		if gotApplied == 1 && gotFailed == 2 {
+			break
+		}
+		if gotApplied > 1 || gotFailed > 2 || time.Now().After(deadline) {
+			t.Fatalf("Expected 1 applied and 2 failed actions to be counted, got %v and %v", gotApplied, gotFailed)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	h := plugintest.NewHost(t, config.Config{
+		HeartbeatIntervalMs:  20,
+		HeartbeatMissedLimit: 2,
+		Plugins:              []config.PluginConfig{{ID: "legacy"}, {ID: "pinged"}},
+	})
+	// A plugin that does not answer pings is never paused, so it keeps receiving events.
+	legacy := h.Connect("legacy")
+	legacy.IgnorePings(true)
+	legacy.Hello(nil)
+	legacy.Subscribe(pb.EventType_CHAT)
+	pinged := h.Connect("pinged")
+	pinged.Hello(&pb.PluginHello{ApiVersions: []string{"v1"}, Capabilities: []string{"heartbeat"}})
+	pinged.Subscribe(pb.EventType_CHAT)
+	waitStatus(t, h, "pinged", "a heartbeat round trip", func(st ports.PluginStatus) bool { return st.RTTMillis > 0 })
+
+	pinged.IgnorePings(true)
+	waitStatus(t, h, "pinged", "missed pings to mark the plugin unhealthy", func(st ports.PluginStatus) bool { return !st.Healthy })
+	steve := h.AddPlayer("Steve")
+	emitChat(h, steve, "hello")
+	legacy.Event(pb.EventType_CHAT)
+	for _, ev := range pinged.Events() {
+		if ev.Type == pb.EventType_CHAT {
+			t.Error("Expected no events to be sent to an unhealthy plugin")
+		}
+	}
+	if st, _ := h.Manager.PluginStatus("legacy"); !st.Healthy || st.RTTMillis != 0 {
+		t.Errorf("Expected the legacy plugin to stay healthy without a round trip, got %+v", st)
+	}
+
+	pinged.IgnorePings(false)
+	waitStatus(t, h, "pinged", "the plugin to recover", func(st ports.PluginStatus) bool { return st.Healthy })
+	emitChat(h, steve, "again")
+	if ev := pinged.Event(pb.EventType_CHAT); ev.GetChat().GetMessage() != "again" {
+		t.Errorf("Expected events to resume after recovery, got %v", ev)
+	}
+}
+
+func TestInProcessPlugin(t *testing.T) {
+	h := newHost(t)
+	h.Manager.RegisterInProcess("greeter", func(ctx context.Context, dial inprocess.Dialer) error {
+		p, err := sdk.New(sdk.WithID("greeter"), sdk.WithLogger(slog.New(slog.DiscardHandler)),
+			sdk.WithDialer(func(ctx context.Context) (sdk.Stream, error) { return dial(ctx) }))
+		if err != nil {
+			return err
+		}
+		sdk.On(p, func(e *sdk.Event, chat *pb.ChatEvent) {
+			switch chat.Message {
+			case "cancel":
+				e.Cancel()
+			case "time":
+				_, err := p.Do(e.Context(), &pb.Action{Kind: &pb.Action_WorldSetTime{WorldSetTime: &pb.WorldSetTimeAction{
+					World: &pb.WorldRef{Dimension: "overworld"}, Time: 1000,
+				}}})
+				if err == nil {
+					e.Mutate(sdk.ChatMessage("time set"))
+				}
+			default:
+				e.Mutate(sdk.ChatMessage("[in-process] " + chat.Message))
+			}
+		})
+		return p.Run(ctx)
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		st, err := h.Manager.PluginStatus("greeter")
+		if err == nil && st.State == "ready" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the in-process plugin to become ready, got %+v (%v)", st, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	steve := h.AddPlayer("Steve")
+	if msg, cancelled := emitChat(h, steve, "hello"); cancelled || msg != "[in-process] hello" {
+		t.Errorf("Expected mutated message, got %q (cancelled %v)", msg, cancelled)
+	}
+	if _, cancelled := emitChat(h, steve, "cancel"); !cancelled {
+		t.Error("Expected the message to be cancelled")
+	}
+	if msg, _ := emitChat(h, steve, "time"); msg != "time set" || h.World.Time() != 1000 {
+		t.Errorf("Expected the action to apply, got %q and time %d", msg, h.World.Time())
+	}
+
+	// A gRPC connection cannot claim the in-process plugin's ID.
+	fp := h.Connect("greeter")
+	fp.Send(&pb.PluginToHost{Payload: &pb.PluginToHost_Hello{Hello: &pb.PluginHello{Name: "impostor"}}})
+	fp.WaitClosed()
+	if hello := fp.HostHello(); hello != nil {
+		t.Errorf("Expected the impostor to be refused, got %v", hello)
+	}
+	if st, _ := h.Manager.PluginStatus("greeter"); st.State != "ready" {
+		t.Errorf("Expected the in-process plugin to stay connected, got %+v", st)
+	}
+	if err := h.Manager.RestartPlugin("greeter"); err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, h, "greeter", "the restarted plugin to be ready", func(st ports.PluginStatus) bool { return st.State == "ready" })
+	if msg, _ := emitChat(h, steve, "again"); msg != "[in-process] again" {
+		t.Errorf("Expected the restarted plugin to answer, got %q", msg)
+	}
+}
+
+// TestCrashHelper is the plugin process of TestCrashReport: it writes to stderr once the test
+// creates the file named by CRASH_HELPER and exits with the code in CRASH_CODE.
+func TestCrashHelper(t *testing.T) {
+	path := os.Getenv("CRASH_HELPER")
+	if path == "" {
+		t.Skip("run by TestCrashReport")
+	}
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	fmt.Fprintln(os.Stderr, "panic: boom")
+	code, _ := strconv.Atoi(os.Getenv("CRASH_CODE"))
+	os.Exit(code)
+}
+
+func TestCrashReport(t *testing.T) {
+	// An exit the host did not ask for is a crash even if the status is 0.
+	for _, code := range []int{3, 0} {
+		t.Run(fmt.Sprintf("code %d", code), func(t *testing.T) {
+			dir := t.TempDir()
+			trigger := filepath.Join(dir, "crash")
+			cfg := config.Config{Plugins: []config.PluginConfig{
+				{ID: "watcher"},
+				{
+					ID:      "crasher",
+					Command: os.Args[0],
+					Args:    []string{"-test.run=^TestCrashHelper$"},
+					Env:     map[string]string{"CRASH_HELPER": trigger, "CRASH_CODE": strconv.Itoa(code)},
+				},
+			}}
+			cfg.CrashReports.Dir = filepath.Join(dir, "reports")
+			h := plugintest.NewHost(t, cfg)
+			watcher := h.Connect("watcher")
+			watcher.Hello(&pb.PluginHello{Name: "watcher"})
+			watcher.Subscribe(pb.EventType_PLUGIN_CRASH)
+
+			if err := os.WriteFile(trigger, nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			crash := watcher.Event(pb.EventType_PLUGIN_CRASH).GetPluginCrash()
+			if crash.PluginId != "crasher" || crash.GetExitCode() != int32(code) || crash.Report == "" {
+				t.Fatalf("Expected a crash event with exit code %d and a report, got %v", code, crash)
+			}
+			info, err := os.Stat(crash.Report)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perm := info.Mode().Perm(); perm != 0o600 {
+				t.Errorf("Expected the report to have mode 0600, got %o", perm)
+			}
+			report, err := os.ReadFile(crash.Report)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"Plugin:  crasher", fmt.Sprintf("Exit:    code %d", code), "panic: boom", "CRASH_HELPER: <redacted>"} {
+				if !strings.Contains(string(report), want) {
+					t.Errorf("Expected the report to contain %q:\n%s", want, report)
+				}
+			}
+		})
+	}
+}
+
+// TestUpdateHelper is the plugin process of TestGitUpdate: it connects with the version its build
+// step wrote to the checkout, or never connects if the version is "bad".
+func TestUpdateHelper(t *testing.T) {
+	if os.Getenv("UPDATE_HELPER") == "" {
+		t.Skip("run by TestGitUpdate")
+	}
+	version, err := os.ReadFile("built")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(version) == "bad" {
+		time.Sleep(time.Hour)
+	}
+	p, err := sdk.New(sdk.WithVersion(string(version)), sdk.WithLogger(slog.New(slog.DiscardHandler)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = p.Run(context.Background())
+}
+
+func TestGitUpdate(t *testing.T) {
+	for _, persistent := range []bool{false, true} {
+		t.Run(fmt.Sprintf("persistent=%v", persistent), func(t *testing.T) { testGitUpdate(t, persistent) })
+	}
+}
+
+func testGitUpdate(t *testing.T, persistent bool) {
+	tmp := t.TempDir()
+	// Checkouts go to the temporary directory.
+	t.Setenv("TMPDIR", tmp)
+	remote := filepath.Join(tmp, "remote")
+	checkout := filepath.Join(tmp, "updater")
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = remote
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(version string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(remote, "version"), []byte(version), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "version")
+		git("commit", "-q", "-m", version)
+		return git("rev-parse", "HEAD")
+	}
+	// checkedOut fails the test unless the configured checkout holds version and the update's
+	// directories beside it are removed.
+	checkedOut := func(version string) {
+		t.Helper()
+		if built, err := os.ReadFile(filepath.Join(checkout, "built")); string(built) != version {
+			t.Errorf("Expected version %s in %s, got %q (%v)", version, checkout, built, err)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			leftover, _ := filepath.Glob(checkout + ".*")
+			if len(leftover) == 0 {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected no leftover update directories, found %v", leftover)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if err := os.Mkdir(remote, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q", "-b", "main")
+	commit("1")
+
+	path := filepath.Join(tmp, "plugins.yaml")
+	writeConfig := func(helper string) {
+		t.Helper()
+		err := os.WriteFile(path, fmt.Appendf(nil, `
+server_port: 127.0.0.1:0
+hello_timeout_ms: 3000
+crash_reports: {dir: %s}
+plugins:
+  - id: updater
+    command: %s
+    args: ["-test.run=^TestUpdateHelper$"]
+    env: {UPDATE_HELPER: %q}
+    work_dir:
+      path: %s
+      git: {enabled: true, persistent: %v, branch: main, poll_interval_ms: 50, build: [cp, version, built]}
+`, filepath.Join(tmp, "crash-reports"), os.Args[0], helper, remote, persistent), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig("1")
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := plugintest.NewHost(t, cfg)
+	status := func(what string, cond func(st ports.PluginStatus) bool) ports.PluginStatus {
+		t.Helper()
+		return waitStatus(t, h, "updater", what, cond)
+	}
+	status("version 1", func(st ports.PluginStatus) bool { return st.Version == "1" })
+
+	second := commit("2")
+	st := status("the update to version 2", func(st ports.PluginStatus) bool { return st.Version == "2" })
+	if st.Commit != second {
+		t.Errorf("Expected commit %s, got %s", second, st.Commit)
+	}
+	checkedOut("2")
+
+	commit("bad")
+	st = status("the bad version to be rolled back", func(st ports.PluginStatus) bool {
+		return strings.Contains(st.LastError, "rolled back") && st.Version == "2"
+	})
+	if st.Commit != second {
+		t.Errorf("Expected commit %s after the rollback, got %s", second, st.Commit)
+	}
+	checkedOut("2")
+
+	// A reload keeps the running checkout rather than cloning the bad version again.
+	if err := h.Manager.ReloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := h.Manager.PluginStatus("updater")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.PID != st.PID || reloaded.Commit != second {
+		t.Errorf("Expected the reload to keep PID %d at %s, got PID %d at %s", st.PID, second, reloaded.PID, reloaded.Commit)
+	}
+	checkedOut("2")
+	if !persistent {
+		// A changed definition is cloned again, but not at the commit the update rejected.
+		writeConfig("2")
+		if err := h.Manager.ReloadConfig(); !errors.Is(err, config.ErrRejectedCommit) {
+			t.Errorf("Expected the reload to refuse the rejected commit, got %v", err)
+		}
+		status("the rejected commit to be reported", func(st ports.PluginStatus) bool {
+			return strings.Contains(st.LastError, config.ErrRejectedCommit.Error())
+		})
+		checkedOut("2")
+		return
+	}
+
+	// The next run reuses the updated checkout.
+	h.Manager.Close()
+	if cfg, err = config.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Plugins[0].WorkDir.Git.Commit != second {
+		t.Errorf("Expected the persistent checkout at %s, got %s", second, cfg.Plugins[0].WorkDir.Git.Commit)
+	}
+}
@@ -0,0 +1,148 @@
+package plugin
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// Capabilities are optional protocol features negotiated in the hello exchange.
+const (
+	// CapabilityCompression lets the host send large event batches as snappy CompressedEventBatch.
+	CapabilityCompression = "compression.snappy"
+	// CapabilityHeartbeat means the plugin answers HostPing with PluginPong.
+	CapabilityHeartbeat = "heartbeat"
+	// CapabilitySessionResume lets the plugin resume a session and have missed events replayed.
+	CapabilitySessionResume = "session.resume"
+	// CapabilityCustomMetrics lets the plugin export metrics through PluginToHost.metrics.
+	CapabilityCustomMetrics = "metrics.custom"
+	// CapabilityTracing means the plugin continues and returns trace context.
+	CapabilityTracing = "tracing"
+	// CapabilityRegistry means the host answers RegistryRequest with its game registry.
+	CapabilityRegistry = "registry"
+	// CapabilityQueryService means the host serves the Query gRPC service next to the event stream.
+	CapabilityQueryService = "query.grpc"
+	// CapabilityObserver makes the plugin a read-only observer: it is sent events without being
+	// asked for a result, so events never wait for it and it cannot cancel or mutate them.
+	CapabilityObserver = "observer"
+)
+
+// supportedAPIVersions lists the API versions the host speaks, newest first.
+var supportedAPIVersions = []string{apiVersion}
+
+// hostCapabilities lists every capability the host supports.
+var hostCapabilities = []string{
+	CapabilityCompression,
+	CapabilityHeartbeat,
+	CapabilitySessionResume,
+	CapabilityCustomMetrics,
+	CapabilityTracing,
+	CapabilityRegistry,
+	CapabilityQueryService,
+	CapabilityObserver,
+}
+
+// legacyCapabilities are enabled for plugins written before negotiation existed: what the host did
+// unconditionally before then. Heartbeats and session resumption must be requested.
+var legacyCapabilities = []string{
+	CapabilityCompression,
+}
+
+// negotiated is the outcome of negotiating with a plugin.
+type negotiated struct {
+	apiVersion   string
+	capabilities []string
+}
+
+func (s *negotiated) has(capability string) bool {
+	return s != nil && slices.Contains(s.capabilities, capability)
+}
+
+// legacySession is used until a plugin's hello has been negotiated.
+var legacySession = &negotiated{apiVersion: apiVersion, capabilities: legacyCapabilities}
+
+// negotiate picks the first API version in the plugin's preference order that the host speaks and
+// enables the capabilities both sides support. It fails if there is no common version or the host
+// lacks a capability the plugin requires.
+func negotiate(hello *pb.PluginHello) (*negotiated, error) {
+	versions := hello.GetApiVersions()
+	if len(versions) == 0 && hello.GetApiVersion() != "" {
+		versions = []string{hello.GetApiVersion()}
+	}
+	if len(versions) == 0 {
+		versions = []string{apiVersion}
+	}
+	s := &negotiated{}
+	for _, v := range versions {
+		if slices.Contains(supportedAPIVersions, v) {
+			s.apiVersion = v
+			break
+		}
+	}
+	if s.apiVersion == "" {
+		return nil, fmt.Errorf("no common api version: plugin speaks %s, host speaks %s",
+			strings.Join(versions, ", "), strings.Join(supportedAPIVersions, ", "))
+	}
+
+	var missing []string
+	for _, c := range hello.GetRequiredCapabilities() {
+		if !slices.Contains(hostCapabilities, c) {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("host does not support required capabilities: %s", strings.Join(missing, ", "))
+	}
+
+	wanted := append(slices.Clone(hello.GetCapabilities()), hello.GetRequiredCapabilities()...)
+	if len(wanted) == 0 && len(hello.GetApiVersions()) == 0 {
+		wanted = legacyCapabilities
+	}
+	for _, c := range hostCapabilities {
+		if slices.Contains(wanted, c) {
+			s.capabilities = append(s.capabilities, c)
+		}
+	}
+	return s, nil
+}
+
+// hostHello builds the HostHello for a negotiated session, or for a failed negotiation if s is nil.
+func (m *Manager) hostHello(s *negotiated) *pb.HostHello {
+	hello := &pb.HostHello{
+		BootId:               m.bootID,
+		SupportedApiVersions: supportedAPIVersions,
+		Capabilities:         hostCapabilities,
+	}
+	if s != nil {
+		hello.ApiVersion = s.apiVersion
+		hello.EnabledCapabilities = s.capabilities
+	}
+	return hello
+}
+
+// refuse tells a plugin that negotiation failed and why. The stream is closed by the caller.
+func (m *Manager) refuse(stream ports.Stream, pluginID string, reason error) {
+	for _, msg := range []*pb.HostToPlugin{
+		{PluginId: pluginID, Payload: &pb.HostToPlugin_Hello{Hello: m.hostHello(nil)}},
+		{PluginId: pluginID, Payload: &pb.HostToPlugin_Shutdown{Shutdown: &pb.HostShutdown{Reason: reason.Error()}}},
+	} {
+		if ms, ok := stream.(ports.MessageStream); ok {
+			if err := ms.SendMessage(msg); err != nil {
+				return
+			}
+			continue
+		}
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			return
+		}
+		if err := stream.Send(data); err != nil {
+			return
+		}
+	}
+}
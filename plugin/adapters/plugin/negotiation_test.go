@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name         string
+		hello        *pb.PluginHello
+		version      string
+		capabilities []string
+		fail         bool
+	}{
+		{
+			name:         "legacy plugin gets v1 defaults",
+			hello:        &pb.PluginHello{ApiVersion: "v1"},
+			version:      "v1",
+			capabilities: []string{CapabilityCompression},
+		},
+		{
+			name:         "preferred version unknown to host",
+			hello:        &pb.PluginHello{ApiVersions: []string{"v2", "v1"}, Capabilities: []string{CapabilityHeartbeat, "example.unsupported"}},
+			version:      "v1",
+			capabilities: []string{CapabilityHeartbeat},
+		},
+		{
+			name:    "no capabilities declared",
+			hello:   &pb.PluginHello{ApiVersions: []string{"v1"}},
+			version: "v1",
+		},
+		{
+			name:  "no common version",
+			hello: &pb.PluginHello{ApiVersions: []string{"v0"}},
+			fail:  true,
+		},
+		{
+			name:  "required capability missing",
+			hello: &pb.PluginHello{ApiVersions: []string{"v1"}, RequiredCapabilities: []string{"example.unsupported"}},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := negotiate(tt.hello)
+			if tt.fail {
+				if err == nil {
+					t.Fatalf("expected negotiation to fail, got %+v", s)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.apiVersion != tt.version || !slices.Equal(s.capabilities, tt.capabilities) {
+				t.Fatalf("got %s %v, want %s %v", s.apiVersion, s.capabilities, tt.version, tt.capabilities)
+			}
+		})
+	}
+}
+
+// messageStream records the messages sent to it and fails raw sends.
+type messageStream struct {
+	sent []*pb.HostToPlugin
+}
+
+func (s *messageStream) Send([]byte) error                      { return errors.New("raw send") }
+func (s *messageStream) Recv() ([]byte, error)                  { return nil, errors.New("raw receive") }
+func (s *messageStream) CloseSend() error                       { return nil }
+func (s *messageStream) Close() error                           { return nil }
+func (s *messageStream) RecvMessage() (*pb.PluginToHost, error) { return nil, errors.New("closed") }
+
+func (s *messageStream) SendMessage(msg *pb.HostToPlugin) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestRefuseMessageStream(t *testing.T) {
+	stream := &messageStream{}
+	(&Manager{}).refuse(stream, "p", errors.New("no common API version"))
+	if len(stream.sent) != 2 {
+		t.Fatalf("expected a hello and a shutdown, got %v", stream.sent)
+	}
+	if hello := stream.sent[0].GetHello(); hello == nil || hello.ApiVersion != "" {
+		t.Errorf("expected a hello without api_version, got %v", stream.sent[0])
+	}
+	if reason := stream.sent[1].GetShutdown().GetReason(); reason != "no common API version" {
+		t.Errorf("expected the shutdown to give the reason, got %q", reason)
+	}
+}
@@ -0,0 +1,114 @@
+package plugin
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/secmc/plugin/plugin/adapters/inprocess"
+	"github.com/secmc/plugin/plugin/adapters/stdio"
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	// peerCheckPID accepts a Unix socket peer only if it is the launched process or one of its children.
+	peerCheckPID = "pid"
+	// peerCheckUID accepts a Unix socket peer running as the same user as the launched process.
+	peerCheckUID = "uid"
+	peerCheckOff = "off"
+
+	// launchWait bounds how long a connection waits for its plugin's launch to be recorded. A fast
+	// plugin may dial before launchProcess has stored the PID.
+	launchWait = 5 * time.Second
+)
+
+// verifyPeer checks that a stream comes from the process the host launched for this plugin, so that
+// no other local process can claim its plugin ID. Streams over a Unix socket are checked by their
+// peer credentials; streams without them (TCP, WebSocket) must present the launch token in hello.
+// The stdio stream of a launched process and plugins the host did not launch are not checked.
+// Plugins the host runs itself (compiled in, WebAssembly, webhooks) only connect in-process.
+func (p *pluginProcess) verifyPeer(stream ports.Stream, hello *pb.PluginHello) error {
+	if p.runsInProcess() {
+		if _, ok := stream.(*inprocess.HostStream); !ok {
+			return fmt.Errorf("plugin %s runs in-process and cannot connect over a transport", p.id)
+		}
+		return nil
+	}
+	if _, ok := stream.(*stdio.Stream); ok {
+		// Only launchProcess creates stdio streams, over the pipes of the process it started.
+		return nil
+	}
+	if ps, ok := stream.(ports.PeerStream); ok {
+		if cred, ok := ps.PeerCredentials(); ok {
+			return p.verifyCredentials(cred)
+		}
+	}
+	return p.verifyToken(hello.GetLaunchToken())
+}
+
+// verifyToken checks that token is the secret passed to the process the host launched for this
+// plugin.
+func (p *pluginProcess) verifyToken(token string) error {
+	if p.manager.peerCheck == peerCheckOff || p.cfg.Command == "" {
+		return nil
+	}
+	if err := p.waitLaunched(); err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("plugin %s connected without peer credentials or a launch token", p.id)
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(p.launchToken)) != 1 {
+		return fmt.Errorf("plugin %s presented the wrong launch token", p.id)
+	}
+	return nil
+}
+
+// waitLaunched waits until the plugin's process has been launched.
+func (p *pluginProcess) waitLaunched() error {
+	select {
+	case <-p.launched:
+		return nil
+	case <-time.After(launchWait):
+		return fmt.Errorf("plugin %s has not been launched", p.id)
+	}
+}
+
+// newLaunchToken returns a secret for a launched process to prove its identity with.
+func newLaunchToken() string {
+	return rand.Text()
+}
+
+// verifyCredentials checks that cred belongs to the process the host launched for this plugin.
+func (p *pluginProcess) verifyCredentials(cred ports.PeerCredentials) error {
+	mode := p.manager.peerCheck
+	if mode == peerCheckOff || p.cfg.Command == "" {
+		return nil
+	}
+
+	if err := p.waitLaunched(); err != nil {
+		return err
+	}
+	pid := int(p.pid.Load())
+	if pid == 0 {
+		return fmt.Errorf("plugin %s has no running process", p.id)
+	}
+
+	if mode == peerCheckUID {
+		uid, err := unixsocket.ProcessUID(pid)
+		if err != nil {
+			return fmt.Errorf("look up plugin process: %w", err)
+		}
+		if cred.UID != uid {
+			return fmt.Errorf("peer uid %d does not match plugin process uid %d", cred.UID, uid)
+		}
+		return nil
+	}
+	if !unixsocket.IsDescendant(int(cred.PID), pid) {
+		return fmt.Errorf("peer pid %d is not plugin process %d or one of its children", cred.PID, pid)
+	}
+	return nil
+}
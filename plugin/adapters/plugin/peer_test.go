@@ -0,0 +1,77 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// tcpStream stands in for a transport without peer credentials.
+type tcpStream struct{ ports.Stream }
+
+func TestVerifyPeerLaunchToken(t *testing.T) {
+	launched := func(peerCheck string) *pluginProcess {
+		p := &pluginProcess{
+			id:          "launched",
+			cfg:         config.PluginConfig{ID: "launched", Command: "plugin"},
+			manager:     &Manager{peerCheck: peerCheck},
+			launched:    make(chan struct{}),
+			launchToken: "secret",
+		}
+		close(p.launched)
+		return p
+	}
+	for _, tc := range []struct {
+		name      string
+		peerCheck string
+		token     string
+		ok        bool
+	}{
+		{"token", peerCheckPID, "secret", true},
+		{"no token", peerCheckPID, "", false},
+		{"wrong token", peerCheckUID, "guess", false},
+		{"check off", peerCheckOff, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := launched(tc.peerCheck).verifyPeer(tcpStream{}, &pb.PluginHello{LaunchToken: tc.token})
+			if tc.ok && err != nil {
+				t.Errorf("Expected the stream to be accepted, got %v", err)
+			}
+			if !tc.ok && err == nil {
+				t.Error("Expected the stream to be refused")
+			}
+		})
+	}
+
+	// Plugins the host did not launch have no token to present.
+	external := &pluginProcess{id: "external", cfg: config.PluginConfig{ID: "external"}, manager: &Manager{peerCheck: peerCheckPID}}
+	if err := external.verifyPeer(tcpStream{}, &pb.PluginHello{}); err != nil {
+		t.Errorf("Expected a plugin started outside the host to be accepted, got %v", err)
+	}
+}
+
+func TestAuthorizeQueryLaunchToken(t *testing.T) {
+	m := &Manager{peerCheck: peerCheckPID}
+	p := &pluginProcess{
+		id:          "launched",
+		cfg:         config.PluginConfig{ID: "launched", Command: "plugin"},
+		manager:     m,
+		launched:    make(chan struct{}),
+		launchToken: "secret",
+	}
+	close(p.launched)
+	p.connected.Store(true)
+	m.plugins = map[string]*pluginProcess{p.id: p}
+	q := &queryService{m: m}
+
+	if err := q.AuthorizeQuery("launched", nil, "secret"); err != nil {
+		t.Errorf("Expected a call with the launch token to be accepted, got %v", err)
+	}
+	for _, token := range []string{"", "guess"} {
+		if err := q.AuthorizeQuery("launched", nil, token); err == nil {
+			t.Errorf("Expected a call with token %q to be refused", token)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package plugintest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// Timeout is how long a FakePlugin waits for the host before failing the test.
+var Timeout = 5 * time.Second
+
+// Answer decides the result a FakePlugin sends for an event that expects a response. Its EventId
+// is filled in. A nil result leaves the event unanswered, so the host waits until it times out.
+type Answer func(*pb.EventEnvelope) *pb.EventResult
+
+// Cancel answers events by cancelling them.
+func Cancel() Answer {
+	return func(*pb.EventEnvelope) *pb.EventResult {
+		return &pb.EventResult{Cancel: proto.Bool(true)}
+	}
+}
+
+// Respond answers events with a copy of res, typically carrying a mutation.
+func Respond(res *pb.EventResult) Answer {
+	return func(*pb.EventEnvelope) *pb.EventResult {
+		return proto.Clone(res).(*pb.EventResult)
+	}
+}
+
+// FakePlugin is the plugin end of a stream, scripted by a test. It answers pings, answers events
+// with the Answer registered for their type, or an empty result if there is none, and records
+// everything else the host sends so the test can wait for it.
+type FakePlugin struct {
+	ID string
+
+	t      testing.TB
+	stream ports.Stream
+
+	mu          sync.Mutex
+	answers     map[pb.EventType]Answer
+	ignorePings bool
+	hello       *pb.HostHello
+	info        int
+	events      []*pb.EventEnvelope
+	results     []*pb.ActionResult
+	// changed is closed and replaced whenever something is recorded.
+	changed chan struct{}
+	closed  bool
+}
+
+// NewFakePlugin starts reading from stream, the plugin end of a connection to the host. Use
+// Host.Connect to get one attached to a Host.
+func NewFakePlugin(t testing.TB, id string, stream ports.Stream) *FakePlugin {
+	f := &FakePlugin{
+		ID:      id,
+		t:       t,
+		stream:  stream,
+		answers: make(map[pb.EventType]Answer),
+		changed: make(chan struct{}),
+	}
+	go f.recvLoop()
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+// Send sends msg to the host as this plugin.
+func (f *FakePlugin) Send(msg *pb.PluginToHost) {
+	f.t.Helper()
+	msg.PluginId = f.ID
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		f.t.Fatalf("plugin %s: marshal: %v", f.ID, err)
+	}
+	if err := f.stream.Send(data); err != nil {
+		f.t.Fatalf("plugin %s: send: %v", f.ID, err)
+	}
+}
+
+// Hello sends hello, naming the plugin after its ID if hello has no name, and waits for the
+// host's reply. A nil hello sends a minimal one.
+func (f *FakePlugin) Hello(hello *pb.PluginHello) *pb.HostHello {
+	f.t.Helper()
+	if hello == nil {
+		hello = &pb.PluginHello{}
+	}
+	if hello.Name == "" {
+		hello.Name = f.ID
+	}
+	f.Send(&pb.PluginToHost{Payload: &pb.PluginToHost_Hello{Hello: hello}})
+	var res *pb.HostHello
+	f.wait("host hello", func() bool {
+		res = f.hello
+		return res != nil
+	})
+	return res
+}
+
+// Subscribe subscribes to events and returns once the host has applied the subscription.
+func (f *FakePlugin) Subscribe(events ...pb.EventType) {
+	f.t.Helper()
+	f.Send(&pb.PluginToHost{Payload: &pb.PluginToHost_Subscribe{Subscribe: &pb.EventSubscribe{Events: events}}})
+	f.sync()
+}
+
+// sync returns once the host has handled every message sent before it. The host handles a
+// plugin's messages in order, so a server info reply means the earlier ones are done.
+func (f *FakePlugin) sync() {
+	f.t.Helper()
+	f.mu.Lock()
+	n := f.info
+	f.mu.Unlock()
+	f.Send(&pb.PluginToHost{Payload: &pb.PluginToHost_ServerInfo{ServerInfo: &pb.ServerInformationRequest{}}})
+	f.wait("server info", func() bool { return f.info > n })
+}
+
+// Answer sets how events of type t are answered. It replaces any previous Answer for t.
+func (f *FakePlugin) Answer(t pb.EventType, answer Answer) {
+	f.mu.Lock()
+	f.answers[t] = answer
+	f.mu.Unlock()
+}
+
+// IgnorePings sets whether the plugin leaves pings unanswered, as a plugin that hangs would.
+func (f *FakePlugin) IgnorePings(ignore bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ignorePings = ignore
+}
+
+// Act sends actions to the host in one batch.
+func (f *FakePlugin) Act(actions ...*pb.Action) {
+	f.t.Helper()
+	f.Send(&pb.PluginToHost{Payload: &pb.PluginToHost_Actions{Actions: &pb.ActionBatch{Actions: actions}}})
+}
+
+// Event waits for an event of type t that has not been returned before and returns it.
+func (f *FakePlugin) Event(t pb.EventType) *pb.EventEnvelope {
+	f.t.Helper()
+	var ev *pb.EventEnvelope
+	f.wait("event "+t.String(), func() bool {
+		for i, e := range f.events {
+			if e.Type == t {
+				ev = e
+				f.events = append(f.events[:i], f.events[i+1:]...)
+				return true
+			}
+		}
+		return false
+	})
+	return ev
+}
+
+// Events returns the events received and not yet returned by Event.
+func (f *FakePlugin) Events() []*pb.EventEnvelope {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*pb.EventEnvelope(nil), f.events...)
+}
+
+// ActionResult waits for the result of the action with the given correlation ID.
+func (f *FakePlugin) ActionResult(correlationID string) *pb.ActionResult {
+	f.t.Helper()
+	var res *pb.ActionResult
+	f.wait("action result "+correlationID, func() bool {
+		for _, r := range f.results {
+			if r.CorrelationId == correlationID {
+				res = r
+				return true
+			}
+		}
+		return false
+	})
+	return res
+}
+
+// Close closes the plugin's end of the stream, disconnecting it from the host.
+func (f *FakePlugin) Close() error {
+	return f.stream.Close()
+}
+
+// HostHello returns the host's reply to the plugin's hello, or nil if it has not sent one.
+func (f *FakePlugin) HostHello() *pb.HostHello {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hello
+}
+
+// WaitClosed waits for the host to close the connection, as it does when it refuses the plugin.
+func (f *FakePlugin) WaitClosed() {
+	f.t.Helper()
+	deadline := time.After(Timeout)
+	for {
+		f.mu.Lock()
+		closed, changed := f.closed, f.changed
+		f.mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			f.t.Fatalf("plugin %s: timed out waiting for the host to close the connection", f.ID)
+		}
+	}
+}
+
+// wait fails the test if cond, called with f.mu held, does not become true within Timeout.
+func (f *FakePlugin) wait(what string, cond func() bool) {
+	f.t.Helper()
+	deadline := time.After(Timeout)
+	for {
+		f.mu.Lock()
+		ok, closed, changed := cond(), f.closed, f.changed
+		f.mu.Unlock()
+		if ok {
+			return
+		}
+		if closed {
+			f.t.Fatalf("plugin %s: stream closed while waiting for %s", f.ID, what)
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			f.t.Fatalf("plugin %s: timed out waiting for %s", f.ID, what)
+		}
+	}
+}
+
+// record runs fn with f.mu held and wakes up waiters.
+func (f *FakePlugin) record(fn func()) {
+	f.mu.Lock()
+	fn()
+	close(f.changed)
+	f.changed = make(chan struct{})
+	f.mu.Unlock()
+}
+
+func (f *FakePlugin) recvLoop() {
+	defer f.record(func() { f.closed = true })
+	for {
+		data, err := f.stream.Recv()
+		if err != nil {
+			return
+		}
+		msg := &pb.HostToPlugin{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			f.t.Errorf("plugin %s: decode: %v", f.ID, err)
+			continue
+		}
+		switch payload := msg.GetPayload().(type) {
+		case *pb.HostToPlugin_Hello:
+			f.record(func() { f.hello = payload.Hello })
+		case *pb.HostToPlugin_ServerInfo:
+			f.record(func() { f.info++ })
+		case *pb.HostToPlugin_Ping:
+			f.mu.Lock()
+			ignore := f.ignorePings
+			f.mu.Unlock()
+			if ignore {
+				continue
+			}
+			f.reply(&pb.PluginToHost{Payload: &pb.PluginToHost_Pong{Pong: &pb.PluginPong{Nonce: payload.Ping.GetNonce()}}})
+		case *pb.HostToPlugin_Event:
+			f.handleEvent(payload.Event)
+		case *pb.HostToPlugin_Events:
+			for _, ev := range payload.Events.GetEvents() {
+				f.handleEvent(ev)
+			}
+		case *pb.HostToPlugin_ActionResult:
+			f.record(func() { f.results = append(f.results, payload.ActionResult) })
+		}
+	}
+}
+
+func (f *FakePlugin) handleEvent(ev *pb.EventEnvelope) {
+	f.record(func() { f.events = append(f.events, ev) })
+	if !ev.ExpectsResponse {
+		return
+	}
+	f.mu.Lock()
+	answer := f.answers[ev.Type]
+	f.mu.Unlock()
+	res := &pb.EventResult{}
+	if answer != nil {
+		if res = answer(ev); res == nil {
+			return
+		}
+	}
+	res.EventId = ev.EventId
+	f.reply(&pb.PluginToHost{Payload: &pb.PluginToHost_EventResult{EventResult: res}})
+}
+
+// reply sends msg from the receive loop, where failing the test is not allowed.
+func (f *FakePlugin) reply(msg *pb.PluginToHost) {
+	msg.PluginId = f.ID
+	data, err := proto.Marshal(msg)
+	if err == nil {
+		err = f.stream.Send(data)
+	}
+	if err != nil {
+		f.t.Logf("plugin %s: reply: %v", f.ID, err)
+	}
+}
@@ -0,0 +1,94 @@
+package plugintest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/google/uuid"
+
+	"github.com/secmc/plugin/plugin/adapters/handlers"
+	"github.com/secmc/plugin/plugin/adapters/plugin"
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/ports"
+)
+
+// Host is a Manager attached to a headless world that generates and saves nothing. Plugins are
+// not launched; they connect through Connect.
+type Host struct {
+	Manager *plugin.Manager
+	World   *world.World
+
+	t testing.TB
+}
+
+// NewHost starts a Manager configured with cfg and attaches a new world to it. Plugins in cfg
+// must not have a command; cfg.ServerPort defaults to a free local port. The manager and world
+// are closed when the test ends.
+func NewHost(t testing.TB, cfg config.Config) *Host {
+	t.Helper()
+	if cfg.ServerPort == "" {
+		cfg.ServerPort = "127.0.0.1:0"
+	}
+	log := slog.New(slog.DiscardHandler)
+	m := plugin.NewManager(nil, log,
+		func(e ports.EventManager) player.Handler { return handlers.NewPlayerHandler(e) },
+		func(e ports.EventManager) world.Handler { return handlers.NewWorldHandler(e) },
+	)
+	if err := m.StartWithConfig(cfg); err != nil {
+		t.Fatalf("start manager: %v", err)
+	}
+	w := world.Config{
+		Log:       log,
+		Provider:  world.NopProvider{},
+		Generator: world.NopGenerator{},
+		Entities:  entity.DefaultRegistry,
+	}.New()
+	m.AttachWorld(w)
+	t.Cleanup(func() {
+		m.Close()
+		_ = w.Close()
+	})
+	return &Host{Manager: m, World: w, t: t}
+}
+
+// Connect connects a fake plugin with the given ID, which must be configured. It sends nothing;
+// call Hello and Subscribe on the result.
+func (h *Host) Connect(id string) *FakePlugin {
+	hostEnd, pluginEnd := Pipe()
+	go func() {
+		// Like a real transport, the connection ends when the host is done with it.
+		defer hostEnd.Close()
+		if err := h.Manager.Accept(hostEnd); err != nil && !errors.Is(err, context.Canceled) {
+			h.t.Logf("plugin %s: %v", id, err)
+		}
+	}()
+	return NewFakePlugin(h.t, id, pluginEnd)
+}
+
+// Exec runs f in a transaction on the world and waits for it to finish.
+func (h *Host) Exec(f func(tx *world.Tx)) {
+	<-h.World.Exec(f)
+}
+
+// AddPlayer spawns a player without a network session and attaches it to the manager, which
+// sends PLAYER_JOIN.
+func (h *Host) AddPlayer(name string) *world.EntityHandle {
+	handle := world.EntitySpawnOpts{}.New(player.Type, player.Config{Name: name, UUID: uuid.New()})
+	h.Exec(func(tx *world.Tx) {
+		h.Manager.AttachPlayer(tx.AddEntity(handle).(*player.Player))
+	})
+	return handle
+}
+
+// WithPlayer runs f with the player behind handle in a transaction on its world.
+func (h *Host) WithPlayer(handle *world.EntityHandle, f func(tx *world.Tx, p *player.Player)) {
+	h.t.Helper()
+	if !handle.ExecWorld(func(tx *world.Tx, e world.Entity) { f(tx, e.(*player.Player)) }) {
+		h.t.Fatal("player is not in a world")
+	}
+}
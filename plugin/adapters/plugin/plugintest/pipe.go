@@ -0,0 +1,45 @@
+// Package plugintest runs a plugin Manager against in-memory streams, scriptable fake plugins and
+// a headless Dragonfly world, so host behaviour can be tested without launching processes.
+package plugintest
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/adapters/inprocess"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// Pipe returns the two ends of an in-memory stream. Messages sent on one end are received on the
+// other, in order. After CloseSend the other end reads the remaining messages and then io.EOF;
+// Close ends both directions.
+//
+// It is an inprocess.Pipe whose ends only deal in bytes, so the host treats the connection like
+// one over a network transport. Messages are unmarshalled when sent and marshalled when received,
+// so a sender may reuse its buffer.
+func Pipe() (host, plugin ports.Stream) {
+	hostEnd, pluginEnd := inprocess.Pipe()
+	// Embedding the interface hides HostStream's ports.MessageStream methods.
+	return struct{ ports.Stream }{hostEnd}, pluginStream{pluginEnd}
+}
+
+// pluginStream is the plugin's end of a Pipe, marshalling the messages of an in-process stream.
+type pluginStream struct {
+	*inprocess.PluginStream
+}
+
+func (s pluginStream) Send(data []byte) error {
+	msg := &pb.PluginToHost{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	return s.PluginStream.Send(msg)
+}
+
+func (s pluginStream) Recv() ([]byte, error) {
+	msg, err := s.PluginStream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(msg)
+}
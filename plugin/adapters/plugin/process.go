@@ -20,8 +20,10 @@ import (
 	"github.com/golang/snappy"
 	"google.golang.org/protobuf/proto"
 
-	"github.com/secmc/plugin/plugin/adapters/grpc"
+	"github.com/secmc/plugin/plugin/adapters/metrics"
+	"github.com/secmc/plugin/plugin/adapters/stdio"
 	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/ports"
 	pb "github.com/secmc/plugin/proto/generated/go"
 )
 
@@ -46,8 +48,26 @@ type pluginProcess struct {
 	manager *Manager
 	log     *slog.Logger
 
+	metrics       *metrics.Plugin
+	customMetrics *metrics.Custom
+	// logLevel overrides the host's log level for this plugin when set through the admin API.
+	logLevel  atomic.Pointer[slog.Level]
+	lastError atomic.Pointer[pluginError]
+	// session holds the negotiated API version and capabilities.
+	session atomic.Pointer[negotiated]
+	tap     eventTap
+	// history keeps recent output and traffic for crash reports.
+	history   *crashHistory
+	startedAt atomic.Pointer[time.Time]
+
 	cmd      *exec.Cmd
-	stream   *grpc.GrpcStream
+	pid      atomic.Int32
+	launched chan struct{}
+	// launchToken is the secret passed to the launched process in DF_PLUGIN_TOKEN. It is set before
+	// launched is closed.
+	launchToken string
+
+	stream   ports.Stream
 	streamMu sync.RWMutex
 
 	sendCh   chan *pb.HostToPlugin
@@ -58,6 +78,10 @@ type pluginProcess struct {
 	subscriptions sync.Map
 	connected     atomic.Bool
 	ready         atomic.Bool
+	// attached is set once the first stream is attached, so later attaches count as reconnects.
+	attached atomic.Bool
+	// actionErrors counts actions that reported an error, to tell failed from successful actions.
+	actionErrors atomic.Uint64
 
 	helloMu sync.RWMutex
 	hello   *pb.PluginHello
@@ -69,37 +93,70 @@ type pluginProcess struct {
 
 	eventBufferMu sync.Mutex
 	eventBuffer   []*pb.EventEnvelope
+
+	replay *replayBuffer
+
+	heartbeatOnce   sync.Once
+	pingNonce       atomic.Uint64
+	lastPingAt      atomic.Int64
+	pingOutstanding atomic.Bool
+	answeredPing    atomic.Bool
+	missedPings     atomic.Int32
+	rtt             atomic.Int64
+	unhealthy       atomic.Bool
 }
 
 func newPluginProcess(m *Manager, cfg config.PluginConfig) *pluginProcess {
-	logger := m.log.With("plugin", cfg.ID)
-	if cfg.Name != "" {
-		logger = logger.With("name", cfg.Name)
-	}
-	return &pluginProcess{
-		id:       cfg.ID,
-		cfg:      cfg,
-		manager:  m,
-		log:      logger,
-		sendCh:   make(chan *pb.HostToPlugin, sendChannelBuffer),
-		actionCh: make(chan *pb.ActionBatch, sendChannelBuffer),
-		done:     make(chan struct{}),
+	p := &pluginProcess{
+		id:            cfg.ID,
+		cfg:           cfg,
+		manager:       m,
+		metrics:       metrics.ForPlugin(cfg.ID),
+		customMetrics: metrics.NewCustom(cfg.ID, m.customMetricsLimit, m.customSeriesLimit),
+		sendCh:        make(chan *pb.HostToPlugin, sendChannelBuffer),
+		actionCh:      make(chan *pb.ActionBatch, sendChannelBuffer),
+		done:          make(chan struct{}),
+		launched:      make(chan struct{}),
 
 		pending: make(map[string]chan *pb.EventResult),
+		replay:  newReplayBuffer(m.replayBufferSize),
+		history: newCrashHistory(m.crashReports.OutputLines, m.crashReports.History),
 	}
+	p.session.Store(legacySession)
+	p.log = slog.New(levelHandler{Handler: m.log.Handler(), level: &p.logLevel}).With("plugin", cfg.ID)
+	if cfg.Name != "" {
+		p.log = p.log.With("name", cfg.Name)
+	}
+	return p
 }
 
 func (p *pluginProcess) start(ctx context.Context, serverAddress string) {
+	defer close(p.launched)
+	now := time.Now()
+	p.startedAt.Store(&now)
+	if git := p.cfg.WorkDir.Git; git.PollIntervalMs > 0 && git.Remote != "" {
+		go p.watchUpdates(ctx)
+	}
+	if run := p.inProcessRunner(); run != nil {
+		p.runInProcess(ctx, run)
+		return
+	}
+	if p.cfg.Transport == "inprocess" {
+		p.log.Error("launch plugin", "error", "no in-process plugin registered")
+		p.recordError("no in-process plugin registered")
+		return
+	}
 	if p.cfg.Command != "" {
 		if err := p.launchProcess(ctx, serverAddress); err != nil {
 			p.log.Error("launch plugin", "error", err)
+			p.recordError(fmt.Sprintf("launch plugin: %v", err))
 			return
 		}
 	}
 }
 
 // attachStream attaches an incoming stream to this plugin process
-func (p *pluginProcess) attachStream(stream *grpc.GrpcStream) error {
+func (p *pluginProcess) attachStream(stream ports.Stream) error {
 	p.streamMu.Lock()
 	// Allow replacing a stale/closed stream to support plugin hot-reload reconnections.
 	if p.stream != nil {
@@ -110,31 +167,45 @@ func (p *pluginProcess) attachStream(stream *grpc.GrpcStream) error {
 	p.streamMu.Unlock()
 
 	p.connected.Store(true)
+	p.resetHeartbeat()
+	if p.attached.Swap(true) {
+		p.metrics.Reconnected()
+	}
 
 	if err := p.sendHello(); err != nil {
 		p.log.Error("send hello", "error", err)
+		p.recordError(fmt.Sprintf("send hello: %v", err))
 		p.Stop()
 		return err
 	}
 
 	p.wg.Add(4)
-	go p.sendLoop()
-	go p.recvLoop()
+	go p.sendLoop(stream)
+	go p.recvLoop(stream)
 	go p.batchSendLoop()
 	go p.actionLoop()
+
+	if interval := p.manager.heartbeatInterval; interval > 0 && p.session.Load().has(CapabilityHeartbeat) {
+		p.heartbeatOnce.Do(func() {
+			p.wg.Add(1)
+			go p.heartbeatLoop(interval, p.manager.heartbeatMissedLimit)
+		})
+	}
 	return nil
 }
 
-// clearStream drops the current gRPC stream and marks the plugin as disconnected,
-// without killing the underlying process. This enables external hot-reload
+// clearStream drops stream if it is still the current plugin stream and marks the plugin as
+// disconnected, without killing the underlying process. This enables external hot-reload
 // wrappers to restart the plugin and reconnect cleanly.
-func (p *pluginProcess) clearStream() {
+func (p *pluginProcess) clearStream(stream ports.Stream) {
+	_ = stream.Close()
 	p.streamMu.Lock()
-	if p.stream != nil {
-		_ = p.stream.Close()
-		p.stream = nil
+	defer p.streamMu.Unlock()
+	if p.stream != stream {
+		// The plugin has already reconnected on a new stream.
+		return
 	}
-	p.streamMu.Unlock()
+	p.stream = nil
 	p.connected.Store(false)
 }
 
@@ -142,6 +213,7 @@ func (p *pluginProcess) queueActions(batch *pb.ActionBatch) {
 	if p.closed.Load() {
 		return
 	}
+	p.history.actionBatch(batch)
 	select {
 	case p.actionCh <- batch:
 	default:
@@ -179,11 +251,33 @@ func (p *pluginProcess) launchProcess(ctx context.Context, serverAddress string)
 	}
 	env = append(env, fmt.Sprintf("DF_PLUGIN_SERVER_ADDRESS=%s", passAddress))
 	env = append(env, fmt.Sprintf("DF_HOST_BOOT_ID=%s", p.manager.bootID))
+	p.launchToken = newLaunchToken()
+	env = append(env, fmt.Sprintf("DF_PLUGIN_TOKEN=%s", p.launchToken))
+	if p.manager.shmServer != nil {
+		env = append(env, fmt.Sprintf("DF_PLUGIN_SHM_ADDRESS=%s", p.manager.shmServer.Address()))
+	}
+	var format stdio.Format
+	if p.cfg.Transport == "stdio" {
+		f, err := stdio.ParseFormat(p.cfg.StdioFormat)
+		if err != nil {
+			return err
+		}
+		format = f
+		env = append(env, "DF_PLUGIN_TRANSPORT=stdio", fmt.Sprintf("DF_PLUGIN_STDIO_FORMAT=%s", format))
+	}
 	for k, v := range p.cfg.Env {
 		env = append(env, fmt.Sprintf("%s=%s", k, v))
 	}
 	cmd.Env = env
 
+	var stdin io.WriteCloser
+	if format != "" {
+		w, err := cmd.StdinPipe()
+		if err != nil {
+			return err
+		}
+		stdin = w
+	}
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return err
@@ -198,30 +292,63 @@ func (p *pluginProcess) launchProcess(ctx context.Context, serverAddress string)
 		return err
 	}
 	p.cmd = cmd
+	p.pid.Store(int32(cmd.Process.Pid))
+	p.metrics.ProcessStarted()
 
-	p.wg.Add(2)
-	go p.consumeOutput(stdout)
-	go p.consumeOutput(stderr)
+	// Output is read to the end before waiting for the process, so that a crash report has the
+	// last lines it wrote.
+	var output sync.WaitGroup
+	output.Add(1)
+	p.wg.Add(1)
+	go p.consumeOutput(stderr, "stderr", &output)
+	if format != "" {
+		// stdout carries protocol frames, so plugin logs are only read from stderr.
+		stream := stdio.NewStream(stdout, stdin, format)
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			if err := p.manager.acceptStream(stream, p.id); err != nil && !p.closed.Load() {
+				p.log.Error("stdio stream", "error", err)
+				_ = stream.Close()
+			}
+		}()
+	} else {
+		output.Add(1)
+		p.wg.Add(1)
+		go p.consumeOutput(stdout, "stdout", &output)
+	}
 
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
-		if err := cmd.Wait(); err != nil && !p.closed.Load() {
-			p.log.Warn("process exited", "error", err)
+		output.Wait()
+		err := cmd.Wait()
+		if p.closed.Load() {
+			return
+		}
+		// Any exit the host did not ask for is a crash, even one with status 0.
+		reason := "process exited: " + cmd.ProcessState.String()
+		if err != nil {
+			reason = fmt.Sprintf("process exited: %v", err)
 		}
+		p.log.Warn("process exited", "reason", reason)
+		p.recordError(reason)
+		p.reportCrash(crash{reason: reason, state: cmd.ProcessState})
 	}()
 	return nil
 }
 
-func (p *pluginProcess) consumeOutput(r io.Reader) {
+func (p *pluginProcess) consumeOutput(r io.Reader, stream string, output *sync.WaitGroup) {
 	defer p.wg.Done()
+	defer output.Done()
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		select {
 		case <-p.done:
 			return
 		default:
-			p.manager.log.Info(scanner.Text())
+			p.history.output(stream, scanner.Text())
+			p.log.Info(scanner.Text())
 		}
 	}
 	if err := scanner.Err(); err != nil && !p.closed.Load() {
@@ -229,32 +356,28 @@ func (p *pluginProcess) consumeOutput(r io.Reader) {
 	}
 }
 
-func (p *pluginProcess) sendServerInfo(plugins []string) error {
-	msg := &pb.HostToPlugin{
+// sendServerInfo queues the reply to a server info request behind the messages already queued.
+func (p *pluginProcess) sendServerInfo(plugins []string) {
+	p.queue(&pb.HostToPlugin{
 		PluginId: p.id,
 		Payload: &pb.HostToPlugin_ServerInfo{
 			ServerInfo: &pb.ServerInformationResponse{
 				Plugins: plugins,
 			},
 		},
-	}
-	payload, err := proto.Marshal(msg)
-	if err != nil {
-		return err
-	}
-	return p.stream.Send(payload)
+	})
 }
 
 func (p *pluginProcess) sendHello() error {
 	msg := &pb.HostToPlugin{
 		PluginId: p.id,
 		Payload: &pb.HostToPlugin_Hello{
-			Hello: &pb.HostHello{
-				ApiVersion: apiVersion,
-				BootId:     p.manager.bootID,
-			},
+			Hello: p.manager.hostHello(p.session.Load()),
 		},
 	}
+	if ms, ok := p.stream.(ports.MessageStream); ok {
+		return ms.SendMessage(msg)
+	}
 	payload, err := proto.Marshal(msg)
 	if err != nil {
 		return err
@@ -262,34 +385,41 @@ func (p *pluginProcess) sendHello() error {
 	return p.stream.Send(payload)
 }
 
-func (p *pluginProcess) sendLoop() {
+func (p *pluginProcess) sendLoop(stream ports.Stream) {
 	defer p.wg.Done()
+	messages, _ := stream.(ports.MessageStream)
 	for {
 		select {
 		case <-p.done:
 			return
 		case msg := <-p.sendCh:
+			p.metrics.QueueDepth(len(p.sendCh))
 			if msg == nil {
 				continue
 			}
 
-			// Get buffer from pool
-			bufPtr := bufferPool.Get().(*[]byte)
-			*bufPtr = (*bufPtr)[:0] // Reset length
+			var err error
+			if messages != nil {
+				err = messages.SendMessage(msg)
+			} else {
+				// Get buffer from pool
+				bufPtr := bufferPool.Get().(*[]byte)
+				*bufPtr = (*bufPtr)[:0] // Reset length
+
+				data, merr := proto.MarshalOptions{}.MarshalAppend(*bufPtr, msg)
+				if merr != nil {
+					p.log.Error("marshal message", "error", merr)
+					bufferPool.Put(bufPtr)
+					continue
+				}
 
-			data, err := proto.MarshalOptions{}.MarshalAppend(*bufPtr, msg)
-			if err != nil {
-				p.log.Error("marshal message", "error", err)
+				// Send using the pooled buffer
+				err = stream.Send(data)
+
+				// Return buffer to pool
 				bufferPool.Put(bufPtr)
-				continue
 			}
 
-			// Send using the pooled buffer
-			err = p.stream.Send(data)
-
-			// Return buffer to pool
-			bufferPool.Put(bufPtr)
-
 			if err != nil {
 				// Treat expected shutdown conditions as non-errors.
 				if st, ok := status.FromError(err); ok && (st.Code() == codes.Canceled || st.Code() == codes.Unavailable) {
@@ -298,19 +428,21 @@ func (p *pluginProcess) sendLoop() {
 					p.log.Info("connection closed", "reason", "canceled")
 				} else {
 					p.log.Error("send message", "error", err)
+					p.recordError(fmt.Sprintf("send message: %v", err))
 				}
 				// Do not kill the process on transient stream errors; allow reconnection.
-				p.clearStream()
+				p.clearStream(stream)
 				return
 			}
 		}
 	}
 }
 
-func (p *pluginProcess) recvLoop() {
+func (p *pluginProcess) recvLoop(stream ports.Stream) {
 	defer p.wg.Done()
+	messages, _ := stream.(ports.MessageStream)
 	for {
-		data, err := p.stream.Recv()
+		msg, err := p.recv(stream, messages)
 		if err != nil {
 			if st, ok := status.FromError(err); ok {
 				switch st.Code() {
@@ -318,27 +450,45 @@ func (p *pluginProcess) recvLoop() {
 					p.log.Info("connection closed", "reason", st.Code().String())
 				default:
 					p.log.Error("receive message", "error", err)
+					p.recordError(fmt.Sprintf("receive message: %v", err))
 				}
 			} else if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) {
 				p.log.Info("connection closed", "reason", "canceled")
 			} else {
 				p.log.Error("receive message", "error", err)
+				p.recordError(fmt.Sprintf("receive message: %v", err))
 			}
 			// Do not kill the process on transient stream errors; allow reconnection.
-			p.clearStream()
+			p.clearStream(stream)
 			return
 		}
-		msg := &pb.PluginToHost{}
-		if err := proto.Unmarshal(data, msg); err != nil {
-			p.log.Error("decode message", "error", err)
+		if msg == nil {
 			continue
 		}
 		p.manager.handlePluginMessage(p, msg)
 	}
 }
 
+// recv reads the next message from stream, or from messages if the stream carries decoded
+// messages. Messages that cannot be decoded are logged and returned as nil.
+func (p *pluginProcess) recv(stream ports.Stream, messages ports.MessageStream) (*pb.PluginToHost, error) {
+	if messages != nil {
+		return messages.RecvMessage()
+	}
+	data, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	msg := &pb.PluginToHost{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		p.log.Error("decode message", "error", err)
+		return nil, nil
+	}
+	return msg, nil
+}
+
 func (p *pluginProcess) HasSubscription(event pb.EventType) bool {
-	if !p.ready.Load() {
+	if !p.ready.Load() || p.closed.Load() {
 		return false
 	}
 	if _, ok := p.subscriptions.Load(pb.EventType_EVENT_TYPE_ALL); ok {
@@ -365,26 +515,38 @@ func (p *pluginProcess) updateSubscriptions(events []pb.EventType) {
 	p.ready.Store(true)
 }
 
-func (p *pluginProcess) queue(msg *pb.HostToPlugin) {
+// queue hands msg to the send loop and reports whether it was accepted.
+func (p *pluginProcess) queue(msg *pb.HostToPlugin) bool {
 	if p.closed.Load() || !p.connected.Load() {
-		return
+		return false
 	}
 	select {
 	case p.sendCh <- msg:
+		p.metrics.QueueDepth(len(p.sendCh))
+		if event := msg.GetEvent(); event != nil {
+			p.tap.publish(event)
+			p.history.event(event)
+		}
+		return true
 	default:
 		p.log.Warn("dropping message", "reason", "queue full")
+		return false
 	}
 }
 
-func (p *pluginProcess) isConnected() bool {
-	return p.connected.Load()
+// receiving reports whether events should be sent to the plugin now. Unhealthy plugins are skipped so
+// cancellable events do not wait on them, but their durable events are still retained for replay.
+func (p *pluginProcess) receiving() bool {
+	return p.connected.Load() && !p.unhealthy.Load()
 }
 
 func (p *pluginProcess) Stop() {
 	if p.closed.CompareAndSwap(false, true) {
+		p.streamMu.Lock()
 		if p.stream != nil {
 			_ = p.stream.Close()
 		}
+		p.streamMu.Unlock()
 		close(p.done)
 		p.pendingMu.Lock()
 		for id, ch := range p.pending {
@@ -393,6 +555,8 @@ func (p *pluginProcess) Stop() {
 		}
 		p.pendingMu.Unlock()
 		p.stopProcess()
+		p.customMetrics.Unregister()
+		p.tap.close()
 
 		// Wait for goroutines to finish with timeout
 		done := make(chan struct{})
@@ -481,9 +645,17 @@ func (p *pluginProcess) deliverEventResult(res *pb.EventResult) {
 }
 
 func (p *pluginProcess) queueEvent(event *pb.EventEnvelope) {
-	if p.closed.Load() || !p.connected.Load() {
+	if p.closed.Load() {
+		return
+	}
+	// Durable events are retained even while disconnected or unhealthy so they can be replayed on resume.
+	event = p.retainEvent(event)
+	if !p.receiving() {
+		p.metrics.EventQueued(event, false)
 		return
 	}
+	p.tap.publish(event)
+	p.history.event(event)
 	p.eventBufferMu.Lock()
 	p.eventBuffer = append(p.eventBuffer, event)
 	shouldFlush := event.Immediate || len(p.eventBuffer) >= 100
@@ -494,6 +666,65 @@ func (p *pluginProcess) queueEvent(event *pb.EventEnvelope) {
 	}
 }
 
+// retainEvent stores durable events in the replay buffer and returns the sequenced copy that should
+// be sent to the plugin. Other events are returned unchanged.
+func (p *pluginProcess) retainEvent(event *pb.EventEnvelope) *pb.EventEnvelope {
+	if p.replay == nil || !isDurableEvent(event.Type) {
+		return event
+	}
+	stored := p.replay.push(event)
+	if !event.ExpectsResponse {
+		return stored
+	}
+	live := proto.Clone(stored).(*pb.EventEnvelope)
+	live.ExpectsResponse = true
+	return live
+}
+
+// observing reports whether the plugin negotiated CapabilityObserver.
+func (p *pluginProcess) observing() bool {
+	return p.session.Load().has(CapabilityObserver)
+}
+
+// observed returns the copy of event sent to an observer, which never answers.
+func observed(event *pb.EventEnvelope) *pb.EventEnvelope {
+	if !event.ExpectsResponse {
+		return event
+	}
+	event = proto.Clone(event).(*pb.EventEnvelope)
+	event.ExpectsResponse = false
+	return event
+}
+
+// resumeSession replays durable events the plugin missed while it was disconnected. Nothing is
+// replayed unless the plugin presents the boot ID of this host instance. Events retained around the
+// reconnect may be delivered twice; plugins should ignore sequences they have already processed.
+func (p *pluginProcess) resumeSession(resume *pb.SessionResume) {
+	if resume == nil || p.replay == nil || !p.session.Load().has(CapabilitySessionResume) {
+		return
+	}
+	if resume.BootId != p.manager.bootID {
+		p.log.Info("not resuming session", "reason", "boot id mismatch")
+		return
+	}
+	events, gap := p.replay.since(resume.LastSequence)
+	if gap != nil {
+		p.log.Warn("replay buffer overflowed", "from_sequence", gap.FromSequence, "to_sequence", gap.ToSequence)
+		p.queue(&pb.HostToPlugin{
+			PluginId: p.id,
+			Payload:  &pb.HostToPlugin_EventGap{EventGap: gap},
+		})
+	}
+	if len(events) == 0 {
+		return
+	}
+	p.log.Info("replaying missed events", "count", len(events), "last_sequence", resume.LastSequence)
+	p.eventBufferMu.Lock()
+	p.eventBuffer = append(events, p.eventBuffer...)
+	p.eventBufferMu.Unlock()
+	p.Flush()
+}
+
 func (p *pluginProcess) Flush() {
 	p.eventBufferMu.Lock()
 	if len(p.eventBuffer) == 0 {
@@ -504,6 +735,18 @@ func (p *pluginProcess) Flush() {
 	// Allocate new buffer, old one is moved to batch
 	p.eventBuffer = make([]*pb.EventEnvelope, 0, cap(p.eventBuffer))
 	p.eventBufferMu.Unlock()
+	p.metrics.EventBatch(len(batch.Events))
+
+	if !p.session.Load().has(CapabilityCompression) {
+		sent := p.queue(&pb.HostToPlugin{
+			PluginId: p.id,
+			Payload: &pb.HostToPlugin_Events{
+				Events: batch,
+			},
+		})
+		p.metrics.EventsQueued(batch.Events, sent)
+		return
+	}
 
 	// Marshal the batch to check its size and potentially compress.
 	originalBatchData, err := proto.Marshal(batch)
@@ -514,7 +757,8 @@ func (p *pluginProcess) Flush() {
 
 	if len(originalBatchData) > compressionThreshold {
 		compressedData := snappy.Encode(nil, originalBatchData)
-		p.queue(&pb.HostToPlugin{
+		p.metrics.Compression(len(originalBatchData), len(compressedData))
+		sent := p.queue(&pb.HostToPlugin{
 			PluginId: p.id,
 			Payload: &pb.HostToPlugin_CompressedEvents{
 				CompressedEvents: &pb.CompressedEventBatch{
@@ -523,21 +767,24 @@ func (p *pluginProcess) Flush() {
 				},
 			},
 		})
+		p.metrics.EventsQueued(batch.Events, sent)
 		p.log.Debug("sent compressed event batch",
 			"original_size", len(originalBatchData),
 			"compressed_size", len(compressedData),
 			"ratio", float64(len(compressedData))/float64(len(originalBatchData)))
 	} else {
-		p.queue(&pb.HostToPlugin{
+		sent := p.queue(&pb.HostToPlugin{
 			PluginId: p.id,
 			Payload: &pb.HostToPlugin_Events{
 				Events: batch,
 			},
 		})
+		p.metrics.EventsQueued(batch.Events, sent)
 		p.log.Debug("sent uncompressed event batch", "size", len(originalBatchData))
 	}
 }
 
+// batchSendLoop flushes buffered events every 5ms.
 func (p *pluginProcess) batchSendLoop() {
 	defer p.wg.Done()
 	ticker := time.NewTicker(5 * time.Millisecond)
@@ -548,21 +795,7 @@ func (p *pluginProcess) batchSendLoop() {
 		case <-p.done:
 			return
 		case <-ticker.C:
-			p.eventBufferMu.Lock()
-			if len(p.eventBuffer) == 0 {
-				p.eventBufferMu.Unlock()
-				continue
-			}
-			batch := &pb.EventBatch{Events: p.eventBuffer}
-			p.eventBuffer = make([]*pb.EventEnvelope, 0)
-			p.eventBufferMu.Unlock()
-
-			p.queue(&pb.HostToPlugin{
-				PluginId: p.id,
-				Payload: &pb.HostToPlugin_Events{
-					Events: batch,
-				},
-			})
+			p.Flush()
 		}
 	}
 }
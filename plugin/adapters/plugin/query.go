@@ -0,0 +1,346 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/google/uuid"
+
+	"github.com/secmc/plugin/plugin/adapters/grpc"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+var (
+	errWorldNotFound   = fmt.Errorf("world %w", ports.ErrNotFound)
+	errPlayerNotFound  = fmt.Errorf("player %w", ports.ErrNotFound)
+	errMissingPosition = errors.New("missing position")
+	errNoServer        = fmt.Errorf("server %w", ports.ErrUnavailable)
+)
+
+var _ grpc.QueryService = (*queryService)(nil)
+
+// queryService answers world, player and server queries. It serves the Query gRPC service and
+// the query actions sent over event streams.
+type queryService struct {
+	pb.UnimplementedQueryServer
+	m *Manager
+}
+
+// AuthorizeQuery accepts calls for plugins that have an open event stream. As for event streams,
+// calls arriving over a Unix socket must come from the plugin's process and other calls must carry
+// its launch token.
+func (q *queryService) AuthorizeQuery(pluginID string, cred *ports.PeerCredentials, token string) error {
+	proc, err := q.m.pluginByID(pluginID)
+	if err != nil {
+		return err
+	}
+	if proc.closed.Load() || !proc.connected.Load() {
+		return fmt.Errorf("plugin %s is not connected", pluginID)
+	}
+	if cred != nil {
+		return proc.verifyCredentials(*cred)
+	}
+	return proc.verifyToken(token)
+}
+
+// sendQueryResult answers a query action with result, or with err if the query failed.
+func (m *Manager) sendQueryResult(p *pluginProcess, correlationID string, result *pb.ActionResult, err error) {
+	if err != nil {
+		m.sendActionError(p, correlationID, err.Error())
+		return
+	}
+	result.CorrelationId = correlationID
+	result.Status = &pb.ActionStatus{Ok: true}
+	m.sendActionResult(p, result)
+}
+
+func (q *queryService) world(ref *pb.WorldRef) (*world.World, error) {
+	if w := q.m.worldFromRef(ref); w != nil {
+		return w, nil
+	}
+	return nil, errWorldNotFound
+}
+
+// worldPos resolves the world and position shared by most world queries.
+func (q *queryService) worldPos(ref *pb.WorldRef, pos *pb.BlockPos) (*world.World, cube.Pos, error) {
+	w, err := q.world(ref)
+	if err != nil {
+		return nil, cube.Pos{}, err
+	}
+	if pos == nil {
+		return nil, cube.Pos{}, errMissingPosition
+	}
+	return w, cube.Pos{int(pos.X), int(pos.Y), int(pos.Z)}, nil
+}
+
+func (q *queryService) WorldEntities(_ context.Context, act *pb.WorldQueryEntitiesAction) (*pb.WorldEntitiesResult, error) {
+	w, err := q.world(act.GetWorld())
+	if err != nil {
+		return nil, err
+	}
+	entities := make([]world.Entity, 0)
+	<-w.Exec(func(tx *world.Tx) {
+		for e := range tx.Entities() {
+			entities = append(entities, e)
+		}
+	})
+	return &pb.WorldEntitiesResult{World: protoWorldRef(w), Entities: protoEntityRefs(entities)}, nil
+}
+
+func (q *queryService) WorldPlayers(_ context.Context, act *pb.WorldQueryPlayersAction) (*pb.WorldPlayersResult, error) {
+	w, err := q.world(act.GetWorld())
+	if err != nil {
+		return nil, err
+	}
+	players := make([]world.Entity, 0)
+	<-w.Exec(func(tx *world.Tx) {
+		for pl := range tx.Players() {
+			players = append(players, pl)
+		}
+	})
+	return &pb.WorldPlayersResult{World: protoWorldRef(w), Players: protoEntityRefs(players)}, nil
+}
+
+func (q *queryService) WorldEntitiesWithin(_ context.Context, act *pb.WorldQueryEntitiesWithinAction) (*pb.WorldEntitiesWithinResult, error) {
+	w, err := q.world(act.GetWorld())
+	if err != nil {
+		return nil, err
+	}
+	box, ok := bboxFromProto(act.Box)
+	if !ok {
+		return nil, errors.New("invalid bounding box")
+	}
+	entities := make([]world.Entity, 0)
+	<-w.Exec(func(tx *world.Tx) {
+		for e := range tx.EntitiesWithin(box) {
+			entities = append(entities, e)
+		}
+	})
+	return &pb.WorldEntitiesWithinResult{World: protoWorldRef(w), Box: protoBBox(box), Entities: protoEntityRefs(entities)}, nil
+}
+
+func (q *queryService) WorldDefaultGameMode(_ context.Context, act *pb.WorldQueryDefaultGameModeAction) (*pb.WorldDefaultGameModeResult, error) {
+	w, err := q.world(act.GetWorld())
+	if err != nil {
+		return nil, err
+	}
+	id, _ := world.GameModeID(w.DefaultGameMode())
+	return &pb.WorldDefaultGameModeResult{World: protoWorldRef(w), GameMode: pb.GameMode(id)}, nil
+}
+
+func (q *queryService) WorldPlayerSpawn(_ context.Context, act *pb.WorldQueryPlayerSpawnAction) (*pb.WorldPlayerSpawnResult, error) {
+	w, err := q.world(act.GetWorld())
+	if err != nil {
+		return nil, err
+	}
+	if act.PlayerUuid == "" {
+		return nil, errors.New("missing player_uuid")
+	}
+	id, err := uuid.Parse(act.PlayerUuid)
+	if err != nil {
+		return nil, errors.New("invalid player_uuid")
+	}
+	return &pb.WorldPlayerSpawnResult{World: protoWorldRef(w), PlayerUuid: act.PlayerUuid, Spawn: protoBlockPos(w.PlayerSpawn(id))}, nil
+}
+
+func (q *queryService) WorldBlock(_ context.Context, act *pb.WorldQueryBlockAction) (*pb.WorldBlockResult, error) {
+	w, pos, err := q.worldPos(act.GetWorld(), act.Position)
+	if err != nil {
+		return nil, err
+	}
+	var block world.Block
+	<-w.Exec(func(tx *world.Tx) {
+		block = tx.Block(pos)
+	})
+	return &pb.WorldBlockResult{World: protoWorldRef(w), Position: act.Position, Block: protoBlockState(block)}, nil
+}
+
+func (q *queryService) WorldBiome(_ context.Context, act *pb.WorldQueryBiomeAction) (*pb.WorldBiomeResult, error) {
+	w, pos, err := q.worldPos(act.GetWorld(), act.Position)
+	if err != nil {
+		return nil, err
+	}
+	var biome world.Biome
+	<-w.Exec(func(tx *world.Tx) {
+		biome = tx.Biome(pos)
+	})
+	return &pb.WorldBiomeResult{World: protoWorldRef(w), Position: act.Position, BiomeId: fmt.Sprintf("%d", biome.EncodeBiome())}, nil
+}
+
+func (q *queryService) WorldLight(_ context.Context, act *pb.WorldQueryLightAction) (*pb.WorldLightResult, error) {
+	w, pos, err := q.worldPos(act.GetWorld(), act.Position)
+	if err != nil {
+		return nil, err
+	}
+	var lightLevel uint8
+	<-w.Exec(func(tx *world.Tx) {
+		lightLevel = tx.Light(pos)
+	})
+	return &pb.WorldLightResult{World: protoWorldRef(w), Position: act.Position, LightLevel: int32(lightLevel)}, nil
+}
+
+func (q *queryService) WorldSkyLight(_ context.Context, act *pb.WorldQuerySkyLightAction) (*pb.WorldSkyLightResult, error) {
+	w, pos, err := q.worldPos(act.GetWorld(), act.Position)
+	if err != nil {
+		return nil, err
+	}
+	var skyLightLevel uint8
+	<-w.Exec(func(tx *world.Tx) {
+		skyLightLevel = tx.SkyLight(pos)
+	})
+	return &pb.WorldSkyLightResult{World: protoWorldRef(w), Position: act.Position, SkyLightLevel: int32(skyLightLevel)}, nil
+}
+
+func (q *queryService) WorldTemperature(_ context.Context, act *pb.WorldQueryTemperatureAction) (*pb.WorldTemperatureResult, error) {
+	w, pos, err := q.worldPos(act.GetWorld(), act.Position)
+	if err != nil {
+		return nil, err
+	}
+	var temperature float64
+	<-w.Exec(func(tx *world.Tx) {
+		temperature = tx.Temperature(pos)
+	})
+	return &pb.WorldTemperatureResult{World: protoWorldRef(w), Position: act.Position, Temperature: temperature}, nil
+}
+
+func (q *queryService) WorldHighestBlock(_ context.Context, act *pb.WorldQueryHighestBlockAction) (*pb.WorldHighestBlockResult, error) {
+	w, err := q.world(act.GetWorld())
+	if err != nil {
+		return nil, err
+	}
+	var y int
+	<-w.Exec(func(tx *world.Tx) {
+		y = tx.HighestBlock(int(act.X), int(act.Z))
+	})
+	return &pb.WorldHighestBlockResult{World: protoWorldRef(w), X: act.X, Z: act.Z, Y: int32(y)}, nil
+}
+
+func (q *queryService) WorldRainingAt(_ context.Context, act *pb.WorldQueryRainingAtAction) (*pb.WorldRainingAtResult, error) {
+	w, pos, err := q.worldPos(act.GetWorld(), act.Position)
+	if err != nil {
+		return nil, err
+	}
+	var raining bool
+	<-w.Exec(func(tx *world.Tx) {
+		raining = tx.RainingAt(pos)
+	})
+	return &pb.WorldRainingAtResult{World: protoWorldRef(w), Position: act.Position, Raining: raining}, nil
+}
+
+func (q *queryService) WorldSnowingAt(_ context.Context, act *pb.WorldQuerySnowingAtAction) (*pb.WorldSnowingAtResult, error) {
+	w, pos, err := q.worldPos(act.GetWorld(), act.Position)
+	if err != nil {
+		return nil, err
+	}
+	var snowing bool
+	<-w.Exec(func(tx *world.Tx) {
+		snowing = tx.SnowingAt(pos)
+	})
+	return &pb.WorldSnowingAtResult{World: protoWorldRef(w), Position: act.Position, Snowing: snowing}, nil
+}
+
+func (q *queryService) WorldThunderingAt(_ context.Context, act *pb.WorldQueryThunderingAtAction) (*pb.WorldThunderingAtResult, error) {
+	w, pos, err := q.worldPos(act.GetWorld(), act.Position)
+	if err != nil {
+		return nil, err
+	}
+	var thundering bool
+	<-w.Exec(func(tx *world.Tx) {
+		thundering = tx.ThunderingAt(pos)
+	})
+	return &pb.WorldThunderingAtResult{World: protoWorldRef(w), Position: act.Position, Thundering: thundering}, nil
+}
+
+func (q *queryService) WorldLiquid(_ context.Context, act *pb.WorldQueryLiquidAction) (*pb.WorldLiquidResult, error) {
+	w, pos, err := q.worldPos(act.GetWorld(), act.Position)
+	if err != nil {
+		return nil, err
+	}
+	var liquidState *pb.LiquidState
+	<-w.Exec(func(tx *world.Tx) {
+		if liq, ok := tx.Liquid(pos); ok {
+			liquidState = protoLiquidState(liq)
+		}
+	})
+	return &pb.WorldLiquidResult{World: protoWorldRef(w), Position: act.Position, Liquid: liquidState}, nil
+}
+
+// Player looks up an online player by UUID or name.
+func (q *queryService) Player(_ context.Context, req *pb.PlayerQuery) (*pb.PlayerInfo, error) {
+	srv := q.m.srv
+	if srv == nil {
+		return nil, errNoServer
+	}
+	var (
+		handle *world.EntityHandle
+		ok     bool
+	)
+	switch {
+	case req.GetPlayerUuid() != "":
+		id, err := uuid.Parse(req.GetPlayerUuid())
+		if err != nil {
+			return nil, errors.New("invalid player_uuid")
+		}
+		handle, ok = srv.Player(id)
+	case strings.TrimSpace(req.GetName()) != "":
+		handle, ok = srv.PlayerByName(req.GetName())
+	default:
+		return nil, errors.New("missing player_uuid or name")
+	}
+	if !ok {
+		return nil, errPlayerNotFound
+	}
+
+	var info *pb.PlayerInfo
+	handle.ExecWorld(func(tx *world.Tx, e world.Entity) {
+		pl, ok := e.(*player.Player)
+		if !ok {
+			return
+		}
+		mode, _ := world.GameModeID(pl.GameMode())
+		info = &pb.PlayerInfo{
+			Player:          protoEntityRef(pl),
+			Name:            pl.Name(),
+			Xuid:            pl.XUID(),
+			World:           protoWorldRef(tx.World()),
+			GameMode:        pb.GameMode(mode),
+			Health:          pl.Health(),
+			MaxHealth:       pl.MaxHealth(),
+			Food:            int32(pl.Food()),
+			ExperienceLevel: int32(pl.ExperienceLevel()),
+			LatencyMs:       pl.Latency().Milliseconds(),
+			Locale:          pl.Locale().String(),
+		}
+		info.Player.Name = &info.Name
+	})
+	if info == nil {
+		return nil, errPlayerNotFound
+	}
+	return info, nil
+}
+
+// Server describes the running server and the plugin host.
+func (q *queryService) Server(context.Context, *pb.ServerQuery) (*pb.ServerInfo, error) {
+	srv := q.m.srv
+	if srv == nil {
+		return nil, errNoServer
+	}
+	info := &pb.ServerInfo{
+		BootId:         q.m.bootID,
+		ApiVersion:     apiVersion,
+		PlayerCount:    int32(srv.PlayerCount()),
+		MaxPlayerCount: int32(srv.MaxPlayerCount()),
+	}
+	for _, w := range []*world.World{srv.World(), srv.Nether(), srv.End()} {
+		if w != nil {
+			info.Worlds = append(info.Worlds, protoWorldRef(w))
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,99 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/ports"
+	"github.com/secmc/plugin/plugin/recording"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// recordingStream records every message that crosses a plugin stream.
+type recordingStream struct {
+	ports.Stream
+	w    *recording.Writer
+	path string
+	log  *slog.Logger
+	// errOnce limits write errors to one log line; the writer keeps returning the same error.
+	errOnce sync.Once
+}
+
+// startRecording wraps stream in a recordingStream if the configuration asks for pluginID to be
+// recorded. first is the message already read from the stream. It returns nil if the connection
+// is not recorded or the recording could not be created.
+//
+// The wrapper only carries bytes, so in-process plugins are marshalled while they are recorded.
+func (m *Manager) startRecording(stream ports.Stream, pluginID string, first *pb.PluginToHost) *recordingStream {
+	cfg := m.recording
+	if cfg.Dir == "" || (len(cfg.Plugins) > 0 && !slices.Contains(cfg.Plugins, pluginID)) {
+		return nil
+	}
+	now := time.Now()
+	data, err := proto.Marshal(first)
+	if err != nil {
+		m.log.Error("create recording", "plugin", pluginID, "error", err)
+		return nil
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		m.log.Error("create recording directory", "plugin", pluginID, "error", err)
+		return nil
+	}
+	name := fmt.Sprintf("%s-%s%s", pluginID, strings.ReplaceAll(now.UTC().Format("20060102T150405.000000"), ".", "-"), recording.Ext)
+	path := filepath.Join(cfg.Dir, name)
+	w, err := recording.Create(path, pluginID, now, int64(max(cfg.MaxSizeMB, 0))<<20)
+	if err != nil {
+		m.log.Error("create recording", "plugin", pluginID, "error", err)
+		return nil
+	}
+	s := &recordingStream{Stream: stream, w: w, path: path, log: m.log.With("plugin", pluginID)}
+	s.record(recording.PluginToHost, now, data)
+	m.log.Info("recording plugin connection", "plugin", pluginID, "path", path)
+	return s
+}
+
+func (s *recordingStream) record(dir recording.Direction, t time.Time, data []byte) {
+	err := s.w.Write(dir, t, data)
+	if errors.Is(err, recording.ErrSizeLimit) {
+		s.errOnce.Do(func() { s.log.Warn("recording stopped at its size limit", "path", s.path) })
+	} else if err != nil {
+		s.errOnce.Do(func() { s.log.Error("write recording", "path", s.path, "error", err) })
+	}
+}
+
+func (s *recordingStream) Send(data []byte) error {
+	err := s.Stream.Send(data)
+	if err == nil {
+		s.record(recording.HostToPlugin, time.Now(), data)
+	}
+	return err
+}
+
+func (s *recordingStream) Recv() ([]byte, error) {
+	data, err := s.Stream.Recv()
+	if err == nil {
+		s.record(recording.PluginToHost, time.Now(), data)
+	}
+	return data, err
+}
+
+func (s *recordingStream) Close() error {
+	s.closeRecording()
+	return s.Stream.Close()
+}
+
+// closeRecording finishes the recording file. It may be called more than once.
+func (s *recordingStream) closeRecording() {
+	if err := s.w.Close(); err != nil {
+		s.log.Error("close recording", "path", s.path, "error", err)
+	}
+}
@@ -0,0 +1,202 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// maxEffectID bounds the effect IDs probed when listing registered effects.
+const maxEffectID = 64
+
+// sendRegistry answers a plugin's RegistryRequest.
+func (m *Manager) sendRegistry(p *pluginProcess, req *pb.RegistryRequest) {
+	reg := m.registry()
+	if req.GetKnownVersion() == reg.Version {
+		reg = &pb.Registry{Version: reg.Version, Unchanged: true}
+	}
+	p.queue(&pb.HostToPlugin{
+		PluginId: p.id,
+		Payload:  &pb.HostToPlugin_Registry{Registry: reg},
+	})
+}
+
+// registry returns the cached registry, building it if custom content changed since it was last
+// built.
+func (m *Manager) registry() *pb.Registry {
+	m.registryMu.Lock()
+	defer m.registryMu.Unlock()
+	if m.registryCache == nil {
+		m.registryCache = m.buildRegistry()
+	}
+	return m.registryCache
+}
+
+// invalidateRegistry drops the cached registry after custom items or blocks were registered.
+func (m *Manager) invalidateRegistry() {
+	m.registryMu.Lock()
+	m.registryCache = nil
+	m.registryMu.Unlock()
+}
+
+func (m *Manager) buildRegistry() *pb.Registry {
+	reg := &pb.Registry{
+		Blocks: registryBlocks(),
+		Items:  registryItems(),
+	}
+
+	entities := entity.DefaultRegistry
+	if m.srv != nil {
+		entities = m.srv.World().EntityRegistry()
+	}
+	for _, t := range entities.Types() {
+		reg.EntityTypes = append(reg.EntityTypes, t.EncodeEntity())
+	}
+	slices.Sort(reg.EntityTypes)
+
+	for _, b := range world.Biomes() {
+		reg.Biomes = append(reg.Biomes, &pb.BiomeType{Name: b.String(), Id: int32(b.EncodeBiome())})
+	}
+	slices.SortFunc(reg.Biomes, func(a, b *pb.BiomeType) int { return int(a.Id - b.Id) })
+
+	for _, e := range item.Enchantments() {
+		id, _ := item.EnchantmentID(e)
+		reg.Enchantments = append(reg.Enchantments, &pb.EnchantmentType{Name: e.Name(), Id: int32(id), MaxLevel: int32(e.MaxLevel())})
+	}
+
+	// Only list enum values the action handlers can actually map.
+	for id := 1; id <= maxEffectID; id++ {
+		if _, ok := effect.ByID(id); ok {
+			reg.Effects = append(reg.Effects, pb.EffectType(id))
+		}
+	}
+	for _, v := range pb.Sound_value {
+		if soundFromProto(pb.Sound(v)) != nil {
+			reg.Sounds = append(reg.Sounds, pb.Sound(v))
+		}
+	}
+	slices.Sort(reg.Sounds)
+	probe := &pb.BlockState{Name: "minecraft:stone"}
+	for _, v := range pb.ParticleType_value {
+		if _, ok := particleFromType(pb.ParticleType(v), probe, nil); ok {
+			reg.Particles = append(reg.Particles, pb.ParticleType(v))
+		}
+	}
+	slices.Sort(reg.Particles)
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(reg)
+	if err == nil {
+		sum := sha256.Sum256(data)
+		reg.Version = hex.EncodeToString(sum[:8])
+	}
+	return reg
+}
+
+// registryBlocks groups every block state by name and collects the values each property takes.
+func registryBlocks() []*pb.BlockType {
+	custom := world.CustomBlocks()
+	byName := make(map[string]*pb.BlockType)
+	values := make(map[string]map[string]map[string]struct{})
+	for _, b := range world.Blocks() {
+		name, props := b.EncodeBlock()
+		bt, ok := byName[name]
+		if !ok {
+			_, isCustom := custom[name]
+			bt = &pb.BlockType{Name: name, Custom: isCustom}
+			byName[name] = bt
+			values[name] = make(map[string]map[string]struct{})
+		}
+		for key, v := range props {
+			set, ok := values[name][key]
+			if !ok {
+				set = make(map[string]struct{})
+				values[name][key] = set
+				bt.Properties = append(bt.Properties, &pb.BlockProperty{Name: key, Type: propertyType(v)})
+			}
+			set[propertyValue(v)] = struct{}{}
+		}
+	}
+
+	blocks := make([]*pb.BlockType, 0, len(byName))
+	for name, bt := range byName {
+		for _, prop := range bt.Properties {
+			for v := range values[name][prop.Name] {
+				prop.Values = append(prop.Values, v)
+			}
+			sortPropertyValues(prop)
+		}
+		slices.SortFunc(bt.Properties, func(a, b *pb.BlockProperty) int { return strings.Compare(a.Name, b.Name) })
+		blocks = append(blocks, bt)
+	}
+	slices.SortFunc(blocks, func(a, b *pb.BlockType) int { return strings.Compare(a.Name, b.Name) })
+	return blocks
+}
+
+func registryItems() []*pb.ItemType {
+	byName := make(map[string]*pb.ItemType)
+	for _, it := range world.Items() {
+		name, meta := it.EncodeItem()
+		t, ok := byName[name]
+		if !ok {
+			_, isCustom := it.(world.CustomItem)
+			t = &pb.ItemType{Name: name, Custom: isCustom}
+			byName[name] = t
+		}
+		t.Metas = append(t.Metas, int32(meta))
+	}
+	items := make([]*pb.ItemType, 0, len(byName))
+	for _, t := range byName {
+		slices.Sort(t.Metas)
+		t.Metas = slices.Compact(t.Metas)
+		items = append(items, t)
+	}
+	slices.SortFunc(items, func(a, b *pb.ItemType) int { return strings.Compare(a.Name, b.Name) })
+	return items
+}
+
+// propertyType names the type of a block property value as listed in BlockProperty.type.
+func propertyType(v any) string {
+	switch v.(type) {
+	case bool:
+		return "bool"
+	case int, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+		return "int"
+	default:
+		return "string"
+	}
+}
+
+// propertyValue formats a block property value the way blockFromProto parses it back.
+func propertyValue(v any) string {
+	switch v := v.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sortPropertyValues(prop *pb.BlockProperty) {
+	if prop.Type != "int" {
+		slices.Sort(prop.Values)
+		return
+	}
+	slices.SortFunc(prop.Values, func(a, b string) int {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	})
+}
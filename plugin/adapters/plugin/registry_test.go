@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func TestBuildRegistryStable(t *testing.T) {
+	m := &Manager{}
+	first, second := m.buildRegistry(), m.buildRegistry()
+	if first.Version == "" {
+		t.Fatal("expected the registry to have a version")
+	}
+	if !proto.Equal(first, second) {
+		t.Fatalf("expected two builds to be equal, got versions %s and %s", first.Version, second.Version)
+	}
+	if len(first.Blocks) == 0 || len(first.Items) == 0 {
+		t.Fatalf("expected blocks and items, got %d blocks and %d items", len(first.Blocks), len(first.Items))
+	}
+	if !slices.IsSortedFunc(first.Blocks, func(a, b *pb.BlockType) int { return strings.Compare(a.Name, b.Name) }) {
+		t.Error("expected blocks sorted by name")
+	}
+	if !slices.IsSortedFunc(first.Items, func(a, b *pb.ItemType) int { return strings.Compare(a.Name, b.Name) }) {
+		t.Error("expected items sorted by name")
+	}
+}
+
+// registryRuns keeps the IDs of the custom content registered by each run of
+// TestRegistryVersionChanges unique, as registrations are global.
+var registryRuns atomic.Int32
+
+func TestRegistryVersionChanges(t *testing.T) {
+	run := registryRuns.Add(1)
+	itemID, blockID := fmt.Sprintf("test:registry_item_%d", run), fmt.Sprintf("test:registry_block_%d", run)
+	log := slog.New(slog.DiscardHandler)
+	m := &Manager{log: log}
+	p := &pluginProcess{id: "registry", log: log}
+	texture := testTexture(t)
+
+	before := m.registry()
+	if m.registry() != before {
+		t.Fatal("expected the registry to be cached")
+	}
+	m.registerCustomItems(p, []*pb.CustomItemDefinition{{Id: itemID, DisplayName: "Registry Item", TextureData: texture}})
+	withItem := m.registry()
+	if withItem.Version == before.Version {
+		t.Fatalf("expected a custom item to change version %s", before.Version)
+	}
+	if i := slices.IndexFunc(withItem.Items, func(it *pb.ItemType) bool { return it.Name == itemID }); i < 0 || !withItem.Items[i].Custom {
+		t.Error("expected the custom item to be listed as custom")
+	}
+
+	m.registerCustomBlocks(p, []*pb.CustomBlockDefinition{{
+		Id:          blockID,
+		DisplayName: "Registry Block",
+		Properties:  &pb.CustomBlockProperties{Cube: true},
+		Textures:    []*pb.CustomBlockTexture{{Name: "registry_block", ImagePng: texture}},
+	}})
+	withBlock := m.registry()
+	if withBlock.Version == withItem.Version {
+		t.Fatalf("expected a custom block to change version %s", withItem.Version)
+	}
+	if i := slices.IndexFunc(withBlock.Blocks, func(b *pb.BlockType) bool { return b.Name == blockID }); i < 0 || !withBlock.Blocks[i].Custom {
+		t.Error("expected the custom block to be listed as custom")
+	}
+}
+
+func testTexture(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 16, 16))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// defaultReplayBufferSize is the number of durable events retained per plugin when the
+// configuration does not specify replay_buffer_size.
+const defaultReplayBufferSize = 256
+
+// durableEvents are the event types retained for replay after a plugin reconnects. These are
+// state-changing events a plugin cannot reconstruct on its own once missed.
+var durableEvents = map[pb.EventType]struct{}{
+	pb.EventType_PLAYER_JOIN:         {},
+	pb.EventType_PLAYER_QUIT:         {},
+	pb.EventType_PLAYER_DEATH:        {},
+	pb.EventType_PLAYER_RESPAWN:      {},
+	pb.EventType_PLAYER_CHANGE_WORLD: {},
+	pb.EventType_WORLD_CLOSE:         {},
+	pb.EventType_PLUGIN_CRASH:        {},
+}
+
+func isDurableEvent(t pb.EventType) bool {
+	_, ok := durableEvents[t]
+	return ok
+}
+
+// replayBuffer is a bounded ring of durable events, each stamped with a per-plugin sequence number.
+type replayBuffer struct {
+	mu     sync.Mutex
+	events []*pb.EventEnvelope
+	start  int
+	size   int
+	// next is the sequence assigned to the next retained event. Sequences start at 1 so that
+	// a last_sequence of 0 means "nothing processed yet".
+	next uint64
+}
+
+func newReplayBuffer(capacity int) *replayBuffer {
+	if capacity <= 0 {
+		capacity = defaultReplayBufferSize
+	}
+	return &replayBuffer{events: make([]*pb.EventEnvelope, capacity), next: 1}
+}
+
+// push stores a copy of the event stamped with the next sequence and returns the copy. The copy
+// never expects a response: by the time it is replayed, nobody is waiting for the result.
+func (b *replayBuffer) push(event *pb.EventEnvelope) *pb.EventEnvelope {
+	stored := proto.Clone(event).(*pb.EventEnvelope)
+	stored.ExpectsResponse = false
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stored.Sequence = b.next
+	b.next++
+	idx := (b.start + b.size) % len(b.events)
+	b.events[idx] = stored
+	if b.size < len(b.events) {
+		b.size++
+	} else {
+		b.start = (b.start + 1) % len(b.events)
+	}
+	return stored
+}
+
+// since returns all retained events with a sequence greater than last. If events after last were
+// already evicted, a gap describing the lost range is returned as well.
+func (b *replayBuffer) since(last uint64) ([]*pb.EventEnvelope, *pb.EventGap) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if last+1 >= b.next {
+		return nil, nil
+	}
+	oldest := b.next - uint64(b.size)
+
+	var gap *pb.EventGap
+	if last+1 < oldest {
+		gap = &pb.EventGap{FromSequence: last + 1, ToSequence: oldest - 1}
+		last = oldest - 1
+	}
+	skip := int(last + 1 - oldest)
+	out := make([]*pb.EventEnvelope, 0, b.size-skip)
+	for i := skip; i < b.size; i++ {
+		out = append(out, b.events[(b.start+i)%len(b.events)])
+	}
+	return out, gap
+}
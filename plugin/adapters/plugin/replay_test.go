@@ -0,0 +1,38 @@
+package plugin
+
+import (
+	"testing"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func TestReplayBufferSince(t *testing.T) {
+	b := newReplayBuffer(3)
+	for i := 0; i < 5; i++ {
+		b.push(&pb.EventEnvelope{Type: pb.EventType_PLAYER_JOIN, ExpectsResponse: true})
+	}
+
+	// Sequences 1..5 were assigned; only 3..5 are retained.
+	events, gap := b.since(0)
+	if gap == nil || gap.FromSequence != 1 || gap.ToSequence != 2 {
+		t.Fatalf("Expected gap 1-2, got %v", gap)
+	}
+	if len(events) != 3 || events[0].Sequence != 3 || events[2].Sequence != 5 {
+		t.Fatalf("Expected sequences 3-5, got %v", events)
+	}
+	if events[0].ExpectsResponse {
+		t.Error("Replayed events should not expect a response")
+	}
+
+	events, gap = b.since(3)
+	if gap != nil {
+		t.Errorf("Expected no gap, got %v", gap)
+	}
+	if len(events) != 2 || events[0].Sequence != 4 {
+		t.Errorf("Expected sequences 4-5, got %v", events)
+	}
+
+	if events, gap := b.since(5); len(events) != 0 || gap != nil {
+		t.Errorf("Expected nothing to replay, got %v %v", events, gap)
+	}
+}
@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"sync"
+	"sync/atomic"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// tailBuffer is how many events a slow tail may fall behind before events are dropped for it.
+const tailBuffer = 256
+
+// eventTap copies events sent to a plugin to admin API tails. Publishing is a single atomic load
+// while nobody is tailing.
+type eventTap struct {
+	mu     sync.Mutex
+	subs   map[chan *pb.EventEnvelope]struct{}
+	count  atomic.Int32
+	closed bool
+}
+
+// subscribe returns a channel receiving published events and a function that ends the
+// subscription. The channel is closed when either is called or the tap is closed.
+func (t *eventTap) subscribe() (<-chan *pb.EventEnvelope, func()) {
+	ch := make(chan *pb.EventEnvelope, tailBuffer)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if t.subs == nil {
+		t.subs = make(map[chan *pb.EventEnvelope]struct{})
+	}
+	t.subs[ch] = struct{}{}
+	t.count.Add(1)
+	return ch, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.subs[ch]; ok {
+			delete(t.subs, ch)
+			t.count.Add(-1)
+			close(ch)
+		}
+	}
+}
+
+func (t *eventTap) publish(event *pb.EventEnvelope) {
+	if t.count.Load() == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for ch := range t.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// close ends every subscription, e.g. when the plugin is stopped.
+func (t *eventTap) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for ch := range t.subs {
+		delete(t.subs, ch)
+		close(ch)
+	}
+	t.count.Store(0)
+}
@@ -0,0 +1,170 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/gitsource"
+)
+
+// defaultHelloTimeout is the time an updated plugin has to send its hello when hello_timeout_ms
+// is not configured.
+const defaultHelloTimeout = 2 * time.Second
+
+// watchUpdates checks the plugin's git repository every poll interval and updates the plugin when
+// the ref it tracks moves, until the plugin is stopped or replaced.
+func (p *pluginProcess) watchUpdates(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(p.cfg.WorkDir.Git.PollIntervalMs) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+		if p.closed.Load() {
+			return
+		}
+		if err := p.checkUpdate(ctx); err != nil {
+			p.log.Warn("plugin update failed", "error", err)
+			p.recordError(fmt.Sprintf("update failed: %v", err))
+		}
+	}
+}
+
+// checkUpdate looks for a new commit and, if there is one, fetches and builds it beside the
+// plugin's checkout while the plugin keeps running, then swaps the plugin over to it. Commits that
+// fail are not tried again.
+func (p *pluginProcess) checkUpdate(ctx context.Context) error {
+	git := p.cfg.WorkDir.Git
+	tr, err := git.Tracking()
+	if err != nil {
+		return err
+	}
+	ref, err := gitsource.Resolve(ctx, git.Remote, tr)
+	if err != nil {
+		return err
+	}
+	if rejected, _ := p.manager.rejectedCommits.Load(p.id); ref.Commit == git.Commit || ref.Commit == rejected {
+		return nil
+	}
+	p.log.Info("plugin update found", "commit", shortCommit(ref.Commit), "ref", ref.Name)
+
+	// Beside the checkout, so that it can be renamed into place.
+	checkout := p.cfg.WorkDir.Path
+	dir, err := os.MkdirTemp(filepath.Dir(checkout), filepath.Base(checkout)+".update-")
+	if err != nil {
+		return err
+	}
+	commit, err := gitsource.Clone(ctx, git.Remote, ref, dir)
+	if err == nil && len(git.Build) > 0 {
+		err = gitsource.Build(ctx, dir, git.Build, p.cfg.Env)
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		if ctx.Err() != nil {
+			return nil
+		}
+		p.manager.rejectedCommits.Store(p.id, ref.Commit)
+		return fmt.Errorf("fetch %s: %w", shortCommit(ref.Commit), err)
+	}
+
+	cfg := p.cfg
+	cfg.WorkDir.Git.Commit = commit
+	p.manager.updatePlugin(p, cfg, dir)
+	// Still there if the update was abandoned.
+	_ = os.RemoveAll(dir)
+	return nil
+}
+
+// updatePlugin stops old, moves the new version of the plugin checked out in dir into its work
+// directory and starts a process running cfg, then waits for it to send its hello. If it does not
+// within the hello timeout, the previous checkout is moved back and the old version is started
+// again. The work directory stays where it was configured, so a persistent checkout is reused
+// at the updated commit by the next run. Updates hold lifecycleMu, so plugins are restarted one
+// at a time.
+func (m *Manager) updatePlugin(old *pluginProcess, cfg config.PluginConfig, dir string) {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	if cur, err := m.pluginByID(old.id); err != nil || cur != old || old.closed.Load() {
+		// Stopped, restarted or removed since the update was found.
+		return
+	}
+	path := cfg.WorkDir.Path
+	from, to := shortCommit(old.cfg.WorkDir.Git.Commit), shortCommit(cfg.WorkDir.Git.Commit)
+	old.log.Info("updating plugin", "from", from, "to", to)
+	m.stopPlugin(old, "updating")
+	if err := swapCheckout(path, dir, previousDir(path)); err != nil {
+		m.rejectedCommits.Store(old.id, cfg.WorkDir.Git.Commit)
+		reason := fmt.Sprintf("update to %s: %v", to, err)
+		old.log.Error("plugin update failed", "commit", to, "error", reason)
+		m.replacePlugin(old, old.cfg).recordError(reason)
+		return
+	}
+	next := m.replacePlugin(old, cfg)
+	if next.waitHello(m.helloTimeout) {
+		next.log.Info("plugin updated", "commit", to)
+		_ = os.RemoveAll(previousDir(path))
+		return
+	}
+
+	m.rejectedCommits.Store(old.id, cfg.WorkDir.Git.Commit)
+	reason := fmt.Sprintf("update to %s sent no hello within %s, rolled back to %s", to, m.helloTimeout, from)
+	next.log.Error("plugin update failed", "commit", to, "error", reason)
+	m.stopPlugin(next, "rolling back")
+	if err := swapCheckout(path, previousDir(path), ""); err != nil {
+		next.log.Error("restore plugin checkout", "error", err)
+	}
+	m.replacePlugin(next, old.cfg).recordError(reason)
+}
+
+// previousDir keeps the checkout an update replaced at path until the update has sent its hello.
+func previousDir(path string) string {
+	return path + ".previous"
+}
+
+// swapCheckout moves the checkout at next to path. The checkout it replaces is moved to prev, or
+// removed if prev is empty.
+func swapCheckout(path, next, prev string) error {
+	if prev == "" {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	} else {
+		if err := os.RemoveAll(prev); err != nil {
+			return err
+		}
+		if err := os.Rename(path, prev); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(next, path); err != nil {
+		if prev != "" {
+			_ = os.Rename(prev, path)
+		}
+		return err
+	}
+	return nil
+}
+
+// waitHello reports whether the plugin sends its hello within timeout.
+func (p *pluginProcess) waitHello(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for p.helloInfo() == nil {
+		if p.closed.Load() || time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return true
+}
+
+func shortCommit(commit string) string {
+	return commit[:min(len(commit), 12)]
+}
@@ -0,0 +1,155 @@
+//go:build linux
+
+package shm
+
+import (
+	"encoding/binary"
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ringHeaderSize keeps each ring's data page aligned. The counters below live on separate cache
+	// lines so the producer and consumer do not false-share.
+	ringHeaderSize = 4096
+
+	headOffset          = 0
+	tailOffset          = 64
+	readerWaitingOffset = 128
+	writerWaitingOffset = 192
+
+	// spinIterations is how often a blocked side re-checks the ring before sleeping on its eventfd.
+	spinIterations = 200
+)
+
+// ring is one direction of a stream: a byte pipe in shared memory with a single writer and a
+// single reader. head and tail only ever grow; their difference is the number of unread bytes.
+type ring struct {
+	head          *uint64
+	tail          *uint64
+	readerWaiting *uint32
+	writerWaiting *uint32
+	data          []byte
+	mask          uint64
+
+	// readable wakes a reader waiting for data, writable wakes a writer waiting for space.
+	readable int
+	writable int
+
+	closed *atomic.Bool
+}
+
+func newRing(mem []byte, capacity int, readable, writable int, closed *atomic.Bool) *ring {
+	return &ring{
+		head:          (*uint64)(unsafe.Pointer(&mem[headOffset])),
+		tail:          (*uint64)(unsafe.Pointer(&mem[tailOffset])),
+		readerWaiting: (*uint32)(unsafe.Pointer(&mem[readerWaitingOffset])),
+		writerWaiting: (*uint32)(unsafe.Pointer(&mem[writerWaitingOffset])),
+		data:          mem[ringHeaderSize : ringHeaderSize+capacity],
+		mask:          uint64(capacity - 1),
+		readable:      readable,
+		writable:      writable,
+		closed:        closed,
+	}
+}
+
+// writeFrame writes a length-prefixed frame. Frames larger than the ring are streamed through it
+// as the reader frees space.
+func (r *ring) writeFrame(data []byte) error {
+	var header [4]byte
+	binary.LittleEndian.PutUint32(header[:], uint32(len(data)))
+	if err := r.write(header[:]); err != nil {
+		return err
+	}
+	return r.write(data)
+}
+
+func (r *ring) write(p []byte) error {
+	capacity := uint64(len(r.data))
+	for len(p) > 0 {
+		head := atomic.LoadUint64(r.head)
+		free := capacity - (head - atomic.LoadUint64(r.tail))
+		if free == 0 {
+			if err := r.wait(r.writerWaiting, r.writable, func() bool {
+				return atomic.LoadUint64(r.tail)+capacity > head
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		n := min(free, uint64(len(p)))
+		off := head & r.mask
+		copied := copy(r.data[off:], p[:n])
+		copy(r.data, p[copied:n])
+		atomic.StoreUint64(r.head, head+n)
+		p = p[n:]
+		if atomic.LoadUint32(r.readerWaiting) != 0 {
+			signal(r.readable)
+		}
+	}
+	return nil
+}
+
+func (r *ring) read(p []byte) error {
+	for len(p) > 0 {
+		tail := atomic.LoadUint64(r.tail)
+		available := atomic.LoadUint64(r.head) - tail
+		if available == 0 {
+			if err := r.wait(r.readerWaiting, r.readable, func() bool {
+				return atomic.LoadUint64(r.head) > tail
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		n := min(available, uint64(len(p)))
+		off := tail & r.mask
+		copied := copy(p[:n], r.data[off:])
+		copy(p[copied:n], r.data)
+		atomic.StoreUint64(r.tail, tail+n)
+		p = p[n:]
+		if atomic.LoadUint32(r.writerWaiting) != 0 {
+			signal(r.writable)
+		}
+	}
+	return nil
+}
+
+// wait blocks until ready reports true or the stream is closed. It spins briefly first so a peer
+// that is keeping up never costs a syscall, then advertises itself through the waiting flag and
+// sleeps on the eventfd. The flag is set before ready is re-checked, so a wakeup cannot be lost.
+func (r *ring) wait(waiting *uint32, efd int, ready func() bool) error {
+	for i := 0; i < spinIterations; i++ {
+		if ready() {
+			return nil
+		}
+		if r.closed.Load() {
+			return errClosed
+		}
+		runtime.Gosched()
+	}
+
+	atomic.StoreUint32(waiting, 1)
+	defer atomic.StoreUint32(waiting, 0)
+	var buf [8]byte
+	for {
+		if ready() {
+			return nil
+		}
+		if r.closed.Load() {
+			return errClosed
+		}
+		if _, err := unix.Read(efd, buf[:]); err != nil && err != unix.EINTR {
+			return err
+		}
+	}
+}
+
+func signal(efd int) {
+	var buf [8]byte
+	binary.NativeEndian.PutUint64(buf[:], 1)
+	_, _ = unix.Write(efd, buf[:])
+}
@@ -0,0 +1,29 @@
+// Package shm implements a shared-memory transport for plugins running on the same Linux host.
+//
+// A plugin connects to a Unix socket and receives a memfd holding two single-producer,
+// single-consumer byte rings (host to plugin and plugin to host) together with eventfds used to
+// wake a peer that is blocked on an empty or full ring. Frames are a 4-byte little-endian length
+// followed by the protobuf bytes. While both sides keep up, frames move without any syscalls; the
+// Unix socket stays open only to detect when either side goes away.
+package shm
+
+import (
+	"errors"
+
+	"github.com/secmc/plugin/plugin/ports"
+)
+
+const (
+	// DefaultRingSize is the capacity in bytes of each direction's ring.
+	DefaultRingSize = 1 << 20
+
+	// maxFrameSize bounds the length a peer writes ahead of a frame, and the ring size it offers.
+	// Frames larger than a ring are streamed through it, so the limit only guards the host against
+	// a corrupt or hostile length.
+	maxFrameSize = 32 << 20
+)
+
+// StreamHandler is called when a new plugin connects
+type StreamHandler func(stream ports.Stream) error
+
+var errClosed = errors.New("stream closed")
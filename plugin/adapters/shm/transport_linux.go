@@ -0,0 +1,347 @@
+//go:build linux
+
+package shm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/ports"
+)
+
+const (
+	handshakeMagic   = "DFSM"
+	handshakeVersion = 1
+	handshakeSize    = 16
+)
+
+const (
+	// Eventfds are passed in this order after the memfd.
+	ring0Readable = iota
+	ring0Writable
+	ring1Readable
+	ring1Writable
+	eventfdCount
+)
+
+// Server accepts plugin connections on a Unix socket and hands each one a shared-memory stream.
+type Server struct {
+	listener *net.UnixListener
+	handler  StreamHandler
+	ringSize int
+
+	mu      sync.Mutex
+	streams map[*Stream]struct{}
+}
+
+// NewServer creates a shared-memory server that plugins will connect to. perms is applied to the
+// socket file.
+func NewServer(address string, perms unixsocket.Permissions, handler StreamHandler) (*Server, error) {
+	address = strings.TrimPrefix(address, "unix://")
+	os.Remove(address) // Clean up old socket file
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: address, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("listen failed: %w", err)
+	}
+	if err := perms.Apply(address); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return &Server{
+		listener: listener,
+		handler:  handler,
+		ringSize: DefaultRingSize,
+		streams:  make(map[*Stream]struct{}),
+	}, nil
+}
+
+// Serve starts accepting plugin connections
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.AcceptUnix()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Stop closes the listener and every open stream
+func (s *Server) Stop() {
+	_ = s.listener.Close()
+	os.Remove(s.listener.Addr().String())
+
+	s.mu.Lock()
+	streams := s.streams
+	s.streams = make(map[*Stream]struct{})
+	s.mu.Unlock()
+	for stream := range streams {
+		_ = stream.Close()
+	}
+}
+
+// Address returns the address the server is listening on
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+func (s *Server) serveConn(conn *net.UnixConn) {
+	stream, err := accept(conn, s.ringSize)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	s.mu.Lock()
+	s.streams[stream] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.streams, stream)
+		s.mu.Unlock()
+		_ = stream.Close()
+	}()
+
+	if s.handler != nil {
+		_ = s.handler(stream)
+	}
+}
+
+// Stream is one end of a shared-memory connection between the host and a plugin
+type Stream struct {
+	conn *net.UnixConn
+	mem  []byte
+	fds  []int
+	tx   *ring
+	rx   *ring
+
+	cred    ports.PeerCredentials
+	hasCred bool
+
+	sendMu sync.Mutex
+	recvMu sync.Mutex
+	// inUse is read-locked by Send and Recv for as long as they touch the mapping, so Close can
+	// wait for them before unmapping it.
+	inUse  sync.RWMutex
+	closed atomic.Bool
+	// eof is set once either side has closed. Blocked reads drain what is left in the ring first.
+	eof       atomic.Bool
+	closeOnce sync.Once
+}
+
+var _ ports.PeerStream = (*Stream)(nil)
+
+// accept creates the shared memory for a new connection and passes it to the plugin. Ring 0
+// carries host to plugin frames, ring 1 plugin to host frames.
+func accept(conn *net.UnixConn, ringSize int) (*Stream, error) {
+	size := 2 * (ringHeaderSize + ringSize)
+	memfd, err := unix.MemfdCreate("df-plugin-shm", unix.MFD_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("create memfd: %w", err)
+	}
+	defer unix.Close(memfd)
+	if err := unix.Ftruncate(memfd, int64(size)); err != nil {
+		return nil, fmt.Errorf("size memfd: %w", err)
+	}
+
+	fds := make([]int, 0, eventfdCount)
+	for range eventfdCount {
+		fd, err := unix.Eventfd(0, unix.EFD_CLOEXEC)
+		if err != nil {
+			closeFds(fds)
+			return nil, fmt.Errorf("create eventfd: %w", err)
+		}
+		fds = append(fds, fd)
+	}
+
+	var header [handshakeSize]byte
+	copy(header[:4], handshakeMagic)
+	binary.LittleEndian.PutUint32(header[4:8], handshakeVersion)
+	binary.LittleEndian.PutUint64(header[8:], uint64(ringSize))
+	rights := unix.UnixRights(append([]int{memfd}, fds...)...)
+	if _, _, err := conn.WriteMsgUnix(header[:], rights, nil); err != nil {
+		closeFds(fds)
+		return nil, fmt.Errorf("send handshake: %w", err)
+	}
+	return newStream(conn, memfd, size, ringSize, fds, true)
+}
+
+// Dial connects to a shared-memory server at address. It is used by Go plugins and tests.
+func Dial(address string) (*Stream, error) {
+	address = strings.TrimPrefix(address, "unix://")
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: address, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	stream, err := handshake(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return stream, nil
+}
+
+func handshake(conn *net.UnixConn) (*Stream, error) {
+	var header [handshakeSize]byte
+	oob := make([]byte, unix.CmsgSpace((1+eventfdCount)*4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(header[:], oob)
+	if err != nil {
+		return nil, fmt.Errorf("read handshake: %w", err)
+	}
+	msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		return nil, fmt.Errorf("parse handshake: %w", err)
+	}
+	var fds []int
+	for _, msg := range msgs {
+		rights, err := unix.ParseUnixRights(&msg)
+		if err != nil {
+			closeFds(fds)
+			return nil, fmt.Errorf("parse handshake: %w", err)
+		}
+		fds = append(fds, rights...)
+	}
+	if n != handshakeSize || string(header[:4]) != handshakeMagic || len(fds) != 1+eventfdCount {
+		closeFds(fds)
+		return nil, errors.New("invalid shared-memory handshake")
+	}
+	if v := binary.LittleEndian.Uint32(header[4:8]); v != handshakeVersion {
+		closeFds(fds)
+		return nil, fmt.Errorf("unsupported shared-memory version %d", v)
+	}
+	memfd := fds[0]
+	defer unix.Close(memfd)
+
+	ringSize := binary.LittleEndian.Uint64(header[8:])
+	if ringSize == 0 || ringSize&(ringSize-1) != 0 || ringSize > maxFrameSize {
+		closeFds(fds[1:])
+		return nil, fmt.Errorf("invalid ring size %d", ringSize)
+	}
+	size := 2 * (ringHeaderSize + int(ringSize))
+	var st unix.Stat_t
+	if err := unix.Fstat(memfd, &st); err != nil || st.Size < int64(size) {
+		closeFds(fds[1:])
+		return nil, errors.New("shared memory smaller than advertised")
+	}
+	return newStream(conn, memfd, size, int(ringSize), fds[1:], false)
+}
+
+func newStream(conn *net.UnixConn, memfd, size, ringSize int, fds []int, host bool) (*Stream, error) {
+	mem, err := unix.Mmap(memfd, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+	if err != nil {
+		closeFds(fds)
+		return nil, fmt.Errorf("map shared memory: %w", err)
+	}
+	s := &Stream{conn: conn, mem: mem, fds: fds}
+	s.cred, s.hasCred = unixsocket.Credentials(conn)
+	ring0 := newRing(mem, ringSize, fds[ring0Readable], fds[ring0Writable], &s.eof)
+	ring1 := newRing(mem[ringHeaderSize+ringSize:], ringSize, fds[ring1Readable], fds[ring1Writable], &s.eof)
+	if host {
+		s.tx, s.rx = ring0, ring1
+	} else {
+		s.tx, s.rx = ring1, ring0
+	}
+	go s.watch()
+	return s, nil
+}
+
+// watch marks the stream as finished once the peer closes its end of the Unix socket or exits.
+func (s *Stream) watch() {
+	_, _ = io.Copy(io.Discard, s.conn)
+	s.finish()
+}
+
+// finish wakes any Send or Recv blocked on the rings so they observe eof.
+func (s *Stream) finish() {
+	if s.eof.CompareAndSwap(false, true) {
+		signal(s.tx.writable)
+		signal(s.rx.readable)
+	}
+}
+
+// Send writes a marshaled message to the peer
+func (s *Stream) Send(data []byte) error {
+	s.inUse.RLock()
+	defer s.inUse.RUnlock()
+	if s.eof.Load() {
+		return errClosed
+	}
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.tx.writeFrame(data)
+}
+
+// Recv reads the next message from the peer
+func (s *Stream) Recv() ([]byte, error) {
+	s.inUse.RLock()
+	defer s.inUse.RUnlock()
+	if s.closed.Load() {
+		return nil, io.EOF
+	}
+	s.recvMu.Lock()
+	defer s.recvMu.Unlock()
+
+	var header [4]byte
+	if err := s.rx.read(header[:]); err != nil {
+		return nil, s.recvErr(err)
+	}
+	size := binary.LittleEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes exceeds limit", size)
+	}
+	data := make([]byte, size)
+	if err := s.rx.read(data); err != nil {
+		return nil, s.recvErr(err)
+	}
+	return data, nil
+}
+
+func (s *Stream) recvErr(err error) error {
+	if errors.Is(err, errClosed) {
+		return io.EOF
+	}
+	return err
+}
+
+// PeerCredentials returns the credentials of the process on the other end of the stream.
+func (s *Stream) PeerCredentials() (ports.PeerCredentials, bool) {
+	return s.cred, s.hasCred
+}
+
+// CloseSend closes the stream; the shared rings cannot be half-closed.
+func (s *Stream) CloseSend() error {
+	return s.Close()
+}
+
+// Close wakes any blocked Send or Recv, waits for them to return and releases the shared memory.
+func (s *Stream) Close() error {
+	s.closeOnce.Do(func() {
+		s.closed.Store(true)
+		_ = s.conn.Close()
+		s.finish()
+
+		s.inUse.Lock()
+		defer s.inUse.Unlock()
+		_ = unix.Munmap(s.mem)
+		closeFds(s.fds)
+	})
+	return nil
+}
+
+func closeFds(fds []int) {
+	for _, fd := range fds {
+		_ = unix.Close(fd)
+	}
+}
@@ -0,0 +1,211 @@
+//go:build linux
+
+package shm_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	gorilla "github.com/gorilla/websocket"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+
+	grpclib "google.golang.org/grpc"
+
+	"github.com/secmc/plugin/plugin/adapters/grpc"
+	"github.com/secmc/plugin/plugin/adapters/shm"
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/adapters/websocket"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// echo sends every received frame straight back.
+func echo(stream ports.Stream) error {
+	for {
+		data, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		if err := stream.Send(data); err != nil {
+			return err
+		}
+	}
+}
+
+func startShm(tb testing.TB) ports.Stream {
+	tb.Helper()
+	server, err := shm.NewServer(filepath.Join(tb.TempDir(), "shm.sock"), unixsocket.Permissions{}, echo)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	go server.Serve()
+	tb.Cleanup(server.Stop)
+
+	client, err := shm.Dial(server.Address())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	client := startShm(t)
+	// Larger than a ring, so the frame has to be streamed through it in pieces.
+	for _, size := range []int{0, 1, 100, shm.DefaultRingSize*2 + 3} {
+		payload := bytes.Repeat([]byte{byte(size)}, size)
+		if err := client.Send(payload); err != nil {
+			t.Fatalf("send %d bytes: %v", size, err)
+		}
+		got, err := client.Recv()
+		if err != nil {
+			t.Fatalf("recv %d bytes: %v", size, err)
+		}
+		if !bytes.Equal(got, payload) {
+			t.Fatalf("frame of %d bytes came back as %d bytes", size, len(got))
+		}
+	}
+}
+
+// grpcClient adapts a raw gRPC client stream to ports.Stream.
+type grpcClient struct {
+	stream grpclib.ClientStream
+}
+
+func (c grpcClient) Send(data []byte) error { return c.stream.SendMsg(&data) }
+func (c grpcClient) Recv() ([]byte, error) {
+	var data []byte
+	err := c.stream.RecvMsg(&data)
+	return data, err
+}
+func (c grpcClient) CloseSend() error { return c.stream.CloseSend() }
+func (c grpcClient) Close() error     { return nil }
+
+type rawCodec struct{}
+
+func (rawCodec) Name() string { return "proto" }
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	return *v.(*[]byte), nil
+}
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	*v.(*[]byte) = append((*v.(*[]byte))[:0], data...)
+	return nil
+}
+
+func startGrpc(tb testing.TB, address string) ports.Stream {
+	tb.Helper()
+	server, err := grpc.NewServer(address, unixsocket.Permissions{}, echo, nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	go server.Serve()
+	tb.Cleanup(server.Stop)
+
+	target := server.Address()
+	if filepath.IsAbs(target) {
+		target = "unix:" + target
+	}
+	conn, err := grpclib.NewClient(target,
+		grpclib.WithTransportCredentials(insecure.NewCredentials()),
+		grpclib.WithDefaultCallOptions(grpclib.ForceCodec(rawCodec{})),
+	)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	tb.Cleanup(func() {
+		cancel()
+		_ = conn.Close()
+	})
+	desc := &grpclib.StreamDesc{StreamName: "EventStream", ServerStreams: true, ClientStreams: true}
+	stream, err := conn.NewStream(ctx, desc, "/df.plugin.Plugin/EventStream")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return grpcClient{stream: stream}
+}
+
+// wsClient adapts a WebSocket connection to ports.Stream.
+type wsClient struct {
+	conn *gorilla.Conn
+}
+
+func (c wsClient) Send(data []byte) error { return c.conn.WriteMessage(gorilla.BinaryMessage, data) }
+func (c wsClient) Recv() ([]byte, error) {
+	_, data, err := c.conn.ReadMessage()
+	return data, err
+}
+func (c wsClient) CloseSend() error { return nil }
+func (c wsClient) Close() error     { return c.conn.Close() }
+
+func startWebSocket(tb testing.TB) ports.Stream {
+	tb.Helper()
+	server, err := websocket.NewServer("127.0.0.1:0", nil, echo)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	go server.Serve()
+	tb.Cleanup(server.Stop)
+
+	u := url.URL{Scheme: "ws", Host: server.Address()}
+	dialer := gorilla.Dialer{Subprotocols: []string{websocket.SubprotocolProto}}
+	conn, _, err := dialer.Dial(u.String(), nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { _ = conn.Close() })
+	return wsClient{conn: conn}
+}
+
+// BenchmarkRoundTrip measures a movement event travelling to an echoing peer and back over each
+// transport.
+func BenchmarkRoundTrip(b *testing.B) {
+	event, err := proto.Marshal(&pb.HostToPlugin{
+		PluginId: "bench",
+		Payload: &pb.HostToPlugin_Event{Event: &pb.EventEnvelope{
+			EventId: "1",
+			Type:    pb.EventType_PLAYER_MOVE,
+			Payload: &pb.EventEnvelope_PlayerMove{PlayerMove: &pb.PlayerMoveEvent{
+				PlayerUuid: "8f6c0f8e-6a77-4a71-9a4b-9b6b1b0d1f55",
+				Name:       "Steve",
+				Position:   &pb.Vec3{X: 12.5, Y: 64, Z: -3.25},
+				Rotation:   &pb.Rotation{Yaw: 90, Pitch: 10},
+			}},
+		}},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	transports := []struct {
+		name  string
+		start func(testing.TB) ports.Stream
+	}{
+		{"shm", startShm},
+		{"grpc-unix", func(tb testing.TB) ports.Stream {
+			return startGrpc(tb, filepath.Join(tb.TempDir(), "grpc.sock"))
+		}},
+		{"grpc-tcp", func(tb testing.TB) ports.Stream { return startGrpc(tb, "127.0.0.1:0") }},
+		{"websocket", startWebSocket},
+	}
+	for _, tr := range transports {
+		b.Run(tr.name, func(b *testing.B) {
+			client := tr.start(b)
+			b.SetBytes(int64(len(event)))
+			b.ResetTimer()
+			for range b.N {
+				if err := client.Send(event); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := client.Recv(); err != nil {
+					b.Fatal(fmt.Errorf("%s: %w", tr.name, err))
+				}
+			}
+		})
+	}
+}
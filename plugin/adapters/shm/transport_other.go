@@ -0,0 +1,42 @@
+//go:build !linux
+
+package shm
+
+import (
+	"errors"
+
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/ports"
+)
+
+var errUnsupported = errors.New("shared-memory transport is only supported on linux")
+
+// Server is unavailable on this platform; NewServer always fails.
+type Server struct{}
+
+// NewServer reports that the shared-memory transport is unsupported on this platform
+func NewServer(string, unixsocket.Permissions, StreamHandler) (*Server, error) {
+	return nil, errUnsupported
+}
+
+func (*Server) Serve() error    { return errUnsupported }
+func (*Server) Stop()           {}
+func (*Server) Address() string { return "" }
+
+// Stream is unavailable on this platform; Dial always fails.
+type Stream struct{}
+
+var _ ports.PeerStream = (*Stream)(nil)
+
+// Dial reports that the shared-memory transport is unsupported on this platform
+func Dial(string) (*Stream, error) {
+	return nil, errUnsupported
+}
+
+func (*Stream) Send([]byte) error     { return errUnsupported }
+func (*Stream) Recv() ([]byte, error) { return nil, errUnsupported }
+func (*Stream) PeerCredentials() (ports.PeerCredentials, bool) {
+	return ports.PeerCredentials{}, false
+}
+func (*Stream) CloseSend() error { return nil }
+func (*Stream) Close() error     { return nil }
@@ -0,0 +1,167 @@
+package stdio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// Format selects how frames are encoded on the plugin's stdin/stdout.
+type Format string
+
+const (
+	// FormatProtobuf frames each message as a 4-byte big-endian length followed by protobuf bytes.
+	FormatProtobuf Format = "protobuf"
+	// FormatJSON writes each message as a single line of protojson.
+	FormatJSON Format = "json"
+
+	// maxFrameSize bounds a length prefix or JSON line read from a plugin's stdout. A plugin
+	// printing stray output there is disconnected instead of the host allocating whatever length
+	// the first four bytes spell, or buffering a line that never ends.
+	maxFrameSize = 32 << 20
+)
+
+// ParseFormat parses a configured stdio format. An empty string selects FormatProtobuf.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatProtobuf:
+		return FormatProtobuf, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown stdio format %q", s)
+	}
+}
+
+// Stream carries plugin frames over a child process's stdin (host to plugin) and stdout (plugin to
+// host). The plugin's logs are expected on stderr.
+type Stream struct {
+	r      *bufio.Reader
+	w      io.WriteCloser
+	format Format
+
+	mu     sync.Mutex
+	closed bool
+}
+
+var _ ports.Stream = (*Stream)(nil)
+
+// NewStream wraps the read end of a plugin's stdout and the write end of its stdin.
+func NewStream(stdout io.Reader, stdin io.WriteCloser, format Format) *Stream {
+	return &Stream{r: bufio.NewReaderSize(stdout, 64*1024), w: stdin, format: format}
+}
+
+// Send writes a marshaled HostToPlugin message, converting it to protojson for JSON streams.
+func (s *Stream) Send(data []byte) error {
+	var frame []byte
+	if s.format == FormatJSON {
+		msg := &pb.HostToPlugin{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return fmt.Errorf("decode outgoing message: %w", err)
+		}
+		encoded, err := protojson.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("encode json message: %w", err)
+		}
+		frame = append(encoded, '\n')
+	} else {
+		frame = make([]byte, 4+len(data))
+		binary.BigEndian.PutUint32(frame, uint32(len(data)))
+		copy(frame[4:], data)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("stream closed")
+	}
+	_, err := s.w.Write(frame)
+	return err
+}
+
+// Recv reads the next PluginToHost frame and returns it in protobuf wire format.
+func (s *Stream) Recv() ([]byte, error) {
+	if s.format == FormatJSON {
+		return s.recvJSON()
+	}
+	var header [4]byte
+	if _, err := io.ReadFull(s.r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes exceeds limit", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(s.r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *Stream) recvJSON() ([]byte, error) {
+	for {
+		line, err := s.readLine()
+		if err != nil && (len(line) == 0 || !errors.Is(err, io.EOF)) {
+			return nil, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		msg := &pb.PluginToHost{}
+		if err := protojson.Unmarshal(line, msg); err != nil {
+			return nil, fmt.Errorf("decode json message: %w", err)
+		}
+		return proto.Marshal(msg)
+	}
+}
+
+// readLine reads up to and including the next newline. It fails as soon as the line grows past
+// maxFrameSize, so at most that much of an unterminated line is ever buffered.
+func (s *Stream) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := s.r.ReadSlice('\n')
+		if len(line)+len(chunk) > maxFrameSize {
+			return nil, fmt.Errorf("json line exceeds limit of %d bytes", maxFrameSize)
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			if line == nil {
+				// ReadSlice's result is only valid until the next read.
+				return bytes.Clone(chunk), err
+			}
+			return append(line, chunk...), err
+		}
+		line = append(line, chunk...)
+	}
+}
+
+// CloseSend closes the plugin's stdin, signalling that no more messages will follow.
+func (s *Stream) CloseSend() error {
+	return s.Close()
+}
+
+// Close closes the plugin's stdin. Its stdout is closed when the process exits.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.w.Close()
+}
@@ -0,0 +1,153 @@
+package stdio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// pipeStream returns a stream whose plugin end writes to stdout and reads from stdin.
+func pipeStream(t *testing.T, format Format) (s *Stream, stdout *io.PipeWriter, stdin *bufio.Reader) {
+	t.Helper()
+	outR, outW := io.Pipe()
+	inR, inW := io.Pipe()
+	t.Cleanup(func() {
+		_ = outW.Close()
+		_ = inR.Close()
+	})
+	return NewStream(outR, inW, format), outW, bufio.NewReader(inR)
+}
+
+// header is the length prefix of a FormatProtobuf frame of size bytes.
+func header(size int) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(size))
+}
+
+func TestFraming(t *testing.T) {
+	hello := &pb.PluginToHost{PluginId: "stdio", Payload: &pb.PluginToHost_Hello{Hello: &pb.PluginHello{Name: "stdio", ApiVersion: "v1"}}}
+	reply := &pb.HostToPlugin{PluginId: "stdio", Payload: &pb.HostToPlugin_Hello{Hello: &pb.HostHello{ApiVersion: "v1"}}}
+	for _, tc := range []struct {
+		name   string
+		format Format
+		// encode frames a message the way the plugin writes it.
+		encode func(proto.Message) []byte
+		// decode reads a frame the host wrote into m.
+		decode func(*bufio.Reader, proto.Message) error
+	}{
+		{
+			name:   "length-prefixed protobuf",
+			format: FormatProtobuf,
+			encode: func(m proto.Message) []byte {
+				data, _ := proto.Marshal(m)
+				return append(header(len(data)), data...)
+			},
+			decode: func(r *bufio.Reader, m proto.Message) error {
+				var prefix [4]byte
+				if _, err := io.ReadFull(r, prefix[:]); err != nil {
+					return err
+				}
+				data := make([]byte, binary.BigEndian.Uint32(prefix[:]))
+				if _, err := io.ReadFull(r, data); err != nil {
+					return err
+				}
+				return proto.Unmarshal(data, m)
+			},
+		},
+		{
+			name:   "newline-delimited protojson",
+			format: FormatJSON,
+			encode: func(m proto.Message) []byte {
+				data, _ := protojson.Marshal(m)
+				// Blank lines between messages are skipped.
+				return append(append([]byte("\n"), data...), '\n')
+			},
+			decode: func(r *bufio.Reader, m proto.Message) error {
+				line, err := r.ReadBytes('\n')
+				if err != nil {
+					return err
+				}
+				return protojson.Unmarshal(line, m)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, stdout, stdin := pipeStream(t, tc.format)
+			go func() { _, _ = stdout.Write(tc.encode(hello)) }()
+			data, err := s.Recv()
+			if err != nil {
+				t.Fatalf("recv: %v", err)
+			}
+			got := &pb.PluginToHost{}
+			if err := proto.Unmarshal(data, got); err != nil || !proto.Equal(got, hello) {
+				t.Errorf("Expected %v, got %v (%v)", hello, got, err)
+			}
+
+			data, err = proto.Marshal(reply)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sent := make(chan error, 1)
+			go func() { sent <- s.Send(data) }()
+			res := &pb.HostToPlugin{}
+			if err := tc.decode(stdin, res); err != nil || !proto.Equal(res, reply) {
+				t.Errorf("Expected %v, got %v (%v)", reply, res, err)
+			}
+			if err := <-sent; err != nil {
+				t.Errorf("send: %v", err)
+			}
+		})
+	}
+}
+
+func TestRecvErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format Format
+		in     []byte
+		err    string
+	}{
+		{"protobuf frame too large", FormatProtobuf, header(maxFrameSize + 1), "exceeds limit"},
+		{"truncated protobuf frame", FormatProtobuf, append(header(8), 1, 2), "unexpected EOF"},
+		{"json line too large", FormatJSON, []byte("{" + strings.Repeat(" ", maxFrameSize) + "}\n"), "exceeds limit"},
+		{"invalid json", FormatJSON, []byte("{not json}\n"), "decode json message"},
+		{"closed", FormatJSON, nil, "EOF"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, stdout, _ := pipeStream(t, tc.format)
+			go func() {
+				_, _ = io.Copy(stdout, bytes.NewReader(tc.in))
+				_ = stdout.Close()
+			}()
+			_, err := s.Recv()
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Expected an error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestUnterminatedJSONLine(t *testing.T) {
+	s, stdout, _ := pipeStream(t, FormatJSON)
+	// The plugin keeps writing without a newline; the host gives up once the limit is passed
+	// instead of waiting for the line to end.
+	go func() {
+		chunk := bytes.Repeat([]byte(" "), 64*1024)
+		for {
+			if _, err := stdout.Write(chunk); err != nil {
+				return
+			}
+		}
+	}()
+	_, err := s.Recv()
+	if err == nil || !strings.Contains(err.Error(), "exceeds limit") {
+		t.Errorf("Expected an error containing %q, got %v", "exceeds limit", err)
+	}
+}
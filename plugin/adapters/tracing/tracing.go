@@ -0,0 +1,111 @@
+// Package tracing sets up OpenTelemetry tracing for the plugin host and converts trace context to
+// and from the TraceContext message carried by events, results and actions.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/secmc/plugin/plugin/config"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	// ExporterOTLP sends spans to an OTLP/HTTP collector.
+	ExporterOTLP = "otlp"
+	// ExporterFile writes spans as JSON to a local file.
+	ExporterFile = "file"
+
+	serviceName = "dragonfly-plugin-host"
+)
+
+var propagator = propagation.TraceContext{}
+
+// Tracer returns the tracer used by the plugin host. Until Setup installs a provider, spans are
+// no-ops and no trace context is sent to plugins.
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/secmc/plugin")
+}
+
+// Setup installs a global tracer provider according to cfg. The returned function flushes and
+// stops the exporter. If no exporter is configured, tracing stays disabled and shutdown is a no-op.
+func Setup(ctx context.Context, cfg config.TracingConfig) (shutdown func(context.Context) error, err error) {
+	var exporter sdktrace.SpanExporter
+	var closer io.Closer
+	switch cfg.Exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open trace file: %w", err)
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("create file exporter: %w", err)
+		}
+		closer = f
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if cerr := closer.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
+
+// Inject returns the trace context of the span in ctx, or nil if ctx carries no sampled span.
+func Inject(ctx context.Context) *pb.TraceContext {
+	if !trace.SpanContextFromContext(ctx).IsSampled() {
+		return nil
+	}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return &pb.TraceContext{Traceparent: carrier["traceparent"], Tracestate: carrier["tracestate"]}
+}
+
+// Extract returns ctx with the remote span described by tc as its parent. A nil tc returns ctx.
+func Extract(ctx context.Context, tc *pb.TraceContext) context.Context {
+	if tc.GetTraceparent() == "" {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{
+		"traceparent": tc.GetTraceparent(),
+		"tracestate":  tc.GetTracestate(),
+	})
+}
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtract(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	ctx, span := provider.Tracer("test").Start(context.Background(), "event")
+	defer span.End()
+
+	tc := Inject(ctx)
+	if tc.GetTraceparent() == "" {
+		t.Fatal("Expected a traceparent for a sampled span")
+	}
+	remote := trace.SpanContextFromContext(Extract(context.Background(), tc))
+	if !remote.IsRemote() || !remote.IsSampled() {
+		t.Errorf("Expected a sampled remote span context, got %+v", remote)
+	}
+	if want := span.SpanContext(); remote.TraceID() != want.TraceID() || remote.SpanID() != want.SpanID() {
+		t.Errorf("Expected span %s/%s, got %s/%s", want.TraceID(), want.SpanID(), remote.TraceID(), remote.SpanID())
+	}
+
+	// A child of the extracted context continues the same trace.
+	_, child := provider.Tracer("test").Start(Extract(context.Background(), tc), "plugin")
+	defer child.End()
+	if child.SpanContext().TraceID() != span.SpanContext().TraceID() {
+		t.Error("Expected the child span to continue the trace")
+	}
+}
+
+func TestInjectWithoutSpan(t *testing.T) {
+	if tc := Inject(context.Background()); tc != nil {
+		t.Errorf("Expected no trace context without a span, got %v", tc)
+	}
+	unsampled := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))
+	ctx, span := unsampled.Tracer("test").Start(context.Background(), "event")
+	defer span.End()
+	if tc := Inject(ctx); tc != nil {
+		t.Errorf("Expected no trace context for an unsampled span, got %v", tc)
+	}
+	ctx = context.Background()
+	if Extract(ctx, nil) != ctx {
+		t.Error("Expected Extract without trace context to return ctx")
+	}
+}
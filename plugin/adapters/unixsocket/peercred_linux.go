@@ -0,0 +1,96 @@
+//go:build linux
+
+package unixsocket
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/secmc/plugin/plugin/ports"
+)
+
+// maxAncestry bounds the walk up the process tree in IsDescendant.
+const maxAncestry = 64
+
+// Credentials returns the SO_PEERCRED credentials of the process on the other end of conn. It
+// returns false for anything but a Unix socket.
+func Credentials(conn net.Conn) (ports.PeerCredentials, bool) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return ports.PeerCredentials{}, false
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return ports.PeerCredentials{}, false
+	}
+	var cred *unix.Ucred
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil || credErr != nil {
+		return ports.PeerCredentials{}, false
+	}
+	return ports.PeerCredentials{PID: cred.Pid, UID: cred.Uid, GID: cred.Gid}, true
+}
+
+// IsDescendant reports whether pid is ancestor or one of its descendants. Plugins are often
+// started through a wrapper such as npm or cargo, so the connecting process is rarely the one the
+// host launched directly.
+func IsDescendant(pid, ancestor int) bool {
+	for i := 0; pid > 1 && i < maxAncestry; i++ {
+		if pid == ancestor {
+			return true
+		}
+		parent, err := parentPID(pid)
+		if err != nil {
+			return false
+		}
+		pid = parent
+	}
+	return pid == ancestor
+}
+
+func parentPID(pid int) (int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// The command name may contain spaces and parentheses, so fields are read after the last ')'.
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	return strconv.Atoi(fields[1])
+}
+
+// ProcessUID returns the real uid of a running process.
+func ProcessUID(pid int) (uint32, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "Uid:" {
+			uid, err := strconv.ParseUint(fields[1], 10, 32)
+			return uint32(uid), err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no uid for pid %d", pid)
+}
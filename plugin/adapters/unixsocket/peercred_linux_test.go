@@ -0,0 +1,45 @@
+//go:build linux
+
+package unixsocket
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peer.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			defer conn.Close()
+			_, _ = conn.Read(make([]byte, 1))
+		}
+	}()
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	cred, ok := Credentials(conn)
+	if !ok {
+		t.Fatal("no credentials for unix socket peer")
+	}
+	if int(cred.PID) != os.Getpid() || int(cred.UID) != os.Getuid() {
+		t.Fatalf("got pid %d uid %d, want pid %d uid %d", cred.PID, cred.UID, os.Getpid(), os.Getuid())
+	}
+	if !IsDescendant(os.Getpid(), os.Getppid()) {
+		t.Fatal("test process is not a descendant of its parent")
+	}
+	if IsDescendant(os.Getppid(), os.Getpid()) {
+		t.Fatal("parent reported as descendant of child")
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !linux
+
+package unixsocket
+
+import (
+	"errors"
+	"net"
+
+	"github.com/secmc/plugin/plugin/ports"
+)
+
+// Credentials is not supported on this platform and always returns false.
+func Credentials(net.Conn) (ports.PeerCredentials, bool) {
+	return ports.PeerCredentials{}, false
+}
+
+// IsDescendant can only recognise the process itself on this platform.
+func IsDescendant(pid, ancestor int) bool {
+	return pid == ancestor
+}
+
+// ProcessUID is not supported on this platform.
+func ProcessUID(int) (uint32, error) {
+	return 0, errors.New("process uid lookup is only supported on linux")
+}
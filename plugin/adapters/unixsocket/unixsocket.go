@@ -0,0 +1,88 @@
+// Package unixsocket holds helpers shared by the transports that listen on Unix sockets: applying
+// ownership and permissions to the socket file and identifying the process on the other end.
+package unixsocket
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
+
+// DefaultMode is applied to plugin sockets when no mode is configured.
+const DefaultMode os.FileMode = 0660
+
+// Permissions describes the ownership and mode of a listening socket file.
+type Permissions struct {
+	Mode  os.FileMode // zero selects DefaultMode
+	Owner string      // user name or numeric uid; empty keeps the current owner
+	Group string      // group name or numeric gid; empty keeps the current group
+}
+
+// ParseMode parses an octal permission string such as "0660". An empty string selects DefaultMode.
+func ParseMode(s string) (os.FileMode, error) {
+	if s == "" {
+		return DefaultMode, nil
+	}
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid socket mode %q", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+// Apply sets the mode and ownership of the socket file at path.
+func (p Permissions) Apply(path string) error {
+	mode := p.Mode
+	if mode == 0 {
+		mode = DefaultMode
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		return fmt.Errorf("chmod socket: %w", err)
+	}
+	if p.Owner == "" && p.Group == "" {
+		return nil
+	}
+	uid, gid := -1, -1
+	if p.Owner != "" {
+		id, err := lookupID(p.Owner, func(name string) (string, error) {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return "", err
+			}
+			return u.Uid, nil
+		})
+		if err != nil {
+			return fmt.Errorf("socket owner %q: %w", p.Owner, err)
+		}
+		uid = id
+	}
+	if p.Group != "" {
+		id, err := lookupID(p.Group, func(name string) (string, error) {
+			g, err := user.LookupGroup(name)
+			if err != nil {
+				return "", err
+			}
+			return g.Gid, nil
+		})
+		if err != nil {
+			return fmt.Errorf("socket group %q: %w", p.Group, err)
+		}
+		gid = id
+	}
+	if err := os.Chown(path, uid, gid); err != nil {
+		return fmt.Errorf("chown socket: %w", err)
+	}
+	return nil
+}
+
+func lookupID(s string, lookup func(string) (string, error)) (int, error) {
+	if id, err := strconv.Atoi(s); err == nil {
+		return id, nil
+	}
+	id, err := lookup(s)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(id)
+}
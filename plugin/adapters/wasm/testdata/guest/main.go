@@ -0,0 +1,86 @@
+//go:build wasip1
+
+// Command guest is the module used by the wasm package's tests. It says hello on start and answers
+// each chat message according to its text:
+//
+//	spin          loop forever
+//	read <path>   reply with the file's contents or the error opening it
+//	grow <mb>     allocate mb MiB
+//	anything else reply with the message reversed
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func main() {}
+
+var inbox []byte
+
+var hoard [][]byte
+
+//go:wasmimport df send
+func hostSend(ptr, size uint32) uint32
+
+func send(msg *pb.PluginToHost) {
+	data, _ := proto.Marshal(msg)
+	hostSend(uint32(uintptr(unsafe.Pointer(&data[0]))), uint32(len(data)))
+}
+
+//go:wasmexport df_start
+func start() {
+	send(&pb.PluginToHost{Payload: &pb.PluginToHost_Hello{Hello: &pb.PluginHello{Name: "guest", ApiVersion: "v1"}}})
+}
+
+//go:wasmexport df_alloc
+func alloc(size uint32) uint32 {
+	inbox = make([]byte, size+1)
+	return uint32(uintptr(unsafe.Pointer(&inbox[0])))
+}
+
+//go:wasmexport df_receive
+func receive(ptr, size uint32) {
+	msg := &pb.HostToPlugin{}
+	if err := proto.Unmarshal(inbox[:size], msg); err != nil {
+		panic(err)
+	}
+	ev := msg.GetEvent()
+	if ev.GetChat() == nil {
+		return
+	}
+	text := ev.GetChat().Message
+	reply := ""
+	switch cmd, arg, _ := strings.Cut(text, " "); cmd {
+	case "spin":
+		for {
+		}
+	case "read":
+		data, err := os.ReadFile(arg)
+		reply = string(data)
+		if err != nil {
+			reply = err.Error()
+		}
+	case "grow":
+		mb, _ := strconv.Atoi(arg)
+		hoard = append(hoard, make([]byte, mb<<20))
+		reply = fmt.Sprintf("grew %d", mb)
+	default:
+		r := []rune(text)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		reply = string(r)
+	}
+	send(&pb.PluginToHost{Payload: &pb.PluginToHost_EventResult{EventResult: &pb.EventResult{
+		EventId: ev.EventId,
+		Update:  &pb.EventResult_Chat{Chat: &pb.ChatMutation{Message: proto.String(reply)}},
+	}}})
+}
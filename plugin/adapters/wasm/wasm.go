@@ -0,0 +1,246 @@
+// Package wasm runs plugins compiled to WebAssembly inside the host with wazero. A module is
+// sandboxed: it sees only the directories it is granted, cannot open network connections, is
+// capped in memory and is stopped when handling a single message takes too long.
+//
+// Modules exchange protobuf-encoded messages with the host:
+//
+//   - The host imports "df" "send" (ptr, len i32) -> i32, which sends the PluginToHost message in
+//     the module's memory at ptr. It returns 0, or non-zero if the message is malformed or the
+//     connection is closed.
+//   - The module exports "df_start" (), called once after instantiation, which must send the
+//     PluginHello.
+//   - The module exports "df_alloc" (len i32) -> i32, returning a buffer of len bytes, and
+//     "df_receive" (ptr, len i32), called with each HostToPlugin message written to that buffer.
+//
+// Modules must be reactors, such as Go programs built with GOOS=wasip1 -buildmode=c-shared or Rust
+// cdylibs: only "_initialize" is run on instantiation. Calls into the module never overlap.
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/adapters/inprocess"
+	"github.com/secmc/plugin/plugin/config"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// pagesPerMB is the number of 64 KiB WebAssembly memory pages in a MiB.
+const pagesPerMB = 16
+
+// cache keeps compiled modules by content, so restarting a plugin whose module is unchanged skips
+// compilation, which takes seconds for large modules.
+var cache = wazero.NewCompilationCache()
+
+// Run loads the module named by cfg.Command and runs it until ctx is cancelled or the host closes
+// the connection made with dial. It returns an error if the module cannot be loaded, traps or
+// exceeds cfg.Wasm.CallTimeoutMs while handling a message; the module cannot be used after that.
+func Run(ctx context.Context, cfg config.PluginConfig, log *slog.Logger, dial inprocess.Dialer) error {
+	code, err := os.ReadFile(resolve(cfg, cfg.Command))
+	if err != nil {
+		return fmt.Errorf("read module: %w", err)
+	}
+	r := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCompilationCache(cache).
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(uint32(cfg.Wasm.MemoryLimitMB*pagesPerMB)))
+	defer r.Close(context.Background())
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
+		return fmt.Errorf("instantiate WASI: %w", err)
+	}
+	compiled, err := r.CompileModule(ctx, code)
+	if err != nil {
+		return fmt.Errorf("compile module: %w", err)
+	}
+
+	stream, err := dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	g := &guest{id: cfg.ID, stream: stream, log: log, timeout: time.Duration(cfg.Wasm.CallTimeoutMs) * time.Millisecond}
+	if _, err := r.NewHostModuleBuilder("df").
+		NewFunctionBuilder().WithFunc(g.send).Export("send").
+		Instantiate(ctx); err != nil {
+		return fmt.Errorf("instantiate host module: %w", err)
+	}
+
+	stdout, stderr := &logWriter{log: log}, &logWriter{log: log}
+	defer stdout.Flush()
+	defer stderr.Flush()
+	err = g.call(ctx, "instantiate module", func(ctx context.Context) error {
+		mod, err := r.InstantiateModule(ctx, compiled, moduleConfig(cfg, stdout, stderr))
+		g.mod = mod
+		return err
+	})
+	if err != nil || ctx.Err() != nil {
+		return err
+	}
+	if g.mod.Memory() == nil {
+		return errors.New("module does not export its memory")
+	}
+	alloc, receive, start := g.mod.ExportedFunction("df_alloc"), g.mod.ExportedFunction("df_receive"), g.mod.ExportedFunction("df_start")
+	for name, fn := range map[string]api.Function{"df_alloc": alloc, "df_receive": receive, "df_start": start} {
+		if fn == nil {
+			return fmt.Errorf("module does not export %s", name)
+		}
+	}
+	if err := g.call(ctx, "df_start", func(ctx context.Context) error {
+		_, err := start.Call(ctx)
+		return err
+	}); err != nil || ctx.Err() != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			// The host closed the connection, or ctx was cancelled.
+			return nil
+		}
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("marshal message: %w", err)
+		}
+		err = g.call(ctx, fmt.Sprintf("handle %T", msg.GetPayload()), func(ctx context.Context) error {
+			res, err := alloc.Call(ctx, uint64(len(data)))
+			if err != nil {
+				return err
+			}
+			ptr := uint32(res[0])
+			if !g.mod.Memory().Write(ptr, data) {
+				return fmt.Errorf("df_alloc returned %d, out of range for %d bytes", ptr, len(data))
+			}
+			_, err = receive.Call(ctx, uint64(ptr), uint64(len(data)))
+			return err
+		})
+		if err != nil || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// moduleConfig grants the module its arguments, environment and mounts, and the clocks and
+// randomness it needs to run. Nothing else on the host is visible to it.
+func moduleConfig(cfg config.PluginConfig, stdout, stderr io.Writer) wazero.ModuleConfig {
+	mc := wazero.NewModuleConfig().
+		WithName(cfg.ID).
+		WithStartFunctions("_initialize").
+		WithArgs(append([]string{filepath.Base(cfg.Command)}, cfg.Args...)...).
+		WithEnv("DF_PLUGIN_ID", cfg.ID).
+		WithStdout(stdout).
+		WithStderr(stderr).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithRandSource(rand.Reader)
+	for k, v := range cfg.Env {
+		mc = mc.WithEnv(k, v)
+	}
+	fsConfig := wazero.NewFSConfig()
+	for _, mount := range cfg.Wasm.Mounts {
+		if mount.ReadOnly {
+			fsConfig = fsConfig.WithReadOnlyDirMount(resolve(cfg, mount.Host), mount.Guest)
+		} else {
+			fsConfig = fsConfig.WithDirMount(resolve(cfg, mount.Host), mount.Guest)
+		}
+	}
+	return mc.WithFSConfig(fsConfig)
+}
+
+// resolve makes a relative path relative to the plugin's working directory.
+func resolve(cfg config.PluginConfig, path string) string {
+	if filepath.IsAbs(path) || cfg.WorkDir.Path == "" {
+		return path
+	}
+	return filepath.Join(cfg.WorkDir.Path, path)
+}
+
+// guest is an instantiated module and its connection to the host.
+type guest struct {
+	id      string
+	stream  *inprocess.PluginStream
+	log     *slog.Logger
+	timeout time.Duration
+	mod     api.Module
+}
+
+// call runs f, which calls into the module, within the call timeout. Errors caused by ctx being
+// cancelled are dropped.
+func (g *guest) call(ctx context.Context, what string, f func(ctx context.Context) error) error {
+	callCtx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+	err := f(callCtx)
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return nil
+	case errors.Is(callCtx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%s: exceeded call timeout of %s", what, g.timeout)
+	}
+	return fmt.Errorf("%s: %w", what, err)
+}
+
+// send implements the "df" "send" host function.
+func (g *guest) send(_ context.Context, m api.Module, ptr, size uint32) uint32 {
+	mem := m.Memory()
+	if mem == nil {
+		return 1
+	}
+	data, ok := mem.Read(ptr, size)
+	if !ok {
+		g.log.Warn("wasm send out of memory range", "ptr", ptr, "len", size)
+		return 1
+	}
+	msg := &pb.PluginToHost{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		g.log.Warn("wasm send malformed message", "error", err)
+		return 1
+	}
+	if msg.PluginId == "" {
+		msg.PluginId = g.id
+	}
+	if err := g.stream.Send(msg); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// logWriter logs each line the module writes to stdout or stderr.
+type logWriter struct {
+	log *slog.Logger
+	buf []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.log.Info(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// Flush logs a final line without a newline.
+func (w *logWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.log.Info(string(w.buf))
+		w.buf = nil
+	}
+}
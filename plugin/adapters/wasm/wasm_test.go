@@ -0,0 +1,181 @@
+package wasm_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/secmc/plugin/plugin/adapters/inprocess"
+	"github.com/secmc/plugin/plugin/adapters/wasm"
+	"github.com/secmc/plugin/plugin/config"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+var (
+	buildOnce sync.Once
+	guestPath string
+	buildErr  error
+)
+
+// guestModule builds testdata/guest once per test binary.
+func guestModule(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("building the guest module is slow")
+	}
+	buildOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "wasm-guest")
+		if err != nil {
+			buildErr = err
+			return
+		}
+		guestPath = filepath.Join(dir, "guest.wasm")
+		cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", guestPath, "./testdata/guest")
+		cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			buildErr = err
+			guestPath = string(out)
+		}
+	})
+	if buildErr != nil {
+		t.Fatalf("build guest: %v\n%s", buildErr, guestPath)
+	}
+	return guestPath
+}
+
+// instance is a guest started by run.
+type instance struct {
+	host *inprocess.HostStream
+	done chan struct{}
+	err  error // result of Run, set once done is closed
+}
+
+// run starts the guest with cfg and waits for its hello.
+func run(t *testing.T, cfg config.PluginConfig) *instance {
+	t.Helper()
+	cfg.ID, cfg.Command = "guest", guestModule(t)
+	if cfg.Wasm.MemoryLimitMB == 0 {
+		cfg.Wasm.MemoryLimitMB = 64
+	}
+	if cfg.Wasm.CallTimeoutMs == 0 {
+		cfg.Wasm.CallTimeoutMs = 5000
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	hosts := make(chan *inprocess.HostStream, 1)
+	inst := &instance{done: make(chan struct{})}
+	go func() {
+		defer close(inst.done)
+		inst.err = wasm.Run(ctx, cfg, slog.New(slog.DiscardHandler), func(ctx context.Context) (*inprocess.PluginStream, error) {
+			host, plugin := inprocess.Pipe()
+			context.AfterFunc(ctx, func() { _ = plugin.Close() })
+			hosts <- host
+			return plugin, nil
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-inst.done
+	})
+	select {
+	case inst.host = <-hosts:
+	case <-inst.done:
+		t.Fatalf("Run: %v", inst.err)
+	}
+	msg, err := inst.host.RecvMessage()
+	if err != nil || msg.GetHello().GetName() != "guest" {
+		t.Fatalf("Expected hello, got %v (%v)", msg, err)
+	}
+	return inst
+}
+
+// wait waits for Run to return.
+func (inst *instance) wait(t *testing.T) error {
+	t.Helper()
+	select {
+	case <-inst.done:
+		return inst.err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the module to be stopped")
+		return nil
+	}
+}
+
+// event sends a chat event that expects no response.
+func (inst *instance) event(text string) {
+	_ = inst.host.SendMessage(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Event{Event: &pb.EventEnvelope{
+		Payload: &pb.EventEnvelope_Chat{Chat: &pb.ChatEvent{Message: text}},
+	}}})
+}
+
+// chat sends a chat event and returns the message of the guest's mutation.
+func chat(t *testing.T, host *inprocess.HostStream, text string) string {
+	t.Helper()
+	err := host.SendMessage(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Event{Event: &pb.EventEnvelope{
+		EventId:         "1",
+		Type:            pb.EventType_CHAT,
+		ExpectsResponse: true,
+		Payload:         &pb.EventEnvelope_Chat{Chat: &pb.ChatEvent{Message: text}},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := host.RecvMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.PluginId != "guest" {
+		t.Errorf("Expected plugin ID to be filled in, got %q", msg.PluginId)
+	}
+	return msg.GetEventResult().GetChat().GetMessage()
+}
+
+func TestRun(t *testing.T) {
+	inst := run(t, config.PluginConfig{})
+	if got := chat(t, inst.host, "hello"); got != "olleh" {
+		t.Errorf("Expected olleh, got %q", got)
+	}
+}
+
+func TestMounts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "motd.txt"), []byte("welcome"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	inst := run(t, config.PluginConfig{})
+	if got := chat(t, inst.host, "read "+filepath.Join(dir, "motd.txt")); !strings.HasPrefix(got, "open ") {
+		t.Errorf("Expected no filesystem access without a mount, got %q", got)
+	}
+
+	cfg := config.PluginConfig{}
+	cfg.Wasm.Mounts = []config.WasmMount{{Host: dir, Guest: "/data", ReadOnly: true}}
+	inst = run(t, cfg)
+	if got := chat(t, inst.host, "read /data/motd.txt"); got != "welcome" {
+		t.Errorf("Expected the mounted file, got %q", got)
+	}
+}
+
+func TestCallTimeout(t *testing.T) {
+	cfg := config.PluginConfig{}
+	cfg.Wasm.CallTimeoutMs = 200
+	inst := run(t, cfg)
+	inst.event("spin")
+	if err := inst.wait(t); err == nil || !strings.Contains(err.Error(), "exceeded call timeout") {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	cfg := config.PluginConfig{}
+	cfg.Wasm.MemoryLimitMB = 32
+	inst := run(t, cfg)
+	inst.event("grow 64")
+	if err := inst.wait(t); err == nil {
+		t.Error("Expected the module to run out of memory")
+	}
+}
@@ -0,0 +1,362 @@
+// Package webhook connects plugins that are HTTP endpoints, such as serverless functions that
+// cannot hold a stream. The host POSTs events to the endpoint as an EventBatch and applies the
+// WebhookResponse in the response body.
+//
+// Events that expect a response are POSTed as soon as they arrive and the request is cancelled at
+// the host's deadline for results, ports.EventResponseTimeout; a response arriving later is
+// discarded with its actions. Events that do not are collected for batch_ms and delivered in order,
+// retried with backoff when the endpoint fails. Requests are signed when a secret is configured:
+//
+//	X-DF-Timestamp: <unix seconds>
+//	X-DF-Signature: sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/adapters/inprocess"
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	// maxBatch is the most events POSTed in one request.
+	maxBatch = 500
+	// maxQueued bounds the observe-only events waiting for delivery; the oldest are dropped first.
+	maxQueued = 10000
+	// maxResponse bounds the response bodies read from the endpoint.
+	maxResponse = 4 << 20
+
+	retryBase = 500 * time.Millisecond
+	retryMax  = 30 * time.Second
+)
+
+// Headers set on every request.
+const (
+	HeaderPluginID  = "X-DF-Plugin-Id"
+	HeaderTimestamp = "X-DF-Timestamp"
+	HeaderSignature = "X-DF-Signature"
+)
+
+// Sign returns the X-DF-Signature value for body sent at timestamp, so endpoints can verify it.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run connects to the host with dial on behalf of the endpoint at cfg.Webhook.URL and delivers
+// the events it is sent until ctx is cancelled or the host shuts the plugin down. It only returns
+// an error if the plugin could not run, such as when the host refuses it.
+func Run(ctx context.Context, cfg config.PluginConfig, log *slog.Logger, dial inprocess.Dialer) error {
+	events, err := eventTypes(cfg.Webhook.Events)
+	if err != nil {
+		return err
+	}
+	stream, err := dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	w := &webhook{
+		id:     cfg.ID,
+		cfg:    cfg.Webhook,
+		client: &http.Client{Timeout: time.Duration(cfg.Webhook.TimeoutMs) * time.Millisecond},
+		stream: stream,
+		log:    log,
+	}
+	name := cfg.Name
+	if name == "" {
+		name = cfg.ID
+	}
+	hello := &pb.PluginHello{Name: name, ApiVersions: []string{"v1"}, Capabilities: []string{"heartbeat"}}
+	if cfg.Webhook.Observe {
+		hello.Capabilities = append(hello.Capabilities, "observer")
+	}
+	w.send(&pb.PluginToHost{Payload: &pb.PluginToHost_Hello{Hello: hello}})
+	w.send(&pb.PluginToHost{Payload: &pb.PluginToHost_Subscribe{Subscribe: &pb.EventSubscribe{Events: events}}})
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.deliverLoop(ctx)
+	}()
+
+	refused := false
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		switch payload := msg.Payload.(type) {
+		case *pb.HostToPlugin_Hello:
+			// A HostHello without api_version means negotiation failed; the reason follows in
+			// HostShutdown.
+			refused = payload.Hello.GetApiVersion() == ""
+		case *pb.HostToPlugin_Ping:
+			w.send(&pb.PluginToHost{Payload: &pb.PluginToHost_Pong{Pong: &pb.PluginPong{Nonce: payload.Ping.Nonce}}})
+		case *pb.HostToPlugin_Event:
+			w.dispatch(ctx, &wg, []*pb.EventEnvelope{payload.Event})
+		case *pb.HostToPlugin_Events:
+			w.dispatch(ctx, &wg, payload.Events.Events)
+		case *pb.HostToPlugin_Shutdown:
+			if refused {
+				return fmt.Errorf("refused by the host: %s", payload.Shutdown.GetReason())
+			}
+			return nil
+		}
+	}
+}
+
+// eventTypes parses the configured event names; none subscribes to every event.
+func eventTypes(names []string) ([]pb.EventType, error) {
+	if len(names) == 0 {
+		return []pb.EventType{pb.EventType_EVENT_TYPE_ALL}, nil
+	}
+	types := make([]pb.EventType, 0, len(names))
+	for _, name := range names {
+		t, ok := pb.EventType_value[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown event type %q", name)
+		}
+		types = append(types, pb.EventType(t))
+	}
+	return types, nil
+}
+
+type webhook struct {
+	id     string
+	cfg    config.WebhookConfig
+	client *http.Client
+	stream *inprocess.PluginStream
+	log    *slog.Logger
+
+	mu      sync.Mutex
+	queue   []*pb.EventEnvelope
+	dropped int
+}
+
+func (w *webhook) send(msg *pb.PluginToHost) {
+	msg.PluginId = w.id
+	_ = w.stream.Send(msg)
+}
+
+// dispatch POSTs the events that expect a response at once and queues the rest.
+func (w *webhook) dispatch(ctx context.Context, wg *sync.WaitGroup, events []*pb.EventEnvelope) {
+	var waiting []*pb.EventEnvelope
+	deadline := time.Now().Add(ports.EventResponseTimeout)
+	w.mu.Lock()
+	for _, ev := range events {
+		if ev.ExpectsResponse {
+			waiting = append(waiting, ev)
+			continue
+		}
+		w.queue = append(w.queue, ev)
+	}
+	if over := len(w.queue) - maxQueued; over > 0 {
+		w.queue = w.queue[over:]
+		w.dropped += over
+	}
+	w.mu.Unlock()
+	if len(waiting) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.respond(ctx, waiting, deadline)
+		}()
+	}
+}
+
+// respond POSTs events that expect a response and sends a result for each. Events the
+// endpoint does not answer, or all of them if the request fails, get an empty result so they
+// continue without waiting for the deadline. The request is abandoned at deadline, when the host
+// stops waiting, so a late response neither changes the events nor runs its actions.
+func (w *webhook) respond(ctx context.Context, events []*pb.EventEnvelope, deadline time.Time) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	res, err := w.post(ctx, events)
+	if err == nil && time.Now().After(deadline) {
+		err = context.DeadlineExceeded
+	}
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			w.log.Warn("webhook did not respond before the event deadline, response discarded", "deadline", ports.EventResponseTimeout)
+		} else {
+			w.log.Warn("webhook request failed", "error", err)
+		}
+		res = &pb.WebhookResponse{}
+	}
+	answered := make(map[string]bool, len(res.Results))
+	for _, r := range res.Results {
+		answered[r.EventId] = true
+		w.send(&pb.PluginToHost{Payload: &pb.PluginToHost_EventResult{EventResult: r}})
+	}
+	for _, ev := range events {
+		if !answered[ev.EventId] {
+			w.send(&pb.PluginToHost{Payload: &pb.PluginToHost_EventResult{EventResult: &pb.EventResult{EventId: ev.EventId}}})
+		}
+	}
+	w.act(res)
+}
+
+// deliverLoop delivers queued events every batch_ms until ctx is cancelled.
+func (w *webhook) deliverLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(w.cfg.BatchMs) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			w.mu.Lock()
+			batch := w.queue[:min(len(w.queue), maxBatch)]
+			w.queue = w.queue[len(batch):]
+			if w.dropped > 0 {
+				w.log.Warn("webhook queue full, dropped events", "count", w.dropped)
+				w.dropped = 0
+			}
+			w.mu.Unlock()
+			if len(batch) == 0 {
+				break
+			}
+			w.deliver(ctx, batch)
+		}
+	}
+}
+
+// deliver POSTs observe-only events, retrying with backoff while the endpoint fails.
+func (w *webhook) deliver(ctx context.Context, events []*pb.EventEnvelope) {
+	delay := retryBase
+	for attempt := 0; ; attempt++ {
+		res, err := w.post(ctx, events)
+		if err == nil {
+			w.act(res)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !retryable(err) || attempt >= w.cfg.MaxRetries {
+			w.log.Warn("webhook delivery failed, dropped events", "count", len(events), "attempts", attempt+1, "error", err)
+			return
+		}
+		w.log.Debug("webhook delivery failed, retrying", "in", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, retryMax)
+	}
+}
+
+// act sends the actions of a response.
+func (w *webhook) act(res *pb.WebhookResponse) {
+	for _, batch := range res.Actions {
+		w.send(&pb.PluginToHost{Payload: &pb.PluginToHost_Actions{Actions: batch}})
+	}
+}
+
+// statusError is a response with a status other than 2xx.
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("endpoint returned %d %s", e.code, http.StatusText(e.code))
+}
+
+// retryable reports whether a failed request may succeed if sent again: network errors, server
+// errors and rate limiting are retried, other client errors are not.
+func retryable(err error) bool {
+	var status statusError
+	if !errors.As(err, &status) {
+		return true
+	}
+	return status.code >= 500 || status.code == http.StatusTooManyRequests
+}
+
+// post sends events to the endpoint and decodes its response.
+func (w *webhook) post(ctx context.Context, events []*pb.EventEnvelope) (*pb.WebhookResponse, error) {
+	batch := &pb.EventBatch{Events: events}
+	var (
+		body        []byte
+		err         error
+		contentType = "application/x-protobuf"
+	)
+	if w.cfg.Format == "json" {
+		body, err = protojson.Marshal(batch)
+		contentType = "application/json"
+	} else {
+		body, err = proto.Marshal(batch)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode events: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", contentType)
+	req.Header.Set(HeaderPluginID, w.id)
+	if w.cfg.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(HeaderTimestamp, timestamp)
+		req.Header.Set(HeaderSignature, Sign(w.cfg.Secret, timestamp, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, statusError{code: resp.StatusCode}
+	}
+	res := &pb.WebhookResponse{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return res, nil
+	}
+	if w.cfg.Format == "json" {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, res)
+	} else {
+		err = proto.Unmarshal(data, res)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return res, nil
+}
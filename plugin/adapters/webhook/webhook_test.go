@@ -0,0 +1,193 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/adapters/inprocess"
+	"github.com/secmc/plugin/plugin/adapters/plugin/plugintest"
+	"github.com/secmc/plugin/plugin/adapters/webhook"
+	"github.com/secmc/plugin/plugin/config"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// endpoint is a webhook that rewrites chat messages and fails its first observe-only delivery.
+type endpoint struct {
+	t      *testing.T
+	secret string
+
+	mu       sync.Mutex
+	failed   bool
+	observed []pb.EventType
+}
+
+func (e *endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if got, want := r.Header.Get(webhook.HeaderSignature), webhook.Sign(e.secret, r.Header.Get(webhook.HeaderTimestamp), body); got != want {
+		e.t.Errorf("Expected signature %s, got %s", want, got)
+	}
+	batch := &pb.EventBatch{}
+	if err := protojson.Unmarshal(body, batch); err != nil {
+		e.t.Errorf("Expected a JSON event batch: %v", err)
+		return
+	}
+	res := &pb.WebhookResponse{}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, ev := range batch.Events {
+		switch {
+		case ev.GetChat() != nil:
+			res.Results = append(res.Results, &pb.EventResult{
+				EventId: ev.EventId,
+				Update:  &pb.EventResult_Chat{Chat: &pb.ChatMutation{Message: proto.String("[hook] " + ev.GetChat().Message)}},
+			})
+		case !e.failed:
+			e.failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		default:
+			e.observed = append(e.observed, ev.Type)
+		}
+	}
+	data, _ := protojson.Marshal(res)
+	_, _ = w.Write(data)
+}
+
+func TestWebhook(t *testing.T) {
+	ep := &endpoint{t: t, secret: "s3cret"}
+	srv := httptest.NewServer(ep)
+	defer srv.Close()
+
+	pc := config.PluginConfig{ID: "hook"}
+	pc.Webhook = config.WebhookConfig{
+		URL: srv.URL, Format: "json", Secret: ep.secret, Events: []string{"chat", "player_join"},
+		TimeoutMs: 1000, BatchMs: 10, MaxRetries: 3,
+	}
+	h := plugintest.NewHost(t, config.Config{Plugins: []config.PluginConfig{pc}})
+	waitFor(t, "plugin to be ready", func() bool {
+		st, err := h.Manager.PluginStatus("hook")
+		return err == nil && st.State == "ready"
+	})
+
+	steve := h.AddPlayer("Steve")
+	waitFor(t, "join to be delivered after a retry", func() bool {
+		ep.mu.Lock()
+		defer ep.mu.Unlock()
+		return len(ep.observed) == 1 && ep.observed[0] == pb.EventType_PLAYER_JOIN
+	})
+
+	msg := "hello"
+	h.WithPlayer(steve, func(_ *world.Tx, p *player.Player) {
+		h.Manager.EmitChat(event.C(p), p, &msg)
+	})
+	if msg != "[hook] hello" {
+		t.Errorf("Expected the webhook's mutation, got %q", msg)
+	}
+}
+
+func TestWebhookLateResponse(t *testing.T) {
+	answered := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(answered)
+		body, _ := io.ReadAll(r.Body)
+		batch := &pb.EventBatch{}
+		if err := protojson.Unmarshal(body, batch); err != nil || len(batch.Events) != 1 {
+			t.Errorf("Expected one event, got %v (%v)", batch, err)
+			return
+		}
+		time.Sleep(400 * time.Millisecond)
+		data, _ := protojson.Marshal(&pb.WebhookResponse{
+			Results: []*pb.EventResult{{
+				EventId: batch.Events[0].EventId,
+				Update:  &pb.EventResult_Chat{Chat: &pb.ChatMutation{Message: proto.String("late")}},
+			}},
+			Actions: []*pb.ActionBatch{{Actions: []*pb.Action{{
+				Kind: &pb.Action_WorldSetTime{WorldSetTime: &pb.WorldSetTimeAction{World: &pb.WorldRef{Dimension: "overworld"}, Time: 1000}},
+			}}}},
+		})
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	pc := config.PluginConfig{ID: "slow"}
+	pc.Webhook = config.WebhookConfig{URL: srv.URL, Format: "json", Events: []string{"chat"}, TimeoutMs: 2000, BatchMs: 10}
+	h := plugintest.NewHost(t, config.Config{Plugins: []config.PluginConfig{pc}})
+	waitFor(t, "plugin to be ready", func() bool {
+		st, err := h.Manager.PluginStatus("slow")
+		return err == nil && st.State == "ready"
+	})
+
+	msg := "hello"
+	steve := h.AddPlayer("Steve")
+	h.WithPlayer(steve, func(_ *world.Tx, p *player.Player) {
+		h.Manager.EmitChat(event.C(p), p, &msg)
+	})
+	if msg != "hello" {
+		t.Errorf("Expected the late response not to change the message, got %q", msg)
+	}
+	<-answered
+	time.Sleep(100 * time.Millisecond)
+	if got := h.World.Time(); got == 1000 {
+		t.Error("Expected the late response's actions not to run")
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebhookShutdown(t *testing.T) {
+	run := func(msgs ...*pb.HostToPlugin) error {
+		hostEnd, pluginEnd := inprocess.Pipe()
+		defer hostEnd.Close()
+		for _, msg := range msgs {
+			if err := hostEnd.SendMessage(msg); err != nil {
+				t.Fatal(err)
+			}
+		}
+		pc := config.PluginConfig{ID: "hook", Webhook: config.WebhookConfig{URL: "http://127.0.0.1:1", Format: "json", TimeoutMs: 1000, BatchMs: 10}}
+		done := make(chan error, 1)
+		go func() {
+			done <- webhook.Run(context.Background(), pc, slog.New(slog.DiscardHandler), func(context.Context) (*inprocess.PluginStream, error) {
+				return pluginEnd, nil
+			})
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the webhook to stop after HostShutdown")
+			return nil
+		}
+	}
+	shutdown := &pb.HostToPlugin{Payload: &pb.HostToPlugin_Shutdown{Shutdown: &pb.HostShutdown{Reason: "server closing"}}}
+
+	accepted := &pb.HostToPlugin{Payload: &pb.HostToPlugin_Hello{Hello: &pb.HostHello{ApiVersion: "v1"}}}
+	if err := run(accepted, shutdown); err != nil {
+		t.Errorf("Expected a shutdown with a reason to stop the webhook cleanly, got %v", err)
+	}
+	refused := &pb.HostToPlugin{Payload: &pb.HostToPlugin_Hello{Hello: &pb.HostHello{}}}
+	if err := run(refused, shutdown); err == nil || !strings.Contains(err.Error(), "server closing") {
+		t.Errorf("Expected a refusal to be reported with its reason, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	// SubprotocolProto carries binary protobuf frames, one PluginToHost/HostToPlugin per message.
+	SubprotocolProto = "df-plugin.proto"
+	// SubprotocolJSON carries protojson-encoded frames as text messages.
+	SubprotocolJSON = "df-plugin.json"
+
+	// maxMessageSize is the read limit of a connection. A plugin sending a larger message is
+	// disconnected with a close frame instead of being buffered in full.
+	maxMessageSize = 32 << 20
+	// maxCloseReason is the longest reason that fits in a close control frame.
+	maxCloseReason = 123
+	shutdownGrace  = 5 * time.Second
+)
+
+// StreamHandler is called when a new plugin connects
+type StreamHandler func(stream ports.Stream) error
+
+// Server accepts WebSocket connections from plugins that cannot speak gRPC, such as browser or
+// edge runtimes. Each connection carries the same frames as the gRPC EventStream.
+type Server struct {
+	http     *http.Server
+	listener net.Listener
+	handler  StreamHandler
+	upgrader websocket.Upgrader
+	origins  []string
+}
+
+// NewServer creates a WebSocket server that plugins will connect to. Browser plugins may only
+// connect from the given origins.
+func NewServer(address string, origins []string, handler StreamHandler) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("listen failed: %w", err)
+	}
+	s := &Server{
+		listener: listener,
+		handler:  handler,
+		origins:  origins,
+	}
+	s.upgrader = websocket.Upgrader{
+		Subprotocols: []string{SubprotocolProto, SubprotocolJSON},
+		CheckOrigin:  s.checkOrigin,
+	}
+	s.http = &http.Server{Handler: http.HandlerFunc(s.serveHTTP)}
+	return s, nil
+}
+
+// Serve starts accepting plugin connections
+func (s *Server) Serve() error {
+	if err := s.http.Serve(s.listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop gracefully stops the server
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+	_ = s.http.Shutdown(ctx)
+}
+
+// Address returns the address the server is listening on
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// checkOrigin accepts connections without an Origin header, which only browsers send, and
+// browser connections from the configured origins. Otherwise any web page opened on a machine that
+// can reach the listener could connect and claim a plugin ID.
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || slices.Contains(s.origins, origin)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an HTTP error response.
+		return
+	}
+	conn.SetReadLimit(maxMessageSize)
+	stream := &Stream{conn: conn, json: conn.Subprotocol() == SubprotocolJSON}
+	defer stream.Close()
+
+	if s.handler == nil {
+		_ = stream.closeWithReason(websocket.CloseInternalServerErr, "no handler registered")
+		return
+	}
+	if err := s.handler(stream); err != nil {
+		_ = stream.closeWithReason(websocket.ClosePolicyViolation, err.Error())
+	}
+}
+
+// Stream wraps a WebSocket connection for a connected plugin
+type Stream struct {
+	conn *websocket.Conn
+	json bool
+
+	mu     sync.Mutex
+	closed bool
+}
+
+var _ ports.MessageStream = (*Stream)(nil)
+
+// SendMessage writes msg as a binary protobuf frame, or as protojson for JSON connections.
+func (s *Stream) SendMessage(msg *pb.HostToPlugin) error {
+	if !s.json {
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("encode message: %w", err)
+		}
+		return s.write(websocket.BinaryMessage, data)
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encode json message: %w", err)
+	}
+	return s.write(websocket.TextMessage, data)
+}
+
+// RecvMessage reads and decodes the next PluginToHost message. JSON connections may also send
+// binary frames.
+func (s *Stream) RecvMessage() (*pb.PluginToHost, error) {
+	msgType, data, err := s.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	msg := &pb.PluginToHost{}
+	if s.json && msgType == websocket.TextMessage {
+		if err := protojson.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("decode json message: %w", err)
+		}
+		return msg, nil
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("decode message: %w", err)
+	}
+	return msg, nil
+}
+
+// Send writes a marshaled HostToPlugin message, converting it to protojson for JSON connections.
+// The host uses SendMessage, which does not decode the message again.
+func (s *Stream) Send(data []byte) error {
+	if !s.json {
+		return s.write(websocket.BinaryMessage, data)
+	}
+	msg := &pb.HostToPlugin{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("decode outgoing message: %w", err)
+	}
+	return s.SendMessage(msg)
+}
+
+// Recv reads the next PluginToHost message and returns it in protobuf wire format.
+func (s *Stream) Recv() ([]byte, error) {
+	msg, err := s.RecvMessage()
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(msg)
+}
+
+func (s *Stream) write(msgType int, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("stream closed")
+	}
+	return s.conn.WriteMessage(msgType, data)
+}
+
+// CloseSend sends a normal close frame; the plugin is expected to close the connection in response.
+func (s *Stream) CloseSend() error {
+	return s.closeWithReason(websocket.CloseNormalClosure, "")
+}
+
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	return s.conn.Close()
+}
+
+func (s *Stream) closeWithReason(code int, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	if len(reason) > maxCloseReason {
+		reason = reason[:maxCloseReason]
+	}
+	msg := websocket.FormatCloseMessage(code, reason)
+	return s.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+}
@@ -0,0 +1,120 @@
+package websocket_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	gorilla "github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/adapters/websocket"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// greet answers a plugin's hello with a host hello naming the plugin, using the decoded messages
+// the host exchanges with WebSocket plugins.
+func greet(stream ports.Stream) error {
+	messages, ok := stream.(ports.MessageStream)
+	if !ok {
+		return errors.New("expected a message stream")
+	}
+	msg, err := messages.RecvMessage()
+	if err != nil {
+		return err
+	}
+	if msg.GetHello() == nil {
+		return errors.New("expected a hello")
+	}
+	return messages.SendMessage(&pb.HostToPlugin{
+		PluginId: msg.PluginId,
+		Payload:  &pb.HostToPlugin_Hello{Hello: &pb.HostHello{ApiVersion: msg.GetHello().ApiVersion}},
+	})
+}
+
+func startServer(t *testing.T, origins []string) string {
+	t.Helper()
+	server, err := websocket.NewServer("127.0.0.1:0", origins, greet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve()
+	t.Cleanup(server.Stop)
+	return (&url.URL{Scheme: "ws", Host: server.Address()}).String()
+}
+
+func TestSubprotocols(t *testing.T) {
+	address := startServer(t, nil)
+	hello := &pb.PluginToHost{PluginId: "ws", Payload: &pb.PluginToHost_Hello{Hello: &pb.PluginHello{Name: "ws", ApiVersion: "v1"}}}
+	for _, tc := range []struct {
+		subprotocol string
+		msgType     int
+		marshal     func(proto.Message) ([]byte, error)
+		unmarshal   func([]byte, proto.Message) error
+	}{
+		{websocket.SubprotocolProto, gorilla.BinaryMessage, proto.Marshal, proto.Unmarshal},
+		{websocket.SubprotocolJSON, gorilla.TextMessage, protojson.Marshal, protojson.Unmarshal},
+	} {
+		dialer := gorilla.Dialer{Subprotocols: []string{tc.subprotocol}}
+		conn, _, err := dialer.Dial(address, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.subprotocol, err)
+		}
+		defer conn.Close()
+		if conn.Subprotocol() != tc.subprotocol {
+			t.Errorf("Expected sub-protocol %s, got %q", tc.subprotocol, conn.Subprotocol())
+		}
+
+		data, err := tc.marshal(hello)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.WriteMessage(tc.msgType, data); err != nil {
+			t.Fatalf("%s: send: %v", tc.subprotocol, err)
+		}
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("%s: recv: %v", tc.subprotocol, err)
+		}
+		if msgType != tc.msgType {
+			t.Errorf("%s: expected message type %d, got %d", tc.subprotocol, tc.msgType, msgType)
+		}
+		res := &pb.HostToPlugin{}
+		if err := tc.unmarshal(data, res); err != nil {
+			t.Fatalf("%s: decode %q: %v", tc.subprotocol, data, err)
+		}
+		if res.PluginId != "ws" || res.GetHello().GetApiVersion() != "v1" {
+			t.Errorf("%s: expected the host hello, got %v", tc.subprotocol, res)
+		}
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	address := startServer(t, []string{"https://plugins.example.com"})
+	for _, tc := range []struct {
+		origin string
+		ok     bool
+	}{
+		{"", true},
+		{"https://plugins.example.com", true},
+		{"https://evil.example.com", false},
+	} {
+		header := http.Header{}
+		if tc.origin != "" {
+			header.Set("Origin", tc.origin)
+		}
+		conn, res, err := gorilla.DefaultDialer.Dial(address, header)
+		if conn != nil {
+			_ = conn.Close()
+		}
+		if tc.ok && err != nil {
+			t.Errorf("Expected origin %q to be accepted, got %v", tc.origin, err)
+		}
+		if !tc.ok && (err == nil || res == nil || res.StatusCode != http.StatusForbidden) {
+			t.Errorf("Expected origin %q to be refused, got %v", tc.origin, err)
+		}
+	}
+}
@@ -1,23 +1,72 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/secmc/plugin/plugin/gitsource"
 )
 
 // ConfigFile is the default configuration file used for plugin definitions.
 const ConfigFile = "plugins/plugins.yaml"
 
 type Config struct {
-	ServerPort      string         `yaml:"server_port"`
-	RequiredPlugins []string       `yaml:"required_plugins"`
-	HelloTimeoutMs  int            `yaml:"hello_timeout_ms"`
-	Plugins         []PluginConfig `yaml:"plugins"`
+	ServerPort           string            `yaml:"server_port"`
+	WebSocketAddress     string            `yaml:"websocket_address"` // optional listener for WebSocket plugins
+	WebSocketOrigins     []string          `yaml:"websocket_origins"` // origins browser plugins may connect from
+	ShmSocket            string            `yaml:"shm_socket"`        // optional Unix socket for shared-memory plugins (linux)
+	SocketMode           string            `yaml:"socket_mode"`       // octal mode for Unix sockets, default "0660"
+	SocketOwner          string            `yaml:"socket_owner"`      // user name or uid for Unix sockets
+	SocketGroup          string            `yaml:"socket_group"`      // group name or gid for Unix sockets
+	PeerCheck            string            `yaml:"peer_check"`        // "pid" (default), "uid" or "off"
+	RequiredPlugins      []string          `yaml:"required_plugins"`
+	HelloTimeoutMs       int               `yaml:"hello_timeout_ms"`
+	ReplayBufferSize     int               `yaml:"replay_buffer_size"`     // durable events kept per plugin for replay
+	HeartbeatIntervalMs  int               `yaml:"heartbeat_interval_ms"`  // pings plugins that negotiate heartbeats; negative disables
+	HeartbeatMissedLimit int               `yaml:"heartbeat_missed_limit"` // unanswered pings before a plugin is unhealthy
+	CustomMetricsLimit   int               `yaml:"custom_metrics_limit"`   // custom metrics each plugin may define
+	CustomSeriesLimit    int               `yaml:"custom_series_limit"`    // label combinations across a plugin's custom metrics
+	Tracing              TracingConfig     `yaml:"tracing"`
+	Recording            RecordingConfig   `yaml:"recording"`
+	CrashReports         CrashReportConfig `yaml:"crash_reports"`
+	AdminAddress         string            `yaml:"admin_address"` // optional listener for the admin HTTP API
+	AdminToken           string            `yaml:"admin_token"`   // bearer token for the admin API, or DF_ADMIN_TOKEN
+	Operators            []string          `yaml:"operators"`     // player names or XUIDs allowed to use /plugins
+	Plugins              []PluginConfig    `yaml:"plugins"`
+
+	// Path is the file the config was loaded from, used to reload plugin definitions.
+	Path string `yaml:"-"`
+}
+
+// RecordingConfig enables recording the traffic of plugin connections for replay with cmd/replay.
+type RecordingConfig struct {
+	Dir       string   `yaml:"dir"`         // directory recordings are written to; empty disables recording
+	Plugins   []string `yaml:"plugins"`     // IDs of the plugins to record, all plugins if empty
+	MaxSizeMB int      `yaml:"max_size_mb"` // size at which a recording stops, default 256, negative for no limit
+}
+
+// CrashReportConfig controls the reports written when a plugin's process dies or an in-process
+// plugin fails.
+type CrashReportConfig struct {
+	Dir         string `yaml:"dir"`          // directory reports are written to, default "crash-reports"
+	OutputLines int    `yaml:"output_lines"` // lines of stdout and stderr kept per plugin, default 100
+	History     int    `yaml:"history"`      // events delivered and actions received kept per plugin, default 50
+}
+
+// TracingConfig selects where spans for event dispatch, plugin waits and mutations are exported.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`     // "otlp", "file" or empty to disable tracing
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/HTTP collector, default "localhost:4318"
+	Insecure    bool    `yaml:"insecure"`     // use plain HTTP for the OTLP exporter
+	File        string  `yaml:"file"`         // output of the file exporter, default "traces.jsonl"
+	SampleRatio float64 `yaml:"sample_ratio"` // fraction of events traced, default 1
 }
 
 type PluginConfig struct {
@@ -26,18 +75,118 @@ type PluginConfig struct {
 	Command string   `yaml:"command"`
 	Args    []string `yaml:"args"`
 	WorkDir struct {
-		Git struct {
-			Enabled    bool   `yaml:"enabled"`
-			Persistent bool   `yaml:"persistent"`
-			Version    string `yaml:"version"`
-		} `yaml:"git"`
-		Path string `yaml:"path"`
+		Git  GitConfig `yaml:"git"`
+		Path string    `yaml:"path"`
 	} `yaml:"work_dir"`
-	Env     map[string]string `yaml:"env"`
-	Address string            `yaml:"address"`
+	Env         map[string]string `yaml:"env"`
+	Address     string            `yaml:"address"`
+	Transport   string            `yaml:"transport"`    // "grpc" (default), "stdio" or "inprocess"
+	StdioFormat string            `yaml:"stdio_format"` // "protobuf" (default) or "json"; stdio transport only
+	Wasm        WasmConfig        `yaml:"wasm"`         // sandbox limits when command is a .wasm module
+	Webhook     WebhookConfig     `yaml:"webhook"`      // deliver events to an HTTP endpoint instead of a stream
+}
+
+// GitConfig clones a plugin's work_dir.path from git. With a poll interval, the host checks the
+// tracked branch or tags for a new commit and updates the plugin to it.
+type GitConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	Persistent     bool     `yaml:"persistent"`
+	Version        string   `yaml:"version"`          // commit or tag to check out; not updated
+	Branch         string   `yaml:"branch"`           // branch to track, default the remote's default branch
+	Tags           string   `yaml:"tags"`             // track the highest release tag matching this constraint, e.g. "^1.2"
+	PollIntervalMs int      `yaml:"poll_interval_ms"` // how often to look for a new commit; 0 disables updates
+	Build          []string `yaml:"build"`            // command run in a new checkout before it is started
+
+	// Remote is the repository cloned, which work_dir.path named before ParseConfig replaced it
+	// with the checkout.
+	Remote string `yaml:"-"`
+	// Commit is the commit checked out.
+	Commit string `yaml:"-"`
+}
+
+// Tracking returns the ref the plugin follows.
+func (g GitConfig) Tracking() (gitsource.Tracking, error) {
+	tr := gitsource.Tracking{Branch: g.Branch}
+	if g.Tags != "" {
+		c, err := gitsource.ParseConstraint(g.Tags)
+		if err != nil {
+			return gitsource.Tracking{}, err
+		}
+		tr.Tags = &c
+	}
+	return tr, nil
+}
+
+func (g GitConfig) validate() error {
+	if g.Branch != "" && g.Tags != "" {
+		return errors.New("git branch and tags are exclusive")
+	}
+	if g.Version != "" && (g.Branch != "" || g.Tags != "" || g.PollIntervalMs > 0) {
+		return errors.New("git version pins the checkout and cannot be combined with branch, tags or poll_interval_ms")
+	}
+	if g.PollIntervalMs < 0 {
+		return errors.New("git poll_interval_ms must not be negative")
+	}
+	_, err := g.Tracking()
+	return err
+}
+
+// WebhookConfig makes a plugin an HTTP endpoint the host POSTs batches of events to. It has no
+// command and no stream.
+type WebhookConfig struct {
+	URL        string   `yaml:"url"`
+	Format     string   `yaml:"format"`      // "protobuf" (default) or "json"
+	Secret     string   `yaml:"secret"`      // HMAC-SHA256 key requests are signed with
+	SecretEnv  string   `yaml:"secret_env"`  // environment variable holding the secret
+	Events     []string `yaml:"events"`      // event types to deliver, e.g. CHAT; all if empty
+	Observe    bool     `yaml:"observe"`     // only observe events, never cancel or mutate them
+	TimeoutMs  int      `yaml:"timeout_ms"`  // per observe-only delivery, default 2000
+	BatchMs    int      `yaml:"batch_ms"`    // how long observe-only events are collected, default 250
+	MaxRetries int      `yaml:"max_retries"` // retries of a failed observe-only delivery, default 5, negative for none
+}
+
+// WasmConfig limits a plugin whose command is a WebAssembly module run inside the host. The module
+// has no filesystem access beyond Mounts and no network access.
+type WasmConfig struct {
+	MemoryLimitMB int         `yaml:"memory_limit_mb"` // linear memory cap, default 64
+	CallTimeoutMs int         `yaml:"call_timeout_ms"` // time the module may run per message, default 1000
+	Mounts        []WasmMount `yaml:"mounts"`          // host directories the module may access
+}
+
+// WasmMount grants a WebAssembly plugin access to a host directory.
+type WasmMount struct {
+	Host     string `yaml:"host"`      // directory on the host, relative to work_dir.path
+	Guest    string `yaml:"guest"`     // path the module sees it at, default "/"
+	ReadOnly bool   `yaml:"read_only"` // reject writes
 }
 
+// IsWasm reports whether the plugin's command is a WebAssembly module run inside the host.
+func (pc PluginConfig) IsWasm() bool {
+	return strings.HasSuffix(pc.Command, ".wasm")
+}
+
+// LoadConfig reads the plugin config at path, ConfigFile if empty, and clones the repository of
+// every git plugin.
 func LoadConfig(path string) (Config, error) {
+	cfg, err := ParseConfig(path)
+	if err != nil {
+		return Config{}, err
+	}
+	for i := range cfg.Plugins {
+		pl := &cfg.Plugins[i]
+		if pl.WorkDir.Git.Remote == "" {
+			continue
+		}
+		if err := ClonePlugin(pl, ""); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// ParseConfig reads and validates the plugin config at path, ConfigFile if empty, without cloning
+// anything. The work directory of a git plugin is set to its checkout, which ClonePlugin creates.
+func ParseConfig(path string) (Config, error) {
 	if path == "" {
 		path = ConfigFile
 	}
@@ -53,59 +202,228 @@ func LoadConfig(path string) (Config, error) {
 		return Config{}, fmt.Errorf("decode plugin config: %w", err)
 	}
 
+	cfg.Path = path
+
 	if cfg.ServerPort == "" {
 		return Config{}, errors.New("server_port is required")
 	}
+	if cfg.AdminToken == "" {
+		cfg.AdminToken = os.Getenv("DF_ADMIN_TOKEN")
+	}
+	if cfg.AdminAddress != "" && cfg.AdminToken == "" {
+		return Config{}, errors.New("admin_token or DF_ADMIN_TOKEN is required when admin_address is set")
+	}
+	switch cfg.Tracing.Exporter {
+	case "", "otlp", "file":
+	default:
+		return Config{}, fmt.Errorf("unknown tracing exporter %q", cfg.Tracing.Exporter)
+	}
+	if cfg.CrashReports.Dir == "" {
+		cfg.CrashReports.Dir = "crash-reports"
+	}
+	if cfg.Recording.MaxSizeMB == 0 {
+		cfg.Recording.MaxSizeMB = 256
+	}
+	if cfg.Tracing.Endpoint == "" {
+		cfg.Tracing.Endpoint = "localhost:4318"
+	}
+	if cfg.Tracing.File == "" {
+		cfg.Tracing.File = "traces.jsonl"
+	}
+	if cfg.Tracing.SampleRatio <= 0 {
+		cfg.Tracing.SampleRatio = 1
+	}
+	switch cfg.PeerCheck {
+	case "":
+		cfg.PeerCheck = "pid"
+	case "pid", "uid", "off":
+	default:
+		return Config{}, fmt.Errorf("unknown peer_check %q", cfg.PeerCheck)
+	}
 	// Default hello wait timeout to 2000ms if not set or invalid.
 	if cfg.HelloTimeoutMs <= 0 {
 		cfg.HelloTimeoutMs = 2000
 	}
+	if cfg.HeartbeatIntervalMs == 0 {
+		cfg.HeartbeatIntervalMs = 5000
+	}
+	if cfg.HeartbeatMissedLimit <= 0 {
+		cfg.HeartbeatMissedLimit = 3
+	}
+	if cfg.CustomMetricsLimit <= 0 {
+		cfg.CustomMetricsLimit = 64
+	}
+	if cfg.CustomSeriesLimit <= 0 {
+		cfg.CustomSeriesLimit = 1000
+	}
 	for i := range cfg.Plugins {
 		pl := &cfg.Plugins[i]
 		if pl.ID == "" {
 			pl.ID = fmt.Sprintf("plugin-%d", i+1)
 		}
+		switch pl.Transport {
+		case "", "grpc", "stdio", "inprocess":
+		default:
+			return Config{}, fmt.Errorf("plugin %q: unknown transport %q", pl.ID, pl.Transport)
+		}
+		switch pl.StdioFormat {
+		case "", "protobuf", "json":
+		default:
+			return Config{}, fmt.Errorf("plugin %q: unknown stdio_format %q", pl.ID, pl.StdioFormat)
+		}
+		if pl.Transport == "stdio" && pl.Command == "" {
+			return Config{}, fmt.Errorf("plugin %q: stdio transport requires a command", pl.ID)
+		}
+		if pl.Transport == "inprocess" && pl.Command != "" {
+			return Config{}, fmt.Errorf("plugin %q: in-process plugins have no command", pl.ID)
+		}
+		if pl.Webhook.URL != "" {
+			if pl.Command != "" || (pl.Transport != "" && pl.Transport != "grpc") {
+				return Config{}, fmt.Errorf("plugin %q: webhook plugins have no command or transport", pl.ID)
+			}
+			if err := pl.Webhook.defaults(); err != nil {
+				return Config{}, fmt.Errorf("plugin %q: %w", pl.ID, err)
+			}
+		}
+		if pl.IsWasm() {
+			if pl.Transport != "" && pl.Transport != "grpc" {
+				return Config{}, fmt.Errorf("plugin %q: WebAssembly plugins do not use transport %q", pl.ID, pl.Transport)
+			}
+			if pl.Wasm.MemoryLimitMB <= 0 {
+				pl.Wasm.MemoryLimitMB = 64
+			}
+			if pl.Wasm.CallTimeoutMs <= 0 {
+				pl.Wasm.CallTimeoutMs = 1000
+			}
+			for j := range pl.Wasm.Mounts {
+				mount := &pl.Wasm.Mounts[j]
+				if mount.Host == "" {
+					return Config{}, fmt.Errorf("plugin %q: wasm mount %d has no host directory", pl.ID, j+1)
+				}
+				if mount.Guest == "" {
+					mount.Guest = "/"
+				}
+			}
+		}
 		if pl.Command == "" || pl.WorkDir.Path == "" {
 			continue
 		}
 
 		if pl.WorkDir.Git.Enabled {
-			path := filepath.Join(os.TempDir(), pl.ID)
-			remote := pl.WorkDir.Path
-
-			needClone := true
-			if pl.WorkDir.Git.Persistent {
-				if _, err := os.Stat(path); err == nil {
-					needClone = false
-				} else if !errors.Is(err, os.ErrNotExist) {
-					return cfg, fmt.Errorf("stat remote plugin %q: %w", pl.ID, err)
-				}
-			} else {
-				if err := os.RemoveAll(path); err != nil {
-					return cfg, fmt.Errorf("reset remote plugin %q: %w", pl.ID, err)
-				}
+			git := &pl.WorkDir.Git
+			if err := git.validate(); err != nil {
+				return Config{}, fmt.Errorf("plugin %q: %w", pl.ID, err)
 			}
+			git.Remote = pl.WorkDir.Path
+			pl.WorkDir.Path = filepath.Join(os.TempDir(), pl.ID)
+		}
 
-			if needClone {
-				if err := run("git", "", "clone", remote, path, "--depth=1"); err != nil {
-					return cfg, fmt.Errorf("clone remote plugin %q: %w", pl.ID, err)
-				}
+		if !filepath.IsAbs(pl.WorkDir.Path) {
+			pl.WorkDir.Path = filepath.Clean(pl.WorkDir.Path)
+		}
+	}
+	return cfg, nil
+}
 
-				if pl.WorkDir.Git.Version != "" {
-					if err := run("git", path, "checkout", "--detach", pl.WorkDir.Git.Version); err != nil {
-						return cfg, err
-					}
-				}
+// ErrRejectedCommit is returned by ClonePlugin when the plugin's ref resolves to the commit it was
+// told to reject.
+var ErrRejectedCommit = errors.New("commit was rejected")
+
+// ClonePlugin clones the repository of a git plugin parsed by ParseConfig into its work directory,
+// unless a persistent checkout is left from an earlier run, and sets the commit checked out. If
+// the ref resolves to rejected, such as a commit that already failed to build or start, nothing is
+// checked out and ErrRejectedCommit is returned.
+func ClonePlugin(pl *PluginConfig, rejected string) error {
+	git := &pl.WorkDir.Git
+	path := pl.WorkDir.Path
+
+	ctx := context.Background()
+	if git.Persistent {
+		if _, err := os.Stat(path); err == nil {
+			commit, err := gitsource.Head(ctx, path)
+			if err != nil {
+				return fmt.Errorf("remote plugin %q: %w", pl.ID, err)
 			}
+			git.Commit = commit
+			return nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("stat remote plugin %q: %w", pl.ID, err)
+		}
+	}
+	// The ref is resolved before the old checkout is removed, so a rejected commit leaves it be.
+	var ref gitsource.Ref
+	if git.Version == "" {
+		tr, _ := git.Tracking()
+		var err error
+		if ref, err = gitsource.Resolve(ctx, git.Remote, tr); err != nil {
+			return fmt.Errorf("resolve remote plugin %q: %w", pl.ID, err)
+		}
+		if ref.Commit == rejected {
+			return fmt.Errorf("remote plugin %q: %s: %w", pl.ID, ref.Commit, ErrRejectedCommit)
+		}
+	}
+	if !git.Persistent {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("reset remote plugin %q: %w", pl.ID, err)
+		}
+	}
 
-			pl.WorkDir.Path = path
+	var commit string
+	if git.Version != "" {
+		if err := run("git", "", "clone", git.Remote, path, "--depth=1"); err != nil {
+			return fmt.Errorf("clone remote plugin %q: %w", pl.ID, err)
+		}
+		if err := run("git", path, "checkout", "--detach", git.Version); err != nil {
+			return err
+		}
+		c, err := gitsource.Head(ctx, path)
+		if err != nil {
+			return fmt.Errorf("remote plugin %q: %w", pl.ID, err)
+		}
+		if c == rejected {
+			_ = os.RemoveAll(path)
+			return fmt.Errorf("remote plugin %q: %s: %w", pl.ID, c, ErrRejectedCommit)
 		}
+		commit = c
+	} else {
+		var err error
+		if commit, err = gitsource.Clone(ctx, git.Remote, ref, path); err != nil {
+			return fmt.Errorf("clone remote plugin %q: %w", pl.ID, err)
+		}
+	}
+	git.Commit = commit
+	if len(git.Build) > 0 {
+		if err := gitsource.Build(ctx, path, git.Build, pl.Env); err != nil {
+			return fmt.Errorf("build remote plugin %q: %w", pl.ID, err)
+		}
+	}
+	return nil
+}
 
-		if !filepath.IsAbs(pl.WorkDir.Path) {
-			pl.WorkDir.Path = filepath.Clean(pl.WorkDir.Path)
+func (w *WebhookConfig) defaults() error {
+	switch w.Format {
+	case "":
+		w.Format = "protobuf"
+	case "protobuf", "json":
+	default:
+		return fmt.Errorf("unknown webhook format %q", w.Format)
+	}
+	if w.Secret == "" && w.SecretEnv != "" {
+		w.Secret = os.Getenv(w.SecretEnv)
+		if w.Secret == "" {
+			return fmt.Errorf("webhook secret_env %s is not set", w.SecretEnv)
 		}
 	}
-	return cfg, nil
+	if w.TimeoutMs <= 0 {
+		w.TimeoutMs = 2000
+	}
+	if w.BatchMs <= 0 {
+		w.BatchMs = 250
+	}
+	if w.MaxRetries == 0 {
+		w.MaxRetries = 5
+	}
+	return nil
 }
 
 func run(bin string, path string, args ...string) error {
@@ -0,0 +1,235 @@
+// Package conformance checks that a plugin SDK speaks the host's protocol. The runner acts as the
+// host: it launches a fixture plugin built with the SDK and drives it through scripted scenarios
+// (handshake, subscriptions, heartbeats, cancellable events, plain and compressed batches, action
+// correlation, reconnecting and shutdown), reporting a result per scenario.
+//
+// The fixture must behave as follows:
+//
+//   - Connect to DF_PLUGIN_SERVER_ADDRESS as DF_PLUGIN_ID, offering API version v1.
+//   - Subscribe to CHAT, COMMAND and PLAYER_JOIN.
+//   - Answer CHAT by cancelling it if the message is "cancel", and otherwise by replacing the
+//     message with its upper-case form.
+//   - For the command "echo", send the arguments joined by spaces as a chat message to the player
+//     who ran it.
+//   - For the command "settime <time>", send a WorldSetTimeAction for the world "world" with a
+//     correlation ID, then tell the player "ok", or "error: " and the error of its ActionResult.
+//   - Reconnect when the stream is lost, resuming the session if session.resume is enabled, and
+//     exit when the host sends HostShutdown.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/secmc/plugin/plugin/adapters/grpc"
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/ports"
+)
+
+// PluginID is the ID the runner gives the plugin under test.
+const PluginID = "conformance"
+
+// Target is a plugin to check, typically the fixture of one SDK.
+type Target struct {
+	// Name identifies the target in the report.
+	Name string
+	// Command and Args launch the plugin, in Dir if set.
+	Command string
+	Args    []string
+	Dir     string
+	// Launch runs the plugin in-process instead of Command. It is cancelled once the scenarios have
+	// run and must return when the plugin exits.
+	Launch func(ctx context.Context, address, pluginID string) error
+}
+
+// Runner runs the conformance scenarios against targets.
+type Runner struct {
+	// Address is where the runner listens for the plugin. It defaults to a free local TCP port.
+	Address string
+	// Timeout bounds how long the runner waits for each expected message. It defaults to 5s.
+	Timeout time.Duration
+	// ConnectTimeout bounds how long the plugin may take to first connect, which includes building
+	// or starting it. It defaults to 60s.
+	ConnectTimeout time.Duration
+	// Output receives the plugin's stdout and stderr. It defaults to discarding them.
+	Output io.Writer
+	// Log receives progress messages. It defaults to discarding them.
+	Log *slog.Logger
+}
+
+// Status is the outcome of a scenario.
+type Status string
+
+const (
+	Pass Status = "pass"
+	Fail Status = "fail"
+	Skip Status = "skip"
+)
+
+// Result is the outcome of one scenario.
+type Result struct {
+	Scenario   string `json:"scenario"`
+	Status     Status `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report holds the results of running every scenario against a target.
+type Report struct {
+	Target  string   `json:"target"`
+	Results []Result `json:"results"`
+}
+
+// Passed reports whether no scenario failed.
+func (r Report) Passed() bool {
+	for _, res := range r.Results {
+		if res.Status == Fail {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteText writes the report in a human readable form.
+func (r Report) WriteText(w io.Writer) {
+	counts := map[Status]int{}
+	fmt.Fprintf(w, "%s\n", r.Target)
+	for _, res := range r.Results {
+		counts[res.Status]++
+		line := fmt.Sprintf("  %-4s %-24s %5dms", strings.ToUpper(string(res.Status)), res.Scenario, res.DurationMs)
+		if res.Detail != "" {
+			line += "  " + res.Detail
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "  %d passed, %d failed, %d skipped\n", counts[Pass], counts[Fail], counts[Skip])
+}
+
+// Run launches target and runs every scenario against it in order. Scenarios that depend on an
+// earlier one which failed are skipped.
+func (r *Runner) Run(ctx context.Context, target Target) Report {
+	report := Report{Target: target.Name}
+	s, err := r.start(ctx, target)
+	if err != nil {
+		report.Results = append(report.Results, Result{Scenario: scenarios[0].name, Status: Fail, Detail: err.Error()})
+		return report
+	}
+	defer s.close()
+
+	var blocked string
+	for _, sc := range scenarios {
+		if blocked != "" {
+			report.Results = append(report.Results, Result{Scenario: sc.name, Status: Skip, Detail: blocked + " failed"})
+			continue
+		}
+		r.log().Info("running scenario", "target", target.Name, "scenario", sc.name)
+		start := time.Now()
+		err := sc.run(s)
+		res := Result{Scenario: sc.name, Status: Pass, DurationMs: time.Since(start).Milliseconds()}
+		var skip skipError
+		switch {
+		case errors.As(err, &skip):
+			res.Status, res.Detail = Skip, string(skip)
+		case err != nil:
+			res.Status, res.Detail = Fail, err.Error()
+			if sc.required {
+				blocked = sc.name
+			}
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+func (r *Runner) log() *slog.Logger {
+	if r.Log == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return r.Log
+}
+
+// start listens for the plugin and launches it.
+func (r *Runner) start(ctx context.Context, target Target) (*session, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &session{
+		timeout:        r.Timeout,
+		connectTimeout: r.ConnectTimeout,
+		bootID:         fmt.Sprintf("conformance-%d", time.Now().UnixNano()),
+		conns:          make(chan *conn, 4),
+		exited:         make(chan error, 1),
+		cancel:         cancel,
+	}
+	if s.timeout <= 0 {
+		s.timeout = 5 * time.Second
+	}
+	if s.connectTimeout <= 0 {
+		s.connectTimeout = 60 * time.Second
+	}
+
+	address := r.Address
+	if address == "" {
+		address = "127.0.0.1:0"
+	}
+	server, err := grpc.NewServer(address, unixsocket.Permissions{}, s.accept, nil)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+	s.server = server
+	go func() {
+		if err := server.Serve(); err != nil {
+			r.log().Error("conformance server error", "error", err)
+		}
+	}()
+
+	address = server.Address()
+	if strings.HasPrefix(address, "/") {
+		address = "unix:" + address
+	}
+	if target.Launch != nil {
+		go func() { s.exited <- target.Launch(ctx, address, PluginID) }()
+		return s, nil
+	}
+
+	output := r.Output
+	if output == nil {
+		output = io.Discard
+	}
+	cmd := exec.CommandContext(ctx, target.Command, target.Args...)
+	cmd.Dir = target.Dir
+	cmd.Env = append(os.Environ(),
+		"DF_PLUGIN_ID="+PluginID,
+		"DF_PLUGIN_SERVER_ADDRESS="+address,
+		"DF_HOST_BOOT_ID="+s.bootID,
+	)
+	cmd.Stdout, cmd.Stderr = output, output
+	if err := cmd.Start(); err != nil {
+		s.close()
+		return nil, fmt.Errorf("launch %s: %w", target.Command, err)
+	}
+	go func() { s.exited <- cmd.Wait() }()
+	return s, nil
+}
+
+// accept hands a plugin stream to the scenarios and holds it open until they drop it or the
+// plugin closes it.
+func (s *session) accept(stream ports.Stream) error {
+	c := newConn(stream)
+	select {
+	case s.conns <- c:
+	default:
+		return errors.New("too many connections")
+	}
+	select {
+	case <-c.release:
+	case <-c.closed:
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package conformance
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// scenario is one step of the conformance run. Scenarios run in order on the same plugin.
+type scenario struct {
+	name string
+	run  func(*session) error
+	// required scenarios leave the plugin in a state later ones rely on; if one fails, the rest are
+	// skipped.
+	required bool
+}
+
+var scenarios = []scenario{
+	{name: "handshake", run: (*session).handshake, required: true},
+	{name: "subscriptions", run: (*session).subscriptions, required: true},
+	{name: "heartbeat", run: (*session).heartbeat},
+	{name: "cancellable events", run: (*session).cancellableEvents},
+	{name: "event batch", run: (*session).eventBatch},
+	{name: "compressed event batch", run: (*session).compressedEventBatch},
+	{name: "action correlation", run: (*session).actionCorrelation},
+	{name: "reconnect", run: (*session).reconnect, required: true},
+	{name: "shutdown", run: (*session).shutdown},
+}
+
+// skipError marks a scenario that does not apply to the plugin.
+type skipError string
+
+func (e skipError) Error() string { return string(e) }
+
+func (s *session) handshake() error {
+	if err := s.waitConn(s.connectTimeout); err != nil {
+		return err
+	}
+	msg, err := s.expect("hello", func(*pb.PluginToHost) bool { return true })
+	if err != nil {
+		return err
+	}
+	return s.negotiate(msg)
+}
+
+func (s *session) subscriptions() error {
+	msg, err := s.expect("subscription", func(msg *pb.PluginToHost) bool { return msg.GetSubscribe() != nil })
+	if err != nil {
+		return err
+	}
+	events := msg.GetSubscribe().GetEvents()
+	if slices.Contains(events, pb.EventType_EVENT_TYPE_ALL) {
+		return nil
+	}
+	var missing []string
+	for _, t := range []pb.EventType{pb.EventType_CHAT, pb.EventType_COMMAND, pb.EventType_PLAYER_JOIN} {
+		if !slices.Contains(events, t) {
+			missing = append(missing, t.String())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("subscription lacks %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (s *session) heartbeat() error {
+	if !s.has("heartbeat") {
+		return skipError("heartbeat not enabled")
+	}
+	const nonce = 7
+	if err := s.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Ping{Ping: &pb.HostPing{Nonce: nonce, SentUnixMs: time.Now().UnixMilli()}}}); err != nil {
+		return err
+	}
+	msg, err := s.expect("pong", func(msg *pb.PluginToHost) bool { return msg.GetPong() != nil })
+	if err != nil {
+		return err
+	}
+	if got := msg.GetPong().GetNonce(); got != nonce {
+		return fmt.Errorf("pong has nonce %d, want %d", got, nonce)
+	}
+	return nil
+}
+
+// chat sends a cancellable chat message and returns the plugin's answer, which must arrive before
+// the host would stop waiting.
+func (s *session) chat(message string) (*pb.EventResult, error) {
+	id := s.nextEventID()
+	start := time.Now()
+	err := s.sendEvent(&pb.EventEnvelope{
+		EventId:         id,
+		Type:            pb.EventType_CHAT,
+		ExpectsResponse: true,
+		Payload:         &pb.EventEnvelope_Chat{Chat: &pb.ChatEvent{PlayerUuid: playerUUID, Name: "Steve", Message: message}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	msg, err := s.expect("result of "+id, func(msg *pb.PluginToHost) bool { return msg.GetEventResult().GetEventId() == id })
+	if err != nil {
+		return nil, err
+	}
+	if d := time.Since(start); d > eventDeadline {
+		return nil, fmt.Errorf("answered chat %q after %s; the host waits %s", message, d.Round(time.Millisecond), eventDeadline)
+	}
+	return msg.GetEventResult(), nil
+}
+
+func (s *session) cancellableEvents() error {
+	res, err := s.chat("hello")
+	if err != nil {
+		return err
+	}
+	if res.GetCancel() || res.GetChat().GetMessage() != "HELLO" {
+		return fmt.Errorf("chat \"hello\" answered with %v, want the message mutated to \"HELLO\"", res)
+	}
+	if res, err = s.chat("cancel"); err != nil {
+		return err
+	}
+	if !res.GetCancel() {
+		return fmt.Errorf("chat \"cancel\" answered with %v, want it cancelled", res)
+	}
+	return nil
+}
+
+// command returns a COMMAND event from the runner's player.
+func (s *session) command(name string, args ...string) *pb.EventEnvelope {
+	return &pb.EventEnvelope{
+		EventId: s.nextEventID(),
+		Type:    pb.EventType_COMMAND,
+		Payload: &pb.EventEnvelope_Command{Command: &pb.CommandEvent{
+			PlayerUuid: playerUUID,
+			Name:       "Steve",
+			Raw:        "/" + strings.Join(append([]string{name}, args...), " "),
+			Command:    name,
+			Args:       args,
+		}},
+	}
+}
+
+// echoBatch sends three echo commands in a batch built by wrap and checks that they are handled
+// in order.
+func (s *session) echoBatch(label string, wrap func(*pb.EventBatch) (*pb.HostToPlugin, error)) error {
+	batch := &pb.EventBatch{}
+	for i := 1; i <= 3; i++ {
+		batch.Events = append(batch.Events, s.command("echo", label, fmt.Sprint(i)))
+	}
+	msg, err := wrap(batch)
+	if err != nil {
+		return err
+	}
+	if err := s.send(msg); err != nil {
+		return err
+	}
+	for i := 1; i <= 3; i++ {
+		if err := s.expectChat(fmt.Sprintf("%s %d", label, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *session) eventBatch() error {
+	return s.echoBatch("batch", func(b *pb.EventBatch) (*pb.HostToPlugin, error) {
+		return &pb.HostToPlugin{Payload: &pb.HostToPlugin_Events{Events: b}}, nil
+	})
+}
+
+func (s *session) compressedEventBatch() error {
+	if !s.has("compression.snappy") {
+		return skipError("compression.snappy not enabled")
+	}
+	return s.echoBatch("compressed", func(b *pb.EventBatch) (*pb.HostToPlugin, error) {
+		data, err := proto.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.HostToPlugin{Payload: &pb.HostToPlugin_CompressedEvents{CompressedEvents: &pb.CompressedEventBatch{
+			Data:         snappy.Encode(nil, data),
+			OriginalSize: int32(len(data)),
+		}}}, nil
+	})
+}
+
+// setTime runs settime and answers the action it causes with status, returning its correlation ID.
+func (s *session) setTime(ticks int32, status *pb.ActionStatus) (string, error) {
+	if err := s.sendEvent(s.command("settime", fmt.Sprint(ticks))); err != nil {
+		return "", err
+	}
+	a, err := s.nextAction("set time action", func(a *pb.Action) bool { return a.GetWorldSetTime() != nil })
+	if err != nil {
+		return "", err
+	}
+	if got := a.GetWorldSetTime().GetTime(); got != ticks {
+		return "", fmt.Errorf("set time action has time %d, want %d", got, ticks)
+	}
+	id := a.GetCorrelationId()
+	if id == "" {
+		return "", fmt.Errorf("set time action has no correlation ID")
+	}
+	return id, s.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_ActionResult{ActionResult: &pb.ActionResult{
+		CorrelationId: id,
+		Status:        status,
+	}}})
+}
+
+func (s *session) actionCorrelation() error {
+	first, err := s.setTime(1000, &pb.ActionStatus{Ok: true})
+	if err != nil {
+		return err
+	}
+	if err := s.expectChat("ok"); err != nil {
+		return err
+	}
+	second, err := s.setTime(2000, &pb.ActionStatus{Error: proto.String("world not found")})
+	if err != nil {
+		return err
+	}
+	if first == second {
+		return fmt.Errorf("correlation ID %q was reused", first)
+	}
+	return s.expectChat("error: world not found")
+}
+
+func (s *session) reconnect() error {
+	// Durable events carry sequences; a resuming plugin reports the last one it handled.
+	for seq := uint64(1); seq <= 2; seq++ {
+		err := s.sendEvent(&pb.EventEnvelope{
+			EventId:  s.nextEventID(),
+			Type:     pb.EventType_PLAYER_JOIN,
+			Sequence: seq,
+			Payload:  &pb.EventEnvelope_PlayerJoin{PlayerJoin: &pb.PlayerJoinEvent{PlayerUuid: playerUUID, Name: "Steve"}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := s.sendEvent(s.command("echo", "before")); err != nil {
+		return err
+	}
+	if err := s.expectChat("before"); err != nil {
+		return err
+	}
+
+	s.conn.drop()
+	if err := s.waitConn(s.timeout); err != nil {
+		return fmt.Errorf("after the stream was dropped: %w", err)
+	}
+	msg, err := s.expect("hello", func(*pb.PluginToHost) bool { return true })
+	if err != nil {
+		return err
+	}
+	resume := msg.GetHello().GetResume()
+	if err := s.negotiate(msg); err != nil {
+		return err
+	}
+	if s.has("session.resume") && (resume.GetBootId() != s.bootID || resume.GetLastSequence() != 2) {
+		return fmt.Errorf("hello resumes %v, want boot_id %q and last_sequence 2", resume, s.bootID)
+	}
+	if err := s.subscriptions(); err != nil {
+		return err
+	}
+	if err := s.sendEvent(s.command("echo", "after")); err != nil {
+		return err
+	}
+	return s.expectChat("after")
+}
+
+func (s *session) shutdown() error {
+	if err := s.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Shutdown{Shutdown: &pb.HostShutdown{Reason: "conformance run finished"}}}); err != nil {
+		return err
+	}
+	select {
+	case <-s.conn.closed:
+	case <-time.After(s.timeout):
+		return fmt.Errorf("stream still open %s after shutdown", s.timeout)
+	}
+	select {
+	case err := <-s.exited:
+		s.gone = true
+		if err != nil {
+			return fmt.Errorf("plugin exited with %v", err)
+		}
+		return nil
+	case <-time.After(s.timeout):
+		return fmt.Errorf("plugin still running %s after shutdown", s.timeout)
+	}
+}
@@ -0,0 +1,239 @@
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/adapters/grpc"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	// apiVersion is the plugin API version the runner speaks.
+	apiVersion = "v1"
+	// eventDeadline is how long the host waits for the answer to a cancellable event.
+	eventDeadline = 250 * time.Millisecond
+	// playerUUID is the player the runner's events come from.
+	playerUUID = "6f1c2a8e-3b7d-4c55-9a0e-2d4f8b1c7e90"
+)
+
+// capabilities are the optional protocol features the runner exercises.
+var capabilities = []string{"compression.snappy", "heartbeat", "session.resume"}
+
+// session is the runner's side of one plugin under test.
+type session struct {
+	timeout        time.Duration
+	connectTimeout time.Duration
+	bootID         string
+
+	server *grpc.GrpcServer
+	conns  chan *conn
+	conn   *conn
+	cancel func()
+
+	// exited receives the plugin's exit error once; gone is set after it has been received.
+	exited chan error
+	gone   bool
+
+	enabled []string
+	actions []*pb.Action
+	eventID int
+}
+
+// conn is a plugin stream accepted by the runner.
+type conn struct {
+	stream  ports.Stream
+	msgs    chan *pb.PluginToHost
+	closed  chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func newConn(stream ports.Stream) *conn {
+	c := &conn{
+		stream:  stream,
+		msgs:    make(chan *pb.PluginToHost, 256),
+		closed:  make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	go c.recvLoop()
+	return c
+}
+
+func (c *conn) recvLoop() {
+	defer close(c.closed)
+	for {
+		data, err := c.stream.Recv()
+		if err != nil {
+			return
+		}
+		msg := &pb.PluginToHost{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			continue
+		}
+		select {
+		case c.msgs <- msg:
+		case <-c.release:
+			return
+		}
+	}
+}
+
+// drop ends the stream from the host's side.
+func (c *conn) drop() {
+	c.once.Do(func() { close(c.release) })
+}
+
+func (s *session) close() {
+	if s.conn != nil {
+		s.conn.drop()
+	}
+	s.cancel()
+	s.server.Stop()
+	if !s.gone {
+		select {
+		case <-s.exited:
+		case <-time.After(s.timeout):
+		}
+	}
+}
+
+// waitConn waits for the plugin to open a stream and makes it the current one.
+func (s *session) waitConn(timeout time.Duration) error {
+	select {
+	case c := <-s.conns:
+		s.conn = c
+		s.actions = nil
+		return nil
+	case err := <-s.exited:
+		s.gone = true
+		if err != nil {
+			return fmt.Errorf("plugin exited before connecting: %w", err)
+		}
+		return errors.New("plugin exited before connecting")
+	case <-time.After(timeout):
+		return fmt.Errorf("plugin did not connect within %s", timeout)
+	}
+}
+
+func (s *session) has(capability string) bool {
+	return slices.Contains(s.enabled, capability)
+}
+
+func (s *session) nextEventID() string {
+	s.eventID++
+	return fmt.Sprintf("conformance-%d", s.eventID)
+}
+
+func (s *session) send(msg *pb.HostToPlugin) error {
+	msg.PluginId = PluginID
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := s.conn.stream.Send(data); err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+	return nil
+}
+
+func (s *session) sendEvent(ev *pb.EventEnvelope) error {
+	return s.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Event{Event: ev}})
+}
+
+// expect returns the first message from the plugin that match accepts, discarding earlier ones
+// apart from actions, which are kept for nextAction.
+func (s *session) expect(what string, match func(*pb.PluginToHost) bool) (*pb.PluginToHost, error) {
+	deadline := time.NewTimer(s.timeout)
+	defer deadline.Stop()
+	for {
+		var msg *pb.PluginToHost
+		select {
+		case msg = <-s.conn.msgs:
+		case <-s.conn.closed:
+			select {
+			case msg = <-s.conn.msgs:
+			default:
+				return nil, fmt.Errorf("stream closed while waiting for %s", what)
+			}
+		case <-deadline.C:
+			return nil, fmt.Errorf("no %s within %s", what, s.timeout)
+		}
+		if match(msg) {
+			return msg, nil
+		}
+		s.actions = append(s.actions, msg.GetActions().GetActions()...)
+	}
+}
+
+// nextAction returns the earliest action received that match accepts.
+func (s *session) nextAction(what string, match func(*pb.Action) bool) (*pb.Action, error) {
+	for {
+		for i, a := range s.actions {
+			if match(a) {
+				s.actions = slices.Delete(s.actions, i, i+1)
+				return a, nil
+			}
+		}
+		msg, err := s.expect(what, func(msg *pb.PluginToHost) bool { return msg.GetActions() != nil })
+		if err != nil {
+			return nil, err
+		}
+		s.actions = append(s.actions, msg.GetActions().GetActions()...)
+	}
+}
+
+// expectChat waits for a chat message to the runner's player and checks its text.
+func (s *session) expectChat(want string) error {
+	a, err := s.nextAction(fmt.Sprintf("chat %q", want), func(a *pb.Action) bool { return a.GetSendChat() != nil })
+	if err != nil {
+		return err
+	}
+	chat := a.GetSendChat()
+	if chat.GetMessage() != want || chat.GetTargetUuid() != playerUUID {
+		return fmt.Errorf("got chat %q to %q, want %q to the player", chat.GetMessage(), chat.GetTargetUuid(), want)
+	}
+	return nil
+}
+
+// negotiate answers hello the way the host does, remembering the enabled capabilities.
+func (s *session) negotiate(msg *pb.PluginToHost) error {
+	hello := msg.GetHello()
+	if hello == nil {
+		return fmt.Errorf("first message is %T, want hello", msg.GetPayload())
+	}
+	if msg.GetPluginId() != PluginID {
+		return fmt.Errorf("hello has plugin_id %q, want %q from DF_PLUGIN_ID", msg.GetPluginId(), PluginID)
+	}
+	versions := hello.GetApiVersions()
+	if len(versions) == 0 && hello.GetApiVersion() != "" {
+		versions = []string{hello.GetApiVersion()}
+	}
+	if !slices.Contains(versions, apiVersion) {
+		return fmt.Errorf("hello offers api versions %v, want %s", versions, apiVersion)
+	}
+	for _, c := range hello.GetRequiredCapabilities() {
+		if !slices.Contains(capabilities, c) {
+			return fmt.Errorf("plugin requires capability %q, which the runner does not exercise", c)
+		}
+	}
+	s.enabled = nil
+	for _, c := range append(slices.Clone(hello.GetCapabilities()), hello.GetRequiredCapabilities()...) {
+		if slices.Contains(capabilities, c) && !slices.Contains(s.enabled, c) {
+			s.enabled = append(s.enabled, c)
+		}
+	}
+	return s.send(&pb.HostToPlugin{Payload: &pb.HostToPlugin_Hello{Hello: &pb.HostHello{
+		ApiVersion:           apiVersion,
+		BootId:               s.bootID,
+		SupportedApiVersions: []string{apiVersion},
+		Capabilities:         capabilities,
+		EnabledCapabilities:  s.enabled,
+	}}})
+}
@@ -0,0 +1,102 @@
+package gitsource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Constraint selects the release tags a plugin may be updated to, such as ">=1.2.0 <2.0.0" or
+// "^1.4". Terms separated by spaces or commas must all match. A term is a version preceded by one
+// of =, !=, >, >=, <, <= (= if omitted), ^ (the same major version, or minor version below 1.0.0)
+// or ~ (the same minor version). "*" or an empty constraint matches every release.
+type Constraint struct {
+	terms []term
+}
+
+type term struct {
+	op      string
+	version string // canonical, e.g. v1.2.0
+}
+
+// ParseConstraint parses a constraint. Versions may omit the leading "v" and trailing parts.
+func ParseConstraint(s string) (Constraint, error) {
+	var c Constraint
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if field == "*" {
+			continue
+		}
+		op := strings.TrimRight(field[:min(2, len(field))], "0123456789vV.")
+		switch op {
+		case "=", "!=", ">", ">=", "<", "<=", "^", "~", "":
+		default:
+			return Constraint{}, fmt.Errorf("constraint %q: unknown operator %q", s, op)
+		}
+		v, ok := Version(field[len(op):])
+		if !ok {
+			return Constraint{}, fmt.Errorf("constraint %q: invalid version %q", s, field[len(op):])
+		}
+		switch op {
+		case "":
+			op = "="
+		case "^", "~":
+			// Expand to a range so Match only compares.
+			upper := bump(v, op)
+			c.terms = append(c.terms, term{op: ">=", version: v}, term{op: "<", version: upper})
+			continue
+		}
+		c.terms = append(c.terms, term{op: op, version: v})
+	}
+	return c, nil
+}
+
+// Match reports whether the canonical version v satisfies the constraint.
+func (c Constraint) Match(v string) bool {
+	for _, t := range c.terms {
+		cmp := semver.Compare(v, t.version)
+		var ok bool
+		switch t.op {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Version returns the canonical form of a release version such as "1.2" or "v1.2.3", and false
+// if s is not a semantic version or is a pre-release.
+func Version(s string) (string, bool) {
+	if !strings.HasPrefix(s, "v") {
+		s = "v" + s
+	}
+	if !semver.IsValid(s) || semver.Prerelease(s) != "" {
+		return "", false
+	}
+	return semver.Canonical(s), true
+}
+
+// bump returns the first version excluded by a ^ or ~ term on v.
+func bump(v, op string) string {
+	parts := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	major, _ := strconv.Atoi(parts[0])
+	minor, _ := strconv.Atoi(parts[1])
+	if op == "^" && major > 0 {
+		return fmt.Sprintf("v%d.0.0", major+1)
+	}
+	return fmt.Sprintf("v%d.%d.0", major, minor+1)
+}
@@ -0,0 +1,167 @@
+// Package gitsource fetches plugins from git repositories and finds the commit a plugin should
+// run: the head of a branch, the highest release tag matching a Constraint, or the head of the
+// remote's default branch. Remotes are queried with git ls-remote, so checking for a new commit
+// does not fetch anything.
+package gitsource
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// outputLines is how much of the output of a failed command is kept in its error.
+const outputLines = 20
+
+// Ref is a commit and the branch or tag it was found through.
+type Ref struct {
+	// Name is a branch or tag name, or empty for the remote's default branch.
+	Name   string
+	Commit string
+}
+
+// Tracking selects the ref of a remote to follow: the release tags matching Tags if it is set,
+// otherwise Branch, otherwise the remote's default branch.
+type Tracking struct {
+	Branch string
+	Tags   *Constraint
+}
+
+// Resolve finds the commit of remote that tr currently points at.
+func Resolve(ctx context.Context, remote string, tr Tracking) (Ref, error) {
+	switch {
+	case tr.Tags != nil:
+		return latestTag(ctx, remote, *tr.Tags)
+	case tr.Branch != "":
+		refs, err := lsRemote(ctx, remote, "refs/heads/"+tr.Branch)
+		if err != nil {
+			return Ref{}, err
+		}
+		commit, ok := refs["refs/heads/"+tr.Branch]
+		if !ok {
+			return Ref{}, fmt.Errorf("branch %s not found in %s", tr.Branch, remote)
+		}
+		return Ref{Name: tr.Branch, Commit: commit}, nil
+	}
+	refs, err := lsRemote(ctx, remote, "HEAD")
+	if err != nil {
+		return Ref{}, err
+	}
+	commit, ok := refs["HEAD"]
+	if !ok {
+		return Ref{}, fmt.Errorf("%s has no HEAD", remote)
+	}
+	return Ref{Commit: commit}, nil
+}
+
+// latestTag returns the highest release tag of remote matching c.
+func latestTag(ctx context.Context, remote string, c Constraint) (Ref, error) {
+	refs, err := lsRemote(ctx, "--tags", remote)
+	if err != nil {
+		return Ref{}, err
+	}
+	var best Ref
+	var bestVersion string
+	for name, commit := range refs {
+		tag, ok := strings.CutPrefix(name, "refs/tags/")
+		if !ok || strings.HasSuffix(tag, "^{}") {
+			continue
+		}
+		v, ok := Version(tag)
+		if !ok || !c.Match(v) || (bestVersion != "" && semver.Compare(v, bestVersion) <= 0) {
+			continue
+		}
+		// Annotated tags point at a tag object; the peeled ref names its commit.
+		if peeled, ok := refs[name+"^{}"]; ok {
+			commit = peeled
+		}
+		best, bestVersion = Ref{Name: tag, Commit: commit}, v
+	}
+	if best.Commit == "" {
+		return Ref{}, fmt.Errorf("no tag in %s matches the constraint", remote)
+	}
+	return best, nil
+}
+
+// lsRemote runs git ls-remote with args and returns the commits it lists by ref name.
+func lsRemote(ctx context.Context, args ...string) (map[string]string, error) {
+	out, err := git(ctx, "", append([]string{"ls-remote"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		commit, name, ok := strings.Cut(sc.Text(), "\t")
+		if ok {
+			refs[name] = commit
+		}
+	}
+	return refs, nil
+}
+
+// Clone makes a shallow clone of ref into dir, which must be empty or not exist, and returns the
+// commit checked out. It is ref.Commit unless the branch moved since it was resolved.
+func Clone(ctx context.Context, remote string, ref Ref, dir string) (string, error) {
+	args := []string{"clone", "--depth=1", "--quiet"}
+	if ref.Name != "" {
+		args = append(args, "--branch", ref.Name)
+	}
+	if _, err := git(ctx, "", append(args, remote, dir)...); err != nil {
+		return "", err
+	}
+	return Head(ctx, dir)
+}
+
+// Head returns the commit checked out in dir.
+func Head(ctx context.Context, dir string) (string, error) {
+	out, err := git(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Build runs the command in dir with env added to the host's environment.
+func Build(ctx context.Context, dir string, command []string, env map[string]string) error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w%s", strings.Join(command, " "), err, tail(out))
+	}
+	return nil
+}
+
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Fail instead of waiting for credentials nobody will type.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w%s", args[0], err, tail(stderr.Bytes()))
+	}
+	return out, nil
+}
+
+// tail returns the last lines of a command's output to append to its error.
+func tail(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return ""
+	}
+	return "\n" + strings.Join(lines[max(0, len(lines)-outputLines):], "\n")
+}
@@ -0,0 +1,117 @@
+package gitsource_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/secmc/plugin/plugin/gitsource"
+)
+
+func TestConstraint(t *testing.T) {
+	for _, tc := range []struct {
+		constraint string
+		match      []string
+		reject     []string
+	}{
+		{"", []string{"0.1.0", "v3.0.0"}, nil},
+		{">=1.2 <2", []string{"1.2.0", "v1.9.9"}, []string{"1.1.9", "2.0.0"}},
+		{"^1.4", []string{"1.4.0", "1.99.0"}, []string{"1.3.9", "2.0.0"}},
+		{"^0.3", []string{"0.3.0", "0.3.7"}, []string{"0.4.0"}},
+		{"~1.4.2", []string{"1.4.2", "1.4.9"}, []string{"1.4.1", "1.5.0"}},
+		{"1.2.3", []string{"v1.2.3"}, []string{"1.2.4"}},
+		{">1, !=1.5.0", []string{"1.0.1", "1.6.0"}, []string{"1.0.0", "1.5.0"}},
+	} {
+		c, err := gitsource.ParseConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.constraint, err)
+		}
+		for _, v := range tc.match {
+			if canonical, _ := gitsource.Version(v); !c.Match(canonical) {
+				t.Errorf("Expected %q to match %s", tc.constraint, v)
+			}
+		}
+		for _, v := range tc.reject {
+			if canonical, _ := gitsource.Version(v); c.Match(canonical) {
+				t.Errorf("Expected %q not to match %s", tc.constraint, v)
+			}
+		}
+	}
+	for _, bad := range []string{"=>1.0.0", ">=one"} {
+		if _, err := gitsource.ParseConstraint(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+	if _, ok := gitsource.Version("1.2.0-rc.1"); ok {
+		t.Error("Expected pre-releases not to be versions")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	remote := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = remote
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(version string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(remote, "version"), []byte(version), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "version")
+		git("commit", "-q", "-m", version)
+		return git("rev-parse", "HEAD")
+	}
+	git("init", "-q", "-b", "main")
+	v1 := commit("1.0.0")
+	git("tag", "v1.0.0")
+	v12 := commit("1.2.0")
+	git("tag", "-a", "-m", "release", "v1.2.0")
+	v2 := commit("2.0.0")
+	git("tag", "v2.0.0")
+	head := commit("dev")
+
+	ctx := context.Background()
+	caret1, _ := gitsource.ParseConstraint("^1")
+	all, _ := gitsource.ParseConstraint("")
+	for _, tc := range []struct {
+		name string
+		tr   gitsource.Tracking
+		want gitsource.Ref
+	}{
+		{"default branch", gitsource.Tracking{}, gitsource.Ref{Commit: head}},
+		{"branch", gitsource.Tracking{Branch: "main"}, gitsource.Ref{Name: "main", Commit: head}},
+		{"annotated tag", gitsource.Tracking{Tags: &caret1}, gitsource.Ref{Name: "v1.2.0", Commit: v12}},
+		{"latest tag", gitsource.Tracking{Tags: &all}, gitsource.Ref{Name: "v2.0.0", Commit: v2}},
+	} {
+		ref, err := gitsource.Resolve(ctx, remote, tc.tr)
+		if err != nil || ref != tc.want {
+			t.Errorf("%s: expected %+v, got %+v (%v)", tc.name, tc.want, ref, err)
+		}
+	}
+
+	dir := filepath.Join(t.TempDir(), "checkout")
+	got, err := gitsource.Clone(ctx, remote, gitsource.Ref{Name: "v1.0.0", Commit: v1}, dir)
+	if err != nil || got != v1 {
+		t.Fatalf("Expected to clone %s, got %s (%v)", v1, got, err)
+	}
+	if err := gitsource.Build(ctx, dir, []string{"cp", "version", "built"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "built")); string(data) != "1.0.0" {
+		t.Errorf("Expected the build to run in the checkout, got %q", data)
+	}
+	if err := gitsource.Build(ctx, dir, []string{"sh", "-c", "echo broken >&2; exit 1"}, nil); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected the build's output in its error, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package inspect
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// worldRefName is filled from a single argument, the world's name or dimension.
+const worldRefName = protoreflect.FullName("df.plugin.WorldRef")
+
+// textForm matches arguments given in protobuf text format, e.g. `player_uuid: "..."`.
+var textForm = regexp.MustCompile(`^(\{|[a-z_]+\s*[:{])`)
+
+// actionKinds are the fields of the Action oneof, by protocol name.
+var actionKinds = (&pb.Action{}).ProtoReflect().Descriptor().Oneofs().ByName("kind").Fields()
+
+// Actions returns the names of every action, such as "teleport" or "world_set_time", sorted.
+func Actions() []string {
+	names := make([]string, 0, actionKinds.Len())
+	for i := 0; i < actionKinds.Len(); i++ {
+		names = append(names, string(actionKinds.Get(i).Name()))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Usage describes the positional arguments of the named action.
+func Usage(name string) (string, error) {
+	fd := actionKinds.ByName(protoreflect.Name(name))
+	if fd == nil {
+		return "", fmt.Errorf("unknown action %q", name)
+	}
+	var b strings.Builder
+	b.WriteString(name)
+	for _, arg := range params(fd.Message(), "") {
+		fmt.Fprintf(&b, " <%s>", arg)
+	}
+	return b.String(), nil
+}
+
+// ParseAction parses an action line: the action's name followed by its fields either as positional
+// arguments in declaration order or in protobuf text format.
+//
+//	teleport 5d6a5ba0-3f2f-4fa4-9b3e-4a6bc7c7f0c1 0 80 0
+//	world_set_time overworld 6000
+//	send_chat target_uuid: "" message: "hello everyone"
+//
+// Nested messages take one argument per field, except worlds, which are given by name or
+// dimension. Strings containing spaces are quoted. Trailing fields may be left out; repeated and
+// map fields can only be set in text format.
+func ParseAction(line string) (*pb.Action, error) {
+	name, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+	fd := actionKinds.ByName(protoreflect.Name(name))
+	if fd == nil {
+		return nil, fmt.Errorf("unknown action %q", name)
+	}
+	action := &pb.Action{}
+	msg := action.ProtoReflect().Mutable(fd).Message()
+	rest = strings.TrimSpace(rest)
+	if textForm.MatchString(rest) {
+		if err := prototext.Unmarshal([]byte(rest), msg.Interface()); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		return action, nil
+	}
+	args, err := split(rest)
+	if err != nil {
+		return nil, err
+	}
+	args, err = fill(msg, args)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	if len(args) > 0 {
+		usage, _ := Usage(name)
+		return nil, fmt.Errorf("too many arguments, usage: %s", usage)
+	}
+	return action, nil
+}
+
+// fill sets the fields of msg from args in declaration order and returns the arguments left over.
+func fill(msg protoreflect.Message, args []string) ([]string, error) {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len() && len(args) > 0; i++ {
+		fd := fields.Get(i)
+		if !positional(fd) {
+			continue
+		}
+		if fd.Kind() == protoreflect.MessageKind {
+			if fd.Message().FullName() == worldRefName {
+				msg.Set(fd, protoreflect.ValueOfMessage((&pb.WorldRef{Name: args[0], Dimension: args[0]}).ProtoReflect()))
+				args = args[1:]
+				continue
+			}
+			var err error
+			if args, err = fill(msg.Mutable(fd).Message(), args); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		v, err := scalar(fd, args[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fd.Name(), err)
+		}
+		msg.Set(fd, v)
+		args = args[1:]
+	}
+	return args, nil
+}
+
+// params names the positional arguments of a message, as fill consumes them.
+func params(md protoreflect.MessageDescriptor, prefix string) []string {
+	var names []string
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := prefix + string(fd.Name())
+		switch {
+		case !positional(fd):
+		case fd.Kind() == protoreflect.MessageKind && fd.Message().FullName() != worldRefName:
+			names = append(names, params(fd.Message(), name+".")...)
+		default:
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// positional reports whether fd can be given as a positional argument: lists, maps and members of
+// a oneof cannot.
+func positional(fd protoreflect.FieldDescriptor) bool {
+	if o := fd.ContainingOneof(); o != nil && !o.IsSynthetic() {
+		return false
+	}
+	return !fd.IsList() && !fd.IsMap()
+}
+
+func scalar(fd protoreflect.FieldDescriptor, arg string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(arg), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(arg)), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(arg)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.EnumKind:
+		if n, err := strconv.Atoi(arg); err == nil {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			if v := values.Get(i); strings.EqualFold(string(v.Name()), arg) {
+				return protoreflect.ValueOfEnum(v.Number()), nil
+			}
+		}
+		return protoreflect.Value{}, fmt.Errorf("unknown %s %q", fd.Enum().Name(), arg)
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(arg, 32)
+		return protoreflect.ValueOfFloat32(float32(f)), err
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(arg, 64)
+		return protoreflect.ValueOfFloat64(f), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(arg, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(arg, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(arg, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(arg, 10, 64)
+		return protoreflect.ValueOfUint64(n), err
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+}
+
+// split splits line at spaces, keeping double-quoted strings together.
+func split(line string) ([]string, error) {
+	var args []string
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return args, nil
+		}
+		if line[0] != '"' {
+			arg, rest, _ := strings.Cut(line, " ")
+			args, line = append(args, arg), rest
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return nil, errors.New("unterminated quoted string")
+		}
+		arg, _ := strconv.Unquote(quoted)
+		args, line = append(args, arg), line[len(quoted):]
+	}
+}
@@ -0,0 +1,138 @@
+// Package inspect filters and prints the events seen by an observer plugin and parses the ad-hoc
+// actions typed into cmd/inspect.
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// ParseEventTypes parses a comma-separated list of event types such as "chat,player_join". Names
+// are matched case-insensitively. An empty list or "all" selects every event.
+func ParseEventTypes(list string) ([]pb.EventType, error) {
+	var types []pb.EventType
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name == "ALL" {
+			return []pb.EventType{pb.EventType_EVENT_TYPE_ALL}, nil
+		}
+		t, ok := pb.EventType_value[name]
+		if !ok || t <= int32(pb.EventType_EVENT_TYPE_ALL) {
+			return nil, fmt.Errorf("unknown event type %q", name)
+		}
+		types = append(types, pb.EventType(t))
+	}
+	if len(types) == 0 {
+		return []pb.EventType{pb.EventType_EVENT_TYPE_ALL}, nil
+	}
+	return types, nil
+}
+
+// Filter selects events by the player and world they concern. Empty fields match every event.
+type Filter struct {
+	// Player is a player name or UUID, matched against the payload's name and player_uuid.
+	Player string
+	// World is a world name or dimension, matched against the payload's world.
+	World string
+}
+
+// Match reports whether env passes the filter. Events without a player or world do not match a
+// filter on them.
+func (f Filter) Match(env *pb.EventEnvelope) bool {
+	if f.Player == "" && f.World == "" {
+		return true
+	}
+	payload := payload(env)
+	if payload == nil {
+		return false
+	}
+	playerOK, worldOK := f.Player == "", f.World == ""
+	payload.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsList() || fd.IsMap() {
+			return true
+		}
+		switch {
+		case fd.Kind() == protoreflect.StringKind && (fd.Name() == "player_uuid" || fd.Name() == "name"):
+			playerOK = playerOK || strings.EqualFold(v.String(), f.Player)
+		case fd.Kind() == protoreflect.StringKind && fd.Name() == "world":
+			worldOK = worldOK || strings.EqualFold(v.String(), f.World)
+		case fd.Kind() == protoreflect.MessageKind && fd.Message().FullName() == worldRefName:
+			ref := v.Message().Interface().(*pb.WorldRef)
+			worldOK = worldOK || strings.EqualFold(ref.GetName(), f.World) || strings.EqualFold(ref.GetDimension(), f.World)
+		}
+		return true
+	})
+	return playerOK && worldOK
+}
+
+// Printer writes events and action results as text or, with JSON set, one JSON object per line.
+type Printer struct {
+	W    io.Writer
+	JSON bool
+}
+
+// Event writes env.
+func (p Printer) Event(env *pb.EventEnvelope) error {
+	if p.JSON {
+		return p.json(env)
+	}
+	text := ""
+	if payload := payload(env); payload != nil {
+		text = compact(payload.Interface())
+	}
+	_, err := fmt.Fprintf(p.W, "%s %-24s %s\n", time.Now().Format("15:04:05.000"), env.GetType(), text)
+	return err
+}
+
+// ActionResult writes res.
+func (p Printer) ActionResult(res *pb.ActionResult) error {
+	if p.JSON {
+		return p.json(res)
+	}
+	_, err := fmt.Fprintf(p.W, "result: %s\n", compact(res))
+	return err
+}
+
+func (p Printer) json(msg proto.Message) error {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	// protojson may add spaces at random; compact it so the output is stable.
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = p.W.Write(buf.Bytes())
+	return err
+}
+
+// compact formats msg as single-line text.
+func compact(msg proto.Message) string {
+	return prototext.MarshalOptions{}.Format(msg)
+}
+
+// payload returns the message set in the envelope's payload, or nil.
+func payload(env *pb.EventEnvelope) protoreflect.Message {
+	msg := env.ProtoReflect()
+	field := msg.WhichOneof(msg.Descriptor().Oneofs().ByName("payload"))
+	if field == nil {
+		return nil
+	}
+	return msg.Get(field).Message()
+}
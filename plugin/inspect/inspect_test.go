@@ -0,0 +1,84 @@
+package inspect_test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/plugin/inspect"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+func TestParseAction(t *testing.T) {
+	const id = "5d6a5ba0-3f2f-4fa4-9b3e-4a6bc7c7f0c1"
+	tests := []struct {
+		line string
+		want *pb.Action
+		err  string
+	}{
+		{
+			line: "teleport " + id + " 0 80 0",
+			want: &pb.Action{Kind: &pb.Action_Teleport{Teleport: &pb.TeleportAction{PlayerUuid: id, Position: &pb.Vec3{Y: 80}}}},
+		},
+		{
+			line: `send_chat "" "hello everyone"`,
+			want: &pb.Action{Kind: &pb.Action_SendChat{SendChat: &pb.SendChatAction{Message: "hello everyone"}}},
+		},
+		{
+			line: "world_set_time overworld 6000",
+			want: &pb.Action{Kind: &pb.Action_WorldSetTime{WorldSetTime: &pb.WorldSetTimeAction{
+				World: &pb.WorldRef{Name: "overworld", Dimension: "overworld"}, Time: 6000,
+			}}},
+		},
+		{
+			line: `teleport player_uuid: "` + id + `" position {y: 80}`,
+			want: &pb.Action{Kind: &pb.Action_Teleport{Teleport: &pb.TeleportAction{PlayerUuid: id, Position: &pb.Vec3{Y: 80}}}},
+		},
+		{line: "fly " + id, err: "unknown action"},
+		{line: "world_set_time overworld noon", err: "time"},
+		{line: "send_tip " + id + " hi there", err: "too many arguments"},
+	}
+	for _, tt := range tests {
+		got, err := inspect.ParseAction(tt.line)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%q: expected error containing %q, got %v", tt.line, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tt.line, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.line, tt.want, got)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	chat := &pb.EventEnvelope{Type: pb.EventType_CHAT, Payload: &pb.EventEnvelope_Chat{Chat: &pb.ChatEvent{PlayerUuid: "u1", Name: "Steve"}}}
+	join := &pb.EventEnvelope{Type: pb.EventType_PLAYER_JOIN, Payload: &pb.EventEnvelope_PlayerJoin{PlayerJoin: &pb.PlayerJoinEvent{
+		PlayerUuid: "u2", Name: "Alex", World: &pb.WorldRef{Name: "World", Dimension: "overworld"},
+	}}}
+	tests := []struct {
+		filter inspect.Filter
+		chat   bool
+		join   bool
+	}{
+		{filter: inspect.Filter{}, chat: true, join: true},
+		{filter: inspect.Filter{Player: "steve"}, chat: true},
+		{filter: inspect.Filter{Player: "u2"}, join: true},
+		{filter: inspect.Filter{World: "overworld"}, join: true},
+		{filter: inspect.Filter{Player: "Steve", World: "overworld"}},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(chat); got != tt.chat {
+			t.Errorf("%+v matching chat: expected %v", tt.filter, tt.chat)
+		}
+		if got := tt.filter.Match(join); got != tt.join {
+			t.Errorf("%+v matching join: expected %v", tt.filter, tt.join)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 	"net"
 	"time"
 
@@ -15,6 +17,10 @@ import (
 	pb "github.com/secmc/plugin/proto/generated/go"
 )
 
+// EventResponseTimeout is how long the host waits for a plugin's result for an event that expects
+// one. Results arriving later are discarded.
+const EventResponseTimeout = 250 * time.Millisecond
+
 type PluginManager interface {
 	Start(configPath string) error
 	Close()
@@ -34,6 +40,91 @@ type Stream interface {
 	Close() error
 }
 
+// MessageStream is implemented by streams that carry decoded messages, such as connections to
+// plugins running in the host process. The host uses SendMessage and RecvMessage instead of Send
+// and Recv so that messages are not marshalled.
+type MessageStream interface {
+	Stream
+	SendMessage(msg *pb.HostToPlugin) error
+	RecvMessage() (*pb.PluginToHost, error)
+}
+
+// PeerCredentials identifies the local process on the other end of a Unix socket.
+type PeerCredentials struct {
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+// PeerStream is implemented by streams that know which local process they are connected to.
+type PeerStream interface {
+	Stream
+	// PeerCredentials returns false when the credentials are unknown, e.g. for TCP connections.
+	PeerCredentials() (PeerCredentials, bool)
+}
+
+// ErrUnknownPlugin is returned by PluginController methods for IDs that are not configured.
+var ErrUnknownPlugin = errors.New("unknown plugin")
+
+// ErrNotFound is wrapped by query errors for worlds and players that do not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrUnavailable is wrapped by query errors for state the host does not have yet, such as a
+// server that has not started.
+var ErrUnavailable = errors.New("unavailable")
+
+// PluginStatus is a snapshot of one plugin as reported by the admin API.
+type PluginStatus struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Version is taken from the plugin's hello and is empty until it has connected.
+	Version    string `json:"version,omitempty"`
+	APIVersion string `json:"api_version,omitempty"`
+	// Commit is the git commit running, for plugins cloned from git.
+	Commit string `json:"commit,omitempty"`
+	// Capabilities are the optional protocol features negotiated with the plugin.
+	Capabilities []string `json:"capabilities"`
+	// State is "stopped", "waiting" (not launched by the host and not connected), "launched",
+	// "connected" or "ready" (connected and subscribed).
+	State         string   `json:"state"`
+	PID           int32    `json:"pid,omitempty"`
+	Healthy       bool     `json:"healthy"`
+	Subscriptions []string `json:"subscriptions"`
+	Commands      []string `json:"commands"`
+	CustomItems   []string `json:"custom_items"`
+	CustomBlocks  []string `json:"custom_blocks"`
+	SendQueue     int      `json:"send_queue"`
+	ActionQueue   int      `json:"action_queue"`
+	RTTMillis     float64  `json:"rtt_ms"`
+	// Event totals since the plugin was last started.
+	EventsDispatched  uint64     `json:"events_dispatched"`
+	EventsDropped     uint64     `json:"events_dropped"`
+	EventTimeouts     uint64     `json:"event_timeouts"`
+	AvgResponseMillis float64    `json:"avg_response_ms"`
+	LogLevel          string     `json:"log_level"`
+	LastError         string     `json:"last_error,omitempty"`
+	LastErrorAt       *time.Time `json:"last_error_at,omitempty"`
+}
+
+// PluginController lets operators inspect and manage running plugins.
+type PluginController interface {
+	PluginStatuses() []PluginStatus
+	PluginStatus(id string) (PluginStatus, error)
+	StartPlugin(id string) error
+	StopPlugin(id string) error
+	RestartPlugin(id string) error
+	// ReloadPlugin re-reads the plugin's definition from the config file and restarts it.
+	ReloadPlugin(id string) error
+	// ReloadConfig re-reads the config file, starting added plugins, stopping removed ones and
+	// restarting those whose definition changed.
+	ReloadConfig() error
+	// SetPluginLogLevel overrides the plugin's log level. A nil level restores the host's level.
+	SetPluginLogLevel(id string, level *slog.Level) error
+	// TailEvents returns a channel receiving every event sent to the plugin until cancel is called
+	// or the plugin is stopped.
+	TailEvents(id string) (events <-chan *pb.EventEnvelope, cancel func(), err error)
+}
+
 type EventManager interface {
 	EmitPlayerJoin(p *player.Player)
 	EmitPlayerQuit(p *player.Player)
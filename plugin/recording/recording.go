@@ -0,0 +1,248 @@
+// Package recording stores the traffic between the host and a plugin so that it can be replayed.
+//
+// A recording holds one plugin connection: every HostToPlugin and PluginToHost message in the
+// order they crossed the stream, with the time since the connection was accepted. The file starts
+// with a magic string, followed by a snappy stream holding the plugin ID, the start time and the
+// frames, each as a direction byte, the offset in microseconds, the message length and the
+// marshalled message.
+package recording
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// Ext is the file extension of recordings.
+const Ext = ".dfrec"
+
+// magic starts every recording; its last byte is the format version.
+const magic = "DFREC\x01"
+
+// flushInterval bounds how much of a recording is lost if the host stops without closing it.
+const flushInterval = time.Second
+
+// maxFrameSize guards against reading a corrupt length.
+const maxFrameSize = 64 << 20
+
+// ErrSizeLimit is returned by Writer.Write once the recording has reached its size limit.
+var ErrSizeLimit = errors.New("recording size limit reached")
+
+// Direction is the way a frame travelled.
+type Direction byte
+
+const (
+	HostToPlugin Direction = iota
+	PluginToHost
+)
+
+func (d Direction) String() string {
+	if d == PluginToHost {
+		return "plugin->host"
+	}
+	return "host->plugin"
+}
+
+// Frame is one message of a recording.
+type Frame struct {
+	Direction Direction
+	// Offset is the time since the connection was accepted.
+	Offset time.Duration
+	// Data is the marshalled HostToPlugin or PluginToHost message.
+	Data []byte
+}
+
+// Decode unmarshals the frame into a *pb.HostToPlugin or *pb.PluginToHost, depending on its
+// direction.
+func (f Frame) Decode() (proto.Message, error) {
+	var msg proto.Message = &pb.HostToPlugin{}
+	if f.Direction == PluginToHost {
+		msg = &pb.PluginToHost{}
+	}
+	if err := proto.Unmarshal(f.Data, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Recording is a recorded plugin connection.
+type Recording struct {
+	PluginID string
+	Start    time.Time
+	Frames   []Frame
+}
+
+// Writer appends frames to a recording file. It is safe for concurrent use.
+type Writer struct {
+	mu        sync.Mutex
+	f         *os.File
+	w         *snappy.Writer
+	start     time.Time
+	lastFlush time.Time
+	// size is the number of frame bytes written so far; limit bounds it if positive.
+	size, limit int64
+	err         error
+	closed      bool
+}
+
+// Create creates a recording file for a connection of pluginID accepted at start. The file is only
+// readable by its owner, as it holds everything the plugin and host sent each other. If maxSize is
+// positive, the recording stops before its frames would take up more than maxSize bytes
+// uncompressed.
+func Create(path, pluginID string, start time.Time, maxSize int64) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(f, magic); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	w := &Writer{f: f, w: snappy.NewBufferedWriter(f), start: start, lastFlush: start, limit: maxSize}
+	var buf []byte
+	buf = binary.AppendUvarint(buf, uint64(len(pluginID)))
+	buf = append(buf, pluginID...)
+	buf = binary.AppendVarint(buf, start.UnixNano())
+	if _, err := w.w.Write(buf); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	// The header gets a chunk of its own so that a recording cut short can still be identified.
+	if err := w.w.Flush(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write appends a frame that crossed the stream at t. Data is copied. Once writing fails, every
+// later call returns the same error; a frame that would exceed the size limit fails with
+// ErrSizeLimit and ends the recording.
+func (w *Writer) Write(dir Direction, t time.Time, data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	if w.closed {
+		return os.ErrClosed
+	}
+	var head [1 + 2*binary.MaxVarintLen64]byte
+	head[0] = byte(dir)
+	n := 1 + binary.PutUvarint(head[1:], uint64(max(t.Sub(w.start), 0).Microseconds()))
+	n += binary.PutUvarint(head[n:], uint64(len(data)))
+	if w.limit > 0 && w.size+int64(n+len(data)) > w.limit {
+		w.err = ErrSizeLimit
+		if err := w.w.Flush(); err != nil {
+			w.err = err
+		}
+		return w.err
+	}
+	w.size += int64(n + len(data))
+	if _, err := w.w.Write(head[:n]); err != nil {
+		w.err = err
+		return err
+	}
+	if _, err := w.w.Write(data); err != nil {
+		w.err = err
+		return err
+	}
+	if t.Sub(w.lastFlush) >= flushInterval {
+		w.lastFlush = t
+		if err := w.w.Flush(); err != nil {
+			w.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes the recording and closes the file. It may be called more than once.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	err := w.w.Close()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Open reads a recording file. A recording cut short, for example because the host crashed, is
+// returned with the frames that were complete.
+func Open(path string) (*Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read reads a recording from r.
+func Read(r io.Reader) (*Recording, error) {
+	head := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, head); err != nil || string(head) != magic {
+		return nil, errors.New("not a recording")
+	}
+	br := bufio.NewReader(snappy.NewReader(r))
+
+	idLen, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if idLen > 1024 {
+		return nil, errors.New("corrupt header")
+	}
+	id := make([]byte, idLen)
+	if _, err := io.ReadFull(br, id); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	start, err := binary.ReadVarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	rec := &Recording{PluginID: string(id), Start: time.Unix(0, start)}
+
+	for {
+		dir, err := br.ReadByte()
+		if err != nil {
+			// A clean end of file, or a file truncated by a crash.
+			return rec, nil
+		}
+		offset, err := binary.ReadUvarint(br)
+		if err != nil {
+			return rec, nil
+		}
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return rec, nil
+		}
+		if dir > byte(PluginToHost) || size > maxFrameSize {
+			return nil, fmt.Errorf("corrupt frame %d", len(rec.Frames))
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return rec, nil
+		}
+		rec.Frames = append(rec.Frames, Frame{
+			Direction: Direction(dir),
+			Offset:    time.Duration(offset) * time.Microsecond,
+			Data:      data,
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package recording_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/secmc/plugin/packages/go/plugin"
+	"github.com/secmc/plugin/plugin/recording"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// write records msgs to a new file, one millisecond apart, and returns its path.
+func write(t *testing.T, pluginID string, msgs ...proto.Message) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), pluginID+recording.Ext)
+	start := time.Now()
+	w, err := recording.Create(path, pluginID, start, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, msg := range msgs {
+		dir := recording.HostToPlugin
+		if _, ok := msg.(*pb.PluginToHost); ok {
+			dir = recording.PluginToHost
+		}
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write(dir, start.Add(time.Duration(i)*time.Millisecond), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadWrite(t *testing.T) {
+	msgs := []proto.Message{
+		&pb.PluginToHost{PluginId: "p", Payload: &pb.PluginToHost_Hello{Hello: &pb.PluginHello{Name: "P"}}},
+		&pb.HostToPlugin{PluginId: "p", Payload: &pb.HostToPlugin_Hello{Hello: &pb.HostHello{ApiVersion: "v1"}}},
+		&pb.PluginToHost{PluginId: "p", Payload: &pb.PluginToHost_Subscribe{Subscribe: &pb.EventSubscribe{Events: []pb.EventType{pb.EventType_CHAT}}}},
+	}
+	path := write(t, "p", msgs...)
+	rec, err := recording.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.PluginID != "p" || len(rec.Frames) != len(msgs) {
+		t.Fatalf("Expected %d frames of plugin p, got %d of %q", len(msgs), len(rec.Frames), rec.PluginID)
+	}
+	for i, f := range rec.Frames {
+		msg, err := f.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(msg, msgs[i]) || f.Offset != time.Duration(i)*time.Millisecond {
+			t.Errorf("Frame %d: expected %v at %s, got %v at %s", i, msgs[i], time.Duration(i)*time.Millisecond, msg, f.Offset)
+		}
+	}
+
+	// A recording cut short still opens, keeping the frames flushed before the cut.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := filepath.Join(t.TempDir(), "truncated"+recording.Ext)
+	if err := os.WriteFile(truncated, data[:len(data)-4], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rec, err := recording.Open(truncated); err != nil || rec.PluginID != "p" {
+		t.Errorf("Expected a truncated recording of plugin p to open, got %+v, %v", rec, err)
+	}
+}
+
+func TestSizeLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limited"+recording.Ext)
+	start := time.Now()
+	w, err := recording.Create(path, "p", start, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame := make([]byte, 30)
+	written := 0
+	for ; written < 10; written++ {
+		if err := w.Write(recording.HostToPlugin, start, frame); err != nil {
+			if !errors.Is(err, recording.ErrSizeLimit) {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+	if written != 3 {
+		t.Errorf("Expected 3 frames of 30 bytes to fit in 100 bytes, wrote %d", written)
+	}
+	if err := w.Write(recording.HostToPlugin, start, nil); !errors.Is(err, recording.ErrSizeLimit) {
+		t.Errorf("Expected writes after the limit to fail with ErrSizeLimit, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("Expected the recording to have mode 0600, got %o", perm)
+	}
+	rec, err := recording.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Frames) != written {
+		t.Errorf("Expected the recording to hold the %d frames within the limit, got %d", written, len(rec.Frames))
+	}
+}
+
+func TestReplay(t *testing.T) {
+	chat := func(id, message string, result string) []proto.Message {
+		return []proto.Message{
+			&pb.HostToPlugin{PluginId: "chat", Payload: &pb.HostToPlugin_Event{Event: &pb.EventEnvelope{
+				EventId: id, Type: pb.EventType_CHAT, ExpectsResponse: true,
+				Payload: &pb.EventEnvelope_Chat{Chat: &pb.ChatEvent{Name: "Steve", Message: message}},
+			}}},
+			&pb.PluginToHost{PluginId: "chat", Payload: &pb.PluginToHost_EventResult{EventResult: &pb.EventResult{
+				EventId: id, Update: &pb.EventResult_Chat{Chat: &pb.ChatMutation{Message: proto.String(result)}},
+			}}},
+		}
+	}
+	msgs := []proto.Message{
+		&pb.PluginToHost{PluginId: "chat", Payload: &pb.PluginToHost_Hello{Hello: &pb.PluginHello{}}},
+		&pb.HostToPlugin{PluginId: "chat", Payload: &pb.HostToPlugin_Hello{Hello: &pb.HostHello{ApiVersion: "v1", BootId: "boot"}}},
+		&pb.PluginToHost{PluginId: "chat", Payload: &pb.PluginToHost_Subscribe{Subscribe: &pb.EventSubscribe{Events: []pb.EventType{pb.EventType_CHAT}}}},
+	}
+	msgs = append(msgs, chat("1", "hello", "HELLO")...)
+	msgs = append(msgs, chat("2", "bye", "BYE")...)
+	rec, err := recording.Open(write(t, "chat", msgs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replay := func(handle func(string) string) *recording.Result {
+		t.Helper()
+		// The SDK's hello differs from the hand-written one.
+		r := &recording.Replayer{Ignore: append([]string{"hello"}, recording.DefaultIgnore...)}
+		res, err := r.Run(context.Background(), rec, recording.Target{
+			Launch: func(ctx context.Context, address, pluginID string) error {
+				p, err := plugin.New(plugin.WithID(pluginID), plugin.WithAddress(address), plugin.WithLogger(slog.New(slog.DiscardHandler)))
+				if err != nil {
+					return err
+				}
+				plugin.On(p, func(e *plugin.Event, chat *pb.ChatEvent) {
+					e.Mutate(plugin.ChatMessage(handle(chat.Message)))
+				})
+				return p.Run(ctx)
+			},
+		})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		return res
+	}
+
+	if res := replay(strings.ToUpper); !res.Matched() || res.Sent != 3 || len(res.Warnings) > 0 {
+		t.Errorf("Expected the replay to match, got %+v", res)
+	}
+	res := replay(func(msg string) string {
+		if msg == "bye" {
+			return "goodbye"
+		}
+		return strings.ToUpper(msg)
+	})
+	if len(res.Differences) != 1 || res.Differences[0].Key != "event_id=2" || !strings.Contains(res.Differences[0].Actual, "goodbye") {
+		t.Errorf("Expected one difference for event 2, got %+v", res.Differences)
+	}
+}
@@ -0,0 +1,488 @@
+package recording
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/secmc/plugin/plugin/adapters/grpc"
+	"github.com/secmc/plugin/plugin/adapters/unixsocket"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// DefaultIgnore are the fields left out when comparing a plugin's messages with a recording:
+// correlation IDs are chosen by the plugin and metrics depend on when they are sampled.
+var DefaultIgnore = []string{"correlation_id", "metrics"}
+
+// Target is the plugin a recording is replayed to.
+type Target struct {
+	// Command and Args launch the plugin, in Dir if set.
+	Command string
+	Args    []string
+	Dir     string
+	// Launch runs the plugin in-process instead of Command. It is cancelled once the replay is
+	// over and must return when the plugin exits.
+	Launch func(ctx context.Context, address, pluginID string) error
+}
+
+// Replayer feeds recordings to plugins, acting as the host.
+type Replayer struct {
+	// Address is where the replayer listens for the plugin. It defaults to a free local TCP port.
+	Address string
+	// Realtime keeps the recorded time between the host's messages. Otherwise each is sent as soon
+	// as the plugin has sent the messages recorded before it.
+	Realtime bool
+	// Timeout bounds how long the replayer waits for the plugin to catch up with the recording
+	// before each host message. It defaults to 5s.
+	Timeout time.Duration
+	// ConnectTimeout bounds how long the plugin may take to connect. It defaults to 60s.
+	ConnectTimeout time.Duration
+	// Ignore names fields, by protocol name, left out when comparing messages. Messages whose
+	// payload is an ignored field are not compared at all. It defaults to DefaultIgnore.
+	Ignore []string
+	// Output receives the plugin's stdout and stderr. It defaults to discarding them.
+	Output io.Writer
+	// Log receives progress messages. It defaults to discarding them.
+	Log *slog.Logger
+}
+
+// Difference is a message the plugin sent differently than recorded, or only in one of the runs.
+type Difference struct {
+	// Kind is the payload of the message, such as "event_result" or "actions".
+	Kind string `json:"kind"`
+	// Key identifies the message within its kind: its event ID for event results, otherwise its
+	// position.
+	Key string `json:"key"`
+	// Recorded and Actual are the message in text format; one is empty if the message is missing.
+	Recorded string `json:"recorded,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// Result is the outcome of a replay.
+type Result struct {
+	PluginID string `json:"plugin_id"`
+	// Sent is the number of host messages replayed.
+	Sent int `json:"sent"`
+	// Recorded and Received count the plugin messages that were compared.
+	Recorded    int          `json:"recorded"`
+	Received    int          `json:"received"`
+	Differences []Difference `json:"differences"`
+	// Warnings describe where the plugin fell behind the recording.
+	Warnings   []string `json:"warnings,omitempty"`
+	DurationMs int64    `json:"duration_ms"`
+}
+
+// Matched reports whether the plugin sent what was recorded.
+func (r *Result) Matched() bool {
+	return len(r.Differences) == 0
+}
+
+// WriteText writes the result in a human readable form.
+func (r *Result) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "%s: replayed %d host messages in %dms, %d plugin messages recorded, %d received\n",
+		r.PluginID, r.Sent, r.DurationMs, r.Recorded, r.Received)
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(w, "  warning: %s\n", warning)
+	}
+	for _, d := range r.Differences {
+		fmt.Fprintf(w, "  %s %s\n", d.Kind, d.Key)
+		if d.Recorded != "" {
+			fmt.Fprintf(w, "    - %s\n", d.Recorded)
+		}
+		if d.Actual != "" {
+			fmt.Fprintf(w, "    + %s\n", d.Actual)
+		}
+	}
+	if r.Matched() {
+		fmt.Fprintln(w, "  responses match the recording")
+	} else {
+		fmt.Fprintf(w, "  %d differences\n", len(r.Differences))
+	}
+}
+
+// hostFrame is a host message to replay, with the number of compared plugin messages recorded
+// before it.
+type hostFrame struct {
+	offset time.Duration
+	msg    *pb.HostToPlugin
+	after  int
+}
+
+// Run launches target, replays rec to it and compares what it sends with the recording. An error
+// is returned if the replay could not run.
+func (r *Replayer) Run(ctx context.Context, rec *Recording, target Target) (*Result, error) {
+	ignore := r.Ignore
+	if ignore == nil {
+		ignore = DefaultIgnore
+	}
+	timeout, connectTimeout := r.Timeout, r.ConnectTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	if connectTimeout <= 0 {
+		connectTimeout = 60 * time.Second
+	}
+
+	var hosts []hostFrame
+	var recorded []*pb.PluginToHost
+	bootID := ""
+	for i, f := range rec.Frames {
+		msg, err := f.Decode()
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %w", i, err)
+		}
+		switch msg := msg.(type) {
+		case *pb.HostToPlugin:
+			if hello := msg.GetHello(); hello != nil && bootID == "" {
+				bootID = hello.GetBootId()
+			}
+			hosts = append(hosts, hostFrame{offset: f.Offset, msg: msg, after: len(recorded)})
+		case *pb.PluginToHost:
+			if compared(msg, ignore) {
+				recorded = append(recorded, msg)
+			}
+		}
+	}
+
+	c, stop, err := r.start(ctx, rec.PluginID, bootID, target, ignore, connectTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	res := &Result{PluginID: rec.PluginID}
+	start := time.Now()
+	for i, h := range hosts {
+		if got := c.wait(ctx, h.after, timeout); got < h.after {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("plugin sent %d of the %d messages recorded before host message %d (%s)",
+				got, h.after, i+1, kind(h.msg)))
+		}
+		if r.Realtime {
+			select {
+			case <-time.After(time.Until(start.Add(h.offset))):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		msg := h.msg
+		if result := msg.GetActionResult(); result != nil {
+			msg = proto.Clone(msg).(*pb.HostToPlugin)
+			msg.GetActionResult().CorrelationId = c.correlationID(recorded[:h.after], result.GetCorrelationId())
+		}
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.stream.Send(data); err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("send host message %d (%s): %v", i+1, kind(h.msg), err))
+			break
+		}
+		res.Sent++
+	}
+	if got := c.wait(ctx, len(recorded), timeout); got < len(recorded) {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("plugin sent %d of %d recorded messages", got, len(recorded)))
+	}
+	res.DurationMs = time.Since(start).Milliseconds()
+
+	actual := c.messages()
+	res.Recorded, res.Received = len(recorded), len(actual)
+	res.Differences = diff(recorded, actual, ignore)
+	return res, nil
+}
+
+// start listens for the plugin, launches it and waits for it to connect. stop ends the plugin and
+// the listener.
+func (r *Replayer) start(ctx context.Context, pluginID, bootID string, target Target, ignore []string, connectTimeout time.Duration) (*conn, func(), error) {
+	log := r.Log
+	if log == nil {
+		log = slog.New(slog.DiscardHandler)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	conns := make(chan *conn, 1)
+	done := make(chan struct{})
+	address := r.Address
+	if address == "" {
+		address = "127.0.0.1:0"
+	}
+	server, err := grpc.NewServer(address, unixsocket.Permissions{}, func(stream ports.Stream) error {
+		c := newConn(stream, ignore)
+		select {
+		case conns <- c:
+		default:
+			return errors.New("the plugin is already connected")
+		}
+		select {
+		case <-done:
+		case <-c.closed:
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("listen: %w", err)
+	}
+	go func() {
+		if err := server.Serve(); err != nil {
+			log.Error("replay server error", "error", err)
+		}
+	}()
+
+	exited := make(chan error, 1)
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			cancel()
+			server.Stop()
+			select {
+			case <-exited:
+			case <-time.After(5 * time.Second):
+			}
+		})
+	}
+
+	address = server.Address()
+	if strings.HasPrefix(address, "/") {
+		address = "unix:" + address
+	}
+	if target.Launch != nil {
+		go func() { exited <- target.Launch(ctx, address, pluginID) }()
+	} else {
+		output := r.Output
+		if output == nil {
+			output = io.Discard
+		}
+		cmd := exec.CommandContext(ctx, target.Command, target.Args...)
+		cmd.Dir = target.Dir
+		cmd.Env = append(os.Environ(),
+			"DF_PLUGIN_ID="+pluginID,
+			"DF_PLUGIN_SERVER_ADDRESS="+address,
+			"DF_HOST_BOOT_ID="+bootID,
+		)
+		cmd.Stdout, cmd.Stderr = output, output
+		if err := cmd.Start(); err != nil {
+			cancel()
+			server.Stop()
+			return nil, nil, fmt.Errorf("launch %s: %w", target.Command, err)
+		}
+		go func() { exited <- cmd.Wait() }()
+	}
+	log.Info("waiting for plugin", "plugin", pluginID, "address", address)
+
+	select {
+	case c := <-conns:
+		return c, stop, nil
+	case err := <-exited:
+		exited <- err
+		stop()
+		if err != nil {
+			return nil, nil, fmt.Errorf("plugin exited before connecting: %w", err)
+		}
+		return nil, nil, errors.New("plugin exited before connecting")
+	case <-time.After(connectTimeout):
+		stop()
+		return nil, nil, fmt.Errorf("plugin did not connect within %s", connectTimeout)
+	case <-ctx.Done():
+		stop()
+		return nil, nil, ctx.Err()
+	}
+}
+
+// conn collects the messages the plugin sends.
+type conn struct {
+	stream ports.Stream
+	ignore []string
+	closed chan struct{}
+
+	mu       sync.Mutex
+	received []*pb.PluginToHost
+	notify   chan struct{}
+}
+
+func newConn(stream ports.Stream, ignore []string) *conn {
+	c := &conn{stream: stream, ignore: ignore, closed: make(chan struct{}), notify: make(chan struct{})}
+	go c.recvLoop()
+	return c
+}
+
+func (c *conn) recvLoop() {
+	defer close(c.closed)
+	for {
+		data, err := c.stream.Recv()
+		if err != nil {
+			return
+		}
+		msg := &pb.PluginToHost{}
+		if err := proto.Unmarshal(data, msg); err != nil || !compared(msg, c.ignore) {
+			continue
+		}
+		c.mu.Lock()
+		c.received = append(c.received, msg)
+		close(c.notify)
+		c.notify = make(chan struct{})
+		c.mu.Unlock()
+	}
+}
+
+func (c *conn) messages() []*pb.PluginToHost {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.received)
+}
+
+// wait waits until the plugin has sent n messages, the stream closes or timeout passes, and
+// returns how many it has sent.
+func (c *conn) wait(ctx context.Context, n int, timeout time.Duration) int {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		c.mu.Lock()
+		got, notify := len(c.received), c.notify
+		c.mu.Unlock()
+		if got >= n {
+			return got
+		}
+		select {
+		case <-notify:
+		case <-c.closed:
+			return len(c.messages())
+		case <-deadline.C:
+			return got
+		case <-ctx.Done():
+			return got
+		}
+	}
+}
+
+// correlationID maps a correlation ID from the recording to the one the plugin used for the
+// same action, pairing the actions that carry one in the order they were sent.
+func (c *conn) correlationID(recorded []*pb.PluginToHost, id string) string {
+	i := slices.Index(correlationIDs(recorded), id)
+	actual := correlationIDs(c.messages())
+	if i < 0 || i >= len(actual) {
+		return id
+	}
+	return actual[i]
+}
+
+func correlationIDs(msgs []*pb.PluginToHost) []string {
+	var ids []string
+	for _, msg := range msgs {
+		for _, a := range msg.GetActions().GetActions() {
+			if id := a.GetCorrelationId(); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// kind returns the name of the message's payload field.
+func kind(msg proto.Message) string {
+	m := msg.ProtoReflect()
+	if fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("payload")); fd != nil {
+		return string(fd.Name())
+	}
+	return "unknown"
+}
+
+// compared reports whether msg takes part in the comparison, which it does unless its payload is
+// ignored.
+func compared(msg *pb.PluginToHost, ignore []string) bool {
+	return !slices.Contains(ignore, kind(msg))
+}
+
+// diff compares the plugin's messages with the recorded ones. Event results are paired by event
+// ID, other messages by their position among messages of the same kind.
+func diff(recorded, actual []*pb.PluginToHost, ignore []string) []Difference {
+	type keyed struct {
+		kind, key string
+		msg       *pb.PluginToHost
+	}
+	index := func(msgs []*pb.PluginToHost) []keyed {
+		counts := map[string]int{}
+		out := make([]keyed, 0, len(msgs))
+		for _, msg := range msgs {
+			k := kind(msg)
+			key := fmt.Sprintf("#%d", counts[k]+1)
+			if id := msg.GetEventResult().GetEventId(); id != "" {
+				key = "event_id=" + id
+			}
+			counts[k]++
+			out = append(out, keyed{kind: k, key: key, msg: normalize(msg, ignore)})
+		}
+		return out
+	}
+	want, got := index(recorded), index(actual)
+	gotByKey := make(map[[2]string]*pb.PluginToHost, len(got))
+	for _, g := range got {
+		gotByKey[[2]string{g.kind, g.key}] = g.msg
+	}
+
+	var diffs []Difference
+	seen := make(map[[2]string]bool, len(want))
+	for _, w := range want {
+		k := [2]string{w.kind, w.key}
+		seen[k] = true
+		g, ok := gotByKey[k]
+		switch {
+		case !ok:
+			diffs = append(diffs, Difference{Kind: w.kind, Key: w.key, Recorded: text(w.msg)})
+		case !proto.Equal(w.msg, g):
+			diffs = append(diffs, Difference{Kind: w.kind, Key: w.key, Recorded: text(w.msg), Actual: text(g)})
+		}
+	}
+	for _, g := range got {
+		if !seen[[2]string{g.kind, g.key}] {
+			diffs = append(diffs, Difference{Kind: g.kind, Key: g.key, Actual: text(g.msg)})
+		}
+	}
+	return diffs
+}
+
+// normalize returns a copy of msg without the ignored fields, at any depth.
+func normalize(msg *pb.PluginToHost, ignore []string) *pb.PluginToHost {
+	out := proto.Clone(msg).(*pb.PluginToHost)
+	var strip func(m protoreflect.Message)
+	strip = func(m protoreflect.Message) {
+		m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			switch {
+			case slices.Contains(ignore, string(fd.Name())):
+				m.Clear(fd)
+			case fd.IsList() && fd.Message() != nil:
+				for i := range v.List().Len() {
+					strip(v.List().Get(i).Message())
+				}
+			case fd.IsMap() && fd.MapValue().Message() != nil:
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					strip(mv.Message())
+					return true
+				})
+			case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+				strip(v.Message())
+			}
+			return true
+		})
+	}
+	strip(out.ProtoReflect())
+	return out
+}
+
+func text(msg proto.Message) string {
+	return prototext.MarshalOptions{}.Format(msg)
+}
@@ -0,0 +1,247 @@
+package simulate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+// positionTolerance is how far a player may be from an expected position.
+const positionTolerance = 0.01
+
+// Action is an action a plugin sent during a step.
+type Action struct {
+	Plugin string `json:"plugin"`
+	// Type is the action's kind as named in the protocol, such as "send_chat".
+	Type string `json:"type"`
+	// Body is the kind's message as JSON.
+	Body json.RawMessage `json:"body,omitempty"`
+
+	kind protoreflect.Message
+}
+
+func newAction(pluginID string, action *pb.Action) Action {
+	a := Action{Plugin: pluginID, Type: "unknown"}
+	msg := action.ProtoReflect()
+	field := msg.WhichOneof(msg.Descriptor().Oneofs().ByName("kind"))
+	if field == nil || field.Message() == nil {
+		return a
+	}
+	// The action may be reused once applied, so keep a copy.
+	a.kind = proto.Clone(msg.Get(field).Message().Interface()).ProtoReflect()
+	a.Type = string(field.Name())
+	if body, err := protojson.Marshal(a.kind.Interface()); err == nil {
+		a.Body = body
+	}
+	return a
+}
+
+// StepResult is the outcome of one step.
+type StepResult struct {
+	Index int    `json:"index"`
+	Step  string `json:"step"`
+	// Outcome describes what happened after plugins had their say, for example `sent "HELLO"`.
+	Outcome  string   `json:"outcome,omitempty"`
+	Actions  []Action `json:"actions,omitempty"`
+	Failures []string `json:"failures,omitempty"`
+
+	cancelled     *bool
+	message       *string
+	xp            *int
+	keepInventory *bool
+	position      *mgl64.Vec3
+}
+
+// Passed reports whether the step ran and met its expectations.
+func (res StepResult) Passed() bool {
+	return len(res.Failures) == 0
+}
+
+// check compares the step's outcome and actions with e, recording every mismatch.
+func (res *StepResult) check(e *Expectation) {
+	if e == nil {
+		return
+	}
+	fail := func(format string, args ...any) {
+		res.Failures = append(res.Failures, fmt.Sprintf(format, args...))
+	}
+	if e.Cancelled != nil && res.cancelled != nil && *e.Cancelled != *res.cancelled {
+		fail("cancelled is %t, want %t", *res.cancelled, *e.Cancelled)
+	}
+	if e.Message != nil && res.message != nil && *e.Message != *res.message {
+		fail("message is %q, want %q", *res.message, *e.Message)
+	}
+	if e.XP != nil && res.xp != nil && *e.XP != *res.xp {
+		fail("xp is %d, want %d", *res.xp, *e.XP)
+	}
+	if e.KeepInventory != nil && res.keepInventory != nil && *e.KeepInventory != *res.keepInventory {
+		fail("keep_inventory is %t, want %t", *res.keepInventory, *e.KeepInventory)
+	}
+	if e.Position != nil && res.position != nil {
+		want := mgl64.Vec3{e.Position[0], e.Position[1], e.Position[2]}
+		if !res.position.ApproxEqualThreshold(want, positionTolerance) {
+			fail("position is %s, want %s", formatVec(*res.position), formatVec(want))
+		}
+	}
+	if e.NoActions && len(res.Actions) > 0 {
+		fail("got %d actions, want none", len(res.Actions))
+	}
+
+	used := make([]bool, len(res.Actions))
+	for _, want := range e.Actions {
+		found := false
+		for i, a := range res.Actions {
+			if !used[i] && want.matches(a) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			fail("no action matching %s", want)
+		}
+	}
+}
+
+// matches reports whether a meets every condition of m.
+func (m ActionMatch) matches(a Action) bool {
+	if m.Plugin != "" && m.Plugin != a.Plugin {
+		return false
+	}
+	if m.Type != "" && m.Type != a.Type {
+		return false
+	}
+	if a.kind == nil {
+		return m.Player == "" && len(m.Fields) == 0
+	}
+	if m.Player != "" {
+		id := PlayerUUID(m.Player).String()
+		player, _ := fieldValue(a.kind, "player_uuid")
+		target, _ := fieldValue(a.kind, "target_uuid")
+		if player != id && target != id {
+			return false
+		}
+	}
+	for path, want := range m.Fields {
+		if got, ok := fieldValue(a.kind, path); !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+func (m ActionMatch) String() string {
+	var parts []string
+	if m.Plugin != "" {
+		parts = append(parts, "plugin="+m.Plugin)
+	}
+	if m.Type != "" {
+		parts = append(parts, "type="+m.Type)
+	}
+	if m.Player != "" {
+		parts = append(parts, "player="+m.Player)
+	}
+	keys := make([]string, 0, len(m.Fields))
+	for k := range m.Fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%q", k, m.Fields[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// fieldValue returns the value of the scalar field at path, a dot-separated list of protocol
+// field names, as text. Enums are given by name.
+func fieldValue(msg protoreflect.Message, path string) (string, bool) {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fields := msg.Descriptor().Fields()
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = fields.ByJSONName(name)
+		}
+		if fd == nil || fd.IsList() || fd.IsMap() {
+			return "", false
+		}
+		v := msg.Get(fd)
+		if i < len(names)-1 {
+			if fd.Message() == nil {
+				return "", false
+			}
+			msg = v.Message()
+			continue
+		}
+		switch {
+		case fd.Message() != nil:
+			return "", false
+		case fd.Enum() != nil:
+			if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+				return string(ev.Name()), true
+			}
+			return fmt.Sprint(v.Enum()), true
+		case fd.Kind() == protoreflect.FloatKind:
+			return strconv.FormatFloat(v.Float(), 'g', -1, 32), true
+		case fd.Kind() == protoreflect.DoubleKind:
+			return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+		default:
+			return fmt.Sprint(v.Interface()), true
+		}
+	}
+	return "", false
+}
+
+// WriteText writes the step, its outcome, its actions and any failures.
+func (res StepResult) WriteText(w io.Writer) {
+	status := "ok"
+	if !res.Passed() {
+		status = "FAIL"
+	}
+	fmt.Fprintf(w, "%-4s %3d. %s", status, res.Index, res.Step)
+	if res.Outcome != "" {
+		fmt.Fprintf(w, " -> %s", res.Outcome)
+	}
+	fmt.Fprintln(w)
+	for _, a := range res.Actions {
+		fmt.Fprintf(w, "           %s: %s %s\n", a.Plugin, a.Type, string(a.Body))
+	}
+	for _, f := range res.Failures {
+		fmt.Fprintf(w, "           ! %s\n", f)
+	}
+}
+
+// Report is the result of a simulation.
+type Report struct {
+	Steps []StepResult `json:"steps"`
+}
+
+// Passed reports whether every step met its expectations.
+func (r *Report) Passed() bool {
+	for _, res := range r.Steps {
+		if !res.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteSummary writes how many steps met their expectations.
+func (r *Report) WriteSummary(w io.Writer) {
+	failed := 0
+	for _, res := range r.Steps {
+		if !res.Passed() {
+			failed++
+		}
+	}
+	fmt.Fprintf(w, "%d steps, %d failed\n", len(r.Steps), failed)
+}
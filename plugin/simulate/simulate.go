@@ -0,0 +1,325 @@
+// Package simulate plays scripted player activity against plugins without a game client. It starts
+// the real plugin manager and transport with a plugins.yaml, attaches the worlds of a Dragonfly
+// server that never listens and whose worlds generate and save nothing, and drives simulated
+// players through a Timeline of joins, chats, commands, moves, block breaks and deaths.
+//
+// Each step emits the events the server would and applies the outcome plugins decide on: a
+// cancelled block break leaves the block in place, a mutated chat message is what is reported as
+// sent. The actions plugins send while the step settles are recorded with it and checked against
+// the step's expectations. World actions are applied to the server's worlds; simulated players have
+// no network session, so actions aimed at them are recorded but change nothing.
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/google/uuid"
+
+	"github.com/secmc/plugin/plugin/adapters/handlers"
+	"github.com/secmc/plugin/plugin/adapters/plugin"
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/ports"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const (
+	defaultConnectTimeout = 30 * time.Second
+	defaultSettle         = 200 * time.Millisecond
+)
+
+// playerNamespace derives stable player UUIDs from names, so that runs are reproducible.
+var playerNamespace = uuid.MustParse("3c9e6f0a-5d1b-4e7a-8f2c-9b4d6a1e0c57")
+
+// PlayerUUID returns the UUID a simulated player with the given name has.
+func PlayerUUID(name string) uuid.UUID {
+	return uuid.NewSHA1(playerNamespace, []byte(name))
+}
+
+// Simulator plays timelines.
+type Simulator struct {
+	// Log receives the manager's logs, including plugin output. It defaults to discarding them.
+	Log *slog.Logger
+	// Output receives each step's result as soon as it has run. It defaults to discarding them.
+	Output io.Writer
+}
+
+// Run starts a manager configured with cfg, waits until all of its plugins have subscribed and
+// plays the timeline. An error is returned if the simulation could not run; failed expectations
+// are reported in the Report.
+func (s *Simulator) Run(ctx context.Context, cfg config.Config, tl *Timeline) (*Report, error) {
+	if err := tl.Validate(); err != nil {
+		return nil, err
+	}
+	log := s.Log
+	if log == nil {
+		log = slog.New(slog.DiscardHandler)
+	}
+	out := s.Output
+	if out == nil {
+		out = io.Discard
+	}
+	connectTimeout, settle := tl.ConnectTimeout, tl.Settle
+	if connectTimeout <= 0 {
+		connectTimeout = defaultConnectTimeout
+	}
+	if settle <= 0 {
+		settle = defaultSettle
+	}
+
+	// Players get no event handler: the simulation emits their events itself, so that it can
+	// apply and report the outcome.
+	m := plugin.NewManager(nil, log, nil, func(e ports.EventManager) world.Handler { return handlers.NewWorldHandler(e) })
+	rec := &recorder{}
+	m.ObserveActions(rec.observe)
+	if err := m.StartWithConfig(cfg); err != nil {
+		m.Close()
+		return nil, fmt.Errorf("start manager: %w", err)
+	}
+	defer m.Close()
+	if err := waitReady(ctx, m, connectTimeout); err != nil {
+		return nil, err
+	}
+
+	// The server is created once plugins have connected, as on a real server, so that their
+	// custom items and blocks are registered. It never listens; only its worlds are used.
+	srv := server.Config{
+		Log:           log,
+		ReadOnlyWorld: true,
+		Generator:     func(world.Dimension) world.Generator { return world.NopGenerator{} },
+	}.New()
+	m.SetServer(srv)
+	worlds := []*world.World{srv.World(), srv.Nether(), srv.End()}
+	for _, w := range worlds {
+		m.AttachWorld(w)
+	}
+	defer func() {
+		for _, w := range worlds {
+			_ = w.Close()
+		}
+	}()
+
+	r := &run{m: m, w: srv.World(), rec: rec, settle: settle, players: make(map[string]*world.EntityHandle)}
+	report := &Report{}
+	for i, st := range tl.Steps {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		res := r.step(ctx, st)
+		res.Index = i + 1
+		report.Steps = append(report.Steps, res)
+		res.WriteText(out)
+	}
+	return report, nil
+}
+
+// waitReady waits until every configured plugin is connected and subscribed.
+func waitReady(ctx context.Context, m *plugin.Manager, timeout time.Duration) error {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	tick := time.NewTicker(50 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		var pending []string
+		for _, st := range m.PluginStatuses() {
+			if st.State != "ready" {
+				pending = append(pending, fmt.Sprintf("%s (%s)", st.ID, st.State))
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return fmt.Errorf("plugins not ready within %s: %s", timeout, strings.Join(pending, ", "))
+		}
+	}
+}
+
+// recorder collects the actions plugins send.
+type recorder struct {
+	mu      sync.Mutex
+	actions []Action
+}
+
+func (r *recorder) observe(pluginID string, action *pb.Action) {
+	a := newAction(pluginID, action)
+	r.mu.Lock()
+	r.actions = append(r.actions, a)
+	r.mu.Unlock()
+}
+
+// take returns the actions recorded since the last call.
+func (r *recorder) take() []Action {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	actions := r.actions
+	r.actions = nil
+	return actions
+}
+
+// run is the state of one simulation.
+type run struct {
+	m       *plugin.Manager
+	w       *world.World
+	rec     *recorder
+	settle  time.Duration
+	players map[string]*world.EntityHandle
+}
+
+// step runs st, waits for its actions to settle and checks its expectations.
+func (r *run) step(ctx context.Context, st Step) StepResult {
+	res := StepResult{Step: st.String()}
+	r.rec.take()
+	var err error
+	switch {
+	case st.Sleep > 0:
+		select {
+		case <-time.After(st.Sleep):
+		case <-ctx.Done():
+		}
+	case st.Join != "":
+		err = r.join(st.Join)
+	default:
+		err = r.withPlayer(st.player(), func(tx *world.Tx, p *player.Player) error {
+			return r.act(tx, p, st, &res)
+		})
+	}
+	if err != nil {
+		res.Failures = append(res.Failures, err.Error())
+		return res
+	}
+	if st.Sleep == 0 {
+		select {
+		case <-time.After(r.settle):
+		case <-ctx.Done():
+		}
+	}
+	res.Actions = r.rec.take()
+	if st.Expect != nil && st.Quit == "" && st.player() != "" {
+		_ = r.withPlayer(st.player(), func(tx *world.Tx, p *player.Player) error {
+			pos := p.Position()
+			res.position = &pos
+			return nil
+		})
+	}
+	res.check(st.Expect)
+	return res
+}
+
+func (r *run) join(name string) error {
+	if _, ok := r.players[name]; ok {
+		return fmt.Errorf("%s has already joined", name)
+	}
+	var spawn mgl64.Vec3
+	<-r.w.Exec(func(tx *world.Tx) { spawn = r.w.Spawn().Vec3Middle() })
+	id := PlayerUUID(name)
+	handle := world.EntitySpawnOpts{Position: spawn, ID: id}.New(player.Type, player.Config{Name: name, UUID: id})
+	<-r.w.Exec(func(tx *world.Tx) {
+		r.m.AttachPlayer(tx.AddEntity(handle).(*player.Player))
+	})
+	r.players[name] = handle
+	return nil
+}
+
+// withPlayer runs f with the named player in a transaction on its world.
+func (r *run) withPlayer(name string, f func(tx *world.Tx, p *player.Player) error) error {
+	handle, ok := r.players[name]
+	if !ok {
+		return fmt.Errorf("%s has not joined", name)
+	}
+	var err error
+	if !handle.ExecWorld(func(tx *world.Tx, e world.Entity) { err = f(tx, e.(*player.Player)) }) {
+		return fmt.Errorf("%s is not in a world", name)
+	}
+	return err
+}
+
+// act emits the events of st for p and applies their outcome.
+func (r *run) act(tx *world.Tx, p *player.Player, st Step, res *StepResult) error {
+	switch {
+	case st.Quit != "":
+		r.m.EmitPlayerQuit(p)
+		_ = tx.RemoveEntity(p).Close()
+		delete(r.players, st.Quit)
+		res.Outcome = "left"
+	case st.Chat != nil:
+		ctx := event.C(p)
+		msg := st.Chat.Message
+		r.m.EmitChat(ctx, p, &msg)
+		res.cancelled, res.message = ptr(ctx.Cancelled()), &msg
+		if ctx.Cancelled() {
+			res.Outcome = "cancelled"
+		} else {
+			res.Outcome = fmt.Sprintf("sent %q", msg)
+		}
+	case st.Command != nil:
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(st.Command.Line), "/"))
+		ctx := event.C(p)
+		r.m.EmitCommand(ctx, p, fields[0], fields[1:])
+		res.cancelled = ptr(ctx.Cancelled())
+		res.Outcome = "ran"
+		if ctx.Cancelled() {
+			res.Outcome = "cancelled"
+		}
+	case st.Move != nil:
+		to := mgl64.Vec3{st.Move.To[0], st.Move.To[1], st.Move.To[2]}
+		r.m.EmitPlayerMove(event.C(p), p, to, p.Rotation())
+		p.Teleport(to)
+		res.Outcome = fmt.Sprintf("moved to %s", formatVec(to))
+	case st.Break != nil:
+		pos := cube.Pos{st.Break.At[0], st.Break.At[1], st.Break.At[2]}
+		if st.Break.Block != "" {
+			b, ok := world.BlockByName(st.Break.Block, nil)
+			if !ok {
+				return fmt.Errorf("unknown block %q", st.Break.Block)
+			}
+			tx.SetBlock(pos, b, nil)
+		}
+		ctx := event.C(p)
+		var drops []item.Stack
+		xp := 0
+		r.m.EmitBlockBreak(ctx, p, pos, &drops, &xp, fmt.Sprint(tx.World().Dimension()))
+		res.cancelled, res.xp = ptr(ctx.Cancelled()), &xp
+		if ctx.Cancelled() {
+			res.Outcome = "cancelled"
+		} else {
+			tx.SetBlock(pos, nil, nil)
+			res.Outcome = fmt.Sprintf("broken, %d drops, %d xp", len(drops), xp)
+		}
+	case st.Death != "":
+		keep := false
+		r.m.EmitPlayerDeath(p, entity.VoidDamageSource{}, &keep)
+		pos := tx.World().Spawn().Vec3Middle()
+		w := tx.World()
+		r.m.EmitPlayerRespawn(p, &pos, &w)
+		p.Teleport(pos)
+		res.keepInventory = &keep
+		res.Outcome = fmt.Sprintf("died, keep inventory %t, respawned at %s", keep, formatVec(pos))
+	}
+	return nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func formatVec(v mgl64.Vec3) string {
+	return fmt.Sprintf("[%g, %g, %g]", v[0], v[1], v[2])
+}
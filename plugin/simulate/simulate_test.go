@@ -0,0 +1,129 @@
+package simulate_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/secmc/plugin/packages/go/plugin"
+	"github.com/secmc/plugin/plugin/config"
+	"github.com/secmc/plugin/plugin/simulate"
+	pb "github.com/secmc/plugin/proto/generated/go"
+)
+
+const timeline = `
+settle: 100ms
+steps:
+  - join: Steve
+    expect:
+      actions:
+        - {plugin: greeter, type: send_chat, player: Steve, fields: {message: "Welcome Steve"}}
+  - chat: {player: Steve, message: hello}
+    expect: {cancelled: false, message: HELLO}
+  - chat: {player: Steve, message: cancel}
+    expect: {cancelled: true, no_actions: true}
+  - break: {player: Steve, at: [0, 64, 0], block: "minecraft:stone"}
+    expect: {cancelled: false, xp: 7}
+  - break: {player: Steve, at: [0, 5, 0]}
+    expect: {cancelled: true}
+  - move: {player: Steve, to: [10, 65, 10]}
+    expect: {position: [10, 65, 10]}
+  - death: Steve
+    expect: {keep_inventory: true}
+  # Fails on purpose: the greeter upper-cases chat.
+  - chat: {player: Steve, message: quiet}
+    expect: {message: quiet}
+  - quit: Steve
+`
+
+// greeter welcomes players, upper-cases chat, and protects blocks below y 10.
+func greeter(p *plugin.Plugin) {
+	plugin.On(p, func(e *plugin.Event, join *pb.PlayerJoinEvent) {
+		_ = p.Actions().SendChat(join.PlayerUuid, "Welcome "+join.Name).Send()
+	})
+	plugin.On(p, func(e *plugin.Event, chat *pb.ChatEvent) {
+		if chat.Message == "cancel" {
+			e.Cancel()
+			return
+		}
+		e.Mutate(plugin.ChatMessage(strings.ToUpper(chat.Message)))
+	})
+	plugin.On(p, func(e *plugin.Event, br *pb.BlockBreakEvent) {
+		if br.Position.Y < 10 {
+			e.Cancel()
+			return
+		}
+		e.Mutate(plugin.BlockBreakXP(7))
+	})
+	plugin.On(p, func(e *plugin.Event, death *pb.PlayerDeathEvent) {
+		e.Mutate(plugin.DeathKeepInventory(true))
+	})
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "timeline.yaml")
+	if err := os.WriteFile(path, []byte(timeline), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tl, err := simulate.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	address := filepath.Join(dir, "plugin.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p, err := plugin.New(plugin.WithID("greeter"), plugin.WithAddress(address), plugin.WithLogger(slog.New(slog.DiscardHandler)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	greeter(p)
+	go func() { _ = p.Run(ctx) }()
+
+	var out bytes.Buffer
+	s := &simulate.Simulator{Output: &out}
+	report, err := s.Run(ctx, config.Config{
+		ServerPort: address,
+		Plugins:    []config.PluginConfig{{ID: "greeter"}},
+	}, tl)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Steps) != len(tl.Steps) {
+		t.Fatalf("Expected %d step results, got %d:\n%s", len(tl.Steps), len(report.Steps), out.String())
+	}
+	for i, res := range report.Steps {
+		if wantFail := i == 7; res.Passed() == wantFail {
+			t.Errorf("Expected step %d to pass %v, got failures %v:\n%s", res.Index, !wantFail, res.Failures, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), `message is "QUIET", want "quiet"`) {
+		t.Errorf("Expected the failed expectation in the output, got:\n%s", out.String())
+	}
+}
+
+func TestLoadRejectsInvalidSteps(t *testing.T) {
+	for name, src := range map[string]string{
+		"no kind":         "steps: [{expect: {cancelled: true}}]",
+		"two kinds":       "steps: [{join: Steve, quit: Steve}]",
+		"wrong expect":    "steps: [{join: Steve, expect: {xp: 1}}]",
+		"short position":  "steps: [{move: {player: Steve, to: [1, 2]}}]",
+		"unknown field":   "steps: [{jump: Steve}]",
+		"empty action":    "steps: [{join: Steve, expect: {actions: [{}]}}]",
+		"missing player":  "steps: [{chat: {message: hi}}]",
+		"no steps at all": "config: plugins.yaml",
+	} {
+		path := filepath.Join(t.TempDir(), "timeline.yaml")
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := simulate.Load(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
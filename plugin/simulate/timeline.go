@@ -0,0 +1,263 @@
+package simulate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Timeline is a scripted sequence of player activity, read from YAML:
+//
+//	config: plugins.yaml
+//	steps:
+//	  - join: Steve
+//	  - chat: {player: Steve, message: hello}
+//	    expect:
+//	      message: HELLO
+//	      actions:
+//	        - {plugin: greeter, type: send_chat, player: Steve, fields: {message: "Hi Steve"}}
+//	  - break: {player: Steve, at: [0, 64, 0], block: "minecraft:stone"}
+//	    expect: {cancelled: true}
+type Timeline struct {
+	// Config is the plugins.yaml to start the manager with, relative to the timeline file.
+	Config string `yaml:"config"`
+	// ConnectTimeout bounds how long plugins may take to connect and subscribe. It defaults to 30s.
+	ConnectTimeout time.Duration `yaml:"connect_timeout"`
+	// Settle is how long actions are collected after each step. It defaults to 200ms.
+	Settle time.Duration `yaml:"settle"`
+	Steps  []Step        `yaml:"steps"`
+}
+
+// Step is one thing that happens on the server. Exactly one of its kinds is set.
+type Step struct {
+	Join    string        `yaml:"join"`
+	Quit    string        `yaml:"quit"`
+	Chat    *ChatStep     `yaml:"chat"`
+	Command *CommandStep  `yaml:"command"`
+	Move    *MoveStep     `yaml:"move"`
+	Break   *BreakStep    `yaml:"break"`
+	Death   string        `yaml:"death"`
+	Sleep   time.Duration `yaml:"sleep"`
+	Expect  *Expectation  `yaml:"expect"`
+}
+
+// ChatStep has a player send a chat message.
+type ChatStep struct {
+	Player  string `yaml:"player"`
+	Message string `yaml:"message"`
+}
+
+// CommandStep has a player run a command line such as "/spawn" or "/tp 0 64 0".
+type CommandStep struct {
+	Player string `yaml:"player"`
+	Line   string `yaml:"line"`
+}
+
+// MoveStep moves a player to a position.
+type MoveStep struct {
+	Player string    `yaml:"player"`
+	To     []float64 `yaml:"to"`
+}
+
+// BreakStep has a player break the block at a position. Block, if set, is placed there first.
+type BreakStep struct {
+	Player string `yaml:"player"`
+	At     []int  `yaml:"at"`
+	Block  string `yaml:"block"`
+}
+
+// Expectation is what a step must produce. Unset fields are not checked.
+type Expectation struct {
+	// Cancelled checks whether plugins cancelled a chat, command or block break.
+	Cancelled *bool `yaml:"cancelled"`
+	// Message is the chat message after plugins mutated it.
+	Message *string `yaml:"message"`
+	// XP is the experience a block break drops after plugins mutated it.
+	XP *int `yaml:"xp"`
+	// KeepInventory is whether a player keeps their inventory on death after plugins mutated it.
+	KeepInventory *bool `yaml:"keep_inventory"`
+	// Position is where the player is after the step, such as after a move or respawn.
+	Position []float64 `yaml:"position"`
+	// Actions must each match a different action plugins sent during the step.
+	Actions []ActionMatch `yaml:"actions"`
+	// NoActions requires that plugins sent no actions during the step.
+	NoActions bool `yaml:"no_actions"`
+}
+
+// ActionMatch matches an action sent by a plugin.
+type ActionMatch struct {
+	// Plugin is the ID of the plugin that sent the action.
+	Plugin string `yaml:"plugin"`
+	// Type is the action's kind as named in the protocol, such as "send_chat" or "teleport".
+	Type string `yaml:"type"`
+	// Player is a simulated player that the action's player_uuid or target_uuid must refer to.
+	Player string `yaml:"player"`
+	// Fields are values of the action's fields, by protocol name. Nested fields are written as
+	// "world.name"; enums match by name.
+	Fields map[string]string `yaml:"fields"`
+}
+
+// Load reads a timeline from a YAML file. A relative Config is resolved against the file's
+// directory.
+func Load(path string) (*Timeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tl := &Timeline{}
+	if err := yaml.UnmarshalStrict(data, tl); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := tl.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if tl.Config != "" && !filepath.IsAbs(tl.Config) {
+		tl.Config = filepath.Join(filepath.Dir(path), tl.Config)
+	}
+	return tl, nil
+}
+
+// Validate checks that every step has exactly one kind and only expects what that kind can produce.
+func (tl *Timeline) Validate() error {
+	if len(tl.Steps) == 0 {
+		return errors.New("timeline has no steps")
+	}
+	for i, st := range tl.Steps {
+		if err := st.validate(); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (st Step) validate() error {
+	var kinds []string
+	set := func(ok bool, kind string) {
+		if ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	set(st.Join != "", "join")
+	set(st.Quit != "", "quit")
+	set(st.Chat != nil, "chat")
+	set(st.Command != nil, "command")
+	set(st.Move != nil, "move")
+	set(st.Break != nil, "break")
+	set(st.Death != "", "death")
+	set(st.Sleep > 0, "sleep")
+	if len(kinds) != 1 {
+		return fmt.Errorf("want exactly one of join, quit, chat, command, move, break, death or sleep, got %v", kinds)
+	}
+	kind := kinds[0]
+
+	switch {
+	case st.Chat != nil && st.Chat.Player == "",
+		st.Command != nil && st.Command.Player == "",
+		st.Move != nil && st.Move.Player == "",
+		st.Break != nil && st.Break.Player == "":
+		return fmt.Errorf("%s needs a player", kind)
+	case st.Command != nil && strings.TrimPrefix(strings.TrimSpace(st.Command.Line), "/") == "":
+		return errors.New("command needs a line")
+	case st.Move != nil && len(st.Move.To) != 3:
+		return errors.New("move needs a position [x, y, z]")
+	case st.Break != nil && len(st.Break.At) != 3:
+		return errors.New("break needs a position [x, y, z]")
+	}
+
+	e := st.Expect
+	if e == nil {
+		return nil
+	}
+	only := func(ok bool, field string, kinds string) error {
+		if !ok {
+			return fmt.Errorf("%s cannot expect %s, only %s can", kind, field, kinds)
+		}
+		return nil
+	}
+	if e.Cancelled != nil {
+		if err := only(st.Chat != nil || st.Command != nil || st.Break != nil, "cancelled", "chat, command and break"); err != nil {
+			return err
+		}
+	}
+	if e.Message != nil {
+		if err := only(st.Chat != nil, "message", "chat"); err != nil {
+			return err
+		}
+	}
+	if e.XP != nil {
+		if err := only(st.Break != nil, "xp", "break"); err != nil {
+			return err
+		}
+	}
+	if e.KeepInventory != nil {
+		if err := only(st.Death != "", "keep_inventory", "death"); err != nil {
+			return err
+		}
+	}
+	if e.Position != nil {
+		if err := only(st.player() != "" && st.Quit == "", "position", "steps with a player in the world"); err != nil {
+			return err
+		}
+		if len(e.Position) != 3 {
+			return errors.New("expected position must be [x, y, z]")
+		}
+	}
+	if e.NoActions && len(e.Actions) > 0 {
+		return errors.New("cannot expect both actions and no_actions")
+	}
+	for i, a := range e.Actions {
+		if a.Plugin == "" && a.Type == "" && a.Player == "" && len(a.Fields) == 0 {
+			return fmt.Errorf("expected action %d matches anything", i+1)
+		}
+	}
+	return nil
+}
+
+// player returns the player the step is about, if any.
+func (st Step) player() string {
+	switch {
+	case st.Join != "":
+		return st.Join
+	case st.Quit != "":
+		return st.Quit
+	case st.Chat != nil:
+		return st.Chat.Player
+	case st.Command != nil:
+		return st.Command.Player
+	case st.Move != nil:
+		return st.Move.Player
+	case st.Break != nil:
+		return st.Break.Player
+	case st.Death != "":
+		return st.Death
+	}
+	return ""
+}
+
+// String describes the step, for example `chat Steve: "hello"`.
+func (st Step) String() string {
+	switch {
+	case st.Join != "":
+		return "join " + st.Join
+	case st.Quit != "":
+		return "quit " + st.Quit
+	case st.Chat != nil:
+		return fmt.Sprintf("chat %s: %q", st.Chat.Player, st.Chat.Message)
+	case st.Command != nil:
+		return fmt.Sprintf("command %s: %s", st.Command.Player, st.Command.Line)
+	case st.Move != nil:
+		return fmt.Sprintf("move %s to %v", st.Move.Player, st.Move.To)
+	case st.Break != nil:
+		return fmt.Sprintf("break %s at %v", st.Break.Player, st.Break.At)
+	case st.Death != "":
+		return "death " + st.Death
+	case st.Sleep > 0:
+		return "sleep " + st.Sleep.String()
+	}
+	return "empty step"
+}
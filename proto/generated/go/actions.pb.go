@@ -307,6 +307,7 @@ func (x *ActionBatch) GetActions() []*Action {
 type Action struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CorrelationId *string                `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3,oneof" json:"correlation_id,omitempty"`
+	Trace         *TraceContext          `protobuf:"bytes,2,opt,name=trace,proto3" json:"trace,omitempty"` // Links the action to the trace of the event that caused it.
 	// Types that are valid to be assigned to Kind:
 	//
 	//	*Action_SendChat
@@ -457,6 +458,13 @@ func (x *Action) GetCorrelationId() string {
 	return ""
 }
 
+func (x *Action) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
 func (x *Action) GetKind() isAction_Kind {
 	if x != nil {
 		return x.Kind
@@ -7920,9 +7928,10 @@ const file_actions_proto_rawDesc = "" +
 	"\n" +
 	"\ractions.proto\x12\tdf.plugin\x1a\fcommon.proto\":\n" +
 	"\vActionBatch\x12+\n" +
-	"\aactions\x18\x01 \x03(\v2\x11.df.plugin.ActionR\aactions\"\xf8F\n" +
+	"\aactions\x18\x01 \x03(\v2\x11.df.plugin.ActionR\aactions\"\xa7G\n" +
 	"\x06Action\x12*\n" +
-	"\x0ecorrelation_id\x18\x01 \x01(\tH\x01R\rcorrelationId\x88\x01\x01\x128\n" +
+	"\x0ecorrelation_id\x18\x01 \x01(\tH\x01R\rcorrelationId\x88\x01\x01\x12-\n" +
+	"\x05trace\x18\x02 \x01(\v2\x17.df.plugin.TraceContextR\x05trace\x128\n" +
 	"\tsend_chat\x18\n" +
 	" \x01(\v2\x19.df.plugin.SendChatActionH\x00R\bsendChat\x127\n" +
 	"\bteleport\x18\v \x01(\v2\x19.df.plugin.TeleportActionH\x00R\bteleport\x12+\n" +
@@ -8694,222 +8703,224 @@ var file_actions_proto_goTypes = []any{
 	(*PlayerOpenBlockContainerAction)(nil),     // 110: df.plugin.PlayerOpenBlockContainerAction
 	(*PlayerDropItemAction)(nil),               // 111: df.plugin.PlayerDropItemAction
 	(*PlayerSetItemCooldownAction)(nil),        // 112: df.plugin.PlayerSetItemCooldownAction
-	(*Vec3)(nil),                               // 113: df.plugin.Vec3
-	(GameMode)(0),                              // 114: df.plugin.GameMode
-	(*ItemStack)(nil),                          // 115: df.plugin.ItemStack
-	(EffectType)(0),                            // 116: df.plugin.EffectType
-	(Sound)(0),                                 // 117: df.plugin.Sound
-	(*WorldRef)(nil),                           // 118: df.plugin.WorldRef
-	(Difficulty)(0),                            // 119: df.plugin.Difficulty
-	(*BlockPos)(nil),                           // 120: df.plugin.BlockPos
-	(*BlockState)(nil),                         // 121: df.plugin.BlockState
-	(*BBox)(nil),                               // 122: df.plugin.BBox
-	(*LiquidState)(nil),                        // 123: df.plugin.LiquidState
-	(*Address)(nil),                            // 124: df.plugin.Address
-	(*EntityRef)(nil),                          // 125: df.plugin.EntityRef
+	(*TraceContext)(nil),                       // 113: df.plugin.TraceContext
+	(*Vec3)(nil),                               // 114: df.plugin.Vec3
+	(GameMode)(0),                              // 115: df.plugin.GameMode
+	(*ItemStack)(nil),                          // 116: df.plugin.ItemStack
+	(EffectType)(0),                            // 117: df.plugin.EffectType
+	(Sound)(0),                                 // 118: df.plugin.Sound
+	(*WorldRef)(nil),                           // 119: df.plugin.WorldRef
+	(Difficulty)(0),                            // 120: df.plugin.Difficulty
+	(*BlockPos)(nil),                           // 121: df.plugin.BlockPos
+	(*BlockState)(nil),                         // 122: df.plugin.BlockState
+	(*BBox)(nil),                               // 123: df.plugin.BBox
+	(*LiquidState)(nil),                        // 124: df.plugin.LiquidState
+	(*Address)(nil),                            // 125: df.plugin.Address
+	(*EntityRef)(nil),                          // 126: df.plugin.EntityRef
 }
 var file_actions_proto_depIdxs = []int32{
 	4,   // 0: df.plugin.ActionBatch.actions:type_name -> df.plugin.Action
-	5,   // 1: df.plugin.Action.send_chat:type_name -> df.plugin.SendChatAction
-	6,   // 2: df.plugin.Action.teleport:type_name -> df.plugin.TeleportAction
-	7,   // 3: df.plugin.Action.kick:type_name -> df.plugin.KickAction
-	8,   // 4: df.plugin.Action.set_game_mode:type_name -> df.plugin.SetGameModeAction
-	9,   // 5: df.plugin.Action.give_item:type_name -> df.plugin.GiveItemAction
-	10,  // 6: df.plugin.Action.clear_inventory:type_name -> df.plugin.ClearInventoryAction
-	11,  // 7: df.plugin.Action.set_held_item:type_name -> df.plugin.SetHeldItemAction
-	92,  // 8: df.plugin.Action.player_set_armour:type_name -> df.plugin.PlayerSetArmourAction
-	110, // 9: df.plugin.Action.player_open_block_container:type_name -> df.plugin.PlayerOpenBlockContainerAction
-	111, // 10: df.plugin.Action.player_drop_item:type_name -> df.plugin.PlayerDropItemAction
-	112, // 11: df.plugin.Action.player_set_item_cooldown:type_name -> df.plugin.PlayerSetItemCooldownAction
-	12,  // 12: df.plugin.Action.set_health:type_name -> df.plugin.SetHealthAction
-	13,  // 13: df.plugin.Action.set_food:type_name -> df.plugin.SetFoodAction
-	14,  // 14: df.plugin.Action.set_experience:type_name -> df.plugin.SetExperienceAction
-	15,  // 15: df.plugin.Action.set_velocity:type_name -> df.plugin.SetVelocityAction
-	16,  // 16: df.plugin.Action.add_effect:type_name -> df.plugin.AddEffectAction
-	17,  // 17: df.plugin.Action.remove_effect:type_name -> df.plugin.RemoveEffectAction
-	18,  // 18: df.plugin.Action.send_title:type_name -> df.plugin.SendTitleAction
-	19,  // 19: df.plugin.Action.send_popup:type_name -> df.plugin.SendPopupAction
-	20,  // 20: df.plugin.Action.send_tip:type_name -> df.plugin.SendTipAction
-	78,  // 21: df.plugin.Action.player_send_toast:type_name -> df.plugin.PlayerSendToastAction
-	79,  // 22: df.plugin.Action.player_send_jukebox_popup:type_name -> df.plugin.PlayerSendJukeboxPopupAction
-	80,  // 23: df.plugin.Action.player_show_coordinates:type_name -> df.plugin.PlayerShowCoordinatesAction
-	81,  // 24: df.plugin.Action.player_hide_coordinates:type_name -> df.plugin.PlayerHideCoordinatesAction
-	82,  // 25: df.plugin.Action.player_enable_instant_respawn:type_name -> df.plugin.PlayerEnableInstantRespawnAction
-	83,  // 26: df.plugin.Action.player_disable_instant_respawn:type_name -> df.plugin.PlayerDisableInstantRespawnAction
-	84,  // 27: df.plugin.Action.player_set_name_tag:type_name -> df.plugin.PlayerSetNameTagAction
-	85,  // 28: df.plugin.Action.player_set_score_tag:type_name -> df.plugin.PlayerSetScoreTagAction
-	21,  // 29: df.plugin.Action.play_sound:type_name -> df.plugin.PlaySoundAction
-	86,  // 30: df.plugin.Action.player_show_particle:type_name -> df.plugin.PlayerShowParticleAction
-	93,  // 31: df.plugin.Action.player_send_scoreboard:type_name -> df.plugin.PlayerSendScoreboardAction
-	94,  // 32: df.plugin.Action.player_remove_scoreboard:type_name -> df.plugin.PlayerRemoveScoreboardAction
-	95,  // 33: df.plugin.Action.player_send_menu_form:type_name -> df.plugin.PlayerSendMenuFormAction
-	96,  // 34: df.plugin.Action.player_send_modal_form:type_name -> df.plugin.PlayerSendModalFormAction
-	97,  // 35: df.plugin.Action.player_send_dialogue:type_name -> df.plugin.PlayerSendDialogueAction
-	102, // 36: df.plugin.Action.player_close_dialogue:type_name -> df.plugin.PlayerCloseDialogueAction
-	103, // 37: df.plugin.Action.player_close_form:type_name -> df.plugin.PlayerCloseFormAction
-	22,  // 38: df.plugin.Action.execute_command:type_name -> df.plugin.ExecuteCommandAction
-	54,  // 39: df.plugin.Action.player_start_sprinting:type_name -> df.plugin.PlayerStartSprintingAction
-	55,  // 40: df.plugin.Action.player_stop_sprinting:type_name -> df.plugin.PlayerStopSprintingAction
-	56,  // 41: df.plugin.Action.player_start_sneaking:type_name -> df.plugin.PlayerStartSneakingAction
-	57,  // 42: df.plugin.Action.player_stop_sneaking:type_name -> df.plugin.PlayerStopSneakingAction
-	58,  // 43: df.plugin.Action.player_start_swimming:type_name -> df.plugin.PlayerStartSwimmingAction
-	59,  // 44: df.plugin.Action.player_stop_swimming:type_name -> df.plugin.PlayerStopSwimmingAction
-	60,  // 45: df.plugin.Action.player_start_crawling:type_name -> df.plugin.PlayerStartCrawlingAction
-	61,  // 46: df.plugin.Action.player_stop_crawling:type_name -> df.plugin.PlayerStopCrawlingAction
-	62,  // 47: df.plugin.Action.player_start_gliding:type_name -> df.plugin.PlayerStartGlidingAction
-	63,  // 48: df.plugin.Action.player_stop_gliding:type_name -> df.plugin.PlayerStopGlidingAction
-	64,  // 49: df.plugin.Action.player_start_flying:type_name -> df.plugin.PlayerStartFlyingAction
-	65,  // 50: df.plugin.Action.player_stop_flying:type_name -> df.plugin.PlayerStopFlyingAction
-	66,  // 51: df.plugin.Action.player_set_immobile:type_name -> df.plugin.PlayerSetImmobileAction
-	67,  // 52: df.plugin.Action.player_set_mobile:type_name -> df.plugin.PlayerSetMobileAction
-	68,  // 53: df.plugin.Action.player_set_speed:type_name -> df.plugin.PlayerSetSpeedAction
-	69,  // 54: df.plugin.Action.player_set_flight_speed:type_name -> df.plugin.PlayerSetFlightSpeedAction
-	70,  // 55: df.plugin.Action.player_set_vertical_flight_speed:type_name -> df.plugin.PlayerSetVerticalFlightSpeedAction
-	71,  // 56: df.plugin.Action.player_set_absorption:type_name -> df.plugin.PlayerSetAbsorptionAction
-	72,  // 57: df.plugin.Action.player_set_on_fire:type_name -> df.plugin.PlayerSetOnFireAction
-	73,  // 58: df.plugin.Action.player_extinguish:type_name -> df.plugin.PlayerExtinguishAction
-	74,  // 59: df.plugin.Action.player_set_invisible:type_name -> df.plugin.PlayerSetInvisibleAction
-	75,  // 60: df.plugin.Action.player_set_visible:type_name -> df.plugin.PlayerSetVisibleAction
-	76,  // 61: df.plugin.Action.player_set_scale:type_name -> df.plugin.PlayerSetScaleAction
-	77,  // 62: df.plugin.Action.player_set_held_slot:type_name -> df.plugin.PlayerSetHeldSlotAction
-	87,  // 63: df.plugin.Action.player_respawn:type_name -> df.plugin.PlayerRespawnAction
-	88,  // 64: df.plugin.Action.player_transfer:type_name -> df.plugin.PlayerTransferAction
-	89,  // 65: df.plugin.Action.player_knock_back:type_name -> df.plugin.PlayerKnockBackAction
-	90,  // 66: df.plugin.Action.player_swing_arm:type_name -> df.plugin.PlayerSwingArmAction
-	91,  // 67: df.plugin.Action.player_punch_air:type_name -> df.plugin.PlayerPunchAirAction
-	98,  // 68: df.plugin.Action.player_send_boss_bar:type_name -> df.plugin.PlayerSendBossBarAction
-	99,  // 69: df.plugin.Action.player_remove_boss_bar:type_name -> df.plugin.PlayerRemoveBossBarAction
-	100, // 70: df.plugin.Action.player_show_hud_element:type_name -> df.plugin.PlayerShowHudElementAction
-	101, // 71: df.plugin.Action.player_hide_hud_element:type_name -> df.plugin.PlayerHideHudElementAction
-	104, // 72: df.plugin.Action.player_open_sign:type_name -> df.plugin.PlayerOpenSignAction
-	105, // 73: df.plugin.Action.player_edit_sign:type_name -> df.plugin.PlayerEditSignAction
-	106, // 74: df.plugin.Action.player_turn_lectern_page:type_name -> df.plugin.PlayerTurnLecternPageAction
-	107, // 75: df.plugin.Action.player_hide_player:type_name -> df.plugin.PlayerHidePlayerAction
-	108, // 76: df.plugin.Action.player_show_player:type_name -> df.plugin.PlayerShowPlayerAction
-	109, // 77: df.plugin.Action.player_remove_all_debug_shapes:type_name -> df.plugin.PlayerRemoveAllDebugShapesAction
-	23,  // 78: df.plugin.Action.world_set_default_game_mode:type_name -> df.plugin.WorldSetDefaultGameModeAction
-	24,  // 79: df.plugin.Action.world_set_difficulty:type_name -> df.plugin.WorldSetDifficultyAction
-	25,  // 80: df.plugin.Action.world_set_tick_range:type_name -> df.plugin.WorldSetTickRangeAction
-	26,  // 81: df.plugin.Action.world_set_block:type_name -> df.plugin.WorldSetBlockAction
-	27,  // 82: df.plugin.Action.world_play_sound:type_name -> df.plugin.WorldPlaySoundAction
-	28,  // 83: df.plugin.Action.world_add_particle:type_name -> df.plugin.WorldAddParticleAction
-	29,  // 84: df.plugin.Action.world_set_time:type_name -> df.plugin.WorldSetTimeAction
-	30,  // 85: df.plugin.Action.world_stop_time:type_name -> df.plugin.WorldStopTimeAction
-	31,  // 86: df.plugin.Action.world_start_time:type_name -> df.plugin.WorldStartTimeAction
-	32,  // 87: df.plugin.Action.world_set_spawn:type_name -> df.plugin.WorldSetSpawnAction
-	48,  // 88: df.plugin.Action.world_set_biome:type_name -> df.plugin.WorldSetBiomeAction
-	49,  // 89: df.plugin.Action.world_set_liquid:type_name -> df.plugin.WorldSetLiquidAction
-	50,  // 90: df.plugin.Action.world_schedule_block_update:type_name -> df.plugin.WorldScheduleBlockUpdateAction
-	53,  // 91: df.plugin.Action.world_build_structure:type_name -> df.plugin.WorldBuildStructureAction
-	35,  // 92: df.plugin.Action.world_query_entities:type_name -> df.plugin.WorldQueryEntitiesAction
-	36,  // 93: df.plugin.Action.world_query_players:type_name -> df.plugin.WorldQueryPlayersAction
-	37,  // 94: df.plugin.Action.world_query_entities_within:type_name -> df.plugin.WorldQueryEntitiesWithinAction
-	34,  // 95: df.plugin.Action.world_query_player_spawn:type_name -> df.plugin.WorldQueryPlayerSpawnAction
-	38,  // 96: df.plugin.Action.world_query_block:type_name -> df.plugin.WorldQueryBlockAction
-	39,  // 97: df.plugin.Action.world_query_biome:type_name -> df.plugin.WorldQueryBiomeAction
-	40,  // 98: df.plugin.Action.world_query_light:type_name -> df.plugin.WorldQueryLightAction
-	41,  // 99: df.plugin.Action.world_query_sky_light:type_name -> df.plugin.WorldQuerySkyLightAction
-	42,  // 100: df.plugin.Action.world_query_temperature:type_name -> df.plugin.WorldQueryTemperatureAction
-	43,  // 101: df.plugin.Action.world_query_highest_block:type_name -> df.plugin.WorldQueryHighestBlockAction
-	44,  // 102: df.plugin.Action.world_query_raining_at:type_name -> df.plugin.WorldQueryRainingAtAction
-	45,  // 103: df.plugin.Action.world_query_snowing_at:type_name -> df.plugin.WorldQuerySnowingAtAction
-	46,  // 104: df.plugin.Action.world_query_thundering_at:type_name -> df.plugin.WorldQueryThunderingAtAction
-	47,  // 105: df.plugin.Action.world_query_liquid:type_name -> df.plugin.WorldQueryLiquidAction
-	33,  // 106: df.plugin.Action.world_query_default_game_mode:type_name -> df.plugin.WorldQueryDefaultGameModeAction
-	113, // 107: df.plugin.TeleportAction.position:type_name -> df.plugin.Vec3
-	113, // 108: df.plugin.TeleportAction.rotation:type_name -> df.plugin.Vec3
-	114, // 109: df.plugin.SetGameModeAction.game_mode:type_name -> df.plugin.GameMode
-	115, // 110: df.plugin.GiveItemAction.item:type_name -> df.plugin.ItemStack
-	115, // 111: df.plugin.SetHeldItemAction.main:type_name -> df.plugin.ItemStack
-	115, // 112: df.plugin.SetHeldItemAction.offhand:type_name -> df.plugin.ItemStack
-	113, // 113: df.plugin.SetVelocityAction.velocity:type_name -> df.plugin.Vec3
-	116, // 114: df.plugin.AddEffectAction.effect_type:type_name -> df.plugin.EffectType
-	116, // 115: df.plugin.RemoveEffectAction.effect_type:type_name -> df.plugin.EffectType
-	117, // 116: df.plugin.PlaySoundAction.sound:type_name -> df.plugin.Sound
-	113, // 117: df.plugin.PlaySoundAction.position:type_name -> df.plugin.Vec3
-	118, // 118: df.plugin.WorldSetDefaultGameModeAction.world:type_name -> df.plugin.WorldRef
-	114, // 119: df.plugin.WorldSetDefaultGameModeAction.game_mode:type_name -> df.plugin.GameMode
-	118, // 120: df.plugin.WorldSetDifficultyAction.world:type_name -> df.plugin.WorldRef
-	119, // 121: df.plugin.WorldSetDifficultyAction.difficulty:type_name -> df.plugin.Difficulty
-	118, // 122: df.plugin.WorldSetTickRangeAction.world:type_name -> df.plugin.WorldRef
-	118, // 123: df.plugin.WorldSetBlockAction.world:type_name -> df.plugin.WorldRef
-	120, // 124: df.plugin.WorldSetBlockAction.position:type_name -> df.plugin.BlockPos
-	121, // 125: df.plugin.WorldSetBlockAction.block:type_name -> df.plugin.BlockState
-	118, // 126: df.plugin.WorldPlaySoundAction.world:type_name -> df.plugin.WorldRef
-	117, // 127: df.plugin.WorldPlaySoundAction.sound:type_name -> df.plugin.Sound
-	113, // 128: df.plugin.WorldPlaySoundAction.position:type_name -> df.plugin.Vec3
-	118, // 129: df.plugin.WorldAddParticleAction.world:type_name -> df.plugin.WorldRef
-	113, // 130: df.plugin.WorldAddParticleAction.position:type_name -> df.plugin.Vec3
-	0,   // 131: df.plugin.WorldAddParticleAction.particle:type_name -> df.plugin.ParticleType
-	121, // 132: df.plugin.WorldAddParticleAction.block:type_name -> df.plugin.BlockState
-	118, // 133: df.plugin.WorldSetTimeAction.world:type_name -> df.plugin.WorldRef
-	118, // 134: df.plugin.WorldStopTimeAction.world:type_name -> df.plugin.WorldRef
-	118, // 135: df.plugin.WorldStartTimeAction.world:type_name -> df.plugin.WorldRef
-	118, // 136: df.plugin.WorldSetSpawnAction.world:type_name -> df.plugin.WorldRef
-	120, // 137: df.plugin.WorldSetSpawnAction.spawn:type_name -> df.plugin.BlockPos
-	118, // 138: df.plugin.WorldQueryDefaultGameModeAction.world:type_name -> df.plugin.WorldRef
-	118, // 139: df.plugin.WorldQueryPlayerSpawnAction.world:type_name -> df.plugin.WorldRef
-	118, // 140: df.plugin.WorldQueryEntitiesAction.world:type_name -> df.plugin.WorldRef
-	118, // 141: df.plugin.WorldQueryPlayersAction.world:type_name -> df.plugin.WorldRef
-	118, // 142: df.plugin.WorldQueryEntitiesWithinAction.world:type_name -> df.plugin.WorldRef
-	122, // 143: df.plugin.WorldQueryEntitiesWithinAction.box:type_name -> df.plugin.BBox
-	118, // 144: df.plugin.WorldQueryBlockAction.world:type_name -> df.plugin.WorldRef
-	120, // 145: df.plugin.WorldQueryBlockAction.position:type_name -> df.plugin.BlockPos
-	118, // 146: df.plugin.WorldQueryBiomeAction.world:type_name -> df.plugin.WorldRef
-	120, // 147: df.plugin.WorldQueryBiomeAction.position:type_name -> df.plugin.BlockPos
-	118, // 148: df.plugin.WorldQueryLightAction.world:type_name -> df.plugin.WorldRef
-	120, // 149: df.plugin.WorldQueryLightAction.position:type_name -> df.plugin.BlockPos
-	118, // 150: df.plugin.WorldQuerySkyLightAction.world:type_name -> df.plugin.WorldRef
-	120, // 151: df.plugin.WorldQuerySkyLightAction.position:type_name -> df.plugin.BlockPos
-	118, // 152: df.plugin.WorldQueryTemperatureAction.world:type_name -> df.plugin.WorldRef
-	120, // 153: df.plugin.WorldQueryTemperatureAction.position:type_name -> df.plugin.BlockPos
-	118, // 154: df.plugin.WorldQueryHighestBlockAction.world:type_name -> df.plugin.WorldRef
-	118, // 155: df.plugin.WorldQueryRainingAtAction.world:type_name -> df.plugin.WorldRef
-	120, // 156: df.plugin.WorldQueryRainingAtAction.position:type_name -> df.plugin.BlockPos
-	118, // 157: df.plugin.WorldQuerySnowingAtAction.world:type_name -> df.plugin.WorldRef
-	120, // 158: df.plugin.WorldQuerySnowingAtAction.position:type_name -> df.plugin.BlockPos
-	118, // 159: df.plugin.WorldQueryThunderingAtAction.world:type_name -> df.plugin.WorldRef
-	120, // 160: df.plugin.WorldQueryThunderingAtAction.position:type_name -> df.plugin.BlockPos
-	118, // 161: df.plugin.WorldQueryLiquidAction.world:type_name -> df.plugin.WorldRef
-	120, // 162: df.plugin.WorldQueryLiquidAction.position:type_name -> df.plugin.BlockPos
-	118, // 163: df.plugin.WorldSetBiomeAction.world:type_name -> df.plugin.WorldRef
-	120, // 164: df.plugin.WorldSetBiomeAction.position:type_name -> df.plugin.BlockPos
-	118, // 165: df.plugin.WorldSetLiquidAction.world:type_name -> df.plugin.WorldRef
-	120, // 166: df.plugin.WorldSetLiquidAction.position:type_name -> df.plugin.BlockPos
-	123, // 167: df.plugin.WorldSetLiquidAction.liquid:type_name -> df.plugin.LiquidState
-	118, // 168: df.plugin.WorldScheduleBlockUpdateAction.world:type_name -> df.plugin.WorldRef
-	120, // 169: df.plugin.WorldScheduleBlockUpdateAction.position:type_name -> df.plugin.BlockPos
-	121, // 170: df.plugin.WorldScheduleBlockUpdateAction.block:type_name -> df.plugin.BlockState
-	121, // 171: df.plugin.StructureVoxel.block:type_name -> df.plugin.BlockState
-	123, // 172: df.plugin.StructureVoxel.liquid:type_name -> df.plugin.LiquidState
-	51,  // 173: df.plugin.StructureDef.voxels:type_name -> df.plugin.StructureVoxel
-	118, // 174: df.plugin.WorldBuildStructureAction.world:type_name -> df.plugin.WorldRef
-	120, // 175: df.plugin.WorldBuildStructureAction.origin:type_name -> df.plugin.BlockPos
-	52,  // 176: df.plugin.WorldBuildStructureAction.structure:type_name -> df.plugin.StructureDef
-	113, // 177: df.plugin.PlayerShowParticleAction.position:type_name -> df.plugin.Vec3
-	0,   // 178: df.plugin.PlayerShowParticleAction.particle:type_name -> df.plugin.ParticleType
-	121, // 179: df.plugin.PlayerShowParticleAction.block:type_name -> df.plugin.BlockState
-	124, // 180: df.plugin.PlayerTransferAction.address:type_name -> df.plugin.Address
-	113, // 181: df.plugin.PlayerKnockBackAction.source:type_name -> df.plugin.Vec3
-	115, // 182: df.plugin.PlayerSetArmourAction.helmet:type_name -> df.plugin.ItemStack
-	115, // 183: df.plugin.PlayerSetArmourAction.chestplate:type_name -> df.plugin.ItemStack
-	115, // 184: df.plugin.PlayerSetArmourAction.leggings:type_name -> df.plugin.ItemStack
-	115, // 185: df.plugin.PlayerSetArmourAction.boots:type_name -> df.plugin.ItemStack
-	125, // 186: df.plugin.PlayerSendDialogueAction.entity:type_name -> df.plugin.EntityRef
-	1,   // 187: df.plugin.PlayerSendBossBarAction.colour:type_name -> df.plugin.BossBarColour
-	2,   // 188: df.plugin.PlayerShowHudElementAction.element:type_name -> df.plugin.HudElement
-	2,   // 189: df.plugin.PlayerHideHudElementAction.element:type_name -> df.plugin.HudElement
-	120, // 190: df.plugin.PlayerOpenSignAction.position:type_name -> df.plugin.BlockPos
-	120, // 191: df.plugin.PlayerEditSignAction.position:type_name -> df.plugin.BlockPos
-	120, // 192: df.plugin.PlayerTurnLecternPageAction.position:type_name -> df.plugin.BlockPos
-	120, // 193: df.plugin.PlayerOpenBlockContainerAction.position:type_name -> df.plugin.BlockPos
-	115, // 194: df.plugin.PlayerDropItemAction.item:type_name -> df.plugin.ItemStack
-	115, // 195: df.plugin.PlayerSetItemCooldownAction.item:type_name -> df.plugin.ItemStack
-	196, // [196:196] is the sub-list for method output_type
-	196, // [196:196] is the sub-list for method input_type
-	196, // [196:196] is the sub-list for extension type_name
-	196, // [196:196] is the sub-list for extension extendee
-	0,   // [0:196] is the sub-list for field type_name
+	113, // 1: df.plugin.Action.trace:type_name -> df.plugin.TraceContext
+	5,   // 2: df.plugin.Action.send_chat:type_name -> df.plugin.SendChatAction
+	6,   // 3: df.plugin.Action.teleport:type_name -> df.plugin.TeleportAction
+	7,   // 4: df.plugin.Action.kick:type_name -> df.plugin.KickAction
+	8,   // 5: df.plugin.Action.set_game_mode:type_name -> df.plugin.SetGameModeAction
+	9,   // 6: df.plugin.Action.give_item:type_name -> df.plugin.GiveItemAction
+	10,  // 7: df.plugin.Action.clear_inventory:type_name -> df.plugin.ClearInventoryAction
+	11,  // 8: df.plugin.Action.set_held_item:type_name -> df.plugin.SetHeldItemAction
+	92,  // 9: df.plugin.Action.player_set_armour:type_name -> df.plugin.PlayerSetArmourAction
+	110, // 10: df.plugin.Action.player_open_block_container:type_name -> df.plugin.PlayerOpenBlockContainerAction
+	111, // 11: df.plugin.Action.player_drop_item:type_name -> df.plugin.PlayerDropItemAction
+	112, // 12: df.plugin.Action.player_set_item_cooldown:type_name -> df.plugin.PlayerSetItemCooldownAction
+	12,  // 13: df.plugin.Action.set_health:type_name -> df.plugin.SetHealthAction
+	13,  // 14: df.plugin.Action.set_food:type_name -> df.plugin.SetFoodAction
+	14,  // 15: df.plugin.Action.set_experience:type_name -> df.plugin.SetExperienceAction
+	15,  // 16: df.plugin.Action.set_velocity:type_name -> df.plugin.SetVelocityAction
+	16,  // 17: df.plugin.Action.add_effect:type_name -> df.plugin.AddEffectAction
+	17,  // 18: df.plugin.Action.remove_effect:type_name -> df.plugin.RemoveEffectAction
+	18,  // 19: df.plugin.Action.send_title:type_name -> df.plugin.SendTitleAction
+	19,  // 20: df.plugin.Action.send_popup:type_name -> df.plugin.SendPopupAction
+	20,  // 21: df.plugin.Action.send_tip:type_name -> df.plugin.SendTipAction
+	78,  // 22: df.plugin.Action.player_send_toast:type_name -> df.plugin.PlayerSendToastAction
+	79,  // 23: df.plugin.Action.player_send_jukebox_popup:type_name -> df.plugin.PlayerSendJukeboxPopupAction
+	80,  // 24: df.plugin.Action.player_show_coordinates:type_name -> df.plugin.PlayerShowCoordinatesAction
+	81,  // 25: df.plugin.Action.player_hide_coordinates:type_name -> df.plugin.PlayerHideCoordinatesAction
+	82,  // 26: df.plugin.Action.player_enable_instant_respawn:type_name -> df.plugin.PlayerEnableInstantRespawnAction
+	83,  // 27: df.plugin.Action.player_disable_instant_respawn:type_name -> df.plugin.PlayerDisableInstantRespawnAction
+	84,  // 28: df.plugin.Action.player_set_name_tag:type_name -> df.plugin.PlayerSetNameTagAction
+	85,  // 29: df.plugin.Action.player_set_score_tag:type_name -> df.plugin.PlayerSetScoreTagAction
+	21,  // 30: df.plugin.Action.play_sound:type_name -> df.plugin.PlaySoundAction
+	86,  // 31: df.plugin.Action.player_show_particle:type_name -> df.plugin.PlayerShowParticleAction
+	93,  // 32: df.plugin.Action.player_send_scoreboard:type_name -> df.plugin.PlayerSendScoreboardAction
+	94,  // 33: df.plugin.Action.player_remove_scoreboard:type_name -> df.plugin.PlayerRemoveScoreboardAction
+	95,  // 34: df.plugin.Action.player_send_menu_form:type_name -> df.plugin.PlayerSendMenuFormAction
+	96,  // 35: df.plugin.Action.player_send_modal_form:type_name -> df.plugin.PlayerSendModalFormAction
+	97,  // 36: df.plugin.Action.player_send_dialogue:type_name -> df.plugin.PlayerSendDialogueAction
+	102, // 37: df.plugin.Action.player_close_dialogue:type_name -> df.plugin.PlayerCloseDialogueAction
+	103, // 38: df.plugin.Action.player_close_form:type_name -> df.plugin.PlayerCloseFormAction
+	22,  // 39: df.plugin.Action.execute_command:type_name -> df.plugin.ExecuteCommandAction
+	54,  // 40: df.plugin.Action.player_start_sprinting:type_name -> df.plugin.PlayerStartSprintingAction
+	55,  // 41: df.plugin.Action.player_stop_sprinting:type_name -> df.plugin.PlayerStopSprintingAction
+	56,  // 42: df.plugin.Action.player_start_sneaking:type_name -> df.plugin.PlayerStartSneakingAction
+	57,  // 43: df.plugin.Action.player_stop_sneaking:type_name -> df.plugin.PlayerStopSneakingAction
+	58,  // 44: df.plugin.Action.player_start_swimming:type_name -> df.plugin.PlayerStartSwimmingAction
+	59,  // 45: df.plugin.Action.player_stop_swimming:type_name -> df.plugin.PlayerStopSwimmingAction
+	60,  // 46: df.plugin.Action.player_start_crawling:type_name -> df.plugin.PlayerStartCrawlingAction
+	61,  // 47: df.plugin.Action.player_stop_crawling:type_name -> df.plugin.PlayerStopCrawlingAction
+	62,  // 48: df.plugin.Action.player_start_gliding:type_name -> df.plugin.PlayerStartGlidingAction
+	63,  // 49: df.plugin.Action.player_stop_gliding:type_name -> df.plugin.PlayerStopGlidingAction
+	64,  // 50: df.plugin.Action.player_start_flying:type_name -> df.plugin.PlayerStartFlyingAction
+	65,  // 51: df.plugin.Action.player_stop_flying:type_name -> df.plugin.PlayerStopFlyingAction
+	66,  // 52: df.plugin.Action.player_set_immobile:type_name -> df.plugin.PlayerSetImmobileAction
+	67,  // 53: df.plugin.Action.player_set_mobile:type_name -> df.plugin.PlayerSetMobileAction
+	68,  // 54: df.plugin.Action.player_set_speed:type_name -> df.plugin.PlayerSetSpeedAction
+	69,  // 55: df.plugin.Action.player_set_flight_speed:type_name -> df.plugin.PlayerSetFlightSpeedAction
+	70,  // 56: df.plugin.Action.player_set_vertical_flight_speed:type_name -> df.plugin.PlayerSetVerticalFlightSpeedAction
+	71,  // 57: df.plugin.Action.player_set_absorption:type_name -> df.plugin.PlayerSetAbsorptionAction
+	72,  // 58: df.plugin.Action.player_set_on_fire:type_name -> df.plugin.PlayerSetOnFireAction
+	73,  // 59: df.plugin.Action.player_extinguish:type_name -> df.plugin.PlayerExtinguishAction
+	74,  // 60: df.plugin.Action.player_set_invisible:type_name -> df.plugin.PlayerSetInvisibleAction
+	75,  // 61: df.plugin.Action.player_set_visible:type_name -> df.plugin.PlayerSetVisibleAction
+	76,  // 62: df.plugin.Action.player_set_scale:type_name -> df.plugin.PlayerSetScaleAction
+	77,  // 63: df.plugin.Action.player_set_held_slot:type_name -> df.plugin.PlayerSetHeldSlotAction
+	87,  // 64: df.plugin.Action.player_respawn:type_name -> df.plugin.PlayerRespawnAction
+	88,  // 65: df.plugin.Action.player_transfer:type_name -> df.plugin.PlayerTransferAction
+	89,  // 66: df.plugin.Action.player_knock_back:type_name -> df.plugin.PlayerKnockBackAction
+	90,  // 67: df.plugin.Action.player_swing_arm:type_name -> df.plugin.PlayerSwingArmAction
+	91,  // 68: df.plugin.Action.player_punch_air:type_name -> df.plugin.PlayerPunchAirAction
+	98,  // 69: df.plugin.Action.player_send_boss_bar:type_name -> df.plugin.PlayerSendBossBarAction
+	99,  // 70: df.plugin.Action.player_remove_boss_bar:type_name -> df.plugin.PlayerRemoveBossBarAction
+	100, // 71: df.plugin.Action.player_show_hud_element:type_name -> df.plugin.PlayerShowHudElementAction
+	101, // 72: df.plugin.Action.player_hide_hud_element:type_name -> df.plugin.PlayerHideHudElementAction
+	104, // 73: df.plugin.Action.player_open_sign:type_name -> df.plugin.PlayerOpenSignAction
+	105, // 74: df.plugin.Action.player_edit_sign:type_name -> df.plugin.PlayerEditSignAction
+	106, // 75: df.plugin.Action.player_turn_lectern_page:type_name -> df.plugin.PlayerTurnLecternPageAction
+	107, // 76: df.plugin.Action.player_hide_player:type_name -> df.plugin.PlayerHidePlayerAction
+	108, // 77: df.plugin.Action.player_show_player:type_name -> df.plugin.PlayerShowPlayerAction
+	109, // 78: df.plugin.Action.player_remove_all_debug_shapes:type_name -> df.plugin.PlayerRemoveAllDebugShapesAction
+	23,  // 79: df.plugin.Action.world_set_default_game_mode:type_name -> df.plugin.WorldSetDefaultGameModeAction
+	24,  // 80: df.plugin.Action.world_set_difficulty:type_name -> df.plugin.WorldSetDifficultyAction
+	25,  // 81: df.plugin.Action.world_set_tick_range:type_name -> df.plugin.WorldSetTickRangeAction
+	26,  // 82: df.plugin.Action.world_set_block:type_name -> df.plugin.WorldSetBlockAction
+	27,  // 83: df.plugin.Action.world_play_sound:type_name -> df.plugin.WorldPlaySoundAction
+	28,  // 84: df.plugin.Action.world_add_particle:type_name -> df.plugin.WorldAddParticleAction
+	29,  // 85: df.plugin.Action.world_set_time:type_name -> df.plugin.WorldSetTimeAction
+	30,  // 86: df.plugin.Action.world_stop_time:type_name -> df.plugin.WorldStopTimeAction
+	31,  // 87: df.plugin.Action.world_start_time:type_name -> df.plugin.WorldStartTimeAction
+	32,  // 88: df.plugin.Action.world_set_spawn:type_name -> df.plugin.WorldSetSpawnAction
+	48,  // 89: df.plugin.Action.world_set_biome:type_name -> df.plugin.WorldSetBiomeAction
+	49,  // 90: df.plugin.Action.world_set_liquid:type_name -> df.plugin.WorldSetLiquidAction
+	50,  // 91: df.plugin.Action.world_schedule_block_update:type_name -> df.plugin.WorldScheduleBlockUpdateAction
+	53,  // 92: df.plugin.Action.world_build_structure:type_name -> df.plugin.WorldBuildStructureAction
+	35,  // 93: df.plugin.Action.world_query_entities:type_name -> df.plugin.WorldQueryEntitiesAction
+	36,  // 94: df.plugin.Action.world_query_players:type_name -> df.plugin.WorldQueryPlayersAction
+	37,  // 95: df.plugin.Action.world_query_entities_within:type_name -> df.plugin.WorldQueryEntitiesWithinAction
+	34,  // 96: df.plugin.Action.world_query_player_spawn:type_name -> df.plugin.WorldQueryPlayerSpawnAction
+	38,  // 97: df.plugin.Action.world_query_block:type_name -> df.plugin.WorldQueryBlockAction
+	39,  // 98: df.plugin.Action.world_query_biome:type_name -> df.plugin.WorldQueryBiomeAction
+	40,  // 99: df.plugin.Action.world_query_light:type_name -> df.plugin.WorldQueryLightAction
+	41,  // 100: df.plugin.Action.world_query_sky_light:type_name -> df.plugin.WorldQuerySkyLightAction
+	42,  // 101: df.plugin.Action.world_query_temperature:type_name -> df.plugin.WorldQueryTemperatureAction
+	43,  // 102: df.plugin.Action.world_query_highest_block:type_name -> df.plugin.WorldQueryHighestBlockAction
+	44,  // 103: df.plugin.Action.world_query_raining_at:type_name -> df.plugin.WorldQueryRainingAtAction
+	45,  // 104: df.plugin.Action.world_query_snowing_at:type_name -> df.plugin.WorldQuerySnowingAtAction
+	46,  // 105: df.plugin.Action.world_query_thundering_at:type_name -> df.plugin.WorldQueryThunderingAtAction
+	47,  // 106: df.plugin.Action.world_query_liquid:type_name -> df.plugin.WorldQueryLiquidAction
+	33,  // 107: df.plugin.Action.world_query_default_game_mode:type_name -> df.plugin.WorldQueryDefaultGameModeAction
+	114, // 108: df.plugin.TeleportAction.position:type_name -> df.plugin.Vec3
+	114, // 109: df.plugin.TeleportAction.rotation:type_name -> df.plugin.Vec3
+	115, // 110: df.plugin.SetGameModeAction.game_mode:type_name -> df.plugin.GameMode
+	116, // 111: df.plugin.GiveItemAction.item:type_name -> df.plugin.ItemStack
+	116, // 112: df.plugin.SetHeldItemAction.main:type_name -> df.plugin.ItemStack
+	116, // 113: df.plugin.SetHeldItemAction.offhand:type_name -> df.plugin.ItemStack
+	114, // 114: df.plugin.SetVelocityAction.velocity:type_name -> df.plugin.Vec3
+	117, // 115: df.plugin.AddEffectAction.effect_type:type_name -> df.plugin.EffectType
+	117, // 116: df.plugin.RemoveEffectAction.effect_type:type_name -> df.plugin.EffectType
+	118, // 117: df.plugin.PlaySoundAction.sound:type_name -> df.plugin.Sound
+	114, // 118: df.plugin.PlaySoundAction.position:type_name -> df.plugin.Vec3
+	119, // 119: df.plugin.WorldSetDefaultGameModeAction.world:type_name -> df.plugin.WorldRef
+	115, // 120: df.plugin.WorldSetDefaultGameModeAction.game_mode:type_name -> df.plugin.GameMode
+	119, // 121: df.plugin.WorldSetDifficultyAction.world:type_name -> df.plugin.WorldRef
+	120, // 122: df.plugin.WorldSetDifficultyAction.difficulty:type_name -> df.plugin.Difficulty
+	119, // 123: df.plugin.WorldSetTickRangeAction.world:type_name -> df.plugin.WorldRef
+	119, // 124: df.plugin.WorldSetBlockAction.world:type_name -> df.plugin.WorldRef
+	121, // 125: df.plugin.WorldSetBlockAction.position:type_name -> df.plugin.BlockPos
+	122, // 126: df.plugin.WorldSetBlockAction.block:type_name -> df.plugin.BlockState
+	119, // 127: df.plugin.WorldPlaySoundAction.world:type_name -> df.plugin.WorldRef
+	118, // 128: df.plugin.WorldPlaySoundAction.sound:type_name -> df.plugin.Sound
+	114, // 129: df.plugin.WorldPlaySoundAction.position:type_name -> df.plugin.Vec3
+	119, // 130: df.plugin.WorldAddParticleAction.world:type_name -> df.plugin.WorldRef
+	114, // 131: df.plugin.WorldAddParticleAction.position:type_name -> df.plugin.Vec3
+	0,   // 132: df.plugin.WorldAddParticleAction.particle:type_name -> df.plugin.ParticleType
+	122, // 133: df.plugin.WorldAddParticleAction.block:type_name -> df.plugin.BlockState
+	119, // 134: df.plugin.WorldSetTimeAction.world:type_name -> df.plugin.WorldRef
+	119, // 135: df.plugin.WorldStopTimeAction.world:type_name -> df.plugin.WorldRef
+	119, // 136: df.plugin.WorldStartTimeAction.world:type_name -> df.plugin.WorldRef
+	119, // 137: df.plugin.WorldSetSpawnAction.world:type_name -> df.plugin.WorldRef
+	121, // 138: df.plugin.WorldSetSpawnAction.spawn:type_name -> df.plugin.BlockPos
+	119, // 139: df.plugin.WorldQueryDefaultGameModeAction.world:type_name -> df.plugin.WorldRef
+	119, // 140: df.plugin.WorldQueryPlayerSpawnAction.world:type_name -> df.plugin.WorldRef
+	119, // 141: df.plugin.WorldQueryEntitiesAction.world:type_name -> df.plugin.WorldRef
+	119, // 142: df.plugin.WorldQueryPlayersAction.world:type_name -> df.plugin.WorldRef
+	119, // 143: df.plugin.WorldQueryEntitiesWithinAction.world:type_name -> df.plugin.WorldRef
+	123, // 144: df.plugin.WorldQueryEntitiesWithinAction.box:type_name -> df.plugin.BBox
+	119, // 145: df.plugin.WorldQueryBlockAction.world:type_name -> df.plugin.WorldRef
+	121, // 146: df.plugin.WorldQueryBlockAction.position:type_name -> df.plugin.BlockPos
+	119, // 147: df.plugin.WorldQueryBiomeAction.world:type_name -> df.plugin.WorldRef
+	121, // 148: df.plugin.WorldQueryBiomeAction.position:type_name -> df.plugin.BlockPos
+	119, // 149: df.plugin.WorldQueryLightAction.world:type_name -> df.plugin.WorldRef
+	121, // 150: df.plugin.WorldQueryLightAction.position:type_name -> df.plugin.BlockPos
+	119, // 151: df.plugin.WorldQuerySkyLightAction.world:type_name -> df.plugin.WorldRef
+	121, // 152: df.plugin.WorldQuerySkyLightAction.position:type_name -> df.plugin.BlockPos
+	119, // 153: df.plugin.WorldQueryTemperatureAction.world:type_name -> df.plugin.WorldRef
+	121, // 154: df.plugin.WorldQueryTemperatureAction.position:type_name -> df.plugin.BlockPos
+	119, // 155: df.plugin.WorldQueryHighestBlockAction.world:type_name -> df.plugin.WorldRef
+	119, // 156: df.plugin.WorldQueryRainingAtAction.world:type_name -> df.plugin.WorldRef
+	121, // 157: df.plugin.WorldQueryRainingAtAction.position:type_name -> df.plugin.BlockPos
+	119, // 158: df.plugin.WorldQuerySnowingAtAction.world:type_name -> df.plugin.WorldRef
+	121, // 159: df.plugin.WorldQuerySnowingAtAction.position:type_name -> df.plugin.BlockPos
+	119, // 160: df.plugin.WorldQueryThunderingAtAction.world:type_name -> df.plugin.WorldRef
+	121, // 161: df.plugin.WorldQueryThunderingAtAction.position:type_name -> df.plugin.BlockPos
+	119, // 162: df.plugin.WorldQueryLiquidAction.world:type_name -> df.plugin.WorldRef
+	121, // 163: df.plugin.WorldQueryLiquidAction.position:type_name -> df.plugin.BlockPos
+	119, // 164: df.plugin.WorldSetBiomeAction.world:type_name -> df.plugin.WorldRef
+	121, // 165: df.plugin.WorldSetBiomeAction.position:type_name -> df.plugin.BlockPos
+	119, // 166: df.plugin.WorldSetLiquidAction.world:type_name -> df.plugin.WorldRef
+	121, // 167: df.plugin.WorldSetLiquidAction.position:type_name -> df.plugin.BlockPos
+	124, // 168: df.plugin.WorldSetLiquidAction.liquid:type_name -> df.plugin.LiquidState
+	119, // 169: df.plugin.WorldScheduleBlockUpdateAction.world:type_name -> df.plugin.WorldRef
+	121, // 170: df.plugin.WorldScheduleBlockUpdateAction.position:type_name -> df.plugin.BlockPos
+	122, // 171: df.plugin.WorldScheduleBlockUpdateAction.block:type_name -> df.plugin.BlockState
+	122, // 172: df.plugin.StructureVoxel.block:type_name -> df.plugin.BlockState
+	124, // 173: df.plugin.StructureVoxel.liquid:type_name -> df.plugin.LiquidState
+	51,  // 174: df.plugin.StructureDef.voxels:type_name -> df.plugin.StructureVoxel
+	119, // 175: df.plugin.WorldBuildStructureAction.world:type_name -> df.plugin.WorldRef
+	121, // 176: df.plugin.WorldBuildStructureAction.origin:type_name -> df.plugin.BlockPos
+	52,  // 177: df.plugin.WorldBuildStructureAction.structure:type_name -> df.plugin.StructureDef
+	114, // 178: df.plugin.PlayerShowParticleAction.position:type_name -> df.plugin.Vec3
+	0,   // 179: df.plugin.PlayerShowParticleAction.particle:type_name -> df.plugin.ParticleType
+	122, // 180: df.plugin.PlayerShowParticleAction.block:type_name -> df.plugin.BlockState
+	125, // 181: df.plugin.PlayerTransferAction.address:type_name -> df.plugin.Address
+	114, // 182: df.plugin.PlayerKnockBackAction.source:type_name -> df.plugin.Vec3
+	116, // 183: df.plugin.PlayerSetArmourAction.helmet:type_name -> df.plugin.ItemStack
+	116, // 184: df.plugin.PlayerSetArmourAction.chestplate:type_name -> df.plugin.ItemStack
+	116, // 185: df.plugin.PlayerSetArmourAction.leggings:type_name -> df.plugin.ItemStack
+	116, // 186: df.plugin.PlayerSetArmourAction.boots:type_name -> df.plugin.ItemStack
+	126, // 187: df.plugin.PlayerSendDialogueAction.entity:type_name -> df.plugin.EntityRef
+	1,   // 188: df.plugin.PlayerSendBossBarAction.colour:type_name -> df.plugin.BossBarColour
+	2,   // 189: df.plugin.PlayerShowHudElementAction.element:type_name -> df.plugin.HudElement
+	2,   // 190: df.plugin.PlayerHideHudElementAction.element:type_name -> df.plugin.HudElement
+	121, // 191: df.plugin.PlayerOpenSignAction.position:type_name -> df.plugin.BlockPos
+	121, // 192: df.plugin.PlayerEditSignAction.position:type_name -> df.plugin.BlockPos
+	121, // 193: df.plugin.PlayerTurnLecternPageAction.position:type_name -> df.plugin.BlockPos
+	121, // 194: df.plugin.PlayerOpenBlockContainerAction.position:type_name -> df.plugin.BlockPos
+	116, // 195: df.plugin.PlayerDropItemAction.item:type_name -> df.plugin.ItemStack
+	116, // 196: df.plugin.PlayerSetItemCooldownAction.item:type_name -> df.plugin.ItemStack
+	197, // [197:197] is the sub-list for method output_type
+	197, // [197:197] is the sub-list for method input_type
+	197, // [197:197] is the sub-list for extension type_name
+	197, // [197:197] is the sub-list for extension extendee
+	0,   // [0:197] is the sub-list for field type_name
 }
 
 func init() { file_actions_proto_init() }
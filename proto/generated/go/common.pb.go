@@ -1676,6 +1676,59 @@ func (x *CustomBlockPermutation) GetProperties() *CustomBlockProperties {
 	return nil
 }
 
+// TraceContext carries W3C Trace Context headers so a trace can continue across the host and plugins.
+type TraceContext struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Traceparent   string                 `protobuf:"bytes,1,opt,name=traceparent,proto3" json:"traceparent,omitempty"`
+	Tracestate    string                 `protobuf:"bytes,2,opt,name=tracestate,proto3" json:"tracestate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceContext) Reset() {
+	*x = TraceContext{}
+	mi := &file_common_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceContext) ProtoMessage() {}
+
+func (x *TraceContext) ProtoReflect() protoreflect.Message {
+	mi := &file_common_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceContext.ProtoReflect.Descriptor instead.
+func (*TraceContext) Descriptor() ([]byte, []int) {
+	return file_common_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TraceContext) GetTraceparent() string {
+	if x != nil {
+		return x.Traceparent
+	}
+	return ""
+}
+
+func (x *TraceContext) GetTracestate() string {
+	if x != nil {
+		return x.Tracestate
+	}
+	return ""
+}
+
 var File_common_proto protoreflect.FileDescriptor
 
 const file_common_proto_rawDesc = "" +
@@ -1796,7 +1849,12 @@ const file_common_proto_rawDesc = "" +
 	"\tcondition\x18\x01 \x01(\tR\tcondition\x12@\n" +
 	"\n" +
 	"properties\x18\x02 \x01(\v2 .df.plugin.CustomBlockPropertiesR\n" +
-	"properties*D\n" +
+	"properties\"P\n" +
+	"\fTraceContext\x12 \n" +
+	"\vtraceparent\x18\x01 \x01(\tR\vtraceparent\x12\x1e\n" +
+	"\n" +
+	"tracestate\x18\x02 \x01(\tR\n" +
+	"tracestate*D\n" +
 	"\bGameMode\x12\f\n" +
 	"\bSURVIVAL\x10\x00\x12\f\n" +
 	"\bCREATIVE\x10\x01\x12\r\n" +
@@ -1903,7 +1961,7 @@ func file_common_proto_rawDescGZIP() []byte {
 }
 
 var file_common_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_common_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_common_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_common_proto_goTypes = []any{
 	(GameMode)(0),                  // 0: df.plugin.GameMode
 	(Difficulty)(0),                // 1: df.plugin.Difficulty
@@ -1930,13 +1988,14 @@ var file_common_proto_goTypes = []any{
 	(*CustomBlockDefinition)(nil),  // 22: df.plugin.CustomBlockDefinition
 	(*CustomBlockStateValues)(nil), // 23: df.plugin.CustomBlockStateValues
 	(*CustomBlockPermutation)(nil), // 24: df.plugin.CustomBlockPermutation
-	nil,                            // 25: df.plugin.BlockState.PropertiesEntry
-	nil,                            // 26: df.plugin.CustomBlockProperties.StatesEntry
+	(*TraceContext)(nil),           // 25: df.plugin.TraceContext
+	nil,                            // 26: df.plugin.BlockState.PropertiesEntry
+	nil,                            // 27: df.plugin.CustomBlockProperties.StatesEntry
 }
 var file_common_proto_depIdxs = []int32{
 	6,  // 0: df.plugin.BBox.min:type_name -> df.plugin.Vec3
 	6,  // 1: df.plugin.BBox.max:type_name -> df.plugin.Vec3
-	25, // 2: df.plugin.BlockState.properties:type_name -> df.plugin.BlockState.PropertiesEntry
+	26, // 2: df.plugin.BlockState.properties:type_name -> df.plugin.BlockState.PropertiesEntry
 	11, // 3: df.plugin.LiquidState.block:type_name -> df.plugin.BlockState
 	6,  // 4: df.plugin.EntityRef.position:type_name -> df.plugin.Vec3
 	7,  // 5: df.plugin.EntityRef.rotation:type_name -> df.plugin.Rotation
@@ -1948,7 +2007,7 @@ var file_common_proto_depIdxs = []int32{
 	6,  // 11: df.plugin.CustomBlockProperties.translation:type_name -> df.plugin.Vec3
 	6,  // 12: df.plugin.CustomBlockProperties.scale:type_name -> df.plugin.Vec3
 	20, // 13: df.plugin.CustomBlockProperties.materials:type_name -> df.plugin.CustomBlockMaterial
-	26, // 14: df.plugin.CustomBlockProperties.states:type_name -> df.plugin.CustomBlockProperties.StatesEntry
+	27, // 14: df.plugin.CustomBlockProperties.states:type_name -> df.plugin.CustomBlockProperties.StatesEntry
 	24, // 15: df.plugin.CustomBlockProperties.permutations:type_name -> df.plugin.CustomBlockPermutation
 	19, // 16: df.plugin.CustomBlockDefinition.textures:type_name -> df.plugin.CustomBlockTexture
 	21, // 17: df.plugin.CustomBlockDefinition.properties:type_name -> df.plugin.CustomBlockProperties
@@ -1979,7 +2038,7 @@ func file_common_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_proto_rawDesc), len(file_common_proto_rawDesc)),
 			NumEnums:      6,
-			NumMessages:   21,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
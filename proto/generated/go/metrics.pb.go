@@ -0,0 +1,411 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: metrics.proto
+
+package generated
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MetricKind int32
+
+const (
+	MetricKind_METRIC_KIND_UNSPECIFIED MetricKind = 0
+	MetricKind_METRIC_KIND_COUNTER     MetricKind = 1
+	MetricKind_METRIC_KIND_GAUGE       MetricKind = 2
+	MetricKind_METRIC_KIND_HISTOGRAM   MetricKind = 3
+)
+
+// Enum value maps for MetricKind.
+var (
+	MetricKind_name = map[int32]string{
+		0: "METRIC_KIND_UNSPECIFIED",
+		1: "METRIC_KIND_COUNTER",
+		2: "METRIC_KIND_GAUGE",
+		3: "METRIC_KIND_HISTOGRAM",
+	}
+	MetricKind_value = map[string]int32{
+		"METRIC_KIND_UNSPECIFIED": 0,
+		"METRIC_KIND_COUNTER":     1,
+		"METRIC_KIND_GAUGE":       2,
+		"METRIC_KIND_HISTOGRAM":   3,
+	}
+)
+
+func (x MetricKind) Enum() *MetricKind {
+	p := new(MetricKind)
+	*p = x
+	return p
+}
+
+func (x MetricKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MetricKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_metrics_proto_enumTypes[0].Descriptor()
+}
+
+func (MetricKind) Type() protoreflect.EnumType {
+	return &file_metrics_proto_enumTypes[0]
+}
+
+func (x MetricKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MetricKind.Descriptor instead.
+func (MetricKind) EnumDescriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{0}
+}
+
+// PluginMetrics registers custom metrics and pushes updates to them. The host exports them on its
+// Prometheus endpoint as dragonfly_custom_<plugin_id>_<name>. Definitions are processed before
+// updates, so a single message may define a metric and update it.
+type PluginMetrics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Definitions   []*MetricDefinition    `protobuf:"bytes,1,rep,name=definitions,proto3" json:"definitions,omitempty"`
+	Updates       []*MetricUpdate        `protobuf:"bytes,2,rep,name=updates,proto3" json:"updates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginMetrics) Reset() {
+	*x = PluginMetrics{}
+	mi := &file_metrics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginMetrics) ProtoMessage() {}
+
+func (x *PluginMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginMetrics.ProtoReflect.Descriptor instead.
+func (*PluginMetrics) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PluginMetrics) GetDefinitions() []*MetricDefinition {
+	if x != nil {
+		return x.Definitions
+	}
+	return nil
+}
+
+func (x *PluginMetrics) GetUpdates() []*MetricUpdate {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+type MetricDefinition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // [a-zA-Z_][a-zA-Z0-9_]*, unique per plugin
+	Kind          MetricKind             `protobuf:"varint,2,opt,name=kind,proto3,enum=df.plugin.MetricKind" json:"kind,omitempty"`
+	Help          string                 `protobuf:"bytes,3,opt,name=help,proto3" json:"help,omitempty"`
+	LabelNames    []string               `protobuf:"bytes,4,rep,name=label_names,json=labelNames,proto3" json:"label_names,omitempty"`
+	Buckets       []float64              `protobuf:"fixed64,5,rep,packed,name=buckets,proto3" json:"buckets,omitempty"` // Histogram upper bounds in increasing order; empty uses the Prometheus defaults.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricDefinition) Reset() {
+	*x = MetricDefinition{}
+	mi := &file_metrics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricDefinition) ProtoMessage() {}
+
+func (x *MetricDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricDefinition.ProtoReflect.Descriptor instead.
+func (*MetricDefinition) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MetricDefinition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetricDefinition) GetKind() MetricKind {
+	if x != nil {
+		return x.Kind
+	}
+	return MetricKind_METRIC_KIND_UNSPECIFIED
+}
+
+func (x *MetricDefinition) GetHelp() string {
+	if x != nil {
+		return x.Help
+	}
+	return ""
+}
+
+func (x *MetricDefinition) GetLabelNames() []string {
+	if x != nil {
+		return x.LabelNames
+	}
+	return nil
+}
+
+func (x *MetricDefinition) GetBuckets() []float64 {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type MetricUpdate struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	LabelValues []string               `protobuf:"bytes,2,rep,name=label_values,json=labelValues,proto3" json:"label_values,omitempty"` // In the order of the definition's label_names.
+	// Types that are valid to be assigned to Op:
+	//
+	//	*MetricUpdate_Add
+	//	*MetricUpdate_Set
+	//	*MetricUpdate_Observe
+	Op            isMetricUpdate_Op `protobuf_oneof:"op"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricUpdate) Reset() {
+	*x = MetricUpdate{}
+	mi := &file_metrics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricUpdate) ProtoMessage() {}
+
+func (x *MetricUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricUpdate.ProtoReflect.Descriptor instead.
+func (*MetricUpdate) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MetricUpdate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetricUpdate) GetLabelValues() []string {
+	if x != nil {
+		return x.LabelValues
+	}
+	return nil
+}
+
+func (x *MetricUpdate) GetOp() isMetricUpdate_Op {
+	if x != nil {
+		return x.Op
+	}
+	return nil
+}
+
+func (x *MetricUpdate) GetAdd() float64 {
+	if x != nil {
+		if x, ok := x.Op.(*MetricUpdate_Add); ok {
+			return x.Add
+		}
+	}
+	return 0
+}
+
+func (x *MetricUpdate) GetSet() float64 {
+	if x != nil {
+		if x, ok := x.Op.(*MetricUpdate_Set); ok {
+			return x.Set
+		}
+	}
+	return 0
+}
+
+func (x *MetricUpdate) GetObserve() float64 {
+	if x != nil {
+		if x, ok := x.Op.(*MetricUpdate_Observe); ok {
+			return x.Observe
+		}
+	}
+	return 0
+}
+
+type isMetricUpdate_Op interface {
+	isMetricUpdate_Op()
+}
+
+type MetricUpdate_Add struct {
+	Add float64 `protobuf:"fixed64,3,opt,name=add,proto3,oneof"` // Counters (non-negative) and gauges.
+}
+
+type MetricUpdate_Set struct {
+	Set float64 `protobuf:"fixed64,4,opt,name=set,proto3,oneof"` // Gauges only.
+}
+
+type MetricUpdate_Observe struct {
+	Observe float64 `protobuf:"fixed64,5,opt,name=observe,proto3,oneof"` // Histograms only.
+}
+
+func (*MetricUpdate_Add) isMetricUpdate_Op() {}
+
+func (*MetricUpdate_Set) isMetricUpdate_Op() {}
+
+func (*MetricUpdate_Observe) isMetricUpdate_Op() {}
+
+var File_metrics_proto protoreflect.FileDescriptor
+
+const file_metrics_proto_rawDesc = "" +
+	"\n" +
+	"\rmetrics.proto\x12\tdf.plugin\"\x81\x01\n" +
+	"\rPluginMetrics\x12=\n" +
+	"\vdefinitions\x18\x01 \x03(\v2\x1b.df.plugin.MetricDefinitionR\vdefinitions\x121\n" +
+	"\aupdates\x18\x02 \x03(\v2\x17.df.plugin.MetricUpdateR\aupdates\"\xa0\x01\n" +
+	"\x10MetricDefinition\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12)\n" +
+	"\x04kind\x18\x02 \x01(\x0e2\x15.df.plugin.MetricKindR\x04kind\x12\x12\n" +
+	"\x04help\x18\x03 \x01(\tR\x04help\x12\x1f\n" +
+	"\vlabel_names\x18\x04 \x03(\tR\n" +
+	"labelNames\x12\x18\n" +
+	"\abuckets\x18\x05 \x03(\x01R\abuckets\"\x8f\x01\n" +
+	"\fMetricUpdate\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\flabel_values\x18\x02 \x03(\tR\vlabelValues\x12\x12\n" +
+	"\x03add\x18\x03 \x01(\x01H\x00R\x03add\x12\x12\n" +
+	"\x03set\x18\x04 \x01(\x01H\x00R\x03set\x12\x1a\n" +
+	"\aobserve\x18\x05 \x01(\x01H\x00R\aobserveB\x04\n" +
+	"\x02op*t\n" +
+	"\n" +
+	"MetricKind\x12\x1b\n" +
+	"\x17METRIC_KIND_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13METRIC_KIND_COUNTER\x10\x01\x12\x15\n" +
+	"\x11METRIC_KIND_GAUGE\x10\x02\x12\x19\n" +
+	"\x15METRIC_KIND_HISTOGRAM\x10\x03B\x8b\x01\n" +
+	"\rcom.df.pluginB\fMetricsProtoP\x01Z'github.com/secmc/plugin/proto/generated\xa2\x02\x03DPX\xaa\x02\tDf.Plugin\xca\x02\tDf\\Plugin\xe2\x02\x15Df\\Plugin\\GPBMetadata\xea\x02\n" +
+	"Df::Pluginb\x06proto3"
+
+var (
+	file_metrics_proto_rawDescOnce sync.Once
+	file_metrics_proto_rawDescData []byte
+)
+
+func file_metrics_proto_rawDescGZIP() []byte {
+	file_metrics_proto_rawDescOnce.Do(func() {
+		file_metrics_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_metrics_proto_rawDesc), len(file_metrics_proto_rawDesc)))
+	})
+	return file_metrics_proto_rawDescData
+}
+
+var file_metrics_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_metrics_proto_goTypes = []any{
+	(MetricKind)(0),          // 0: df.plugin.MetricKind
+	(*PluginMetrics)(nil),    // 1: df.plugin.PluginMetrics
+	(*MetricDefinition)(nil), // 2: df.plugin.MetricDefinition
+	(*MetricUpdate)(nil),     // 3: df.plugin.MetricUpdate
+}
+var file_metrics_proto_depIdxs = []int32{
+	2, // 0: df.plugin.PluginMetrics.definitions:type_name -> df.plugin.MetricDefinition
+	3, // 1: df.plugin.PluginMetrics.updates:type_name -> df.plugin.MetricUpdate
+	0, // 2: df.plugin.MetricDefinition.kind:type_name -> df.plugin.MetricKind
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_metrics_proto_init() }
+func file_metrics_proto_init() {
+	if File_metrics_proto != nil {
+		return
+	}
+	file_metrics_proto_msgTypes[2].OneofWrappers = []any{
+		(*MetricUpdate_Add)(nil),
+		(*MetricUpdate_Set)(nil),
+		(*MetricUpdate_Observe)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_metrics_proto_rawDesc), len(file_metrics_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_metrics_proto_goTypes,
+		DependencyIndexes: file_metrics_proto_depIdxs,
+		EnumInfos:         file_metrics_proto_enumTypes,
+		MessageInfos:      file_metrics_proto_msgTypes,
+	}.Build()
+	File_metrics_proto = out.File
+	file_metrics_proto_goTypes = nil
+	file_metrics_proto_depIdxs = nil
+}
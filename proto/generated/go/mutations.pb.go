@@ -25,6 +25,7 @@ type EventResult struct {
 	state   protoimpl.MessageState `protogen:"open.v1"`
 	EventId string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
 	Cancel  *bool                  `protobuf:"varint,2,opt,name=cancel,proto3,oneof" json:"cancel,omitempty"`
+	Trace   *TraceContext          `protobuf:"bytes,3,opt,name=trace,proto3" json:"trace,omitempty"` // The plugin's span for this event; mutation spans are parented to it.
 	// Types that are valid to be assigned to Update:
 	//
 	//	*EventResult_Chat
@@ -89,6 +90,13 @@ func (x *EventResult) GetCancel() bool {
 	return false
 }
 
+func (x *EventResult) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
 func (x *EventResult) GetUpdate() isEventResult_Update {
 	if x != nil {
 		return x.Update
@@ -1068,10 +1076,11 @@ var File_mutations_proto protoreflect.FileDescriptor
 
 const file_mutations_proto_rawDesc = "" +
 	"\n" +
-	"\x0fmutations.proto\x12\tdf.plugin\x1a\ractions.proto\x1a\fcommon.proto\"\xc0\b\n" +
+	"\x0fmutations.proto\x12\tdf.plugin\x1a\ractions.proto\x1a\fcommon.proto\"\xef\b\n" +
 	"\vEventResult\x12\x19\n" +
 	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1b\n" +
 	"\x06cancel\x18\x02 \x01(\bH\x01R\x06cancel\x88\x01\x01\x12-\n" +
+	"\x05trace\x18\x03 \x01(\v2\x17.df.plugin.TraceContextR\x05trace\x12-\n" +
 	"\x04chat\x18\n" +
 	" \x01(\v2\x17.df.plugin.ChatMutationH\x00R\x04chat\x12@\n" +
 	"\vblock_break\x18\v \x01(\v2\x1d.df.plugin.BlockBreakMutationH\x00R\n" +
@@ -1190,40 +1199,42 @@ var file_mutations_proto_goTypes = []any{
 	(*PlayerItemPickupMutation)(nil),      // 14: df.plugin.PlayerItemPickupMutation
 	(*PlayerTransferMutation)(nil),        // 15: df.plugin.PlayerTransferMutation
 	(*WorldExplosionMutation)(nil),        // 16: df.plugin.WorldExplosionMutation
-	(*ItemStack)(nil),                     // 17: df.plugin.ItemStack
-	(*BlockPos)(nil),                      // 18: df.plugin.BlockPos
-	(*Vec3)(nil),                          // 19: df.plugin.Vec3
-	(*WorldRef)(nil),                      // 20: df.plugin.WorldRef
-	(*Address)(nil),                       // 21: df.plugin.Address
+	(*TraceContext)(nil),                  // 17: df.plugin.TraceContext
+	(*ItemStack)(nil),                     // 18: df.plugin.ItemStack
+	(*BlockPos)(nil),                      // 19: df.plugin.BlockPos
+	(*Vec3)(nil),                          // 20: df.plugin.Vec3
+	(*WorldRef)(nil),                      // 21: df.plugin.WorldRef
+	(*Address)(nil),                       // 22: df.plugin.Address
 }
 var file_mutations_proto_depIdxs = []int32{
-	4,  // 0: df.plugin.EventResult.chat:type_name -> df.plugin.ChatMutation
-	5,  // 1: df.plugin.EventResult.block_break:type_name -> df.plugin.BlockBreakMutation
-	6,  // 2: df.plugin.EventResult.player_food_loss:type_name -> df.plugin.PlayerFoodLossMutation
-	7,  // 3: df.plugin.EventResult.player_heal:type_name -> df.plugin.PlayerHealMutation
-	8,  // 4: df.plugin.EventResult.player_hurt:type_name -> df.plugin.PlayerHurtMutation
-	9,  // 5: df.plugin.EventResult.player_death:type_name -> df.plugin.PlayerDeathMutation
-	10, // 6: df.plugin.EventResult.player_respawn:type_name -> df.plugin.PlayerRespawnMutation
-	11, // 7: df.plugin.EventResult.player_attack_entity:type_name -> df.plugin.PlayerAttackEntityMutation
-	12, // 8: df.plugin.EventResult.player_experience_gain:type_name -> df.plugin.PlayerExperienceGainMutation
-	13, // 9: df.plugin.EventResult.player_lectern_page_turn:type_name -> df.plugin.PlayerLecternPageTurnMutation
-	14, // 10: df.plugin.EventResult.player_item_pickup:type_name -> df.plugin.PlayerItemPickupMutation
-	15, // 11: df.plugin.EventResult.player_transfer:type_name -> df.plugin.PlayerTransferMutation
-	16, // 12: df.plugin.EventResult.world_explosion:type_name -> df.plugin.WorldExplosionMutation
-	17, // 13: df.plugin.ItemStackList.items:type_name -> df.plugin.ItemStack
-	18, // 14: df.plugin.BlockPosList.positions:type_name -> df.plugin.BlockPos
-	1,  // 15: df.plugin.BlockBreakMutation.drops:type_name -> df.plugin.ItemStackList
-	19, // 16: df.plugin.PlayerRespawnMutation.position:type_name -> df.plugin.Vec3
-	20, // 17: df.plugin.PlayerRespawnMutation.world:type_name -> df.plugin.WorldRef
-	17, // 18: df.plugin.PlayerItemPickupMutation.item:type_name -> df.plugin.ItemStack
-	21, // 19: df.plugin.PlayerTransferMutation.address:type_name -> df.plugin.Address
-	2,  // 20: df.plugin.WorldExplosionMutation.entity_uuids:type_name -> df.plugin.StringList
-	3,  // 21: df.plugin.WorldExplosionMutation.blocks:type_name -> df.plugin.BlockPosList
-	22, // [22:22] is the sub-list for method output_type
-	22, // [22:22] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	17, // 0: df.plugin.EventResult.trace:type_name -> df.plugin.TraceContext
+	4,  // 1: df.plugin.EventResult.chat:type_name -> df.plugin.ChatMutation
+	5,  // 2: df.plugin.EventResult.block_break:type_name -> df.plugin.BlockBreakMutation
+	6,  // 3: df.plugin.EventResult.player_food_loss:type_name -> df.plugin.PlayerFoodLossMutation
+	7,  // 4: df.plugin.EventResult.player_heal:type_name -> df.plugin.PlayerHealMutation
+	8,  // 5: df.plugin.EventResult.player_hurt:type_name -> df.plugin.PlayerHurtMutation
+	9,  // 6: df.plugin.EventResult.player_death:type_name -> df.plugin.PlayerDeathMutation
+	10, // 7: df.plugin.EventResult.player_respawn:type_name -> df.plugin.PlayerRespawnMutation
+	11, // 8: df.plugin.EventResult.player_attack_entity:type_name -> df.plugin.PlayerAttackEntityMutation
+	12, // 9: df.plugin.EventResult.player_experience_gain:type_name -> df.plugin.PlayerExperienceGainMutation
+	13, // 10: df.plugin.EventResult.player_lectern_page_turn:type_name -> df.plugin.PlayerLecternPageTurnMutation
+	14, // 11: df.plugin.EventResult.player_item_pickup:type_name -> df.plugin.PlayerItemPickupMutation
+	15, // 12: df.plugin.EventResult.player_transfer:type_name -> df.plugin.PlayerTransferMutation
+	16, // 13: df.plugin.EventResult.world_explosion:type_name -> df.plugin.WorldExplosionMutation
+	18, // 14: df.plugin.ItemStackList.items:type_name -> df.plugin.ItemStack
+	19, // 15: df.plugin.BlockPosList.positions:type_name -> df.plugin.BlockPos
+	1,  // 16: df.plugin.BlockBreakMutation.drops:type_name -> df.plugin.ItemStackList
+	20, // 17: df.plugin.PlayerRespawnMutation.position:type_name -> df.plugin.Vec3
+	21, // 18: df.plugin.PlayerRespawnMutation.world:type_name -> df.plugin.WorldRef
+	18, // 19: df.plugin.PlayerItemPickupMutation.item:type_name -> df.plugin.ItemStack
+	22, // 20: df.plugin.PlayerTransferMutation.address:type_name -> df.plugin.Address
+	2,  // 21: df.plugin.WorldExplosionMutation.entity_uuids:type_name -> df.plugin.StringList
+	3,  // 22: df.plugin.WorldExplosionMutation.blocks:type_name -> df.plugin.BlockPosList
+	23, // [23:23] is the sub-list for method output_type
+	23, // [23:23] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_mutations_proto_init() }
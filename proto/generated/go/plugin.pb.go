@@ -75,6 +75,7 @@ const (
 	EventType_WORLD_ENTITY_DESPAWN      EventType = 79
 	EventType_WORLD_EXPLOSION           EventType = 80
 	EventType_WORLD_CLOSE               EventType = 81
+	EventType_PLUGIN_CRASH              EventType = 90
 )
 
 // Enum value maps for EventType.
@@ -131,6 +132,7 @@ var (
 		79: "WORLD_ENTITY_DESPAWN",
 		80: "WORLD_EXPLOSION",
 		81: "WORLD_CLOSE",
+		90: "PLUGIN_CRASH",
 	}
 	EventType_value = map[string]int32{
 		"EVENT_TYPE_UNSPECIFIED":    0,
@@ -184,6 +186,7 @@ var (
 		"WORLD_ENTITY_DESPAWN":      79,
 		"WORLD_EXPLOSION":           80,
 		"WORLD_CLOSE":               81,
+		"PLUGIN_CRASH":              90,
 	}
 )
 
@@ -222,11 +225,13 @@ type HostToPlugin struct {
 	//	*HostToPlugin_Hello
 	//	*HostToPlugin_Shutdown
 	//	*HostToPlugin_ServerInfo
+	//	*HostToPlugin_Ping
 	//	*HostToPlugin_Event
 	//	*HostToPlugin_ActionResult
 	//	*HostToPlugin_Events
 	//	*HostToPlugin_CompressedEvents
-	//	*HostToPlugin_PlayerMovementsPacked
+	//	*HostToPlugin_EventGap
+	//	*HostToPlugin_Registry
 	Payload       isHostToPlugin_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -303,6 +308,15 @@ func (x *HostToPlugin) GetServerInfo() *ServerInformationResponse {
 	return nil
 }
 
+func (x *HostToPlugin) GetPing() *HostPing {
+	if x != nil {
+		if x, ok := x.Payload.(*HostToPlugin_Ping); ok {
+			return x.Ping
+		}
+	}
+	return nil
+}
+
 func (x *HostToPlugin) GetEvent() *EventEnvelope {
 	if x != nil {
 		if x, ok := x.Payload.(*HostToPlugin_Event); ok {
@@ -339,10 +353,19 @@ func (x *HostToPlugin) GetCompressedEvents() *CompressedEventBatch {
 	return nil
 }
 
-func (x *HostToPlugin) GetPlayerMovementsPacked() *PlayerMovementsPacked {
+func (x *HostToPlugin) GetEventGap() *EventGap {
+	if x != nil {
+		if x, ok := x.Payload.(*HostToPlugin_EventGap); ok {
+			return x.EventGap
+		}
+	}
+	return nil
+}
+
+func (x *HostToPlugin) GetRegistry() *Registry {
 	if x != nil {
-		if x, ok := x.Payload.(*HostToPlugin_PlayerMovementsPacked); ok {
-			return x.PlayerMovementsPacked
+		if x, ok := x.Payload.(*HostToPlugin_Registry); ok {
+			return x.Registry
 		}
 	}
 	return nil
@@ -364,6 +387,10 @@ type HostToPlugin_ServerInfo struct {
 	ServerInfo *ServerInformationResponse `protobuf:"bytes,12,opt,name=server_info,json=serverInfo,proto3,oneof"`
 }
 
+type HostToPlugin_Ping struct {
+	Ping *HostPing `protobuf:"bytes,13,opt,name=ping,proto3,oneof"`
+}
+
 type HostToPlugin_Event struct {
 	Event *EventEnvelope `protobuf:"bytes,20,opt,name=event,proto3,oneof"`
 }
@@ -380,8 +407,12 @@ type HostToPlugin_CompressedEvents struct {
 	CompressedEvents *CompressedEventBatch `protobuf:"bytes,23,opt,name=compressed_events,json=compressedEvents,proto3,oneof"`
 }
 
-type HostToPlugin_PlayerMovementsPacked struct {
-	PlayerMovementsPacked *PlayerMovementsPacked `protobuf:"bytes,24,opt,name=player_movements_packed,json=playerMovementsPacked,proto3,oneof"`
+type HostToPlugin_EventGap struct {
+	EventGap *EventGap `protobuf:"bytes,25,opt,name=event_gap,json=eventGap,proto3,oneof"`
+}
+
+type HostToPlugin_Registry struct {
+	Registry *Registry `protobuf:"bytes,26,opt,name=registry,proto3,oneof"`
 }
 
 func (*HostToPlugin_Hello) isHostToPlugin_Payload() {}
@@ -390,6 +421,8 @@ func (*HostToPlugin_Shutdown) isHostToPlugin_Payload() {}
 
 func (*HostToPlugin_ServerInfo) isHostToPlugin_Payload() {}
 
+func (*HostToPlugin_Ping) isHostToPlugin_Payload() {}
+
 func (*HostToPlugin_Event) isHostToPlugin_Payload() {}
 
 func (*HostToPlugin_ActionResult) isHostToPlugin_Payload() {}
@@ -398,30 +431,34 @@ func (*HostToPlugin_Events) isHostToPlugin_Payload() {}
 
 func (*HostToPlugin_CompressedEvents) isHostToPlugin_Payload() {}
 
-func (*HostToPlugin_PlayerMovementsPacked) isHostToPlugin_Payload() {}
+func (*HostToPlugin_EventGap) isHostToPlugin_Payload() {}
 
-type CompressedEventBatch struct {
+func (*HostToPlugin_Registry) isHostToPlugin_Payload() {}
+
+// EventGap is sent ahead of replayed events when the host's replay buffer overflowed while the plugin
+// was disconnected. Durable events with sequences in [from_sequence, to_sequence] were lost.
+type EventGap struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	OriginalSize  int32                  `protobuf:"varint,2,opt,name=original_size,json=originalSize,proto3" json:"original_size,omitempty"`
+	FromSequence  uint64                 `protobuf:"varint,1,opt,name=from_sequence,json=fromSequence,proto3" json:"from_sequence,omitempty"`
+	ToSequence    uint64                 `protobuf:"varint,2,opt,name=to_sequence,json=toSequence,proto3" json:"to_sequence,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CompressedEventBatch) Reset() {
-	*x = CompressedEventBatch{}
+func (x *EventGap) Reset() {
+	*x = EventGap{}
 	mi := &file_plugin_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CompressedEventBatch) String() string {
+func (x *EventGap) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CompressedEventBatch) ProtoMessage() {}
+func (*EventGap) ProtoMessage() {}
 
-func (x *CompressedEventBatch) ProtoReflect() protoreflect.Message {
+func (x *EventGap) ProtoReflect() protoreflect.Message {
 	mi := &file_plugin_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -433,46 +470,47 @@ func (x *CompressedEventBatch) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CompressedEventBatch.ProtoReflect.Descriptor instead.
-func (*CompressedEventBatch) Descriptor() ([]byte, []int) {
+// Deprecated: Use EventGap.ProtoReflect.Descriptor instead.
+func (*EventGap) Descriptor() ([]byte, []int) {
 	return file_plugin_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *CompressedEventBatch) GetData() []byte {
+func (x *EventGap) GetFromSequence() uint64 {
 	if x != nil {
-		return x.Data
+		return x.FromSequence
 	}
-	return nil
+	return 0
 }
 
-func (x *CompressedEventBatch) GetOriginalSize() int32 {
+func (x *EventGap) GetToSequence() uint64 {
 	if x != nil {
-		return x.OriginalSize
+		return x.ToSequence
 	}
 	return 0
 }
 
-type PlayerMovementsPacked struct {
+type CompressedEventBatch struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Moves         []*PackedPlayerMove    `protobuf:"bytes,1,rep,name=moves,proto3" json:"moves,omitempty"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	OriginalSize  int32                  `protobuf:"varint,2,opt,name=original_size,json=originalSize,proto3" json:"original_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PlayerMovementsPacked) Reset() {
-	*x = PlayerMovementsPacked{}
+func (x *CompressedEventBatch) Reset() {
+	*x = CompressedEventBatch{}
 	mi := &file_plugin_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PlayerMovementsPacked) String() string {
+func (x *CompressedEventBatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PlayerMovementsPacked) ProtoMessage() {}
+func (*CompressedEventBatch) ProtoMessage() {}
 
-func (x *PlayerMovementsPacked) ProtoReflect() protoreflect.Message {
+func (x *CompressedEventBatch) ProtoReflect() protoreflect.Message {
 	mi := &file_plugin_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -484,98 +522,21 @@ func (x *PlayerMovementsPacked) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PlayerMovementsPacked.ProtoReflect.Descriptor instead.
-func (*PlayerMovementsPacked) Descriptor() ([]byte, []int) {
+// Deprecated: Use CompressedEventBatch.ProtoReflect.Descriptor instead.
+func (*CompressedEventBatch) Descriptor() ([]byte, []int) {
 	return file_plugin_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *PlayerMovementsPacked) GetMoves() []*PackedPlayerMove {
-	if x != nil {
-		return x.Moves
-	}
-	return nil
-}
-
-type PackedPlayerMove struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	PlayerUuidBytes []byte                 `protobuf:"bytes,1,opt,name=player_uuid_bytes,json=playerUuidBytes,proto3" json:"player_uuid_bytes,omitempty"` // 16-byte UUID
-	X               float32                `protobuf:"fixed32,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y               float32                `protobuf:"fixed32,3,opt,name=y,proto3" json:"y,omitempty"`
-	Z               float32                `protobuf:"fixed32,4,opt,name=z,proto3" json:"z,omitempty"`
-	Yaw             float32                `protobuf:"fixed32,5,opt,name=yaw,proto3" json:"yaw,omitempty"`
-	Pitch           float32                `protobuf:"fixed32,6,opt,name=pitch,proto3" json:"pitch,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
-}
-
-func (x *PackedPlayerMove) Reset() {
-	*x = PackedPlayerMove{}
-	mi := &file_plugin_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *PackedPlayerMove) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*PackedPlayerMove) ProtoMessage() {}
-
-func (x *PackedPlayerMove) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[3]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use PackedPlayerMove.ProtoReflect.Descriptor instead.
-func (*PackedPlayerMove) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *PackedPlayerMove) GetPlayerUuidBytes() []byte {
+func (x *CompressedEventBatch) GetData() []byte {
 	if x != nil {
-		return x.PlayerUuidBytes
+		return x.Data
 	}
 	return nil
 }
 
-func (x *PackedPlayerMove) GetX() float32 {
-	if x != nil {
-		return x.X
-	}
-	return 0
-}
-
-func (x *PackedPlayerMove) GetY() float32 {
-	if x != nil {
-		return x.Y
-	}
-	return 0
-}
-
-func (x *PackedPlayerMove) GetZ() float32 {
-	if x != nil {
-		return x.Z
-	}
-	return 0
-}
-
-func (x *PackedPlayerMove) GetYaw() float32 {
-	if x != nil {
-		return x.Yaw
-	}
-	return 0
-}
-
-func (x *PackedPlayerMove) GetPitch() float32 {
+func (x *CompressedEventBatch) GetOriginalSize() int32 {
 	if x != nil {
-		return x.Pitch
+		return x.OriginalSize
 	}
 	return 0
 }
@@ -589,7 +550,7 @@ type EventBatch struct {
 
 func (x *EventBatch) Reset() {
 	*x = EventBatch{}
-	mi := &file_plugin_proto_msgTypes[4]
+	mi := &file_plugin_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -601,7 +562,7 @@ func (x *EventBatch) String() string {
 func (*EventBatch) ProtoMessage() {}
 
 func (x *EventBatch) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[4]
+	mi := &file_plugin_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -614,7 +575,7 @@ func (x *EventBatch) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EventBatch.ProtoReflect.Descriptor instead.
 func (*EventBatch) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{4}
+	return file_plugin_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *EventBatch) GetEvents() []*EventEnvelope {
@@ -632,7 +593,7 @@ type ServerInformationRequest struct {
 
 func (x *ServerInformationRequest) Reset() {
 	*x = ServerInformationRequest{}
-	mi := &file_plugin_proto_msgTypes[5]
+	mi := &file_plugin_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -644,7 +605,7 @@ func (x *ServerInformationRequest) String() string {
 func (*ServerInformationRequest) ProtoMessage() {}
 
 func (x *ServerInformationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[5]
+	mi := &file_plugin_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -657,7 +618,7 @@ func (x *ServerInformationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerInformationRequest.ProtoReflect.Descriptor instead.
 func (*ServerInformationRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{5}
+	return file_plugin_proto_rawDescGZIP(), []int{4}
 }
 
 type ServerInformationResponse struct {
@@ -669,7 +630,7 @@ type ServerInformationResponse struct {
 
 func (x *ServerInformationResponse) Reset() {
 	*x = ServerInformationResponse{}
-	mi := &file_plugin_proto_msgTypes[6]
+	mi := &file_plugin_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -681,7 +642,7 @@ func (x *ServerInformationResponse) String() string {
 func (*ServerInformationResponse) ProtoMessage() {}
 
 func (x *ServerInformationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[6]
+	mi := &file_plugin_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -694,7 +655,7 @@ func (x *ServerInformationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerInformationResponse.ProtoReflect.Descriptor instead.
 func (*ServerInformationResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{6}
+	return file_plugin_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ServerInformationResponse) GetPlugins() []string {
@@ -704,17 +665,22 @@ func (x *ServerInformationResponse) GetPlugins() []string {
 	return nil
 }
 
+// HostHello answers the plugin's hello with the result of version and capability negotiation.
+// Capabilities are named optional protocol features, e.g. "compression.snappy" or "heartbeat".
 type HostHello struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiVersion    string                 `protobuf:"bytes,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
-	BootId        string                 `protobuf:"bytes,2,opt,name=boot_id,json=bootId,proto3" json:"boot_id,omitempty"` // Used for auto reload to distinguish between startup and reload
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	ApiVersion           string                 `protobuf:"bytes,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`                                 // API version chosen for this session; empty if negotiation failed.
+	BootId               string                 `protobuf:"bytes,2,opt,name=boot_id,json=bootId,proto3" json:"boot_id,omitempty"`                                             // Used for auto reload to distinguish between startup and reload
+	SupportedApiVersions []string               `protobuf:"bytes,3,rep,name=supported_api_versions,json=supportedApiVersions,proto3" json:"supported_api_versions,omitempty"` // Every API version the host speaks, newest first.
+	Capabilities         []string               `protobuf:"bytes,4,rep,name=capabilities,proto3" json:"capabilities,omitempty"`                                               // Every capability the host supports.
+	EnabledCapabilities  []string               `protobuf:"bytes,5,rep,name=enabled_capabilities,json=enabledCapabilities,proto3" json:"enabled_capabilities,omitempty"`      // Capabilities in effect for this session.
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *HostHello) Reset() {
 	*x = HostHello{}
-	mi := &file_plugin_proto_msgTypes[7]
+	mi := &file_plugin_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -726,7 +692,7 @@ func (x *HostHello) String() string {
 func (*HostHello) ProtoMessage() {}
 
 func (x *HostHello) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[7]
+	mi := &file_plugin_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -739,7 +705,7 @@ func (x *HostHello) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostHello.ProtoReflect.Descriptor instead.
 func (*HostHello) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{7}
+	return file_plugin_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *HostHello) GetApiVersion() string {
@@ -756,6 +722,27 @@ func (x *HostHello) GetBootId() string {
 	return ""
 }
 
+func (x *HostHello) GetSupportedApiVersions() []string {
+	if x != nil {
+		return x.SupportedApiVersions
+	}
+	return nil
+}
+
+func (x *HostHello) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *HostHello) GetEnabledCapabilities() []string {
+	if x != nil {
+		return x.EnabledCapabilities
+	}
+	return nil
+}
+
 type HostShutdown struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
@@ -765,7 +752,7 @@ type HostShutdown struct {
 
 func (x *HostShutdown) Reset() {
 	*x = HostShutdown{}
-	mi := &file_plugin_proto_msgTypes[8]
+	mi := &file_plugin_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -777,7 +764,7 @@ func (x *HostShutdown) String() string {
 func (*HostShutdown) ProtoMessage() {}
 
 func (x *HostShutdown) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[8]
+	mi := &file_plugin_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -790,7 +777,7 @@ func (x *HostShutdown) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostShutdown.ProtoReflect.Descriptor instead.
 func (*HostShutdown) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{8}
+	return file_plugin_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *HostShutdown) GetReason() string {
@@ -800,12 +787,112 @@ func (x *HostShutdown) GetReason() string {
 	return ""
 }
 
+// HostPing is sent periodically by the host. Plugins must answer with a PluginPong carrying the same
+// nonce; plugins that miss too many heartbeats stop receiving events until they answer again.
+type HostPing struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nonce         uint64                 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	SentUnixMs    int64                  `protobuf:"varint,2,opt,name=sent_unix_ms,json=sentUnixMs,proto3" json:"sent_unix_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostPing) Reset() {
+	*x = HostPing{}
+	mi := &file_plugin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostPing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostPing) ProtoMessage() {}
+
+func (x *HostPing) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostPing.ProtoReflect.Descriptor instead.
+func (*HostPing) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HostPing) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *HostPing) GetSentUnixMs() int64 {
+	if x != nil {
+		return x.SentUnixMs
+	}
+	return 0
+}
+
+type PluginPong struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nonce         uint64                 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginPong) Reset() {
+	*x = PluginPong{}
+	mi := &file_plugin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginPong) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginPong) ProtoMessage() {}
+
+func (x *PluginPong) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginPong.ProtoReflect.Descriptor instead.
+func (*PluginPong) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PluginPong) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
 type EventEnvelope struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	EventId         string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
 	Type            EventType              `protobuf:"varint,2,opt,name=type,proto3,enum=df.plugin.EventType" json:"type,omitempty"`
 	ExpectsResponse bool                   `protobuf:"varint,3,opt,name=expects_response,json=expectsResponse,proto3" json:"expects_response,omitempty"` // If an event can be cancelled or mutated it expects an acknowledgement.
 	Immediate       bool                   `protobuf:"varint,4,opt,name=immediate,proto3" json:"immediate,omitempty"`                                    // If true, the event is sent immediately, bypassing any batching.
+	Sequence        uint64                 `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`                                      // Per-plugin replay sequence. Only set on durable events (join, quit, death, ...).
+	Trace           *TraceContext          `protobuf:"bytes,6,opt,name=trace,proto3" json:"trace,omitempty"`                                             // Set when the host is tracing this event; plugins may continue the trace.
 	// Types that are valid to be assigned to Payload:
 	//
 	//	*EventEnvelope_PlayerJoin
@@ -857,6 +944,7 @@ type EventEnvelope struct {
 	//	*EventEnvelope_WorldEntityDespawn
 	//	*EventEnvelope_WorldExplosion
 	//	*EventEnvelope_WorldClose
+	//	*EventEnvelope_PluginCrash
 	Payload       isEventEnvelope_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -864,7 +952,7 @@ type EventEnvelope struct {
 
 func (x *EventEnvelope) Reset() {
 	*x = EventEnvelope{}
-	mi := &file_plugin_proto_msgTypes[9]
+	mi := &file_plugin_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -876,7 +964,7 @@ func (x *EventEnvelope) String() string {
 func (*EventEnvelope) ProtoMessage() {}
 
 func (x *EventEnvelope) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[9]
+	mi := &file_plugin_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -889,7 +977,7 @@ func (x *EventEnvelope) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EventEnvelope.ProtoReflect.Descriptor instead.
 func (*EventEnvelope) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{9}
+	return file_plugin_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *EventEnvelope) GetEventId() string {
@@ -920,6 +1008,20 @@ func (x *EventEnvelope) GetImmediate() bool {
 	return false
 }
 
+func (x *EventEnvelope) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *EventEnvelope) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
 func (x *EventEnvelope) GetPayload() isEventEnvelope_Payload {
 	if x != nil {
 		return x.Payload
@@ -1368,6 +1470,15 @@ func (x *EventEnvelope) GetWorldClose() *WorldCloseEvent {
 	return nil
 }
 
+func (x *EventEnvelope) GetPluginCrash() *PluginCrashEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_PluginCrash); ok {
+			return x.PluginCrash
+		}
+	}
+	return nil
+}
+
 type isEventEnvelope_Payload interface {
 	isEventEnvelope_Payload()
 }
@@ -1568,6 +1679,10 @@ type EventEnvelope_WorldClose struct {
 	WorldClose *WorldCloseEvent `protobuf:"bytes,81,opt,name=world_close,json=worldClose,proto3,oneof"`
 }
 
+type EventEnvelope_PluginCrash struct {
+	PluginCrash *PluginCrashEvent `protobuf:"bytes,90,opt,name=plugin_crash,json=pluginCrash,proto3,oneof"`
+}
+
 func (*EventEnvelope_PlayerJoin) isEventEnvelope_Payload() {}
 
 func (*EventEnvelope_PlayerQuit) isEventEnvelope_Payload() {}
@@ -1666,6 +1781,8 @@ func (*EventEnvelope_WorldExplosion) isEventEnvelope_Payload() {}
 
 func (*EventEnvelope_WorldClose) isEventEnvelope_Payload() {}
 
+func (*EventEnvelope_PluginCrash) isEventEnvelope_Payload() {}
+
 type PluginToHost struct {
 	state    protoimpl.MessageState `protogen:"open.v1"`
 	PluginId string                 `protobuf:"bytes,1,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
@@ -1674,8 +1791,11 @@ type PluginToHost struct {
 	//	*PluginToHost_Hello
 	//	*PluginToHost_Subscribe
 	//	*PluginToHost_ServerInfo
+	//	*PluginToHost_Pong
+	//	*PluginToHost_Registry
 	//	*PluginToHost_Actions
 	//	*PluginToHost_Log
+	//	*PluginToHost_Metrics
 	//	*PluginToHost_EventResult
 	Payload       isPluginToHost_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
@@ -1684,7 +1804,7 @@ type PluginToHost struct {
 
 func (x *PluginToHost) Reset() {
 	*x = PluginToHost{}
-	mi := &file_plugin_proto_msgTypes[10]
+	mi := &file_plugin_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1696,7 +1816,7 @@ func (x *PluginToHost) String() string {
 func (*PluginToHost) ProtoMessage() {}
 
 func (x *PluginToHost) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[10]
+	mi := &file_plugin_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1709,7 +1829,7 @@ func (x *PluginToHost) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginToHost.ProtoReflect.Descriptor instead.
 func (*PluginToHost) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{10}
+	return file_plugin_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *PluginToHost) GetPluginId() string {
@@ -1753,6 +1873,24 @@ func (x *PluginToHost) GetServerInfo() *ServerInformationRequest {
 	return nil
 }
 
+func (x *PluginToHost) GetPong() *PluginPong {
+	if x != nil {
+		if x, ok := x.Payload.(*PluginToHost_Pong); ok {
+			return x.Pong
+		}
+	}
+	return nil
+}
+
+func (x *PluginToHost) GetRegistry() *RegistryRequest {
+	if x != nil {
+		if x, ok := x.Payload.(*PluginToHost_Registry); ok {
+			return x.Registry
+		}
+	}
+	return nil
+}
+
 func (x *PluginToHost) GetActions() *ActionBatch {
 	if x != nil {
 		if x, ok := x.Payload.(*PluginToHost_Actions); ok {
@@ -1771,6 +1909,15 @@ func (x *PluginToHost) GetLog() *LogMessage {
 	return nil
 }
 
+func (x *PluginToHost) GetMetrics() *PluginMetrics {
+	if x != nil {
+		if x, ok := x.Payload.(*PluginToHost_Metrics); ok {
+			return x.Metrics
+		}
+	}
+	return nil
+}
+
 func (x *PluginToHost) GetEventResult() *EventResult {
 	if x != nil {
 		if x, ok := x.Payload.(*PluginToHost_EventResult); ok {
@@ -1796,6 +1943,14 @@ type PluginToHost_ServerInfo struct {
 	ServerInfo *ServerInformationRequest `protobuf:"bytes,12,opt,name=server_info,json=serverInfo,proto3,oneof"`
 }
 
+type PluginToHost_Pong struct {
+	Pong *PluginPong `protobuf:"bytes,13,opt,name=pong,proto3,oneof"`
+}
+
+type PluginToHost_Registry struct {
+	Registry *RegistryRequest `protobuf:"bytes,14,opt,name=registry,proto3,oneof"`
+}
+
 type PluginToHost_Actions struct {
 	Actions *ActionBatch `protobuf:"bytes,20,opt,name=actions,proto3,oneof"`
 }
@@ -1804,6 +1959,10 @@ type PluginToHost_Log struct {
 	Log *LogMessage `protobuf:"bytes,30,opt,name=log,proto3,oneof"`
 }
 
+type PluginToHost_Metrics struct {
+	Metrics *PluginMetrics `protobuf:"bytes,31,opt,name=metrics,proto3,oneof"`
+}
+
 type PluginToHost_EventResult struct {
 	EventResult *EventResult `protobuf:"bytes,40,opt,name=event_result,json=eventResult,proto3,oneof"`
 }
@@ -1814,27 +1973,44 @@ func (*PluginToHost_Subscribe) isPluginToHost_Payload() {}
 
 func (*PluginToHost_ServerInfo) isPluginToHost_Payload() {}
 
+func (*PluginToHost_Pong) isPluginToHost_Payload() {}
+
+func (*PluginToHost_Registry) isPluginToHost_Payload() {}
+
 func (*PluginToHost_Actions) isPluginToHost_Payload() {}
 
 func (*PluginToHost_Log) isPluginToHost_Payload() {}
 
+func (*PluginToHost_Metrics) isPluginToHost_Payload() {}
+
 func (*PluginToHost_EventResult) isPluginToHost_Payload() {}
 
 type PluginHello struct {
-	state         protoimpl.MessageState   `protogen:"open.v1"`
-	Name          string                   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Version       string                   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	ApiVersion    string                   `protobuf:"bytes,3,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
-	Commands      []*CommandSpec           `protobuf:"bytes,4,rep,name=commands,proto3" json:"commands,omitempty"`
-	CustomItems   []*CustomItemDefinition  `protobuf:"bytes,5,rep,name=custom_items,json=customItems,proto3" json:"custom_items,omitempty"`
-	CustomBlocks  []*CustomBlockDefinition `protobuf:"bytes,6,rep,name=custom_blocks,json=customBlocks,proto3" json:"custom_blocks,omitempty"`
+	state        protoimpl.MessageState   `protogen:"open.v1"`
+	Name         string                   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version      string                   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	ApiVersion   string                   `protobuf:"bytes,3,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	Commands     []*CommandSpec           `protobuf:"bytes,4,rep,name=commands,proto3" json:"commands,omitempty"`
+	CustomItems  []*CustomItemDefinition  `protobuf:"bytes,5,rep,name=custom_items,json=customItems,proto3" json:"custom_items,omitempty"`
+	CustomBlocks []*CustomBlockDefinition `protobuf:"bytes,6,rep,name=custom_blocks,json=customBlocks,proto3" json:"custom_blocks,omitempty"`
+	Resume       *SessionResume           `protobuf:"bytes,7,opt,name=resume,proto3" json:"resume,omitempty"` // Set when reconnecting to resume a previous session.
+	// API versions the plugin speaks in order of preference. If empty, api_version is used alone.
+	ApiVersions []string `protobuf:"bytes,8,rep,name=api_versions,json=apiVersions,proto3" json:"api_versions,omitempty"`
+	// Optional capabilities the plugin supports. A plugin that lists neither capabilities nor
+	// api_versions is treated as a legacy v1 plugin and gets the v1 defaults.
+	Capabilities []string `protobuf:"bytes,9,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// Capabilities the plugin cannot work without; the host refuses the plugin if any is unsupported.
+	RequiredCapabilities []string `protobuf:"bytes,10,rep,name=required_capabilities,json=requiredCapabilities,proto3" json:"required_capabilities,omitempty"`
+	// The DF_PLUGIN_TOKEN the host passed to the plugin when launching it. Launched plugins must send it
+	// when their connection carries no peer credentials, unless peer_check is off.
+	LaunchToken   string `protobuf:"bytes,11,opt,name=launch_token,json=launchToken,proto3" json:"launch_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PluginHello) Reset() {
 	*x = PluginHello{}
-	mi := &file_plugin_proto_msgTypes[11]
+	mi := &file_plugin_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1846,7 +2022,7 @@ func (x *PluginHello) String() string {
 func (*PluginHello) ProtoMessage() {}
 
 func (x *PluginHello) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[11]
+	mi := &file_plugin_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1859,7 +2035,7 @@ func (x *PluginHello) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginHello.ProtoReflect.Descriptor instead.
 func (*PluginHello) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{11}
+	return file_plugin_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *PluginHello) GetName() string {
@@ -1904,6 +2080,197 @@ func (x *PluginHello) GetCustomBlocks() []*CustomBlockDefinition {
 	return nil
 }
 
+func (x *PluginHello) GetResume() *SessionResume {
+	if x != nil {
+		return x.Resume
+	}
+	return nil
+}
+
+func (x *PluginHello) GetApiVersions() []string {
+	if x != nil {
+		return x.ApiVersions
+	}
+	return nil
+}
+
+func (x *PluginHello) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *PluginHello) GetRequiredCapabilities() []string {
+	if x != nil {
+		return x.RequiredCapabilities
+	}
+	return nil
+}
+
+func (x *PluginHello) GetLaunchToken() string {
+	if x != nil {
+		return x.LaunchToken
+	}
+	return ""
+}
+
+// SessionResume asks the host to replay durable events missed while the plugin was disconnected.
+// Replay only happens if boot_id matches the host's current HostHello.boot_id.
+type SessionResume struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BootId        string                 `protobuf:"bytes,1,opt,name=boot_id,json=bootId,proto3" json:"boot_id,omitempty"`
+	LastSequence  uint64                 `protobuf:"varint,2,opt,name=last_sequence,json=lastSequence,proto3" json:"last_sequence,omitempty"` // Highest EventEnvelope.sequence the plugin has processed.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionResume) Reset() {
+	*x = SessionResume{}
+	mi := &file_plugin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionResume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionResume) ProtoMessage() {}
+
+func (x *SessionResume) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionResume.ProtoReflect.Descriptor instead.
+func (*SessionResume) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SessionResume) GetBootId() string {
+	if x != nil {
+		return x.BootId
+	}
+	return ""
+}
+
+func (x *SessionResume) GetLastSequence() uint64 {
+	if x != nil {
+		return x.LastSequence
+	}
+	return 0
+}
+
+// PluginCrashEvent is sent when another plugin's process dies or the host stops running an
+// in-process plugin because it failed.
+type PluginCrashEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PluginId        string                 `protobuf:"bytes,1,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Reason          string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ExitCode        *int32                 `protobuf:"varint,4,opt,name=exit_code,json=exitCode,proto3,oneof" json:"exit_code,omitempty"` // Set if the process exited rather than being killed by a signal.
+	Signal          string                 `protobuf:"bytes,5,opt,name=signal,proto3" json:"signal,omitempty"`                            // The signal that killed the process, if any.
+	UptimeMs        int64                  `protobuf:"varint,6,opt,name=uptime_ms,json=uptimeMs,proto3" json:"uptime_ms,omitempty"`
+	PendingEventIds []string               `protobuf:"bytes,7,rep,name=pending_event_ids,json=pendingEventIds,proto3" json:"pending_event_ids,omitempty"` // Events that were waiting for the plugin's result.
+	Report          string                 `protobuf:"bytes,8,opt,name=report,proto3" json:"report,omitempty"`                                            // Path of the crash report on the host, empty if none was written.
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PluginCrashEvent) Reset() {
+	*x = PluginCrashEvent{}
+	mi := &file_plugin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginCrashEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginCrashEvent) ProtoMessage() {}
+
+func (x *PluginCrashEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginCrashEvent.ProtoReflect.Descriptor instead.
+func (*PluginCrashEvent) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PluginCrashEvent) GetPluginId() string {
+	if x != nil {
+		return x.PluginId
+	}
+	return ""
+}
+
+func (x *PluginCrashEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PluginCrashEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PluginCrashEvent) GetExitCode() int32 {
+	if x != nil && x.ExitCode != nil {
+		return *x.ExitCode
+	}
+	return 0
+}
+
+func (x *PluginCrashEvent) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *PluginCrashEvent) GetUptimeMs() int64 {
+	if x != nil {
+		return x.UptimeMs
+	}
+	return 0
+}
+
+func (x *PluginCrashEvent) GetPendingEventIds() []string {
+	if x != nil {
+		return x.PendingEventIds
+	}
+	return nil
+}
+
+func (x *PluginCrashEvent) GetReport() string {
+	if x != nil {
+		return x.Report
+	}
+	return ""
+}
+
 type LogMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Level         string                 `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
@@ -1914,7 +2281,7 @@ type LogMessage struct {
 
 func (x *LogMessage) Reset() {
 	*x = LogMessage{}
-	mi := &file_plugin_proto_msgTypes[12]
+	mi := &file_plugin_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1926,7 +2293,7 @@ func (x *LogMessage) String() string {
 func (*LogMessage) ProtoMessage() {}
 
 func (x *LogMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[12]
+	mi := &file_plugin_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1939,7 +2306,7 @@ func (x *LogMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogMessage.ProtoReflect.Descriptor instead.
 func (*LogMessage) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{12}
+	return file_plugin_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *LogMessage) GetLevel() string {
@@ -1965,7 +2332,7 @@ type EventSubscribe struct {
 
 func (x *EventSubscribe) Reset() {
 	*x = EventSubscribe{}
-	mi := &file_plugin_proto_msgTypes[13]
+	mi := &file_plugin_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1977,7 +2344,7 @@ func (x *EventSubscribe) String() string {
 func (*EventSubscribe) ProtoMessage() {}
 
 func (x *EventSubscribe) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[13]
+	mi := &file_plugin_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1990,7 +2357,7 @@ func (x *EventSubscribe) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EventSubscribe.ProtoReflect.Descriptor instead.
 func (*EventSubscribe) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{13}
+	return file_plugin_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *EventSubscribe) GetEvents() []EventType {
@@ -2004,49 +2371,58 @@ var File_plugin_proto protoreflect.FileDescriptor
 
 const file_plugin_proto_rawDesc = "" +
 	"\n" +
-	"\fplugin.proto\x12\tdf.plugin\x1a\x13player_events.proto\x1a\x12world_events.proto\x1a\rcommand.proto\x1a\ractions.proto\x1a\x0fmutations.proto\x1a\fcommon.proto\x1a\x14action_results.proto\"\xb3\x04\n" +
+	"\fplugin.proto\x12\tdf.plugin\x1a\x13player_events.proto\x1a\x12world_events.proto\x1a\rcommand.proto\x1a\ractions.proto\x1a\x0fmutations.proto\x1a\fcommon.proto\x1a\x14action_results.proto\x1a\rmetrics.proto\x1a\x0eregistry.proto\"\x88\x05\n" +
 	"\fHostToPlugin\x12\x1b\n" +
 	"\tplugin_id\x18\x01 \x01(\tR\bpluginId\x12,\n" +
 	"\x05hello\x18\n" +
 	" \x01(\v2\x14.df.plugin.HostHelloH\x00R\x05hello\x125\n" +
 	"\bshutdown\x18\v \x01(\v2\x17.df.plugin.HostShutdownH\x00R\bshutdown\x12G\n" +
 	"\vserver_info\x18\f \x01(\v2$.df.plugin.ServerInformationResponseH\x00R\n" +
-	"serverInfo\x120\n" +
+	"serverInfo\x12)\n" +
+	"\x04ping\x18\r \x01(\v2\x13.df.plugin.HostPingH\x00R\x04ping\x120\n" +
 	"\x05event\x18\x14 \x01(\v2\x18.df.plugin.EventEnvelopeH\x00R\x05event\x12>\n" +
 	"\raction_result\x18\x15 \x01(\v2\x17.df.plugin.ActionResultH\x00R\factionResult\x12/\n" +
 	"\x06events\x18\x16 \x01(\v2\x15.df.plugin.EventBatchH\x00R\x06events\x12N\n" +
-	"\x11compressed_events\x18\x17 \x01(\v2\x1f.df.plugin.CompressedEventBatchH\x00R\x10compressedEvents\x12Z\n" +
-	"\x17player_movements_packed\x18\x18 \x01(\v2 .df.plugin.PlayerMovementsPackedH\x00R\x15playerMovementsPackedB\t\n" +
-	"\apayload\"O\n" +
+	"\x11compressed_events\x18\x17 \x01(\v2\x1f.df.plugin.CompressedEventBatchH\x00R\x10compressedEvents\x122\n" +
+	"\tevent_gap\x18\x19 \x01(\v2\x13.df.plugin.EventGapH\x00R\beventGap\x121\n" +
+	"\bregistry\x18\x1a \x01(\v2\x13.df.plugin.RegistryH\x00R\bregistryB\t\n" +
+	"\apayloadJ\x04\b\x18\x10\x19R\x17player_movements_packed\"P\n" +
+	"\bEventGap\x12#\n" +
+	"\rfrom_sequence\x18\x01 \x01(\x04R\ffromSequence\x12\x1f\n" +
+	"\vto_sequence\x18\x02 \x01(\x04R\n" +
+	"toSequence\"O\n" +
 	"\x14CompressedEventBatch\x12\x12\n" +
 	"\x04data\x18\x01 \x01(\fR\x04data\x12#\n" +
-	"\roriginal_size\x18\x02 \x01(\x05R\foriginalSize\"J\n" +
-	"\x15PlayerMovementsPacked\x121\n" +
-	"\x05moves\x18\x01 \x03(\v2\x1b.df.plugin.PackedPlayerMoveR\x05moves\"\x90\x01\n" +
-	"\x10PackedPlayerMove\x12*\n" +
-	"\x11player_uuid_bytes\x18\x01 \x01(\fR\x0fplayerUuidBytes\x12\f\n" +
-	"\x01x\x18\x02 \x01(\x02R\x01x\x12\f\n" +
-	"\x01y\x18\x03 \x01(\x02R\x01y\x12\f\n" +
-	"\x01z\x18\x04 \x01(\x02R\x01z\x12\x10\n" +
-	"\x03yaw\x18\x05 \x01(\x02R\x03yaw\x12\x14\n" +
-	"\x05pitch\x18\x06 \x01(\x02R\x05pitch\">\n" +
+	"\roriginal_size\x18\x02 \x01(\x05R\foriginalSize\">\n" +
 	"\n" +
 	"EventBatch\x120\n" +
 	"\x06events\x18\x01 \x03(\v2\x18.df.plugin.EventEnvelopeR\x06events\"\x1a\n" +
 	"\x18ServerInformationRequest\"5\n" +
 	"\x19ServerInformationResponse\x12\x18\n" +
-	"\aplugins\x18\x01 \x03(\tR\aplugins\"E\n" +
+	"\aplugins\x18\x01 \x03(\tR\aplugins\"\xd2\x01\n" +
 	"\tHostHello\x12\x1f\n" +
 	"\vapi_version\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x17\n" +
-	"\aboot_id\x18\x02 \x01(\tR\x06bootId\"&\n" +
+	"\aboot_id\x18\x02 \x01(\tR\x06bootId\x124\n" +
+	"\x16supported_api_versions\x18\x03 \x03(\tR\x14supportedApiVersions\x12\"\n" +
+	"\fcapabilities\x18\x04 \x03(\tR\fcapabilities\x121\n" +
+	"\x14enabled_capabilities\x18\x05 \x03(\tR\x13enabledCapabilities\"&\n" +
 	"\fHostShutdown\x12\x16\n" +
-	"\x06reason\x18\x01 \x01(\tR\x06reason\"\x84\x1f\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\"B\n" +
+	"\bHostPing\x12\x14\n" +
+	"\x05nonce\x18\x01 \x01(\x04R\x05nonce\x12 \n" +
+	"\fsent_unix_ms\x18\x02 \x01(\x03R\n" +
+	"sentUnixMs\"\"\n" +
+	"\n" +
+	"PluginPong\x12\x14\n" +
+	"\x05nonce\x18\x01 \x01(\x04R\x05nonce\"\x91 \n" +
 	"\rEventEnvelope\x12\x19\n" +
 	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12(\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x14.df.plugin.EventTypeR\x04type\x12)\n" +
 	"\x10expects_response\x18\x03 \x01(\bR\x0fexpectsResponse\x12\x1c\n" +
-	"\timmediate\x18\x04 \x01(\bR\timmediate\x12=\n" +
+	"\timmediate\x18\x04 \x01(\bR\timmediate\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\x04R\bsequence\x12-\n" +
+	"\x05trace\x18\x06 \x01(\v2\x17.df.plugin.TraceContextR\x05trace\x12=\n" +
 	"\vplayer_join\x18\n" +
 	" \x01(\v2\x1a.df.plugin.PlayerJoinEventH\x00R\n" +
 	"playerJoin\x12=\n" +
@@ -2105,19 +2481,23 @@ const file_plugin_proto_rawDesc = "" +
 	"\x14world_entity_despawn\x18O \x01(\v2\".df.plugin.WorldEntityDespawnEventH\x00R\x12worldEntityDespawn\x12I\n" +
 	"\x0fworld_explosion\x18P \x01(\v2\x1e.df.plugin.WorldExplosionEventH\x00R\x0eworldExplosion\x12=\n" +
 	"\vworld_close\x18Q \x01(\v2\x1a.df.plugin.WorldCloseEventH\x00R\n" +
-	"worldCloseB\t\n" +
-	"\apayload\"\x85\x03\n" +
+	"worldClose\x12@\n" +
+	"\fplugin_crash\x18Z \x01(\v2\x1b.df.plugin.PluginCrashEventH\x00R\vpluginCrashB\t\n" +
+	"\apayload\"\xa2\x04\n" +
 	"\fPluginToHost\x12\x1b\n" +
 	"\tplugin_id\x18\x01 \x01(\tR\bpluginId\x12.\n" +
 	"\x05hello\x18\n" +
 	" \x01(\v2\x16.df.plugin.PluginHelloH\x00R\x05hello\x129\n" +
 	"\tsubscribe\x18\v \x01(\v2\x19.df.plugin.EventSubscribeH\x00R\tsubscribe\x12F\n" +
 	"\vserver_info\x18\f \x01(\v2#.df.plugin.ServerInformationRequestH\x00R\n" +
-	"serverInfo\x122\n" +
+	"serverInfo\x12+\n" +
+	"\x04pong\x18\r \x01(\v2\x15.df.plugin.PluginPongH\x00R\x04pong\x128\n" +
+	"\bregistry\x18\x0e \x01(\v2\x1a.df.plugin.RegistryRequestH\x00R\bregistry\x122\n" +
 	"\aactions\x18\x14 \x01(\v2\x16.df.plugin.ActionBatchH\x00R\aactions\x12)\n" +
-	"\x03log\x18\x1e \x01(\v2\x15.df.plugin.LogMessageH\x00R\x03log\x12;\n" +
+	"\x03log\x18\x1e \x01(\v2\x15.df.plugin.LogMessageH\x00R\x03log\x124\n" +
+	"\ametrics\x18\x1f \x01(\v2\x18.df.plugin.PluginMetricsH\x00R\ametrics\x12;\n" +
 	"\fevent_result\x18( \x01(\v2\x16.df.plugin.EventResultH\x00R\veventResultB\t\n" +
-	"\apayload\"\x9b\x02\n" +
+	"\apayload\"\xec\x03\n" +
 	"\vPluginHello\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12\x1f\n" +
@@ -2125,13 +2505,33 @@ const file_plugin_proto_rawDesc = "" +
 	"apiVersion\x122\n" +
 	"\bcommands\x18\x04 \x03(\v2\x16.df.plugin.CommandSpecR\bcommands\x12B\n" +
 	"\fcustom_items\x18\x05 \x03(\v2\x1f.df.plugin.CustomItemDefinitionR\vcustomItems\x12E\n" +
-	"\rcustom_blocks\x18\x06 \x03(\v2 .df.plugin.CustomBlockDefinitionR\fcustomBlocks\"<\n" +
+	"\rcustom_blocks\x18\x06 \x03(\v2 .df.plugin.CustomBlockDefinitionR\fcustomBlocks\x120\n" +
+	"\x06resume\x18\a \x01(\v2\x18.df.plugin.SessionResumeR\x06resume\x12!\n" +
+	"\fapi_versions\x18\b \x03(\tR\vapiVersions\x12\"\n" +
+	"\fcapabilities\x18\t \x03(\tR\fcapabilities\x123\n" +
+	"\x15required_capabilities\x18\n" +
+	" \x03(\tR\x14requiredCapabilities\x12!\n" +
+	"\flaunch_token\x18\v \x01(\tR\vlaunchToken\"M\n" +
+	"\rSessionResume\x12\x17\n" +
+	"\aboot_id\x18\x01 \x01(\tR\x06bootId\x12#\n" +
+	"\rlast_sequence\x18\x02 \x01(\x04R\flastSequence\"\x84\x02\n" +
+	"\x10PluginCrashEvent\x12\x1b\n" +
+	"\tplugin_id\x18\x01 \x01(\tR\bpluginId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12 \n" +
+	"\texit_code\x18\x04 \x01(\x05H\x00R\bexitCode\x88\x01\x01\x12\x16\n" +
+	"\x06signal\x18\x05 \x01(\tR\x06signal\x12\x1b\n" +
+	"\tuptime_ms\x18\x06 \x01(\x03R\buptimeMs\x12*\n" +
+	"\x11pending_event_ids\x18\a \x03(\tR\x0fpendingEventIds\x12\x16\n" +
+	"\x06report\x18\b \x01(\tR\x06reportB\f\n" +
+	"\n" +
+	"_exit_code\"<\n" +
 	"\n" +
 	"LogMessage\x12\x14\n" +
 	"\x05level\x18\x01 \x01(\tR\x05level\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\">\n" +
 	"\x0eEventSubscribe\x12,\n" +
-	"\x06events\x18\x01 \x03(\x0e2\x14.df.plugin.EventTypeR\x06events*\x8a\t\n" +
+	"\x06events\x18\x01 \x03(\x0e2\x14.df.plugin.EventTypeR\x06events*\x9c\t\n" +
 	"\tEventType\x12\x1a\n" +
 	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x12\n" +
 	"\x0eEVENT_TYPE_ALL\x10\x01\x12\x0f\n" +
@@ -2184,7 +2584,8 @@ const file_plugin_proto_rawDesc = "" +
 	"\x12WORLD_ENTITY_SPAWN\x10N\x12\x18\n" +
 	"\x14WORLD_ENTITY_DESPAWN\x10O\x12\x13\n" +
 	"\x0fWORLD_EXPLOSION\x10P\x12\x0f\n" +
-	"\vWORLD_CLOSE\x10Q2M\n" +
+	"\vWORLD_CLOSE\x10Q\x12\x10\n" +
+	"\fPLUGIN_CRASH\x10Z2M\n" +
 	"\x06Plugin\x12C\n" +
 	"\vEventStream\x12\x17.df.plugin.PluginToHost\x1a\x17.df.plugin.HostToPlugin(\x010\x01B\x8a\x01\n" +
 	"\rcom.df.pluginB\vPluginProtoP\x01Z'github.com/secmc/plugin/proto/generated\xa2\x02\x03DPX\xaa\x02\tDf.Plugin\xca\x02\tDf\\Plugin\xe2\x02\x15Df\\Plugin\\GPBMetadata\xea\x02\n" +
@@ -2203,157 +2604,171 @@ func file_plugin_proto_rawDescGZIP() []byte {
 }
 
 var file_plugin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_plugin_proto_goTypes = []any{
 	(EventType)(0),                     // 0: df.plugin.EventType
 	(*HostToPlugin)(nil),               // 1: df.plugin.HostToPlugin
-	(*CompressedEventBatch)(nil),       // 2: df.plugin.CompressedEventBatch
-	(*PlayerMovementsPacked)(nil),      // 3: df.plugin.PlayerMovementsPacked
-	(*PackedPlayerMove)(nil),           // 4: df.plugin.PackedPlayerMove
-	(*EventBatch)(nil),                 // 5: df.plugin.EventBatch
-	(*ServerInformationRequest)(nil),   // 6: df.plugin.ServerInformationRequest
-	(*ServerInformationResponse)(nil),  // 7: df.plugin.ServerInformationResponse
-	(*HostHello)(nil),                  // 8: df.plugin.HostHello
-	(*HostShutdown)(nil),               // 9: df.plugin.HostShutdown
-	(*EventEnvelope)(nil),              // 10: df.plugin.EventEnvelope
-	(*PluginToHost)(nil),               // 11: df.plugin.PluginToHost
-	(*PluginHello)(nil),                // 12: df.plugin.PluginHello
-	(*LogMessage)(nil),                 // 13: df.plugin.LogMessage
-	(*EventSubscribe)(nil),             // 14: df.plugin.EventSubscribe
-	(*ActionResult)(nil),               // 15: df.plugin.ActionResult
-	(*PlayerJoinEvent)(nil),            // 16: df.plugin.PlayerJoinEvent
-	(*PlayerQuitEvent)(nil),            // 17: df.plugin.PlayerQuitEvent
-	(*PlayerMoveEvent)(nil),            // 18: df.plugin.PlayerMoveEvent
-	(*PlayerJumpEvent)(nil),            // 19: df.plugin.PlayerJumpEvent
-	(*PlayerTeleportEvent)(nil),        // 20: df.plugin.PlayerTeleportEvent
-	(*PlayerChangeWorldEvent)(nil),     // 21: df.plugin.PlayerChangeWorldEvent
-	(*PlayerToggleSprintEvent)(nil),    // 22: df.plugin.PlayerToggleSprintEvent
-	(*PlayerToggleSneakEvent)(nil),     // 23: df.plugin.PlayerToggleSneakEvent
-	(*ChatEvent)(nil),                  // 24: df.plugin.ChatEvent
-	(*PlayerFoodLossEvent)(nil),        // 25: df.plugin.PlayerFoodLossEvent
-	(*PlayerHealEvent)(nil),            // 26: df.plugin.PlayerHealEvent
-	(*PlayerHurtEvent)(nil),            // 27: df.plugin.PlayerHurtEvent
-	(*PlayerDeathEvent)(nil),           // 28: df.plugin.PlayerDeathEvent
-	(*PlayerRespawnEvent)(nil),         // 29: df.plugin.PlayerRespawnEvent
-	(*PlayerSkinChangeEvent)(nil),      // 30: df.plugin.PlayerSkinChangeEvent
-	(*PlayerFireExtinguishEvent)(nil),  // 31: df.plugin.PlayerFireExtinguishEvent
-	(*PlayerStartBreakEvent)(nil),      // 32: df.plugin.PlayerStartBreakEvent
-	(*BlockBreakEvent)(nil),            // 33: df.plugin.BlockBreakEvent
-	(*PlayerBlockPlaceEvent)(nil),      // 34: df.plugin.PlayerBlockPlaceEvent
-	(*PlayerBlockPickEvent)(nil),       // 35: df.plugin.PlayerBlockPickEvent
-	(*PlayerItemUseEvent)(nil),         // 36: df.plugin.PlayerItemUseEvent
-	(*PlayerItemUseOnBlockEvent)(nil),  // 37: df.plugin.PlayerItemUseOnBlockEvent
-	(*PlayerItemUseOnEntityEvent)(nil), // 38: df.plugin.PlayerItemUseOnEntityEvent
-	(*PlayerItemReleaseEvent)(nil),     // 39: df.plugin.PlayerItemReleaseEvent
-	(*PlayerItemConsumeEvent)(nil),     // 40: df.plugin.PlayerItemConsumeEvent
-	(*PlayerAttackEntityEvent)(nil),    // 41: df.plugin.PlayerAttackEntityEvent
-	(*PlayerExperienceGainEvent)(nil),  // 42: df.plugin.PlayerExperienceGainEvent
-	(*PlayerPunchAirEvent)(nil),        // 43: df.plugin.PlayerPunchAirEvent
-	(*PlayerSignEditEvent)(nil),        // 44: df.plugin.PlayerSignEditEvent
-	(*PlayerLecternPageTurnEvent)(nil), // 45: df.plugin.PlayerLecternPageTurnEvent
-	(*PlayerItemDamageEvent)(nil),      // 46: df.plugin.PlayerItemDamageEvent
-	(*PlayerItemPickupEvent)(nil),      // 47: df.plugin.PlayerItemPickupEvent
-	(*PlayerHeldSlotChangeEvent)(nil),  // 48: df.plugin.PlayerHeldSlotChangeEvent
-	(*PlayerItemDropEvent)(nil),        // 49: df.plugin.PlayerItemDropEvent
-	(*PlayerTransferEvent)(nil),        // 50: df.plugin.PlayerTransferEvent
-	(*CommandEvent)(nil),               // 51: df.plugin.CommandEvent
-	(*PlayerDiagnosticsEvent)(nil),     // 52: df.plugin.PlayerDiagnosticsEvent
-	(*WorldLiquidFlowEvent)(nil),       // 53: df.plugin.WorldLiquidFlowEvent
-	(*WorldLiquidDecayEvent)(nil),      // 54: df.plugin.WorldLiquidDecayEvent
-	(*WorldLiquidHardenEvent)(nil),     // 55: df.plugin.WorldLiquidHardenEvent
-	(*WorldSoundEvent)(nil),            // 56: df.plugin.WorldSoundEvent
-	(*WorldFireSpreadEvent)(nil),       // 57: df.plugin.WorldFireSpreadEvent
-	(*WorldBlockBurnEvent)(nil),        // 58: df.plugin.WorldBlockBurnEvent
-	(*WorldCropTrampleEvent)(nil),      // 59: df.plugin.WorldCropTrampleEvent
-	(*WorldLeavesDecayEvent)(nil),      // 60: df.plugin.WorldLeavesDecayEvent
-	(*WorldEntitySpawnEvent)(nil),      // 61: df.plugin.WorldEntitySpawnEvent
-	(*WorldEntityDespawnEvent)(nil),    // 62: df.plugin.WorldEntityDespawnEvent
-	(*WorldExplosionEvent)(nil),        // 63: df.plugin.WorldExplosionEvent
-	(*WorldCloseEvent)(nil),            // 64: df.plugin.WorldCloseEvent
-	(*ActionBatch)(nil),                // 65: df.plugin.ActionBatch
-	(*EventResult)(nil),                // 66: df.plugin.EventResult
-	(*CommandSpec)(nil),                // 67: df.plugin.CommandSpec
-	(*CustomItemDefinition)(nil),       // 68: df.plugin.CustomItemDefinition
-	(*CustomBlockDefinition)(nil),      // 69: df.plugin.CustomBlockDefinition
+	(*EventGap)(nil),                   // 2: df.plugin.EventGap
+	(*CompressedEventBatch)(nil),       // 3: df.plugin.CompressedEventBatch
+	(*EventBatch)(nil),                 // 4: df.plugin.EventBatch
+	(*ServerInformationRequest)(nil),   // 5: df.plugin.ServerInformationRequest
+	(*ServerInformationResponse)(nil),  // 6: df.plugin.ServerInformationResponse
+	(*HostHello)(nil),                  // 7: df.plugin.HostHello
+	(*HostShutdown)(nil),               // 8: df.plugin.HostShutdown
+	(*HostPing)(nil),                   // 9: df.plugin.HostPing
+	(*PluginPong)(nil),                 // 10: df.plugin.PluginPong
+	(*EventEnvelope)(nil),              // 11: df.plugin.EventEnvelope
+	(*PluginToHost)(nil),               // 12: df.plugin.PluginToHost
+	(*PluginHello)(nil),                // 13: df.plugin.PluginHello
+	(*SessionResume)(nil),              // 14: df.plugin.SessionResume
+	(*PluginCrashEvent)(nil),           // 15: df.plugin.PluginCrashEvent
+	(*LogMessage)(nil),                 // 16: df.plugin.LogMessage
+	(*EventSubscribe)(nil),             // 17: df.plugin.EventSubscribe
+	(*ActionResult)(nil),               // 18: df.plugin.ActionResult
+	(*Registry)(nil),                   // 19: df.plugin.Registry
+	(*TraceContext)(nil),               // 20: df.plugin.TraceContext
+	(*PlayerJoinEvent)(nil),            // 21: df.plugin.PlayerJoinEvent
+	(*PlayerQuitEvent)(nil),            // 22: df.plugin.PlayerQuitEvent
+	(*PlayerMoveEvent)(nil),            // 23: df.plugin.PlayerMoveEvent
+	(*PlayerJumpEvent)(nil),            // 24: df.plugin.PlayerJumpEvent
+	(*PlayerTeleportEvent)(nil),        // 25: df.plugin.PlayerTeleportEvent
+	(*PlayerChangeWorldEvent)(nil),     // 26: df.plugin.PlayerChangeWorldEvent
+	(*PlayerToggleSprintEvent)(nil),    // 27: df.plugin.PlayerToggleSprintEvent
+	(*PlayerToggleSneakEvent)(nil),     // 28: df.plugin.PlayerToggleSneakEvent
+	(*ChatEvent)(nil),                  // 29: df.plugin.ChatEvent
+	(*PlayerFoodLossEvent)(nil),        // 30: df.plugin.PlayerFoodLossEvent
+	(*PlayerHealEvent)(nil),            // 31: df.plugin.PlayerHealEvent
+	(*PlayerHurtEvent)(nil),            // 32: df.plugin.PlayerHurtEvent
+	(*PlayerDeathEvent)(nil),           // 33: df.plugin.PlayerDeathEvent
+	(*PlayerRespawnEvent)(nil),         // 34: df.plugin.PlayerRespawnEvent
+	(*PlayerSkinChangeEvent)(nil),      // 35: df.plugin.PlayerSkinChangeEvent
+	(*PlayerFireExtinguishEvent)(nil),  // 36: df.plugin.PlayerFireExtinguishEvent
+	(*PlayerStartBreakEvent)(nil),      // 37: df.plugin.PlayerStartBreakEvent
+	(*BlockBreakEvent)(nil),            // 38: df.plugin.BlockBreakEvent
+	(*PlayerBlockPlaceEvent)(nil),      // 39: df.plugin.PlayerBlockPlaceEvent
+	(*PlayerBlockPickEvent)(nil),       // 40: df.plugin.PlayerBlockPickEvent
+	(*PlayerItemUseEvent)(nil),         // 41: df.plugin.PlayerItemUseEvent
+	(*PlayerItemUseOnBlockEvent)(nil),  // 42: df.plugin.PlayerItemUseOnBlockEvent
+	(*PlayerItemUseOnEntityEvent)(nil), // 43: df.plugin.PlayerItemUseOnEntityEvent
+	(*PlayerItemReleaseEvent)(nil),     // 44: df.plugin.PlayerItemReleaseEvent
+	(*PlayerItemConsumeEvent)(nil),     // 45: df.plugin.PlayerItemConsumeEvent
+	(*PlayerAttackEntityEvent)(nil),    // 46: df.plugin.PlayerAttackEntityEvent
+	(*PlayerExperienceGainEvent)(nil),  // 47: df.plugin.PlayerExperienceGainEvent
+	(*PlayerPunchAirEvent)(nil),        // 48: df.plugin.PlayerPunchAirEvent
+	(*PlayerSignEditEvent)(nil),        // 49: df.plugin.PlayerSignEditEvent
+	(*PlayerLecternPageTurnEvent)(nil), // 50: df.plugin.PlayerLecternPageTurnEvent
+	(*PlayerItemDamageEvent)(nil),      // 51: df.plugin.PlayerItemDamageEvent
+	(*PlayerItemPickupEvent)(nil),      // 52: df.plugin.PlayerItemPickupEvent
+	(*PlayerHeldSlotChangeEvent)(nil),  // 53: df.plugin.PlayerHeldSlotChangeEvent
+	(*PlayerItemDropEvent)(nil),        // 54: df.plugin.PlayerItemDropEvent
+	(*PlayerTransferEvent)(nil),        // 55: df.plugin.PlayerTransferEvent
+	(*CommandEvent)(nil),               // 56: df.plugin.CommandEvent
+	(*PlayerDiagnosticsEvent)(nil),     // 57: df.plugin.PlayerDiagnosticsEvent
+	(*WorldLiquidFlowEvent)(nil),       // 58: df.plugin.WorldLiquidFlowEvent
+	(*WorldLiquidDecayEvent)(nil),      // 59: df.plugin.WorldLiquidDecayEvent
+	(*WorldLiquidHardenEvent)(nil),     // 60: df.plugin.WorldLiquidHardenEvent
+	(*WorldSoundEvent)(nil),            // 61: df.plugin.WorldSoundEvent
+	(*WorldFireSpreadEvent)(nil),       // 62: df.plugin.WorldFireSpreadEvent
+	(*WorldBlockBurnEvent)(nil),        // 63: df.plugin.WorldBlockBurnEvent
+	(*WorldCropTrampleEvent)(nil),      // 64: df.plugin.WorldCropTrampleEvent
+	(*WorldLeavesDecayEvent)(nil),      // 65: df.plugin.WorldLeavesDecayEvent
+	(*WorldEntitySpawnEvent)(nil),      // 66: df.plugin.WorldEntitySpawnEvent
+	(*WorldEntityDespawnEvent)(nil),    // 67: df.plugin.WorldEntityDespawnEvent
+	(*WorldExplosionEvent)(nil),        // 68: df.plugin.WorldExplosionEvent
+	(*WorldCloseEvent)(nil),            // 69: df.plugin.WorldCloseEvent
+	(*RegistryRequest)(nil),            // 70: df.plugin.RegistryRequest
+	(*ActionBatch)(nil),                // 71: df.plugin.ActionBatch
+	(*PluginMetrics)(nil),              // 72: df.plugin.PluginMetrics
+	(*EventResult)(nil),                // 73: df.plugin.EventResult
+	(*CommandSpec)(nil),                // 74: df.plugin.CommandSpec
+	(*CustomItemDefinition)(nil),       // 75: df.plugin.CustomItemDefinition
+	(*CustomBlockDefinition)(nil),      // 76: df.plugin.CustomBlockDefinition
 }
 var file_plugin_proto_depIdxs = []int32{
-	8,  // 0: df.plugin.HostToPlugin.hello:type_name -> df.plugin.HostHello
-	9,  // 1: df.plugin.HostToPlugin.shutdown:type_name -> df.plugin.HostShutdown
-	7,  // 2: df.plugin.HostToPlugin.server_info:type_name -> df.plugin.ServerInformationResponse
-	10, // 3: df.plugin.HostToPlugin.event:type_name -> df.plugin.EventEnvelope
-	15, // 4: df.plugin.HostToPlugin.action_result:type_name -> df.plugin.ActionResult
-	5,  // 5: df.plugin.HostToPlugin.events:type_name -> df.plugin.EventBatch
-	2,  // 6: df.plugin.HostToPlugin.compressed_events:type_name -> df.plugin.CompressedEventBatch
-	3,  // 7: df.plugin.HostToPlugin.player_movements_packed:type_name -> df.plugin.PlayerMovementsPacked
-	4,  // 8: df.plugin.PlayerMovementsPacked.moves:type_name -> df.plugin.PackedPlayerMove
-	10, // 9: df.plugin.EventBatch.events:type_name -> df.plugin.EventEnvelope
-	0,  // 10: df.plugin.EventEnvelope.type:type_name -> df.plugin.EventType
-	16, // 11: df.plugin.EventEnvelope.player_join:type_name -> df.plugin.PlayerJoinEvent
-	17, // 12: df.plugin.EventEnvelope.player_quit:type_name -> df.plugin.PlayerQuitEvent
-	18, // 13: df.plugin.EventEnvelope.player_move:type_name -> df.plugin.PlayerMoveEvent
-	19, // 14: df.plugin.EventEnvelope.player_jump:type_name -> df.plugin.PlayerJumpEvent
-	20, // 15: df.plugin.EventEnvelope.player_teleport:type_name -> df.plugin.PlayerTeleportEvent
-	21, // 16: df.plugin.EventEnvelope.player_change_world:type_name -> df.plugin.PlayerChangeWorldEvent
-	22, // 17: df.plugin.EventEnvelope.player_toggle_sprint:type_name -> df.plugin.PlayerToggleSprintEvent
-	23, // 18: df.plugin.EventEnvelope.player_toggle_sneak:type_name -> df.plugin.PlayerToggleSneakEvent
-	24, // 19: df.plugin.EventEnvelope.chat:type_name -> df.plugin.ChatEvent
-	25, // 20: df.plugin.EventEnvelope.player_food_loss:type_name -> df.plugin.PlayerFoodLossEvent
-	26, // 21: df.plugin.EventEnvelope.player_heal:type_name -> df.plugin.PlayerHealEvent
-	27, // 22: df.plugin.EventEnvelope.player_hurt:type_name -> df.plugin.PlayerHurtEvent
-	28, // 23: df.plugin.EventEnvelope.player_death:type_name -> df.plugin.PlayerDeathEvent
-	29, // 24: df.plugin.EventEnvelope.player_respawn:type_name -> df.plugin.PlayerRespawnEvent
-	30, // 25: df.plugin.EventEnvelope.player_skin_change:type_name -> df.plugin.PlayerSkinChangeEvent
-	31, // 26: df.plugin.EventEnvelope.player_fire_extinguish:type_name -> df.plugin.PlayerFireExtinguishEvent
-	32, // 27: df.plugin.EventEnvelope.player_start_break:type_name -> df.plugin.PlayerStartBreakEvent
-	33, // 28: df.plugin.EventEnvelope.block_break:type_name -> df.plugin.BlockBreakEvent
-	34, // 29: df.plugin.EventEnvelope.player_block_place:type_name -> df.plugin.PlayerBlockPlaceEvent
-	35, // 30: df.plugin.EventEnvelope.player_block_pick:type_name -> df.plugin.PlayerBlockPickEvent
-	36, // 31: df.plugin.EventEnvelope.player_item_use:type_name -> df.plugin.PlayerItemUseEvent
-	37, // 32: df.plugin.EventEnvelope.player_item_use_on_block:type_name -> df.plugin.PlayerItemUseOnBlockEvent
-	38, // 33: df.plugin.EventEnvelope.player_item_use_on_entity:type_name -> df.plugin.PlayerItemUseOnEntityEvent
-	39, // 34: df.plugin.EventEnvelope.player_item_release:type_name -> df.plugin.PlayerItemReleaseEvent
-	40, // 35: df.plugin.EventEnvelope.player_item_consume:type_name -> df.plugin.PlayerItemConsumeEvent
-	41, // 36: df.plugin.EventEnvelope.player_attack_entity:type_name -> df.plugin.PlayerAttackEntityEvent
-	42, // 37: df.plugin.EventEnvelope.player_experience_gain:type_name -> df.plugin.PlayerExperienceGainEvent
-	43, // 38: df.plugin.EventEnvelope.player_punch_air:type_name -> df.plugin.PlayerPunchAirEvent
-	44, // 39: df.plugin.EventEnvelope.player_sign_edit:type_name -> df.plugin.PlayerSignEditEvent
-	45, // 40: df.plugin.EventEnvelope.player_lectern_page_turn:type_name -> df.plugin.PlayerLecternPageTurnEvent
-	46, // 41: df.plugin.EventEnvelope.player_item_damage:type_name -> df.plugin.PlayerItemDamageEvent
-	47, // 42: df.plugin.EventEnvelope.player_item_pickup:type_name -> df.plugin.PlayerItemPickupEvent
-	48, // 43: df.plugin.EventEnvelope.player_held_slot_change:type_name -> df.plugin.PlayerHeldSlotChangeEvent
-	49, // 44: df.plugin.EventEnvelope.player_item_drop:type_name -> df.plugin.PlayerItemDropEvent
-	50, // 45: df.plugin.EventEnvelope.player_transfer:type_name -> df.plugin.PlayerTransferEvent
-	51, // 46: df.plugin.EventEnvelope.command:type_name -> df.plugin.CommandEvent
-	52, // 47: df.plugin.EventEnvelope.player_diagnostics:type_name -> df.plugin.PlayerDiagnosticsEvent
-	53, // 48: df.plugin.EventEnvelope.world_liquid_flow:type_name -> df.plugin.WorldLiquidFlowEvent
-	54, // 49: df.plugin.EventEnvelope.world_liquid_decay:type_name -> df.plugin.WorldLiquidDecayEvent
-	55, // 50: df.plugin.EventEnvelope.world_liquid_harden:type_name -> df.plugin.WorldLiquidHardenEvent
-	56, // 51: df.plugin.EventEnvelope.world_sound:type_name -> df.plugin.WorldSoundEvent
-	57, // 52: df.plugin.EventEnvelope.world_fire_spread:type_name -> df.plugin.WorldFireSpreadEvent
-	58, // 53: df.plugin.EventEnvelope.world_block_burn:type_name -> df.plugin.WorldBlockBurnEvent
-	59, // 54: df.plugin.EventEnvelope.world_crop_trample:type_name -> df.plugin.WorldCropTrampleEvent
-	60, // 55: df.plugin.EventEnvelope.world_leaves_decay:type_name -> df.plugin.WorldLeavesDecayEvent
-	61, // 56: df.plugin.EventEnvelope.world_entity_spawn:type_name -> df.plugin.WorldEntitySpawnEvent
-	62, // 57: df.plugin.EventEnvelope.world_entity_despawn:type_name -> df.plugin.WorldEntityDespawnEvent
-	63, // 58: df.plugin.EventEnvelope.world_explosion:type_name -> df.plugin.WorldExplosionEvent
-	64, // 59: df.plugin.EventEnvelope.world_close:type_name -> df.plugin.WorldCloseEvent
-	12, // 60: df.plugin.PluginToHost.hello:type_name -> df.plugin.PluginHello
-	14, // 61: df.plugin.PluginToHost.subscribe:type_name -> df.plugin.EventSubscribe
-	6,  // 62: df.plugin.PluginToHost.server_info:type_name -> df.plugin.ServerInformationRequest
-	65, // 63: df.plugin.PluginToHost.actions:type_name -> df.plugin.ActionBatch
-	13, // 64: df.plugin.PluginToHost.log:type_name -> df.plugin.LogMessage
-	66, // 65: df.plugin.PluginToHost.event_result:type_name -> df.plugin.EventResult
-	67, // 66: df.plugin.PluginHello.commands:type_name -> df.plugin.CommandSpec
-	68, // 67: df.plugin.PluginHello.custom_items:type_name -> df.plugin.CustomItemDefinition
-	69, // 68: df.plugin.PluginHello.custom_blocks:type_name -> df.plugin.CustomBlockDefinition
-	0,  // 69: df.plugin.EventSubscribe.events:type_name -> df.plugin.EventType
-	11, // 70: df.plugin.Plugin.EventStream:input_type -> df.plugin.PluginToHost
-	1,  // 71: df.plugin.Plugin.EventStream:output_type -> df.plugin.HostToPlugin
-	71, // [71:72] is the sub-list for method output_type
-	70, // [70:71] is the sub-list for method input_type
-	70, // [70:70] is the sub-list for extension type_name
-	70, // [70:70] is the sub-list for extension extendee
-	0,  // [0:70] is the sub-list for field type_name
+	7,  // 0: df.plugin.HostToPlugin.hello:type_name -> df.plugin.HostHello
+	8,  // 1: df.plugin.HostToPlugin.shutdown:type_name -> df.plugin.HostShutdown
+	6,  // 2: df.plugin.HostToPlugin.server_info:type_name -> df.plugin.ServerInformationResponse
+	9,  // 3: df.plugin.HostToPlugin.ping:type_name -> df.plugin.HostPing
+	11, // 4: df.plugin.HostToPlugin.event:type_name -> df.plugin.EventEnvelope
+	18, // 5: df.plugin.HostToPlugin.action_result:type_name -> df.plugin.ActionResult
+	4,  // 6: df.plugin.HostToPlugin.events:type_name -> df.plugin.EventBatch
+	3,  // 7: df.plugin.HostToPlugin.compressed_events:type_name -> df.plugin.CompressedEventBatch
+	2,  // 8: df.plugin.HostToPlugin.event_gap:type_name -> df.plugin.EventGap
+	19, // 9: df.plugin.HostToPlugin.registry:type_name -> df.plugin.Registry
+	11, // 10: df.plugin.EventBatch.events:type_name -> df.plugin.EventEnvelope
+	0,  // 11: df.plugin.EventEnvelope.type:type_name -> df.plugin.EventType
+	20, // 12: df.plugin.EventEnvelope.trace:type_name -> df.plugin.TraceContext
+	21, // 13: df.plugin.EventEnvelope.player_join:type_name -> df.plugin.PlayerJoinEvent
+	22, // 14: df.plugin.EventEnvelope.player_quit:type_name -> df.plugin.PlayerQuitEvent
+	23, // 15: df.plugin.EventEnvelope.player_move:type_name -> df.plugin.PlayerMoveEvent
+	24, // 16: df.plugin.EventEnvelope.player_jump:type_name -> df.plugin.PlayerJumpEvent
+	25, // 17: df.plugin.EventEnvelope.player_teleport:type_name -> df.plugin.PlayerTeleportEvent
+	26, // 18: df.plugin.EventEnvelope.player_change_world:type_name -> df.plugin.PlayerChangeWorldEvent
+	27, // 19: df.plugin.EventEnvelope.player_toggle_sprint:type_name -> df.plugin.PlayerToggleSprintEvent
+	28, // 20: df.plugin.EventEnvelope.player_toggle_sneak:type_name -> df.plugin.PlayerToggleSneakEvent
+	29, // 21: df.plugin.EventEnvelope.chat:type_name -> df.plugin.ChatEvent
+	30, // 22: df.plugin.EventEnvelope.player_food_loss:type_name -> df.plugin.PlayerFoodLossEvent
+	31, // 23: df.plugin.EventEnvelope.player_heal:type_name -> df.plugin.PlayerHealEvent
+	32, // 24: df.plugin.EventEnvelope.player_hurt:type_name -> df.plugin.PlayerHurtEvent
+	33, // 25: df.plugin.EventEnvelope.player_death:type_name -> df.plugin.PlayerDeathEvent
+	34, // 26: df.plugin.EventEnvelope.player_respawn:type_name -> df.plugin.PlayerRespawnEvent
+	35, // 27: df.plugin.EventEnvelope.player_skin_change:type_name -> df.plugin.PlayerSkinChangeEvent
+	36, // 28: df.plugin.EventEnvelope.player_fire_extinguish:type_name -> df.plugin.PlayerFireExtinguishEvent
+	37, // 29: df.plugin.EventEnvelope.player_start_break:type_name -> df.plugin.PlayerStartBreakEvent
+	38, // 30: df.plugin.EventEnvelope.block_break:type_name -> df.plugin.BlockBreakEvent
+	39, // 31: df.plugin.EventEnvelope.player_block_place:type_name -> df.plugin.PlayerBlockPlaceEvent
+	40, // 32: df.plugin.EventEnvelope.player_block_pick:type_name -> df.plugin.PlayerBlockPickEvent
+	41, // 33: df.plugin.EventEnvelope.player_item_use:type_name -> df.plugin.PlayerItemUseEvent
+	42, // 34: df.plugin.EventEnvelope.player_item_use_on_block:type_name -> df.plugin.PlayerItemUseOnBlockEvent
+	43, // 35: df.plugin.EventEnvelope.player_item_use_on_entity:type_name -> df.plugin.PlayerItemUseOnEntityEvent
+	44, // 36: df.plugin.EventEnvelope.player_item_release:type_name -> df.plugin.PlayerItemReleaseEvent
+	45, // 37: df.plugin.EventEnvelope.player_item_consume:type_name -> df.plugin.PlayerItemConsumeEvent
+	46, // 38: df.plugin.EventEnvelope.player_attack_entity:type_name -> df.plugin.PlayerAttackEntityEvent
+	47, // 39: df.plugin.EventEnvelope.player_experience_gain:type_name -> df.plugin.PlayerExperienceGainEvent
+	48, // 40: df.plugin.EventEnvelope.player_punch_air:type_name -> df.plugin.PlayerPunchAirEvent
+	49, // 41: df.plugin.EventEnvelope.player_sign_edit:type_name -> df.plugin.PlayerSignEditEvent
+	50, // 42: df.plugin.EventEnvelope.player_lectern_page_turn:type_name -> df.plugin.PlayerLecternPageTurnEvent
+	51, // 43: df.plugin.EventEnvelope.player_item_damage:type_name -> df.plugin.PlayerItemDamageEvent
+	52, // 44: df.plugin.EventEnvelope.player_item_pickup:type_name -> df.plugin.PlayerItemPickupEvent
+	53, // 45: df.plugin.EventEnvelope.player_held_slot_change:type_name -> df.plugin.PlayerHeldSlotChangeEvent
+	54, // 46: df.plugin.EventEnvelope.player_item_drop:type_name -> df.plugin.PlayerItemDropEvent
+	55, // 47: df.plugin.EventEnvelope.player_transfer:type_name -> df.plugin.PlayerTransferEvent
+	56, // 48: df.plugin.EventEnvelope.command:type_name -> df.plugin.CommandEvent
+	57, // 49: df.plugin.EventEnvelope.player_diagnostics:type_name -> df.plugin.PlayerDiagnosticsEvent
+	58, // 50: df.plugin.EventEnvelope.world_liquid_flow:type_name -> df.plugin.WorldLiquidFlowEvent
+	59, // 51: df.plugin.EventEnvelope.world_liquid_decay:type_name -> df.plugin.WorldLiquidDecayEvent
+	60, // 52: df.plugin.EventEnvelope.world_liquid_harden:type_name -> df.plugin.WorldLiquidHardenEvent
+	61, // 53: df.plugin.EventEnvelope.world_sound:type_name -> df.plugin.WorldSoundEvent
+	62, // 54: df.plugin.EventEnvelope.world_fire_spread:type_name -> df.plugin.WorldFireSpreadEvent
+	63, // 55: df.plugin.EventEnvelope.world_block_burn:type_name -> df.plugin.WorldBlockBurnEvent
+	64, // 56: df.plugin.EventEnvelope.world_crop_trample:type_name -> df.plugin.WorldCropTrampleEvent
+	65, // 57: df.plugin.EventEnvelope.world_leaves_decay:type_name -> df.plugin.WorldLeavesDecayEvent
+	66, // 58: df.plugin.EventEnvelope.world_entity_spawn:type_name -> df.plugin.WorldEntitySpawnEvent
+	67, // 59: df.plugin.EventEnvelope.world_entity_despawn:type_name -> df.plugin.WorldEntityDespawnEvent
+	68, // 60: df.plugin.EventEnvelope.world_explosion:type_name -> df.plugin.WorldExplosionEvent
+	69, // 61: df.plugin.EventEnvelope.world_close:type_name -> df.plugin.WorldCloseEvent
+	15, // 62: df.plugin.EventEnvelope.plugin_crash:type_name -> df.plugin.PluginCrashEvent
+	13, // 63: df.plugin.PluginToHost.hello:type_name -> df.plugin.PluginHello
+	17, // 64: df.plugin.PluginToHost.subscribe:type_name -> df.plugin.EventSubscribe
+	5,  // 65: df.plugin.PluginToHost.server_info:type_name -> df.plugin.ServerInformationRequest
+	10, // 66: df.plugin.PluginToHost.pong:type_name -> df.plugin.PluginPong
+	70, // 67: df.plugin.PluginToHost.registry:type_name -> df.plugin.RegistryRequest
+	71, // 68: df.plugin.PluginToHost.actions:type_name -> df.plugin.ActionBatch
+	16, // 69: df.plugin.PluginToHost.log:type_name -> df.plugin.LogMessage
+	72, // 70: df.plugin.PluginToHost.metrics:type_name -> df.plugin.PluginMetrics
+	73, // 71: df.plugin.PluginToHost.event_result:type_name -> df.plugin.EventResult
+	74, // 72: df.plugin.PluginHello.commands:type_name -> df.plugin.CommandSpec
+	75, // 73: df.plugin.PluginHello.custom_items:type_name -> df.plugin.CustomItemDefinition
+	76, // 74: df.plugin.PluginHello.custom_blocks:type_name -> df.plugin.CustomBlockDefinition
+	14, // 75: df.plugin.PluginHello.resume:type_name -> df.plugin.SessionResume
+	0,  // 76: df.plugin.EventSubscribe.events:type_name -> df.plugin.EventType
+	12, // 77: df.plugin.Plugin.EventStream:input_type -> df.plugin.PluginToHost
+	1,  // 78: df.plugin.Plugin.EventStream:output_type -> df.plugin.HostToPlugin
+	78, // [78:79] is the sub-list for method output_type
+	77, // [77:78] is the sub-list for method input_type
+	77, // [77:77] is the sub-list for extension type_name
+	77, // [77:77] is the sub-list for extension extendee
+	0,  // [0:77] is the sub-list for field type_name
 }
 
 func init() { file_plugin_proto_init() }
@@ -2368,17 +2783,21 @@ func file_plugin_proto_init() {
 	file_mutations_proto_init()
 	file_common_proto_init()
 	file_action_results_proto_init()
+	file_metrics_proto_init()
+	file_registry_proto_init()
 	file_plugin_proto_msgTypes[0].OneofWrappers = []any{
 		(*HostToPlugin_Hello)(nil),
 		(*HostToPlugin_Shutdown)(nil),
 		(*HostToPlugin_ServerInfo)(nil),
+		(*HostToPlugin_Ping)(nil),
 		(*HostToPlugin_Event)(nil),
 		(*HostToPlugin_ActionResult)(nil),
 		(*HostToPlugin_Events)(nil),
 		(*HostToPlugin_CompressedEvents)(nil),
-		(*HostToPlugin_PlayerMovementsPacked)(nil),
+		(*HostToPlugin_EventGap)(nil),
+		(*HostToPlugin_Registry)(nil),
 	}
-	file_plugin_proto_msgTypes[9].OneofWrappers = []any{
+	file_plugin_proto_msgTypes[10].OneofWrappers = []any{
 		(*EventEnvelope_PlayerJoin)(nil),
 		(*EventEnvelope_PlayerQuit)(nil),
 		(*EventEnvelope_PlayerMove)(nil),
@@ -2428,22 +2847,27 @@ func file_plugin_proto_init() {
 		(*EventEnvelope_WorldEntityDespawn)(nil),
 		(*EventEnvelope_WorldExplosion)(nil),
 		(*EventEnvelope_WorldClose)(nil),
+		(*EventEnvelope_PluginCrash)(nil),
 	}
-	file_plugin_proto_msgTypes[10].OneofWrappers = []any{
+	file_plugin_proto_msgTypes[11].OneofWrappers = []any{
 		(*PluginToHost_Hello)(nil),
 		(*PluginToHost_Subscribe)(nil),
 		(*PluginToHost_ServerInfo)(nil),
+		(*PluginToHost_Pong)(nil),
+		(*PluginToHost_Registry)(nil),
 		(*PluginToHost_Actions)(nil),
 		(*PluginToHost_Log)(nil),
+		(*PluginToHost_Metrics)(nil),
 		(*PluginToHost_EventResult)(nil),
 	}
+	file_plugin_proto_msgTypes[14].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_proto_rawDesc), len(file_plugin_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   14,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
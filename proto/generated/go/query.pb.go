@@ -0,0 +1,495 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: query.proto
+
+package generated
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PlayerQuery looks up an online player by UUID, or by name if player_uuid is empty.
+type PlayerQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerUuid    string                 `protobuf:"bytes,1,opt,name=player_uuid,json=playerUuid,proto3" json:"player_uuid,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerQuery) Reset() {
+	*x = PlayerQuery{}
+	mi := &file_query_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerQuery) ProtoMessage() {}
+
+func (x *PlayerQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_query_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerQuery.ProtoReflect.Descriptor instead.
+func (*PlayerQuery) Descriptor() ([]byte, []int) {
+	return file_query_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PlayerQuery) GetPlayerUuid() string {
+	if x != nil {
+		return x.PlayerUuid
+	}
+	return ""
+}
+
+func (x *PlayerQuery) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PlayerInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Player          *EntityRef             `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Xuid            string                 `protobuf:"bytes,3,opt,name=xuid,proto3" json:"xuid,omitempty"`
+	World           *WorldRef              `protobuf:"bytes,4,opt,name=world,proto3" json:"world,omitempty"`
+	GameMode        GameMode               `protobuf:"varint,5,opt,name=game_mode,json=gameMode,proto3,enum=df.plugin.GameMode" json:"game_mode,omitempty"`
+	Health          float64                `protobuf:"fixed64,6,opt,name=health,proto3" json:"health,omitempty"`
+	MaxHealth       float64                `protobuf:"fixed64,7,opt,name=max_health,json=maxHealth,proto3" json:"max_health,omitempty"`
+	Food            int32                  `protobuf:"varint,8,opt,name=food,proto3" json:"food,omitempty"`
+	ExperienceLevel int32                  `protobuf:"varint,9,opt,name=experience_level,json=experienceLevel,proto3" json:"experience_level,omitempty"`
+	LatencyMs       int64                  `protobuf:"varint,10,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	Locale          string                 `protobuf:"bytes,11,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PlayerInfo) Reset() {
+	*x = PlayerInfo{}
+	mi := &file_query_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerInfo) ProtoMessage() {}
+
+func (x *PlayerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_query_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerInfo.ProtoReflect.Descriptor instead.
+func (*PlayerInfo) Descriptor() ([]byte, []int) {
+	return file_query_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PlayerInfo) GetPlayer() *EntityRef {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+func (x *PlayerInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlayerInfo) GetXuid() string {
+	if x != nil {
+		return x.Xuid
+	}
+	return ""
+}
+
+func (x *PlayerInfo) GetWorld() *WorldRef {
+	if x != nil {
+		return x.World
+	}
+	return nil
+}
+
+func (x *PlayerInfo) GetGameMode() GameMode {
+	if x != nil {
+		return x.GameMode
+	}
+	return GameMode_SURVIVAL
+}
+
+func (x *PlayerInfo) GetHealth() float64 {
+	if x != nil {
+		return x.Health
+	}
+	return 0
+}
+
+func (x *PlayerInfo) GetMaxHealth() float64 {
+	if x != nil {
+		return x.MaxHealth
+	}
+	return 0
+}
+
+func (x *PlayerInfo) GetFood() int32 {
+	if x != nil {
+		return x.Food
+	}
+	return 0
+}
+
+func (x *PlayerInfo) GetExperienceLevel() int32 {
+	if x != nil {
+		return x.ExperienceLevel
+	}
+	return 0
+}
+
+func (x *PlayerInfo) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *PlayerInfo) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ServerQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerQuery) Reset() {
+	*x = ServerQuery{}
+	mi := &file_query_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerQuery) ProtoMessage() {}
+
+func (x *ServerQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_query_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerQuery.ProtoReflect.Descriptor instead.
+func (*ServerQuery) Descriptor() ([]byte, []int) {
+	return file_query_proto_rawDescGZIP(), []int{2}
+}
+
+type ServerInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BootId         string                 `protobuf:"bytes,1,opt,name=boot_id,json=bootId,proto3" json:"boot_id,omitempty"`
+	ApiVersion     string                 `protobuf:"bytes,2,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	PlayerCount    int32                  `protobuf:"varint,3,opt,name=player_count,json=playerCount,proto3" json:"player_count,omitempty"`
+	MaxPlayerCount int32                  `protobuf:"varint,4,opt,name=max_player_count,json=maxPlayerCount,proto3" json:"max_player_count,omitempty"`
+	Worlds         []*WorldRef            `protobuf:"bytes,5,rep,name=worlds,proto3" json:"worlds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ServerInfo) Reset() {
+	*x = ServerInfo{}
+	mi := &file_query_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerInfo) ProtoMessage() {}
+
+func (x *ServerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_query_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerInfo.ProtoReflect.Descriptor instead.
+func (*ServerInfo) Descriptor() ([]byte, []int) {
+	return file_query_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ServerInfo) GetBootId() string {
+	if x != nil {
+		return x.BootId
+	}
+	return ""
+}
+
+func (x *ServerInfo) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *ServerInfo) GetPlayerCount() int32 {
+	if x != nil {
+		return x.PlayerCount
+	}
+	return 0
+}
+
+func (x *ServerInfo) GetMaxPlayerCount() int32 {
+	if x != nil {
+		return x.MaxPlayerCount
+	}
+	return 0
+}
+
+func (x *ServerInfo) GetWorlds() []*WorldRef {
+	if x != nil {
+		return x.Worlds
+	}
+	return nil
+}
+
+var File_query_proto protoreflect.FileDescriptor
+
+const file_query_proto_rawDesc = "" +
+	"\n" +
+	"\vquery.proto\x12\tdf.plugin\x1a\fcommon.proto\x1a\ractions.proto\x1a\x14action_results.proto\"B\n" +
+	"\vPlayerQuery\x12\x1f\n" +
+	"\vplayer_uuid\x18\x01 \x01(\tR\n" +
+	"playerUuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xec\x02\n" +
+	"\n" +
+	"PlayerInfo\x12,\n" +
+	"\x06player\x18\x01 \x01(\v2\x14.df.plugin.EntityRefR\x06player\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04xuid\x18\x03 \x01(\tR\x04xuid\x12)\n" +
+	"\x05world\x18\x04 \x01(\v2\x13.df.plugin.WorldRefR\x05world\x120\n" +
+	"\tgame_mode\x18\x05 \x01(\x0e2\x13.df.plugin.GameModeR\bgameMode\x12\x16\n" +
+	"\x06health\x18\x06 \x01(\x01R\x06health\x12\x1d\n" +
+	"\n" +
+	"max_health\x18\a \x01(\x01R\tmaxHealth\x12\x12\n" +
+	"\x04food\x18\b \x01(\x05R\x04food\x12)\n" +
+	"\x10experience_level\x18\t \x01(\x05R\x0fexperienceLevel\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\n" +
+	" \x01(\x03R\tlatencyMs\x12\x16\n" +
+	"\x06locale\x18\v \x01(\tR\x06locale\"\r\n" +
+	"\vServerQuery\"\xc0\x01\n" +
+	"\n" +
+	"ServerInfo\x12\x17\n" +
+	"\aboot_id\x18\x01 \x01(\tR\x06bootId\x12\x1f\n" +
+	"\vapi_version\x18\x02 \x01(\tR\n" +
+	"apiVersion\x12!\n" +
+	"\fplayer_count\x18\x03 \x01(\x05R\vplayerCount\x12(\n" +
+	"\x10max_player_count\x18\x04 \x01(\x05R\x0emaxPlayerCount\x12+\n" +
+	"\x06worlds\x18\x05 \x03(\v2\x13.df.plugin.WorldRefR\x06worlds2\xb6\v\n" +
+	"\x05Query\x12T\n" +
+	"\rWorldEntities\x12#.df.plugin.WorldQueryEntitiesAction\x1a\x1e.df.plugin.WorldEntitiesResult\x12Q\n" +
+	"\fWorldPlayers\x12\".df.plugin.WorldQueryPlayersAction\x1a\x1d.df.plugin.WorldPlayersResult\x12f\n" +
+	"\x13WorldEntitiesWithin\x12).df.plugin.WorldQueryEntitiesWithinAction\x1a$.df.plugin.WorldEntitiesWithinResult\x12i\n" +
+	"\x14WorldDefaultGameMode\x12*.df.plugin.WorldQueryDefaultGameModeAction\x1a%.df.plugin.WorldDefaultGameModeResult\x12]\n" +
+	"\x10WorldPlayerSpawn\x12&.df.plugin.WorldQueryPlayerSpawnAction\x1a!.df.plugin.WorldPlayerSpawnResult\x12K\n" +
+	"\n" +
+	"WorldBlock\x12 .df.plugin.WorldQueryBlockAction\x1a\x1b.df.plugin.WorldBlockResult\x12K\n" +
+	"\n" +
+	"WorldBiome\x12 .df.plugin.WorldQueryBiomeAction\x1a\x1b.df.plugin.WorldBiomeResult\x12K\n" +
+	"\n" +
+	"WorldLight\x12 .df.plugin.WorldQueryLightAction\x1a\x1b.df.plugin.WorldLightResult\x12T\n" +
+	"\rWorldSkyLight\x12#.df.plugin.WorldQuerySkyLightAction\x1a\x1e.df.plugin.WorldSkyLightResult\x12]\n" +
+	"\x10WorldTemperature\x12&.df.plugin.WorldQueryTemperatureAction\x1a!.df.plugin.WorldTemperatureResult\x12`\n" +
+	"\x11WorldHighestBlock\x12'.df.plugin.WorldQueryHighestBlockAction\x1a\".df.plugin.WorldHighestBlockResult\x12W\n" +
+	"\x0eWorldRainingAt\x12$.df.plugin.WorldQueryRainingAtAction\x1a\x1f.df.plugin.WorldRainingAtResult\x12W\n" +
+	"\x0eWorldSnowingAt\x12$.df.plugin.WorldQuerySnowingAtAction\x1a\x1f.df.plugin.WorldSnowingAtResult\x12`\n" +
+	"\x11WorldThunderingAt\x12'.df.plugin.WorldQueryThunderingAtAction\x1a\".df.plugin.WorldThunderingAtResult\x12N\n" +
+	"\vWorldLiquid\x12!.df.plugin.WorldQueryLiquidAction\x1a\x1c.df.plugin.WorldLiquidResult\x127\n" +
+	"\x06Player\x12\x16.df.plugin.PlayerQuery\x1a\x15.df.plugin.PlayerInfo\x127\n" +
+	"\x06Server\x12\x16.df.plugin.ServerQuery\x1a\x15.df.plugin.ServerInfoB\x89\x01\n" +
+	"\rcom.df.pluginB\n" +
+	"QueryProtoP\x01Z'github.com/secmc/plugin/proto/generated\xa2\x02\x03DPX\xaa\x02\tDf.Plugin\xca\x02\tDf\\Plugin\xe2\x02\x15Df\\Plugin\\GPBMetadata\xea\x02\n" +
+	"Df::Pluginb\x06proto3"
+
+var (
+	file_query_proto_rawDescOnce sync.Once
+	file_query_proto_rawDescData []byte
+)
+
+func file_query_proto_rawDescGZIP() []byte {
+	file_query_proto_rawDescOnce.Do(func() {
+		file_query_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_query_proto_rawDesc), len(file_query_proto_rawDesc)))
+	})
+	return file_query_proto_rawDescData
+}
+
+var file_query_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_query_proto_goTypes = []any{
+	(*PlayerQuery)(nil),                     // 0: df.plugin.PlayerQuery
+	(*PlayerInfo)(nil),                      // 1: df.plugin.PlayerInfo
+	(*ServerQuery)(nil),                     // 2: df.plugin.ServerQuery
+	(*ServerInfo)(nil),                      // 3: df.plugin.ServerInfo
+	(*EntityRef)(nil),                       // 4: df.plugin.EntityRef
+	(*WorldRef)(nil),                        // 5: df.plugin.WorldRef
+	(GameMode)(0),                           // 6: df.plugin.GameMode
+	(*WorldQueryEntitiesAction)(nil),        // 7: df.plugin.WorldQueryEntitiesAction
+	(*WorldQueryPlayersAction)(nil),         // 8: df.plugin.WorldQueryPlayersAction
+	(*WorldQueryEntitiesWithinAction)(nil),  // 9: df.plugin.WorldQueryEntitiesWithinAction
+	(*WorldQueryDefaultGameModeAction)(nil), // 10: df.plugin.WorldQueryDefaultGameModeAction
+	(*WorldQueryPlayerSpawnAction)(nil),     // 11: df.plugin.WorldQueryPlayerSpawnAction
+	(*WorldQueryBlockAction)(nil),           // 12: df.plugin.WorldQueryBlockAction
+	(*WorldQueryBiomeAction)(nil),           // 13: df.plugin.WorldQueryBiomeAction
+	(*WorldQueryLightAction)(nil),           // 14: df.plugin.WorldQueryLightAction
+	(*WorldQuerySkyLightAction)(nil),        // 15: df.plugin.WorldQuerySkyLightAction
+	(*WorldQueryTemperatureAction)(nil),     // 16: df.plugin.WorldQueryTemperatureAction
+	(*WorldQueryHighestBlockAction)(nil),    // 17: df.plugin.WorldQueryHighestBlockAction
+	(*WorldQueryRainingAtAction)(nil),       // 18: df.plugin.WorldQueryRainingAtAction
+	(*WorldQuerySnowingAtAction)(nil),       // 19: df.plugin.WorldQuerySnowingAtAction
+	(*WorldQueryThunderingAtAction)(nil),    // 20: df.plugin.WorldQueryThunderingAtAction
+	(*WorldQueryLiquidAction)(nil),          // 21: df.plugin.WorldQueryLiquidAction
+	(*WorldEntitiesResult)(nil),             // 22: df.plugin.WorldEntitiesResult
+	(*WorldPlayersResult)(nil),              // 23: df.plugin.WorldPlayersResult
+	(*WorldEntitiesWithinResult)(nil),       // 24: df.plugin.WorldEntitiesWithinResult
+	(*WorldDefaultGameModeResult)(nil),      // 25: df.plugin.WorldDefaultGameModeResult
+	(*WorldPlayerSpawnResult)(nil),          // 26: df.plugin.WorldPlayerSpawnResult
+	(*WorldBlockResult)(nil),                // 27: df.plugin.WorldBlockResult
+	(*WorldBiomeResult)(nil),                // 28: df.plugin.WorldBiomeResult
+	(*WorldLightResult)(nil),                // 29: df.plugin.WorldLightResult
+	(*WorldSkyLightResult)(nil),             // 30: df.plugin.WorldSkyLightResult
+	(*WorldTemperatureResult)(nil),          // 31: df.plugin.WorldTemperatureResult
+	(*WorldHighestBlockResult)(nil),         // 32: df.plugin.WorldHighestBlockResult
+	(*WorldRainingAtResult)(nil),            // 33: df.plugin.WorldRainingAtResult
+	(*WorldSnowingAtResult)(nil),            // 34: df.plugin.WorldSnowingAtResult
+	(*WorldThunderingAtResult)(nil),         // 35: df.plugin.WorldThunderingAtResult
+	(*WorldLiquidResult)(nil),               // 36: df.plugin.WorldLiquidResult
+}
+var file_query_proto_depIdxs = []int32{
+	4,  // 0: df.plugin.PlayerInfo.player:type_name -> df.plugin.EntityRef
+	5,  // 1: df.plugin.PlayerInfo.world:type_name -> df.plugin.WorldRef
+	6,  // 2: df.plugin.PlayerInfo.game_mode:type_name -> df.plugin.GameMode
+	5,  // 3: df.plugin.ServerInfo.worlds:type_name -> df.plugin.WorldRef
+	7,  // 4: df.plugin.Query.WorldEntities:input_type -> df.plugin.WorldQueryEntitiesAction
+	8,  // 5: df.plugin.Query.WorldPlayers:input_type -> df.plugin.WorldQueryPlayersAction
+	9,  // 6: df.plugin.Query.WorldEntitiesWithin:input_type -> df.plugin.WorldQueryEntitiesWithinAction
+	10, // 7: df.plugin.Query.WorldDefaultGameMode:input_type -> df.plugin.WorldQueryDefaultGameModeAction
+	11, // 8: df.plugin.Query.WorldPlayerSpawn:input_type -> df.plugin.WorldQueryPlayerSpawnAction
+	12, // 9: df.plugin.Query.WorldBlock:input_type -> df.plugin.WorldQueryBlockAction
+	13, // 10: df.plugin.Query.WorldBiome:input_type -> df.plugin.WorldQueryBiomeAction
+	14, // 11: df.plugin.Query.WorldLight:input_type -> df.plugin.WorldQueryLightAction
+	15, // 12: df.plugin.Query.WorldSkyLight:input_type -> df.plugin.WorldQuerySkyLightAction
+	16, // 13: df.plugin.Query.WorldTemperature:input_type -> df.plugin.WorldQueryTemperatureAction
+	17, // 14: df.plugin.Query.WorldHighestBlock:input_type -> df.plugin.WorldQueryHighestBlockAction
+	18, // 15: df.plugin.Query.WorldRainingAt:input_type -> df.plugin.WorldQueryRainingAtAction
+	19, // 16: df.plugin.Query.WorldSnowingAt:input_type -> df.plugin.WorldQuerySnowingAtAction
+	20, // 17: df.plugin.Query.WorldThunderingAt:input_type -> df.plugin.WorldQueryThunderingAtAction
+	21, // 18: df.plugin.Query.WorldLiquid:input_type -> df.plugin.WorldQueryLiquidAction
+	0,  // 19: df.plugin.Query.Player:input_type -> df.plugin.PlayerQuery
+	2,  // 20: df.plugin.Query.Server:input_type -> df.plugin.ServerQuery
+	22, // 21: df.plugin.Query.WorldEntities:output_type -> df.plugin.WorldEntitiesResult
+	23, // 22: df.plugin.Query.WorldPlayers:output_type -> df.plugin.WorldPlayersResult
+	24, // 23: df.plugin.Query.WorldEntitiesWithin:output_type -> df.plugin.WorldEntitiesWithinResult
+	25, // 24: df.plugin.Query.WorldDefaultGameMode:output_type -> df.plugin.WorldDefaultGameModeResult
+	26, // 25: df.plugin.Query.WorldPlayerSpawn:output_type -> df.plugin.WorldPlayerSpawnResult
+	27, // 26: df.plugin.Query.WorldBlock:output_type -> df.plugin.WorldBlockResult
+	28, // 27: df.plugin.Query.WorldBiome:output_type -> df.plugin.WorldBiomeResult
+	29, // 28: df.plugin.Query.WorldLight:output_type -> df.plugin.WorldLightResult
+	30, // 29: df.plugin.Query.WorldSkyLight:output_type -> df.plugin.WorldSkyLightResult
+	31, // 30: df.plugin.Query.WorldTemperature:output_type -> df.plugin.WorldTemperatureResult
+	32, // 31: df.plugin.Query.WorldHighestBlock:output_type -> df.plugin.WorldHighestBlockResult
+	33, // 32: df.plugin.Query.WorldRainingAt:output_type -> df.plugin.WorldRainingAtResult
+	34, // 33: df.plugin.Query.WorldSnowingAt:output_type -> df.plugin.WorldSnowingAtResult
+	35, // 34: df.plugin.Query.WorldThunderingAt:output_type -> df.plugin.WorldThunderingAtResult
+	36, // 35: df.plugin.Query.WorldLiquid:output_type -> df.plugin.WorldLiquidResult
+	1,  // 36: df.plugin.Query.Player:output_type -> df.plugin.PlayerInfo
+	3,  // 37: df.plugin.Query.Server:output_type -> df.plugin.ServerInfo
+	21, // [21:38] is the sub-list for method output_type
+	4,  // [4:21] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_query_proto_init() }
+func file_query_proto_init() {
+	if File_query_proto != nil {
+		return
+	}
+	file_common_proto_init()
+	file_actions_proto_init()
+	file_action_results_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_query_proto_rawDesc), len(file_query_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_query_proto_goTypes,
+		DependencyIndexes: file_query_proto_depIdxs,
+		MessageInfos:      file_query_proto_msgTypes,
+	}.Build()
+	File_query_proto = out.File
+	file_query_proto_goTypes = nil
+	file_query_proto_depIdxs = nil
+}
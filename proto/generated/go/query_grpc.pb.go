@@ -0,0 +1,747 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: query.proto
+
+package generated
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Query_WorldEntities_FullMethodName        = "/df.plugin.Query/WorldEntities"
+	Query_WorldPlayers_FullMethodName         = "/df.plugin.Query/WorldPlayers"
+	Query_WorldEntitiesWithin_FullMethodName  = "/df.plugin.Query/WorldEntitiesWithin"
+	Query_WorldDefaultGameMode_FullMethodName = "/df.plugin.Query/WorldDefaultGameMode"
+	Query_WorldPlayerSpawn_FullMethodName     = "/df.plugin.Query/WorldPlayerSpawn"
+	Query_WorldBlock_FullMethodName           = "/df.plugin.Query/WorldBlock"
+	Query_WorldBiome_FullMethodName           = "/df.plugin.Query/WorldBiome"
+	Query_WorldLight_FullMethodName           = "/df.plugin.Query/WorldLight"
+	Query_WorldSkyLight_FullMethodName        = "/df.plugin.Query/WorldSkyLight"
+	Query_WorldTemperature_FullMethodName     = "/df.plugin.Query/WorldTemperature"
+	Query_WorldHighestBlock_FullMethodName    = "/df.plugin.Query/WorldHighestBlock"
+	Query_WorldRainingAt_FullMethodName       = "/df.plugin.Query/WorldRainingAt"
+	Query_WorldSnowingAt_FullMethodName       = "/df.plugin.Query/WorldSnowingAt"
+	Query_WorldThunderingAt_FullMethodName    = "/df.plugin.Query/WorldThunderingAt"
+	Query_WorldLiquid_FullMethodName          = "/df.plugin.Query/WorldLiquid"
+	Query_Player_FullMethodName               = "/df.plugin.Query/Player"
+	Query_Server_FullMethodName               = "/df.plugin.Query/Server"
+)
+
+// QueryClient is the client API for Query service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Query answers read-only questions with unary calls, as an alternative to sending query actions
+// and matching their ActionResult by correlation_id. It is served on the same gRPC listener as
+// Plugin. Every call must carry the caller's plugin ID in the "plugin-id" metadata entry, and
+// that plugin must have an open event stream. Calls that do not arrive over a Unix socket must
+// also carry the plugin's launch token in the "plugin-token" metadata entry.
+type QueryClient interface {
+	// World
+	WorldEntities(ctx context.Context, in *WorldQueryEntitiesAction, opts ...grpc.CallOption) (*WorldEntitiesResult, error)
+	WorldPlayers(ctx context.Context, in *WorldQueryPlayersAction, opts ...grpc.CallOption) (*WorldPlayersResult, error)
+	WorldEntitiesWithin(ctx context.Context, in *WorldQueryEntitiesWithinAction, opts ...grpc.CallOption) (*WorldEntitiesWithinResult, error)
+	WorldDefaultGameMode(ctx context.Context, in *WorldQueryDefaultGameModeAction, opts ...grpc.CallOption) (*WorldDefaultGameModeResult, error)
+	WorldPlayerSpawn(ctx context.Context, in *WorldQueryPlayerSpawnAction, opts ...grpc.CallOption) (*WorldPlayerSpawnResult, error)
+	WorldBlock(ctx context.Context, in *WorldQueryBlockAction, opts ...grpc.CallOption) (*WorldBlockResult, error)
+	WorldBiome(ctx context.Context, in *WorldQueryBiomeAction, opts ...grpc.CallOption) (*WorldBiomeResult, error)
+	WorldLight(ctx context.Context, in *WorldQueryLightAction, opts ...grpc.CallOption) (*WorldLightResult, error)
+	WorldSkyLight(ctx context.Context, in *WorldQuerySkyLightAction, opts ...grpc.CallOption) (*WorldSkyLightResult, error)
+	WorldTemperature(ctx context.Context, in *WorldQueryTemperatureAction, opts ...grpc.CallOption) (*WorldTemperatureResult, error)
+	WorldHighestBlock(ctx context.Context, in *WorldQueryHighestBlockAction, opts ...grpc.CallOption) (*WorldHighestBlockResult, error)
+	WorldRainingAt(ctx context.Context, in *WorldQueryRainingAtAction, opts ...grpc.CallOption) (*WorldRainingAtResult, error)
+	WorldSnowingAt(ctx context.Context, in *WorldQuerySnowingAtAction, opts ...grpc.CallOption) (*WorldSnowingAtResult, error)
+	WorldThunderingAt(ctx context.Context, in *WorldQueryThunderingAtAction, opts ...grpc.CallOption) (*WorldThunderingAtResult, error)
+	WorldLiquid(ctx context.Context, in *WorldQueryLiquidAction, opts ...grpc.CallOption) (*WorldLiquidResult, error)
+	// Player
+	Player(ctx context.Context, in *PlayerQuery, opts ...grpc.CallOption) (*PlayerInfo, error)
+	// Server
+	Server(ctx context.Context, in *ServerQuery, opts ...grpc.CallOption) (*ServerInfo, error)
+}
+
+type queryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewQueryClient(cc grpc.ClientConnInterface) QueryClient {
+	return &queryClient{cc}
+}
+
+func (c *queryClient) WorldEntities(ctx context.Context, in *WorldQueryEntitiesAction, opts ...grpc.CallOption) (*WorldEntitiesResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldEntitiesResult)
+	err := c.cc.Invoke(ctx, Query_WorldEntities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldPlayers(ctx context.Context, in *WorldQueryPlayersAction, opts ...grpc.CallOption) (*WorldPlayersResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldPlayersResult)
+	err := c.cc.Invoke(ctx, Query_WorldPlayers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldEntitiesWithin(ctx context.Context, in *WorldQueryEntitiesWithinAction, opts ...grpc.CallOption) (*WorldEntitiesWithinResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldEntitiesWithinResult)
+	err := c.cc.Invoke(ctx, Query_WorldEntitiesWithin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldDefaultGameMode(ctx context.Context, in *WorldQueryDefaultGameModeAction, opts ...grpc.CallOption) (*WorldDefaultGameModeResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldDefaultGameModeResult)
+	err := c.cc.Invoke(ctx, Query_WorldDefaultGameMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldPlayerSpawn(ctx context.Context, in *WorldQueryPlayerSpawnAction, opts ...grpc.CallOption) (*WorldPlayerSpawnResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldPlayerSpawnResult)
+	err := c.cc.Invoke(ctx, Query_WorldPlayerSpawn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldBlock(ctx context.Context, in *WorldQueryBlockAction, opts ...grpc.CallOption) (*WorldBlockResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldBlockResult)
+	err := c.cc.Invoke(ctx, Query_WorldBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldBiome(ctx context.Context, in *WorldQueryBiomeAction, opts ...grpc.CallOption) (*WorldBiomeResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldBiomeResult)
+	err := c.cc.Invoke(ctx, Query_WorldBiome_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldLight(ctx context.Context, in *WorldQueryLightAction, opts ...grpc.CallOption) (*WorldLightResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldLightResult)
+	err := c.cc.Invoke(ctx, Query_WorldLight_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldSkyLight(ctx context.Context, in *WorldQuerySkyLightAction, opts ...grpc.CallOption) (*WorldSkyLightResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldSkyLightResult)
+	err := c.cc.Invoke(ctx, Query_WorldSkyLight_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldTemperature(ctx context.Context, in *WorldQueryTemperatureAction, opts ...grpc.CallOption) (*WorldTemperatureResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldTemperatureResult)
+	err := c.cc.Invoke(ctx, Query_WorldTemperature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldHighestBlock(ctx context.Context, in *WorldQueryHighestBlockAction, opts ...grpc.CallOption) (*WorldHighestBlockResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldHighestBlockResult)
+	err := c.cc.Invoke(ctx, Query_WorldHighestBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldRainingAt(ctx context.Context, in *WorldQueryRainingAtAction, opts ...grpc.CallOption) (*WorldRainingAtResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldRainingAtResult)
+	err := c.cc.Invoke(ctx, Query_WorldRainingAt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldSnowingAt(ctx context.Context, in *WorldQuerySnowingAtAction, opts ...grpc.CallOption) (*WorldSnowingAtResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldSnowingAtResult)
+	err := c.cc.Invoke(ctx, Query_WorldSnowingAt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldThunderingAt(ctx context.Context, in *WorldQueryThunderingAtAction, opts ...grpc.CallOption) (*WorldThunderingAtResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldThunderingAtResult)
+	err := c.cc.Invoke(ctx, Query_WorldThunderingAt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) WorldLiquid(ctx context.Context, in *WorldQueryLiquidAction, opts ...grpc.CallOption) (*WorldLiquidResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorldLiquidResult)
+	err := c.cc.Invoke(ctx, Query_WorldLiquid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) Player(ctx context.Context, in *PlayerQuery, opts ...grpc.CallOption) (*PlayerInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlayerInfo)
+	err := c.cc.Invoke(ctx, Query_Player_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) Server(ctx context.Context, in *ServerQuery, opts ...grpc.CallOption) (*ServerInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerInfo)
+	err := c.cc.Invoke(ctx, Query_Server_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QueryServer is the server API for Query service.
+// All implementations must embed UnimplementedQueryServer
+// for forward compatibility.
+//
+// Query answers read-only questions with unary calls, as an alternative to sending query actions
+// and matching their ActionResult by correlation_id. It is served on the same gRPC listener as
+// Plugin. Every call must carry the caller's plugin ID in the "plugin-id" metadata entry, and
+// that plugin must have an open event stream. Calls that do not arrive over a Unix socket must
+// also carry the plugin's launch token in the "plugin-token" metadata entry.
+type QueryServer interface {
+	// World
+	WorldEntities(context.Context, *WorldQueryEntitiesAction) (*WorldEntitiesResult, error)
+	WorldPlayers(context.Context, *WorldQueryPlayersAction) (*WorldPlayersResult, error)
+	WorldEntitiesWithin(context.Context, *WorldQueryEntitiesWithinAction) (*WorldEntitiesWithinResult, error)
+	WorldDefaultGameMode(context.Context, *WorldQueryDefaultGameModeAction) (*WorldDefaultGameModeResult, error)
+	WorldPlayerSpawn(context.Context, *WorldQueryPlayerSpawnAction) (*WorldPlayerSpawnResult, error)
+	WorldBlock(context.Context, *WorldQueryBlockAction) (*WorldBlockResult, error)
+	WorldBiome(context.Context, *WorldQueryBiomeAction) (*WorldBiomeResult, error)
+	WorldLight(context.Context, *WorldQueryLightAction) (*WorldLightResult, error)
+	WorldSkyLight(context.Context, *WorldQuerySkyLightAction) (*WorldSkyLightResult, error)
+	WorldTemperature(context.Context, *WorldQueryTemperatureAction) (*WorldTemperatureResult, error)
+	WorldHighestBlock(context.Context, *WorldQueryHighestBlockAction) (*WorldHighestBlockResult, error)
+	WorldRainingAt(context.Context, *WorldQueryRainingAtAction) (*WorldRainingAtResult, error)
+	WorldSnowingAt(context.Context, *WorldQuerySnowingAtAction) (*WorldSnowingAtResult, error)
+	WorldThunderingAt(context.Context, *WorldQueryThunderingAtAction) (*WorldThunderingAtResult, error)
+	WorldLiquid(context.Context, *WorldQueryLiquidAction) (*WorldLiquidResult, error)
+	// Player
+	Player(context.Context, *PlayerQuery) (*PlayerInfo, error)
+	// Server
+	Server(context.Context, *ServerQuery) (*ServerInfo, error)
+	mustEmbedUnimplementedQueryServer()
+}
+
+// UnimplementedQueryServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedQueryServer struct{}
+
+func (UnimplementedQueryServer) WorldEntities(context.Context, *WorldQueryEntitiesAction) (*WorldEntitiesResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldEntities not implemented")
+}
+func (UnimplementedQueryServer) WorldPlayers(context.Context, *WorldQueryPlayersAction) (*WorldPlayersResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldPlayers not implemented")
+}
+func (UnimplementedQueryServer) WorldEntitiesWithin(context.Context, *WorldQueryEntitiesWithinAction) (*WorldEntitiesWithinResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldEntitiesWithin not implemented")
+}
+func (UnimplementedQueryServer) WorldDefaultGameMode(context.Context, *WorldQueryDefaultGameModeAction) (*WorldDefaultGameModeResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldDefaultGameMode not implemented")
+}
+func (UnimplementedQueryServer) WorldPlayerSpawn(context.Context, *WorldQueryPlayerSpawnAction) (*WorldPlayerSpawnResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldPlayerSpawn not implemented")
+}
+func (UnimplementedQueryServer) WorldBlock(context.Context, *WorldQueryBlockAction) (*WorldBlockResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldBlock not implemented")
+}
+func (UnimplementedQueryServer) WorldBiome(context.Context, *WorldQueryBiomeAction) (*WorldBiomeResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldBiome not implemented")
+}
+func (UnimplementedQueryServer) WorldLight(context.Context, *WorldQueryLightAction) (*WorldLightResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldLight not implemented")
+}
+func (UnimplementedQueryServer) WorldSkyLight(context.Context, *WorldQuerySkyLightAction) (*WorldSkyLightResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldSkyLight not implemented")
+}
+func (UnimplementedQueryServer) WorldTemperature(context.Context, *WorldQueryTemperatureAction) (*WorldTemperatureResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldTemperature not implemented")
+}
+func (UnimplementedQueryServer) WorldHighestBlock(context.Context, *WorldQueryHighestBlockAction) (*WorldHighestBlockResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldHighestBlock not implemented")
+}
+func (UnimplementedQueryServer) WorldRainingAt(context.Context, *WorldQueryRainingAtAction) (*WorldRainingAtResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldRainingAt not implemented")
+}
+func (UnimplementedQueryServer) WorldSnowingAt(context.Context, *WorldQuerySnowingAtAction) (*WorldSnowingAtResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldSnowingAt not implemented")
+}
+func (UnimplementedQueryServer) WorldThunderingAt(context.Context, *WorldQueryThunderingAtAction) (*WorldThunderingAtResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldThunderingAt not implemented")
+}
+func (UnimplementedQueryServer) WorldLiquid(context.Context, *WorldQueryLiquidAction) (*WorldLiquidResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldLiquid not implemented")
+}
+func (UnimplementedQueryServer) Player(context.Context, *PlayerQuery) (*PlayerInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Player not implemented")
+}
+func (UnimplementedQueryServer) Server(context.Context, *ServerQuery) (*ServerInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Server not implemented")
+}
+func (UnimplementedQueryServer) mustEmbedUnimplementedQueryServer() {}
+func (UnimplementedQueryServer) testEmbeddedByValue()               {}
+
+// UnsafeQueryServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to QueryServer will
+// result in compilation errors.
+type UnsafeQueryServer interface {
+	mustEmbedUnimplementedQueryServer()
+}
+
+func RegisterQueryServer(s grpc.ServiceRegistrar, srv QueryServer) {
+	// If the following call pancis, it indicates UnimplementedQueryServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Query_ServiceDesc, srv)
+}
+
+func _Query_WorldEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryEntitiesAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldEntities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldEntities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldEntities(ctx, req.(*WorldQueryEntitiesAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldPlayers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryPlayersAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldPlayers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldPlayers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldPlayers(ctx, req.(*WorldQueryPlayersAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldEntitiesWithin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryEntitiesWithinAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldEntitiesWithin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldEntitiesWithin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldEntitiesWithin(ctx, req.(*WorldQueryEntitiesWithinAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldDefaultGameMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryDefaultGameModeAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldDefaultGameMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldDefaultGameMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldDefaultGameMode(ctx, req.(*WorldQueryDefaultGameModeAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldPlayerSpawn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryPlayerSpawnAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldPlayerSpawn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldPlayerSpawn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldPlayerSpawn(ctx, req.(*WorldQueryPlayerSpawnAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryBlockAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldBlock(ctx, req.(*WorldQueryBlockAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldBiome_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryBiomeAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldBiome(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldBiome_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldBiome(ctx, req.(*WorldQueryBiomeAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldLight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryLightAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldLight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldLight_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldLight(ctx, req.(*WorldQueryLightAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldSkyLight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQuerySkyLightAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldSkyLight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldSkyLight_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldSkyLight(ctx, req.(*WorldQuerySkyLightAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldTemperature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryTemperatureAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldTemperature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldTemperature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldTemperature(ctx, req.(*WorldQueryTemperatureAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldHighestBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryHighestBlockAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldHighestBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldHighestBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldHighestBlock(ctx, req.(*WorldQueryHighestBlockAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldRainingAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryRainingAtAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldRainingAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldRainingAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldRainingAt(ctx, req.(*WorldQueryRainingAtAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldSnowingAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQuerySnowingAtAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldSnowingAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldSnowingAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldSnowingAt(ctx, req.(*WorldQuerySnowingAtAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldThunderingAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryThunderingAtAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldThunderingAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldThunderingAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldThunderingAt(ctx, req.(*WorldQueryThunderingAtAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_WorldLiquid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldQueryLiquidAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).WorldLiquid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_WorldLiquid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).WorldLiquid(ctx, req.(*WorldQueryLiquidAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_Player_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Player(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_Player_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Player(ctx, req.(*PlayerQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_Server_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Server(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_Server_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Server(ctx, req.(*ServerQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Query_ServiceDesc is the grpc.ServiceDesc for Query service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Query_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "df.plugin.Query",
+	HandlerType: (*QueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "WorldEntities",
+			Handler:    _Query_WorldEntities_Handler,
+		},
+		{
+			MethodName: "WorldPlayers",
+			Handler:    _Query_WorldPlayers_Handler,
+		},
+		{
+			MethodName: "WorldEntitiesWithin",
+			Handler:    _Query_WorldEntitiesWithin_Handler,
+		},
+		{
+			MethodName: "WorldDefaultGameMode",
+			Handler:    _Query_WorldDefaultGameMode_Handler,
+		},
+		{
+			MethodName: "WorldPlayerSpawn",
+			Handler:    _Query_WorldPlayerSpawn_Handler,
+		},
+		{
+			MethodName: "WorldBlock",
+			Handler:    _Query_WorldBlock_Handler,
+		},
+		{
+			MethodName: "WorldBiome",
+			Handler:    _Query_WorldBiome_Handler,
+		},
+		{
+			MethodName: "WorldLight",
+			Handler:    _Query_WorldLight_Handler,
+		},
+		{
+			MethodName: "WorldSkyLight",
+			Handler:    _Query_WorldSkyLight_Handler,
+		},
+		{
+			MethodName: "WorldTemperature",
+			Handler:    _Query_WorldTemperature_Handler,
+		},
+		{
+			MethodName: "WorldHighestBlock",
+			Handler:    _Query_WorldHighestBlock_Handler,
+		},
+		{
+			MethodName: "WorldRainingAt",
+			Handler:    _Query_WorldRainingAt_Handler,
+		},
+		{
+			MethodName: "WorldSnowingAt",
+			Handler:    _Query_WorldSnowingAt_Handler,
+		},
+		{
+			MethodName: "WorldThunderingAt",
+			Handler:    _Query_WorldThunderingAt_Handler,
+		},
+		{
+			MethodName: "WorldLiquid",
+			Handler:    _Query_WorldLiquid_Handler,
+		},
+		{
+			MethodName: "Player",
+			Handler:    _Query_Player_Handler,
+		},
+		{
+			MethodName: "Server",
+			Handler:    _Query_Server_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "query.proto",
+}
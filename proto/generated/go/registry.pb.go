@@ -0,0 +1,590 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: registry.proto
+
+package generated
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RegistryRequest asks the host for its game registry. If known_version matches the current
+// registry version, the host answers with an unchanged Registry instead of the full catalog.
+type RegistryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KnownVersion  string                 `protobuf:"bytes,1,opt,name=known_version,json=knownVersion,proto3" json:"known_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegistryRequest) Reset() {
+	*x = RegistryRequest{}
+	mi := &file_registry_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegistryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistryRequest) ProtoMessage() {}
+
+func (x *RegistryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistryRequest.ProtoReflect.Descriptor instead.
+func (*RegistryRequest) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegistryRequest) GetKnownVersion() string {
+	if x != nil {
+		return x.KnownVersion
+	}
+	return ""
+}
+
+// Registry lists every name the host accepts in actions, so SDKs can validate them ahead of time.
+// version is a hash of the contents; it changes when plugins register custom items or blocks.
+type Registry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Unchanged     bool                   `protobuf:"varint,2,opt,name=unchanged,proto3" json:"unchanged,omitempty"` // Set when the request's known_version is current; all other fields are empty.
+	Blocks        []*BlockType           `protobuf:"bytes,3,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	Items         []*ItemType            `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	EntityTypes   []string               `protobuf:"bytes,5,rep,name=entity_types,json=entityTypes,proto3" json:"entity_types,omitempty"`
+	Biomes        []*BiomeType           `protobuf:"bytes,6,rep,name=biomes,proto3" json:"biomes,omitempty"`
+	Enchantments  []*EnchantmentType     `protobuf:"bytes,7,rep,name=enchantments,proto3" json:"enchantments,omitempty"`
+	Effects       []EffectType           `protobuf:"varint,8,rep,packed,name=effects,proto3,enum=df.plugin.EffectType" json:"effects,omitempty"`        // Effect types that can be applied.
+	Sounds        []Sound                `protobuf:"varint,9,rep,packed,name=sounds,proto3,enum=df.plugin.Sound" json:"sounds,omitempty"`               // Sounds that can be played.
+	Particles     []ParticleType         `protobuf:"varint,10,rep,packed,name=particles,proto3,enum=df.plugin.ParticleType" json:"particles,omitempty"` // Particles that can be shown.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Registry) Reset() {
+	*x = Registry{}
+	mi := &file_registry_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Registry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Registry) ProtoMessage() {}
+
+func (x *Registry) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Registry.ProtoReflect.Descriptor instead.
+func (*Registry) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Registry) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Registry) GetUnchanged() bool {
+	if x != nil {
+		return x.Unchanged
+	}
+	return false
+}
+
+func (x *Registry) GetBlocks() []*BlockType {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *Registry) GetItems() []*ItemType {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Registry) GetEntityTypes() []string {
+	if x != nil {
+		return x.EntityTypes
+	}
+	return nil
+}
+
+func (x *Registry) GetBiomes() []*BiomeType {
+	if x != nil {
+		return x.Biomes
+	}
+	return nil
+}
+
+func (x *Registry) GetEnchantments() []*EnchantmentType {
+	if x != nil {
+		return x.Enchantments
+	}
+	return nil
+}
+
+func (x *Registry) GetEffects() []EffectType {
+	if x != nil {
+		return x.Effects
+	}
+	return nil
+}
+
+func (x *Registry) GetSounds() []Sound {
+	if x != nil {
+		return x.Sounds
+	}
+	return nil
+}
+
+func (x *Registry) GetParticles() []ParticleType {
+	if x != nil {
+		return x.Particles
+	}
+	return nil
+}
+
+// BlockType describes a block and the properties its states accept in BlockState.properties.
+type BlockType struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Properties    []*BlockProperty       `protobuf:"bytes,2,rep,name=properties,proto3" json:"properties,omitempty"`
+	Custom        bool                   `protobuf:"varint,3,opt,name=custom,proto3" json:"custom,omitempty"` // Registered by a plugin.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockType) Reset() {
+	*x = BlockType{}
+	mi := &file_registry_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockType) ProtoMessage() {}
+
+func (x *BlockType) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockType.ProtoReflect.Descriptor instead.
+func (*BlockType) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BlockType) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BlockType) GetProperties() []*BlockProperty {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *BlockType) GetCustom() bool {
+	if x != nil {
+		return x.Custom
+	}
+	return false
+}
+
+type BlockProperty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`     // "bool", "int" or "string"
+	Values        []string               `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"` // Every accepted value, formatted as in BlockState.properties.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockProperty) Reset() {
+	*x = BlockProperty{}
+	mi := &file_registry_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockProperty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockProperty) ProtoMessage() {}
+
+func (x *BlockProperty) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockProperty.ProtoReflect.Descriptor instead.
+func (*BlockProperty) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BlockProperty) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BlockProperty) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *BlockProperty) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// ItemType describes an item and the metas accepted in ItemStack.meta.
+type ItemType struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Metas         []int32                `protobuf:"varint,2,rep,packed,name=metas,proto3" json:"metas,omitempty"`
+	Custom        bool                   `protobuf:"varint,3,opt,name=custom,proto3" json:"custom,omitempty"` // Registered by a plugin.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ItemType) Reset() {
+	*x = ItemType{}
+	mi := &file_registry_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemType) ProtoMessage() {}
+
+func (x *ItemType) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemType.ProtoReflect.Descriptor instead.
+func (*ItemType) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ItemType) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ItemType) GetMetas() []int32 {
+	if x != nil {
+		return x.Metas
+	}
+	return nil
+}
+
+func (x *ItemType) GetCustom() bool {
+	if x != nil {
+		return x.Custom
+	}
+	return false
+}
+
+type BiomeType struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Id            int32                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BiomeType) Reset() {
+	*x = BiomeType{}
+	mi := &file_registry_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BiomeType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BiomeType) ProtoMessage() {}
+
+func (x *BiomeType) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BiomeType.ProtoReflect.Descriptor instead.
+func (*BiomeType) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BiomeType) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BiomeType) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type EnchantmentType struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Id            int32                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	MaxLevel      int32                  `protobuf:"varint,3,opt,name=max_level,json=maxLevel,proto3" json:"max_level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnchantmentType) Reset() {
+	*x = EnchantmentType{}
+	mi := &file_registry_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnchantmentType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnchantmentType) ProtoMessage() {}
+
+func (x *EnchantmentType) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnchantmentType.ProtoReflect.Descriptor instead.
+func (*EnchantmentType) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EnchantmentType) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EnchantmentType) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EnchantmentType) GetMaxLevel() int32 {
+	if x != nil {
+		return x.MaxLevel
+	}
+	return 0
+}
+
+var File_registry_proto protoreflect.FileDescriptor
+
+const file_registry_proto_rawDesc = "" +
+	"\n" +
+	"\x0eregistry.proto\x12\tdf.plugin\x1a\fcommon.proto\x1a\ractions.proto\"6\n" +
+	"\x0fRegistryRequest\x12#\n" +
+	"\rknown_version\x18\x01 \x01(\tR\fknownVersion\"\xbe\x03\n" +
+	"\bRegistry\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1c\n" +
+	"\tunchanged\x18\x02 \x01(\bR\tunchanged\x12,\n" +
+	"\x06blocks\x18\x03 \x03(\v2\x14.df.plugin.BlockTypeR\x06blocks\x12)\n" +
+	"\x05items\x18\x04 \x03(\v2\x13.df.plugin.ItemTypeR\x05items\x12!\n" +
+	"\fentity_types\x18\x05 \x03(\tR\ventityTypes\x12,\n" +
+	"\x06biomes\x18\x06 \x03(\v2\x14.df.plugin.BiomeTypeR\x06biomes\x12>\n" +
+	"\fenchantments\x18\a \x03(\v2\x1a.df.plugin.EnchantmentTypeR\fenchantments\x12/\n" +
+	"\aeffects\x18\b \x03(\x0e2\x15.df.plugin.EffectTypeR\aeffects\x12(\n" +
+	"\x06sounds\x18\t \x03(\x0e2\x10.df.plugin.SoundR\x06sounds\x125\n" +
+	"\tparticles\x18\n" +
+	" \x03(\x0e2\x17.df.plugin.ParticleTypeR\tparticles\"q\n" +
+	"\tBlockType\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x128\n" +
+	"\n" +
+	"properties\x18\x02 \x03(\v2\x18.df.plugin.BlockPropertyR\n" +
+	"properties\x12\x16\n" +
+	"\x06custom\x18\x03 \x01(\bR\x06custom\"O\n" +
+	"\rBlockProperty\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06values\x18\x03 \x03(\tR\x06values\"L\n" +
+	"\bItemType\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05metas\x18\x02 \x03(\x05R\x05metas\x12\x16\n" +
+	"\x06custom\x18\x03 \x01(\bR\x06custom\"/\n" +
+	"\tBiomeType\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x05R\x02id\"R\n" +
+	"\x0fEnchantmentType\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x05R\x02id\x12\x1b\n" +
+	"\tmax_level\x18\x03 \x01(\x05R\bmaxLevelB\x8c\x01\n" +
+	"\rcom.df.pluginB\rRegistryProtoP\x01Z'github.com/secmc/plugin/proto/generated\xa2\x02\x03DPX\xaa\x02\tDf.Plugin\xca\x02\tDf\\Plugin\xe2\x02\x15Df\\Plugin\\GPBMetadata\xea\x02\n" +
+	"Df::Pluginb\x06proto3"
+
+var (
+	file_registry_proto_rawDescOnce sync.Once
+	file_registry_proto_rawDescData []byte
+)
+
+func file_registry_proto_rawDescGZIP() []byte {
+	file_registry_proto_rawDescOnce.Do(func() {
+		file_registry_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_registry_proto_rawDesc), len(file_registry_proto_rawDesc)))
+	})
+	return file_registry_proto_rawDescData
+}
+
+var file_registry_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_registry_proto_goTypes = []any{
+	(*RegistryRequest)(nil), // 0: df.plugin.RegistryRequest
+	(*Registry)(nil),        // 1: df.plugin.Registry
+	(*BlockType)(nil),       // 2: df.plugin.BlockType
+	(*BlockProperty)(nil),   // 3: df.plugin.BlockProperty
+	(*ItemType)(nil),        // 4: df.plugin.ItemType
+	(*BiomeType)(nil),       // 5: df.plugin.BiomeType
+	(*EnchantmentType)(nil), // 6: df.plugin.EnchantmentType
+	(EffectType)(0),         // 7: df.plugin.EffectType
+	(Sound)(0),              // 8: df.plugin.Sound
+	(ParticleType)(0),       // 9: df.plugin.ParticleType
+}
+var file_registry_proto_depIdxs = []int32{
+	2, // 0: df.plugin.Registry.blocks:type_name -> df.plugin.BlockType
+	4, // 1: df.plugin.Registry.items:type_name -> df.plugin.ItemType
+	5, // 2: df.plugin.Registry.biomes:type_name -> df.plugin.BiomeType
+	6, // 3: df.plugin.Registry.enchantments:type_name -> df.plugin.EnchantmentType
+	7, // 4: df.plugin.Registry.effects:type_name -> df.plugin.EffectType
+	8, // 5: df.plugin.Registry.sounds:type_name -> df.plugin.Sound
+	9, // 6: df.plugin.Registry.particles:type_name -> df.plugin.ParticleType
+	3, // 7: df.plugin.BlockType.properties:type_name -> df.plugin.BlockProperty
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_registry_proto_init() }
+func file_registry_proto_init() {
+	if File_registry_proto != nil {
+		return
+	}
+	file_common_proto_init()
+	file_actions_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registry_proto_rawDesc), len(file_registry_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_registry_proto_goTypes,
+		DependencyIndexes: file_registry_proto_depIdxs,
+		MessageInfos:      file_registry_proto_msgTypes,
+	}.Build()
+	File_registry_proto = out.File
+	file_registry_proto_goTypes = nil
+	file_registry_proto_depIdxs = nil
+}